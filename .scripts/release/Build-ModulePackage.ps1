@@ -5,7 +5,8 @@
     Builds the Bolt module package for release
 .DESCRIPTION
     Creates the module structure using New-BoltModule.ps1 and prepares
-    the release directory for packaging.
+    the release directory for packaging. The release version is written
+    into the packaged bolt.ps1 for -Upgrade.
 .PARAMETER Version
     The version being released (e.g., 0.1.0, 1.0.0-beta)
 #>
@@ -37,3 +38,14 @@ if (-not (Test-Path -Path $moduleDir)) {
 }
 
 Write-Host "✓ Module structure created successfully" -ForegroundColor Green
+
+# Stamp the release version into bolt.ps1 so -Upgrade can compare it with newer releases
+$boltScriptPath = Join-Path -Path $moduleDir -ChildPath "bolt.ps1"
+$boltScript = Get-Content -Path $boltScriptPath -Raw
+$versionLine = "`$script:BoltVersion = '0.0.0-dev'"
+if (-not $boltScript.Contains($versionLine)) {
+    Write-Error "❌ Could not find the BoltVersion line in $boltScriptPath"
+    exit 1
+}
+Set-Content -Path $boltScriptPath -Value $boltScript.Replace($versionLine, "`$script:BoltVersion = '$Version'") -NoNewline
+Write-Host "✓ Stamped bolt.ps1 with version $Version" -ForegroundColor Green
@@ -202,14 +202,102 @@ function Invoke-Bolt {
         One or more task names to execute
     .PARAMETER ListTasks
         Display all available tasks
+    .PARAMETER Filter
+        Wildcard pattern to narrow -ListTasks by task name
+    .PARAMETER NoHeader
+        One line per task without headings in -ListTasks
+    .PARAMETER Tag
+        Run or list the tasks with any of these # TAGS:
     .PARAMETER Only
         Skip task dependencies
     .PARAMETER Outline
         Show task execution plan without running
+    .PARAMETER DryRun
+        Show commands, environment, parallel groups, and cache hits without running
+    .PARAMETER NoCache
+        Run tasks even when their inputs have not changed
+    .PARAMETER UpdateSnapshot
+        Rewrite the # SNAPSHOT: files of the tasks that run
+    .PARAMETER StrictResources
+        Fail tasks with CPU or memory limits where they cannot be applied
+    .PARAMETER Parallel
+        Run independent tasks at the same time
+    .PARAMETER Parallelism
+        Maximum number of tasks running at once with -Parallel
+    .PARAMETER NoProgress
+        Do not show the live display of running tasks with -Parallel
+    .PARAMETER Interactive
+        Pick the tasks to run from a menu when no task names are given
+    .PARAMETER FailFast
+        Stop at the first failing task (the default)
+    .PARAMETER NoFailFast
+        Keep running tasks whose dependencies succeeded and list every failure
+    .PARAMETER OutputFormat
+        Text or Json (RunSummary object on stdout)
+    .PARAMETER Watch
+        Re-run tasks when their input files change
+    .PARAMETER Debounce
+        Milliseconds to wait for more changes in -Watch mode
+    .PARAMETER Benchmark
+        Time -Runs full runs of the tasks with the cache turned off
+    .PARAMETER Runs
+        Number of runs with -Benchmark
+    .PARAMETER CleanEnv
+        Run tasks with only the declared environment variables
+    .PARAMETER UndefinedVars
+        Error, Empty, or Passthrough for undefined `${NAME} in inline hooks
+    .PARAMETER GracePeriod
+        How long child tasks get to exit after Ctrl+C or SIGTERM
+    .PARAMETER LockTimeout
+        How long to wait for another bolt run in the same project
+    .PARAMETER BaseDir
+        Directory relative # WORKDIR: paths are resolved from
+    .PARAMETER RefreshRemotes
+        Download the RemoteIncludes task files again
+    .PARAMETER AuditLog
+        File to append a JSON record per task to, or to read with -Audit Tail
+    .PARAMETER CpuProfile
+        File for a JSON profile of bolt's own time per phase
+    .PARAMETER MemProfile
+        File for a JSON summary of bolt's memory use
+    .PARAMETER OtelEndpoint
+        OTLP/HTTP collector URL to send a trace of the run to
+    .PARAMETER OtelServiceName
+        The service.name of the trace, bolt by default
+    .PARAMETER Since
+        Only run tasks whose inputs changed since this git ref
+    .PARAMETER LogLevel
+        Debug, Info, Warn, or Error for the structured log on stderr
+    .PARAMETER LogFormat
+        Text or Json for the structured log
     .PARAMETER TaskDirectory
         Override the default .build directory name
     .PARAMETER NewTask
         Create a new task file
+    .PARAMETER Init
+        Scaffold starter task files in the current directory
+    .PARAMETER Type
+        Go, Node, or Generic project type for -Init
+    .PARAMETER Force
+        Overwrite existing task files with -Init
+    .PARAMETER Graph
+        Write the task dependency graph
+    .PARAMETER Format
+        Dot or Mermaid output for -Graph
+    .PARAMETER Completion
+        Write a bash, zsh, or fish completion script
+    .PARAMETER Secret
+        Set, Get, or Delete a secret in the OS keychain
+    .PARAMETER Key
+        The secret name for -Secret
+    .PARAMETER ListLogs
+        Print the task log files of the last run (needs LogDir in bolt.config.json)
+    .PARAMETER Audit
+        Tail prints the last records of the -AuditLog file
+    .PARAMETER Last
+        Number of records for -Audit Tail
+    .PARAMETER Upgrade
+        Check for, or Apply, a newer bolt release from GitHub
     .PARAMETER Arguments
         Additional arguments to pass to tasks
     #>
@@ -221,20 +309,124 @@ function Invoke-Bolt {
         [Alias('Help')]
         [switch]`$ListTasks,
 
+        [string]`$Filter,
+
+        [switch]`$NoHeader,
+
+        [string[]]`$Tag,
+
         [switch]`$Only,
 
         [switch]`$Outline,
 
+        [switch]`$DryRun,
+
+        [switch]`$NoCache,
+
+        [switch]`$UpdateSnapshot,
+
+        [switch]`$StrictResources,
+
+        [switch]`$Parallel,
+
+        [int]`$Parallelism,
+
+        [switch]`$NoProgress,
+
+        [switch]`$Interactive,
+
+        [switch]`$FailFast,
+
+        [switch]`$NoFailFast,
+
+        [ValidateSet('Text', 'Json')]
+        [string]`$OutputFormat = 'Text',
+
+        [switch]`$Watch,
+
+        [int]`$Debounce,
+
+        [switch]`$Benchmark,
+
+        [int]`$Runs,
+
+        [switch]`$CleanEnv,
+
+        [ValidateSet('Error', 'Empty', 'Passthrough')]
+        [string]`$UndefinedVars = 'Error',
+
+        [string]`$GracePeriod,
+
+        [string]`$LockTimeout,
+
+        [string]`$BaseDir,
+
+        [switch]`$RefreshRemotes,
+
+        [string]`$AuditLog,
+
+        [string]`$CpuProfile,
+
+        [string]`$MemProfile,
+
+        [string]`$OtelEndpoint,
+
+        [string]`$OtelServiceName,
+
+        [string]`$Since,
+
+        [ValidateSet('Debug', 'Info', 'Warn', 'Error')]
+        [string]`$LogLevel,
+
+        [ValidateSet('Text', 'Json')]
+        [string]`$LogFormat = 'Text',
+
         [string]`$TaskDirectory = '.build',
 
         [string]`$NewTask,
 
+        [switch]`$Init,
+
+        [ValidateSet('Go', 'Node', 'Generic')]
+        [string]`$Type = 'Generic',
+
+        [switch]`$Force,
+
+        [switch]`$Graph,
+
+        [ValidateSet('Dot', 'Mermaid')]
+        [string]`$Format = 'Dot',
+
+        [ValidateSet('Bash', 'Zsh', 'Fish')]
+        [string]`$Completion,
+
+        [ValidateSet('Set', 'Get', 'Delete')]
+        [string]`$Secret,
+
+        [string]`$Key,
+
+        [switch]`$ListLogs,
+
+        [ValidateSet('Tail')]
+        [string]`$Audit,
+
+        [ValidateRange(1, 100000)]
+        [int]`$Last = 10,
+
+        [ValidateSet('Check', 'Apply')]
+        [string]`$Upgrade,
+
         [Parameter(ValueFromRemainingArguments)]
         [string[]]`$Arguments
     )
 
-    # Find the project root with .build directory
-    `$buildPath = Find-BuildDirectory -TaskDirectory `$TaskDirectory
+    # Find the project root with .build directory (-Init scaffolds into the current directory,
+    # and -Completion, -Secret, -Audit, and -Upgrade do not need a project)
+    `$buildPath = if (`$Init -or `$Completion -or `$Secret -or `$Audit -or `$Upgrade) {
+        Join-Path -Path (Get-Location).Path -ChildPath `$TaskDirectory
+    } else {
+        Find-BuildDirectory -TaskDirectory `$TaskDirectory
+    }
 
     if (-not `$buildPath) {
         Write-Error "Could not find '`$TaskDirectory' directory in current path or any parent directory."
@@ -256,10 +448,63 @@ function Invoke-Bolt {
     `$boltParams = @{}
     if (`$Task) { `$boltParams['Task'] = `$Task }
     if (`$ListTasks) { `$boltParams['ListTasks'] = `$true }
+    if (`$Filter) { `$boltParams['Filter'] = `$Filter }
+    if (`$NoHeader) { `$boltParams['NoHeader'] = `$true }
+    if (`$Tag) { `$boltParams['Tag'] = `$Tag }
     if (`$Only) { `$boltParams['Only'] = `$true }
     if (`$Outline) { `$boltParams['Outline'] = `$true }
+    if (`$DryRun) { `$boltParams['DryRun'] = `$true }
+    if (`$NoCache) { `$boltParams['NoCache'] = `$true }
+    if (`$UpdateSnapshot) { `$boltParams['UpdateSnapshot'] = `$true }
+    if (`$StrictResources) { `$boltParams['StrictResources'] = `$true }
+    if (`$Parallel) { `$boltParams['Parallel'] = `$true }
+    if (`$Parallelism) { `$boltParams['Parallelism'] = `$Parallelism }
+    if (`$NoProgress) { `$boltParams['NoProgress'] = `$true }
+    if (`$Interactive) { `$boltParams['Interactive'] = `$true }
+    if (`$FailFast) { `$boltParams['FailFast'] = `$true }
+    if (`$NoFailFast) { `$boltParams['NoFailFast'] = `$true }
+    if (`$OutputFormat -ne 'Text') { `$boltParams['OutputFormat'] = `$OutputFormat }
+    if (`$Watch) { `$boltParams['Watch'] = `$true }
+    if (`$PSBoundParameters.ContainsKey('Debounce')) { `$boltParams['Debounce'] = `$Debounce }
+    if (`$Benchmark) { `$boltParams['Benchmark'] = `$true }
+    if (`$PSBoundParameters.ContainsKey('Runs')) { `$boltParams['Runs'] = `$Runs }
+    if (`$CleanEnv) { `$boltParams['CleanEnv'] = `$true }
+    if (`$UndefinedVars -ne 'Error') { `$boltParams['UndefinedVars'] = `$UndefinedVars }
+    if (`$GracePeriod) { `$boltParams['GracePeriod'] = `$GracePeriod }
+    if (`$LockTimeout) { `$boltParams['LockTimeout'] = `$LockTimeout }
+    # Resolved here because bolt.ps1 runs from the project root
+    if (`$BaseDir) { `$boltParams['BaseDir'] = [System.IO.Path]::GetFullPath(`$BaseDir, (Get-Location).Path) }
+    if (`$RefreshRemotes) { `$boltParams['RefreshRemotes'] = `$true }
+    if (`$AuditLog) { `$boltParams['AuditLog'] = [System.IO.Path]::GetFullPath(`$AuditLog, (Get-Location).Path) }
+    if (`$CpuProfile) { `$boltParams['CpuProfile'] = [System.IO.Path]::GetFullPath(`$CpuProfile, (Get-Location).Path) }
+    if (`$MemProfile) { `$boltParams['MemProfile'] = [System.IO.Path]::GetFullPath(`$MemProfile, (Get-Location).Path) }
+    if (`$OtelEndpoint) { `$boltParams['OtelEndpoint'] = `$OtelEndpoint }
+    if (`$OtelServiceName) { `$boltParams['OtelServiceName'] = `$OtelServiceName }
+    if (`$Since) { `$boltParams['Since'] = `$Since }
+    if (`$LogLevel) { `$boltParams['LogLevel'] = `$LogLevel }
+    if (`$LogFormat -ne 'Text') { `$boltParams['LogFormat'] = `$LogFormat }
     if (`$TaskDirectory -ne '.build') { `$boltParams['TaskDirectory'] = `$TaskDirectory }
     if (`$NewTask) { `$boltParams['NewTask'] = `$NewTask }
+    if (`$Graph) {
+        `$boltParams['Graph'] = `$true
+        `$boltParams['Format'] = `$Format
+    }
+    if (`$Completion) { `$boltParams['Completion'] = `$Completion }
+    if (`$Secret) {
+        `$boltParams['Secret'] = `$Secret
+        `$boltParams['Key'] = `$Key
+    }
+    if (`$ListLogs) { `$boltParams['ListLogs'] = `$true }
+    if (`$Audit) {
+        `$boltParams['Audit'] = `$Audit
+        `$boltParams['Last'] = `$Last
+    }
+    if (`$Upgrade) { `$boltParams['Upgrade'] = `$Upgrade }
+    if (`$Init) {
+        `$boltParams['Init'] = `$true
+        `$boltParams['Type'] = `$Type
+        if (`$Force) { `$boltParams['Force'] = `$true }
+    }
     if (`$Arguments) { `$boltParams['Arguments'] = `$Arguments }
 
     # Execute bolt.ps1 from the project root directory
@@ -13,22 +13,189 @@ using namespace System.Management.Automation
 .PARAMETER Task
     One or more task names to execute. Tasks are executed in sequence.
 .PARAMETER ListTasks
-    Display all available tasks with their descriptions and dependencies.
+    Display all available tasks with their descriptions, dependencies, and timeouts.
+.PARAMETER Filter
+    With -ListTasks, only show tasks whose name or alias matches this wildcard
+    pattern, like 'build*' or '*-deploy'.
+.PARAMETER Tag
+    Run, or with -ListTasks list, the tasks whose # TAGS: include any of these
+    tags. Tagged tasks run with their dependencies, as if their names were given.
+.PARAMETER NoHeader
+    With -ListTasks, print one line per task (name and description) without
+    headings, for use in scripts.
 .PARAMETER Only
     Skip task dependencies and execute only the specified tasks.
 .PARAMETER Outline
     Display the task dependency tree and execution order without executing tasks.
     Shows what would be executed when the task is run.
+.PARAMETER DryRun
+    Print the execution plan without running any task or hook: the tasks in
+    order, grouped by what could run in parallel, each task's command line,
+    declared environment variables, hooks with ${NAME} values filled in, and
+    which tasks the cache would skip. Use with -OutputFormat Json for a JSON plan.
+.PARAMETER NoCache
+    Run every task even when its INPUTS have not changed since the last successful
+    run. Cache entries are still updated.
+.PARAMETER UpdateSnapshot
+    Write the standard output of tasks with a # SNAPSHOT: to their snapshot files
+    instead of comparing it. These tasks run even when they are cached.
+.PARAMETER StrictResources
+    Fail tasks with # CPU_QUOTA: or # MEMORY_LIMIT_MB: on Windows and macOS, where
+    the limits cannot be applied, instead of running them without limits.
+.PARAMETER Parallel
+    Run tasks that do not depend on each other at the same time. Each task runs in
+    its own pwsh process and its output lines are prefixed with the task name.
+    The first failing task stops all tasks that are still running.
+.PARAMETER Interactive
+    When no task names or -Tag are given, pick the tasks to run from a menu of all
+    tasks. Bolt also shows the menu when it is started with no arguments from an
+    interactive terminal (set BOLT_NO_INTERACTIVE=1 to show the usage instead).
+.PARAMETER FailFast
+    Stop the run at the first failing task, cancelling tasks still running with
+    -Parallel. This is the default, and it also applies with -ErrorAction Continue.
+.PARAMETER NoFailFast
+    Keep running every task whose dependencies all succeeded after a task fails.
+    Tasks that depend on a failed task are skipped. The run exits with code 1 and
+    lists every failed task.
+.PARAMETER Parallelism
+    Maximum number of tasks running at once with -Parallel. Defaults to the number
+    of processors.
+.PARAMETER NoProgress
+    Do not show the live display of running tasks that -Parallel draws when
+    standard error is a terminal. Output is still printed line by line.
+.PARAMETER OutputFormat
+    Text (default) or Json. With Json, human-readable output is hidden and a single
+    RunSummary JSON object with the result of every task is written to stdout when
+    all tasks finish. Project tasks run in child processes so their output is
+    captured instead of mixed into the JSON. With -ListTasks, Json writes a JSON
+    array with the metadata of each task.
+.PARAMETER Watch
+    Run the tasks, then keep watching the files listed in their # INPUTS: metadata
+    and re-run the affected tasks when those files change. Press Ctrl+C or 'q' to stop.
+.PARAMETER Debounce
+    Milliseconds to wait for more changes before re-running tasks in -Watch mode.
+    Defaults to 200.
+.PARAMETER Benchmark
+    Run the tasks and their dependencies -Runs times with the cache turned off,
+    then report the mean, median, p95, p99, and standard deviation of the run
+    times. Honors -Parallel and -Parallelism. With -OutputFormat Json, a single
+    JSON object with the durations and statistics is written to stdout.
+.PARAMETER Runs
+    Number of runs with -Benchmark. Defaults to 10.
+.PARAMETER CleanEnv
+    Run project tasks with only the environment variables declared in the Env
+    section of bolt.config.json and in # ENV: metadata, plus PATH, HOME, USERPROFILE,
+    SystemRoot, TEMP, TMP, TMPDIR, and on Windows PATHEXT and ComSpec. Tools and
+    pwsh do not start without these, and Env or # ENV: values with the same names
+    replace them. Tasks run in child processes.
+.PARAMETER UndefinedVars
+    What to do when an inline hook uses ${NAME} for a variable that is not set:
+    Error (default) fails the hook, Empty replaces it with an empty string, and
+    Passthrough leaves the ${NAME} text in the command.
+.PARAMETER GracePeriod
+    How long a task that runs in a child process gets to exit after bolt receives
+    Ctrl+C (SIGINT) or SIGTERM, before it is killed. Uses the same duration format as
+    # TIMEOUT:. Defaults to 5s. The task is recorded with exit code 128 + the signal
+    number (130 or 143) and bolt exits with the same code.
+.PARAMETER BaseDir
+    Directory that relative # WORKDIR: paths are resolved from, instead of the
+    project root. Relative to the current directory. Useful in CI, where the
+    checkout path changes between runs.
+.PARAMETER RefreshRemotes
+    Download every RemoteIncludes task file in bolt.config.json again instead of
+    using the copies in .bolt/remote/. Each download is still checked against its
+    Sha256.
+.PARAMETER AuditLog
+    Append one JSON record per task to this file: timestamp, task, user, hostname,
+    git_sha, status, duration_ms, and exit_code. Relative to the current directory.
+    Concurrent runs can share the file. With -Audit Tail, the file to read.
+.PARAMETER CpuProfile
+    Write a JSON profile of bolt's own wall-clock and processor time to this file
+    when the run ends, in total and per phase (discover, plan, lock, run, and
+    cache-key). Tasks in child processes are not counted, so the profile shows
+    bolt's overhead. Relative to the current directory.
+.PARAMETER MemProfile
+    Write a JSON summary of bolt's memory use to this file when the run ends: the
+    managed heap, bytes allocated, working set and its peak, and garbage collection
+    counts. Relative to the current directory.
+.PARAMETER OtelEndpoint
+    Send an OpenTelemetry trace of the run to this OTLP/HTTP collector, like
+    http://localhost:4318 for Jaeger or Tempo. The run is the root span and each
+    task is a child span with bolt.task.name, bolt.task.exit_code,
+    bolt.task.cached, and bolt.task.duration_ms. The trace is sent when the run
+    ends; a collector that cannot be reached only prints a warning.
+.PARAMETER OtelServiceName
+    The service.name of the -OtelEndpoint trace. Defaults to OTEL_SERVICE_NAME,
+    or bolt when that is not set.
+.PARAMETER LockTimeout
+    How long to wait for another bolt run in the same project to finish before
+    failing. Only one run at a time holds .bolt/bolt.lock. Uses the same duration
+    format as # TIMEOUT:. Defaults to 60s; 0 fails at once.
+.PARAMETER Since
+    Run only the tasks whose # INPUTS: files changed since a git ref, and the tasks
+    that depend on them. The changed files come from git diff --name-only <ref>.
+    Other project tasks are skipped and reported as SKIPPED(git). Needs git on the
+    PATH; without it a warning is shown and every task runs.
+.PARAMETER LogLevel
+    Write a structured log to standard error: Debug, Info, Warn, or Error. Info logs
+    task start and finish events. Debug also logs each child process with its full
+    argument list, working directory, environment variable names (not values), and
+    start and stop times. Off unless set; console output is unchanged.
+.PARAMETER LogFormat
+    Text (key=value pairs, the default) or Json (one object per line) for -LogLevel.
 .PARAMETER TaskDirectory
     Directory containing task scripts. Defaults to .build in the script's directory.
     Relative paths are resolved relative to the script location.
 .PARAMETER NewTask
     Create a new task file with the specified name. Creates a stubbed file in
     the task directory with proper metadata structure.
+.PARAMETER Init
+    Scaffold starter task files for a project in the task directory. Refuses to
+    overwrite existing task files unless -Force is passed.
+.PARAMETER Type
+    With -Init, the kind of project: Go (reads the module name and Go version
+    from go.mod), Node (reads the scripts from package.json), or Generic.
+.PARAMETER Force
+    With -Init, overwrite task files that already exist.
+.PARAMETER Graph
+    Write the task dependency graph to stdout. Edges point from a dependency to
+    the task that depends on it. With task names, only those tasks and what they
+    depend on are included.
+.PARAMETER Format
+    With -Graph, Dot (Graphviz, the default) or Mermaid.
+.PARAMETER Completion
+    Write a completion script for bash, zsh, or fish to stdout. It completes task
+    names, the flags for each mode, flag values, and directories for -TaskDirectory.
+.PARAMETER Secret
+    Set, Get, or Delete the secret named by -Key in the OS keychain (Windows
+    Credential Manager, macOS keychain, or secret-tool on Linux). Set reads the
+    value from standard input, or prompts for it without echo. Get writes the value
+    to stdout. Tasks read secrets with # SECRET_ENV: when they start.
+.PARAMETER Key
+    With -Secret, the secret name. It is also the environment variable name that
+    # SECRET_ENV: sets.
+.PARAMETER ListLogs
+    Print the paths of the task log files that the last run wrote to the LogDir
+    set in bolt.config.json, one per line.
+.PARAMETER Audit
+    Tail prints the last -Last records of the -AuditLog file as a table.
+.PARAMETER Last
+    Number of records -Audit Tail prints. Defaults to 10.
+.PARAMETER Upgrade
+    Check asks the GitHub Releases API for the latest bolt release and says whether
+    it is newer than this bolt.ps1. Apply also downloads the release archive,
+    verifies it against the release's SHA-256 checksum file, and replaces this
+    bolt.ps1 with the released copy, or the whole module in module mode. Run bolt
+    again afterwards to use it. Apply refuses development copies (0.0.0-dev) and
+    copies inside a git work tree.
 .PARAMETER ValidateTasks
     Validate all task files in the task directory. Checks for required metadata
     (TASK, DESCRIPTION, DEPENDS) and proper exit codes. Displays a detailed
-    report showing the status of each task file.
+    report showing the status of each task file. Missing dependencies, invalid
+    TIMEOUT or RETRY values, invalid INPUTS/OUTPUTS globs, invalid container settings, and empty scripts are listed in
+    a Task | Field | Issue table and make the exit code 1.
+.PARAMETER Strict
+    With -ValidateTasks, also fail when a task has no description.
 .PARAMETER Arguments
     Additional arguments to pass to the task scripts.
 .EXAMPLE
@@ -37,24 +204,69 @@ using namespace System.Management.Automation
 .EXAMPLE
     .\bolt.ps1 format lint build -Only
     Executes format, lint, and build tasks without their dependencies.
+.EXAMPLE
+    .\bolt.ps1 build test -Parallel -Parallelism 4
+    Executes build and test and their dependencies, running up to four independent tasks at once.
+.EXAMPLE
+    .\bolt.ps1 build -OutputFormat Json | ConvertFrom-Json
+    Runs build and returns the task results as an object for CI scripts.
+.EXAMPLE
+    .\bolt.ps1 build -Watch
+    Runs build, then re-runs it whenever one of its input files changes.
 .EXAMPLE
     .\bolt.ps1 -TaskDirectory "custom-tasks"
     Lists and executes tasks from the custom-tasks directory instead of .build.
 .EXAMPLE
     .\bolt.ps1 -ListTasks
     Shows all available tasks.
+.EXAMPLE
+    .\bolt.ps1 -ListTasks -Filter 'test*' -OutputFormat Json
+    Writes the metadata of every task starting with 'test' as a JSON array.
+.EXAMPLE
+    .\bolt.ps1 build -DryRun
+    Shows the execution plan for the build task, including cache hits, without running anything.
 .EXAMPLE
     .\bolt.ps1 build -Outline
     Shows the dependency tree and execution order for the build task without executing it.
 .EXAMPLE
     .\bolt.ps1 -NewTask clean
     Creates a new task file named Invoke-Clean.ps1 in the task directory.
+.EXAMPLE
+    .\bolt.ps1 -Init -Type Go
+    Creates tidy, lint, test, build, and release tasks for the Go module in the current project.
+.EXAMPLE
+    .\bolt.ps1 -Graph build -Format Mermaid
+    Writes a Mermaid diagram of the build task and everything it depends on.
+.EXAMPLE
+    pwsh -File bolt.ps1 -Completion bash > ~/.bolt-completion.bash
+    Writes a bash completion script to source from ~/.bashrc.
+.EXAMPLE
+    .\bolt.ps1 -Secret Set -Key AWS_SECRET_ACCESS_KEY
+    Prompts for a value and stores it in the OS keychain for tasks with # SECRET_ENV: AWS_SECRET_ACCESS_KEY.
+.EXAMPLE
+    .\bolt.ps1 -Audit Tail -AuditLog .bolt/audit.ndjson -Last 20
+    Prints the last 20 task records that runs with -AuditLog .bolt/audit.ndjson appended.
+.EXAMPLE
+    .\bolt.ps1 -Upgrade Check
+    Says whether a newer bolt release is available on GitHub.
 .EXAMPLE
     .\bolt.ps1 -ValidateTasks
     Validates all task files and displays a detailed report of metadata compliance.
 .EXAMPLE
     .\bolt.ps1 format,lint,build -ErrorAction Continue
     Runs all tasks even if one fails (useful for seeing all errors at once).
+.EXAMPLE
+    .\bolt.ps1 build -OtelEndpoint http://localhost:4318 -OtelServiceName my-app
+    Runs build and sends its trace to a local Jaeger or Tempo collector.
+.EXAMPLE
+    .\bolt.ps1 -Interactive -Parallel
+    Shows a menu of all tasks and runs the selected ones in parallel.
+.EXAMPLE
+    .\bolt.ps1 -Tag release
+    Runs every task tagged release, and their dependencies.
+.EXAMPLE
+    .\bolt.ps1 test -Parallel -NoFailFast
+    Runs every test task whose dependencies succeeded and lists all failures at the end.
 .NOTES
     For module installation, use the New-BoltModule.ps1 script:
     - Install: .\New-BoltModule.ps1 -Install
@@ -63,7 +275,8 @@ using namespace System.Management.Automation
 [CmdletBinding(DefaultParameterSetName = 'Help')]
 param(
     # TaskExecution parameter set (for running tasks)
-    [Parameter(Mandatory = $true, Position = 0, ParameterSetName = 'TaskExecution')]
+    [Parameter(Position = 0, ParameterSetName = 'TaskExecution')]
+    [Parameter(Position = 0, ParameterSetName = 'Graph')]
     [ValidateScript({
         foreach ($taskArg in $_) {
             # SECURITY: Validate task name format (P0 - Task Name Validation)
@@ -87,6 +300,28 @@ param(
     [Alias('Help')]
     [switch]$ListTasks,
 
+    [Parameter(ParameterSetName = 'ListTasks')]
+    [ValidateNotNullOrEmpty()]
+    [string]$Filter,
+
+    [Parameter(ParameterSetName = 'ListTasks')]
+    [switch]$NoHeader,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [Parameter(ParameterSetName = 'ListTasks')]
+    [ValidateScript({
+        foreach ($tagArg in $_) {
+            # Comma-separated tags arrive as one string from pwsh -File
+            foreach ($tagName in ($tagArg -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })) {
+                if ($tagName -cnotmatch '^[a-z0-9][a-z0-9\-]*$') {
+                    throw "Tag '$tagName' contains invalid characters. Only lowercase letters, numbers, and hyphens are allowed."
+                }
+            }
+        }
+        return $true
+    })]
+    [string[]]$Tag,
+
     # TaskExecution parameter set options
     [Parameter(ParameterSetName = 'TaskExecution')]
     [switch]$Only,
@@ -94,10 +329,121 @@ param(
     [Parameter(ParameterSetName = 'TaskExecution')]
     [switch]$Outline,
 
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [switch]$DryRun,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [switch]$NoCache,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [switch]$UpdateSnapshot,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [switch]$StrictResources,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [switch]$Parallel,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [switch]$Interactive,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [switch]$FailFast,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [switch]$NoFailFast,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [ValidateRange(1, 256)]
+    [int]$Parallelism = [Environment]::ProcessorCount,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [switch]$NoProgress,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [Parameter(ParameterSetName = 'ListTasks')]
+    [ValidateSet('Text', 'Json')]
+    [string]$OutputFormat = 'Text',
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [switch]$Watch,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [ValidateRange(0, 60000)]
+    [int]$Debounce = 200,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [switch]$Benchmark,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [ValidateRange(1, 10000)]
+    [int]$Runs = 10,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [switch]$CleanEnv,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [ValidateSet('Error', 'Empty', 'Passthrough')]
+    [string]$UndefinedVars = 'Error',
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [string]$GracePeriod = '5s',
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [string]$LockTimeout = '60s',
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [Parameter(ParameterSetName = 'ValidateTasks')]
+    [string]$BaseDir,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [Parameter(ParameterSetName = 'ListTasks')]
+    [Parameter(ParameterSetName = 'ValidateTasks')]
+    [switch]$RefreshRemotes,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [Parameter(Mandatory = $true, ParameterSetName = 'Audit')]
+    [string]$AuditLog,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [string]$CpuProfile,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [string]$MemProfile,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [ValidateNotNullOrEmpty()]
+    [string]$OtelEndpoint,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [ValidateNotNullOrEmpty()]
+    [string]$OtelServiceName,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [ValidateNotNullOrEmpty()]
+    [ValidateScript({
+        # A ref starting with '-' would be read as a git option
+        if ($_.StartsWith('-')) {
+            throw "Git ref '$_' cannot start with '-'"
+        }
+        return $true
+    })]
+    [string]$Since,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [ValidateSet('Debug', 'Info', 'Warn', 'Error')]
+    [string]$LogLevel,
+
+    [Parameter(ParameterSetName = 'TaskExecution')]
+    [ValidateSet('Text', 'Json')]
+    [string]$LogFormat = 'Text',
+
     # Available in multiple parameter sets
     [Parameter(ParameterSetName = 'TaskExecution')]
     [Parameter(ParameterSetName = 'ListTasks')]
     [Parameter(ParameterSetName = 'CreateTask')]
+    [Parameter(ParameterSetName = 'Init')]
+    [Parameter(ParameterSetName = 'Graph')]
     [Parameter(ParameterSetName = 'ValidateTasks')]
     [ValidatePattern('^[a-zA-Z0-9_\-\./\\]+$')]
     [ValidateScript({
@@ -122,6 +468,17 @@ param(
     })]
     [string]$NewTask,
 
+    # Init parameter set
+    [Parameter(Mandatory = $true, ParameterSetName = 'Init')]
+    [switch]$Init,
+
+    [Parameter(ParameterSetName = 'Init')]
+    [ValidateSet('Go', 'Node', 'Generic')]
+    [string]$Type = 'Generic',
+
+    [Parameter(ParameterSetName = 'Init')]
+    [switch]$Force,
+
     # ListVariables parameter set
     [Parameter(Mandatory = $true, ParameterSetName = 'ListVariables')]
     [switch]$ListVariables,
@@ -158,10 +515,53 @@ param(
     })]
     [string]$VariableName,
 
+    # Graph parameter set
+    [Parameter(Mandatory = $true, ParameterSetName = 'Graph')]
+    [switch]$Graph,
+
+    [Parameter(ParameterSetName = 'Graph')]
+    [ValidateSet('Dot', 'Mermaid')]
+    [string]$Format = 'Dot',
+
+    # Completion parameter set
+    [Parameter(Mandatory = $true, ParameterSetName = 'Completion')]
+    [ValidateSet('Bash', 'Zsh', 'Fish')]
+    [string]$Completion,
+
+    # Secret parameter set
+    [Parameter(Mandatory = $true, ParameterSetName = 'Secret')]
+    [ValidateSet('Set', 'Get', 'Delete')]
+    [string]$Secret,
+
+    [Parameter(Mandatory = $true, ParameterSetName = 'Secret')]
+    [ValidatePattern('^[A-Za-z_][A-Za-z0-9_]*$')]
+    [string]$Key,
+
+    # ListLogs parameter set
+    [Parameter(Mandatory = $true, ParameterSetName = 'ListLogs')]
+    [switch]$ListLogs,
+
+    # Audit parameter set
+    [Parameter(Mandatory = $true, ParameterSetName = 'Audit')]
+    [ValidateSet('Tail')]
+    [string]$Audit,
+
+    [Parameter(ParameterSetName = 'Audit')]
+    [ValidateRange(1, 100000)]
+    [int]$Last = 10,
+
+    # Upgrade parameter set
+    [Parameter(Mandatory = $true, ParameterSetName = 'Upgrade')]
+    [ValidateSet('Check', 'Apply')]
+    [string]$Upgrade,
+
     # ValidateTasks parameter set
     [Parameter(Mandatory = $true, ParameterSetName = 'ValidateTasks')]
     [switch]$ValidateTasks,
 
+    [Parameter(ParameterSetName = 'ValidateTasks')]
+    [switch]$Strict,
+
     # TaskExecution parameter set - additional arguments
     [Parameter(ParameterSetName = 'TaskExecution', ValueFromRemainingArguments)]
     [string[]]$Arguments
@@ -179,6 +579,10 @@ elseif ($executionPolicy -eq 'Restricted') {
     Write-Warning "Run: Set-ExecutionPolicy RemoteSigned -Scope CurrentUser"
 }
 
+# Release version of this script. .scripts/release/Build-ModulePackage.ps1 writes the
+# tag version into released copies, so a copy from the repository reports 0.0.0-dev
+$script:BoltVersion = '0.0.0-dev'
+
 # Main script logic
 # Note: Don't override $ErrorActionPreference here - respect the common parameter from CmdletBinding
 # Default ErrorActionPreference for scripts is 'Continue', but we want 'Stop' unless user specifies otherwise
@@ -393,6 +797,132 @@ function Write-SecurityLog {
     }
 }
 
+function New-BoltLogger {
+    <#
+    .SYNOPSIS
+        Creates a structured logger for -LogLevel
+    .DESCRIPTION
+        Returns a logger object with two methods:
+          Enabled(level)                  $true when entries of that level are written
+          Log(level, message, fields)     writes one entry with its fields
+
+        Levels are Debug, Info, Warn, and Error. Text entries look like
+        time=... level=INFO msg="task start" task=build, and Json entries are one
+        JSON object per line with the same keys. Array fields are written as a
+        space-separated string in Text and as an array in Json.
+
+        Bolt keeps the logger in $script:BoltLogger. Tests can pass a
+        System.IO.StringWriter as -Writer to read what was logged.
+    .PARAMETER Writer
+        Where entries are written. Defaults to standard error.
+    #>
+    param(
+        [ValidateSet('Debug', 'Info', 'Warn', 'Error')]
+        [string]$Level = 'Info',
+
+        [ValidateSet('Text', 'Json')]
+        [string]$Format = 'Text',
+
+        [System.IO.TextWriter]$Writer = [Console]::Error
+    )
+
+    $ranks = @{ Debug = 0; Info = 1; Warn = 2; Error = 3 }
+    $logger = [PSCustomObject]@{
+        Level   = $Level
+        Format  = $Format
+        Writer  = $Writer
+        Ranks   = $ranks
+        MinRank = $ranks[$Level]
+    }
+
+    $logger | Add-Member -MemberType ScriptMethod -Name Enabled -Value {
+        param([string]$Level)
+
+        return $this.Ranks[$Level] -ge $this.MinRank
+    }
+
+    $logger | Add-Member -MemberType ScriptMethod -Name Log -Value {
+        param([string]$Level, [string]$Message, [System.Collections.IDictionary]$Fields)
+
+        if (-not $this.Enabled($Level)) {
+            return
+        }
+
+        $entry = [ordered]@{
+            time  = [DateTimeOffset]::Now.ToString('yyyy-MM-ddTHH:mm:ss.fffzzz', [System.Globalization.CultureInfo]::InvariantCulture)
+            level = $Level.ToUpperInvariant()
+            msg   = $Message
+        }
+        if ($Fields) {
+            foreach ($key in $Fields.Keys) {
+                $entry[$key] = $Fields[$key]
+            }
+        }
+
+        if ($this.Format -eq 'Json') {
+            $line = $entry | ConvertTo-Json -Compress -Depth 3
+        } else {
+            $line = @(
+                foreach ($key in $entry.Keys) {
+                    $value = $entry[$key]
+                    $text = if ($value -is [array]) { $value -join ' ' } elseif ($value -is [bool]) { "$value".ToLowerInvariant() } else { [string]$value }
+                    # Quote values with spaces, quotes, or '=' like log/slog does
+                    if ($text -eq '' -or $text -match '[\s"=]') {
+                        $text = '"' + ($text -replace '\\', '\\' -replace '"', '\"' -replace "`n", '\n' -replace "`r", '\r') + '"'
+                    }
+                    "$key=$text"
+                }
+            ) -join ' '
+        }
+
+        $this.Writer.WriteLine($line)
+        $this.Writer.Flush()
+    }
+
+    return $logger
+}
+
+function Write-BoltLog {
+    <#
+    .SYNOPSIS
+        Writes an entry to the structured log when -LogLevel is set
+    .DESCRIPTION
+        Does nothing unless $script:BoltLogger holds a logger from New-BoltLogger.
+        Info entries are task start and finish events, Debug entries describe child
+        processes, Warn entries are retries, and Error entries are failed tasks.
+    .EXAMPLE
+        Write-BoltLog -Level Info -Message 'task start' -Fields ([ordered]@{ task = 'build' })
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [ValidateSet('Debug', 'Info', 'Warn', 'Error')]
+        [string]$Level,
+
+        [Parameter(Mandatory = $true)]
+        [string]$Message,
+
+        [System.Collections.IDictionary]$Fields = [ordered]@{}
+    )
+
+    if ($script:BoltLogger) {
+        $script:BoltLogger.Log($Level, $Message, $Fields)
+    }
+}
+
+function Test-BoltLogLevel {
+    <#
+    .SYNOPSIS
+        Returns $true when entries of a level are logged, so callers can skip building debug fields
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [ValidateSet('Debug', 'Info', 'Warn', 'Error')]
+        [string]$Level
+    )
+
+    return [bool]($script:BoltLogger -and $script:BoltLogger.Enabled($Level))
+}
+
 function Test-CommandOutput {
     <#
     .SYNOPSIS
@@ -1314,6 +1844,31 @@ function Get-ProjectTasks {
             IsCore                 = $false
             UsedFilenameFallback   = $false
             Namespace              = $TaskNamespace
+            Inputs                 = @()
+            Outputs                = @()
+            Produces               = @()
+            Consumes               = @()
+            Before                 = @()
+            After                  = @()
+            Rollback               = @()
+            Timeout                = ''
+            Retry                  = ''
+            Container              = ''
+            Tags                   = @()
+            OutputVars             = @()
+            InputVars              = @()
+            Volumes                = @()
+            EnvPassthrough         = @()
+            SecretEnv              = @()
+            Type                   = ''
+            When                   = ''
+            Platform               = @()
+            Snapshot               = ''
+            WorkDir                = ''
+            GoTest                 = @{ Packages = @(); Race = ''; Cover = ''; CoverProfile = '' }
+            Resources              = @{ CpuQuota = ''; MemoryLimitMb = '' }
+            Env                    = [ordered]@{}
+            Matrix                 = [ordered]@{}
         }
 
         # Extract task names
@@ -1370,8 +1925,137 @@ function Get-ProjectTasks {
         if ($content -match '(?m)^#\s*DEPENDS:(.*)$') {
             $depString = $Matches[1].Trim()
             if (-not [string]::IsNullOrWhiteSpace($depString)) {
-                $metadata.Dependencies = @($depString -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+                # Split on commas outside brackets so matrix instances like build[a=1,b=2] stay whole
+                $metadata.Dependencies = @($depString -split ',(?![^\[]*\])' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+            }
+        }
+
+        # Extract timeout (e.g., 30s, 5m, 1h30m)
+        if ($content -match '(?m)^#\s*TIMEOUT:[ \t]*([^\r\n]*)') {
+            $metadata.Timeout = $Matches[1].Trim()
+        }
+
+        # Extract retry settings (e.g., 3 or attempts=3, delay=2s, backoff=2)
+        if ($content -match '(?m)^#\s*RETRY:[ \t]*([^\r\n]*)') {
+            $metadata.Retry = $Matches[1].Trim()
+        }
+
+        # Extract container settings (image, host:container volumes, variable names to pass in)
+        if ($content -match '(?m)^#\s*CONTAINER:[ \t]*([^\r\n]*)') {
+            $metadata.Container = $Matches[1].Trim()
+        }
+        if ($content -match '(?m)^#\s*VOLUMES:(.*)$') {
+            $metadata.Volumes = @($Matches[1] -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+        }
+        if ($content -match '(?m)^#\s*ENV_PASSTHROUGH:(.*)$') {
+            $metadata.EnvPassthrough = @($Matches[1] -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+        }
+
+        # Extract the secrets to read from the OS keychain when the task starts
+        if ($content -match '(?m)^#\s*SECRET_ENV:(.*)$') {
+            $metadata.SecretEnv = @($Matches[1] -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+        }
+
+        # Extract the task type (# TYPE: go-test or plugin/<name>) and its go test settings
+        if ($content -match '(?m)^#\s*TYPE:[ \t]*([^\r\n]*)') {
+            $metadata.Type = $Matches[1].Trim()
+        }
+        if ($content -match '(?m)^#\s*PACKAGES:(.*)$') {
+            $metadata.GoTest.Packages = @($Matches[1] -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+        }
+        foreach ($goTestSetting in @('Race', 'Cover', 'CoverProfile')) {
+            if ($content -match "(?m)^#\s*$($goTestSetting.ToUpper()):[ \t]*([^\r\n]*)") {
+                $metadata.GoTest[$goTestSetting] = $Matches[1].Trim()
+            }
+        }
+
+        # Extract the run condition (e.g., env.CI == 'true' && exists('go.sum'))
+        if ($content -match '(?m)^#\s*WHEN:[ \t]*([^\r\n]*)') {
+            $metadata.When = $Matches[1].Trim()
+        }
+
+        # Extract the platforms the task runs on (GOOS or GOOS/GOARCH, e.g., linux/amd64, darwin)
+        if ($content -match '(?m)^#\s*PLATFORM:(.*)$') {
+            $metadata.Platform = @($Matches[1] -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+        }
+
+        # Extract the CPU and memory limits (e.g., 0.5 of a core and 512 MB)
+        foreach ($resourceSetting in @(@('CpuQuota', 'CPU_QUOTA'), @('MemoryLimitMb', 'MEMORY_LIMIT_MB'))) {
+            if ($content -match "(?m)^#\s*$($resourceSetting[1]):[ \t]*([^\r\n]*)") {
+                $metadata.Resources[$resourceSetting[0]] = $Matches[1].Trim()
+            }
+        }
+
+        # Extract the directory the task runs in
+        if ($content -match '(?m)^#\s*WORKDIR:[ \t]*([^\r\n]*)') {
+            $metadata.WorkDir = $Matches[1].Trim()
+        }
+
+        # Extract the golden file the task's standard output is compared with
+        if ($content -match '(?m)^#\s*SNAPSHOT:[ \t]*([^\r\n]*)') {
+            $metadata.Snapshot = $Matches[1].Trim()
+        }
+
+        # Extract lifecycle hooks (one per line, task names or inline PowerShell commands)
+        $metadata.Before = @([regex]::Matches($content, '(?m)^#\s*BEFORE:[ \t]*(\S[^\r\n]*)$') | ForEach-Object { $_.Groups[1].Value.Trim() })
+        $metadata.After = @([regex]::Matches($content, '(?m)^#\s*AFTER:[ \t]*(\S[^\r\n]*)$') | ForEach-Object { $_.Groups[1].Value.Trim() })
+
+        # Extract rollback commands (one per line, inline PowerShell commands run when the task fails)
+        $metadata.Rollback = @([regex]::Matches($content, '(?m)^#\s*ROLLBACK:[ \t]*(\S[^\r\n]*)$') | ForEach-Object { $_.Groups[1].Value.Trim() })
+
+        # Extract environment variables (one NAME=value per line)
+        foreach ($envMatch in [regex]::Matches($content, '(?m)^#\s*ENV:[ \t]*([^\r\n]*)$')) {
+            $envLine = $envMatch.Groups[1].Value.Trim()
+            if ($envLine -notmatch '^([A-Za-z_][A-Za-z0-9_]*)=(.*)$') {
+                Write-Warning "Invalid ENV entry '$envLine' in $FilePath (expected NAME=value)"
+                continue
+            }
+            $metadata.Env[$Matches[1]] = $Matches[2]
+        }
+
+        # Extract matrix values (one NAME=value1, value2 per line)
+        foreach ($matrixMatch in [regex]::Matches($content, '(?m)^#\s*MATRIX:[ \t]*([^\r\n]*)$')) {
+            $matrixLine = $matrixMatch.Groups[1].Value.Trim()
+            if ($matrixLine -notmatch '^([A-Za-z_][A-Za-z0-9_]*)=(.+)$') {
+                Write-Warning "Invalid MATRIX entry '$matrixLine' in $FilePath (expected NAME=value1, value2)"
+                continue
             }
+            $matrixName = $Matches[1]
+            $matrixValues = @($Matches[2] -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+            if ($matrixValues | Where-Object { $_ -match '[\[\]=]' }) {
+                Write-Warning "Invalid MATRIX values for '$matrixName' in $FilePath (values cannot contain '[', ']' or '=')"
+                continue
+            }
+            $metadata.Matrix[$matrixName] = $matrixValues
+        }
+
+        # Extract the variables the task sets with ::set-output and the ones it reads from earlier tasks
+        if ($content -match '(?m)^#\s*OUTPUT_VARS:(.*)$') {
+            $metadata.OutputVars = @($Matches[1] -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+        }
+        if ($content -match '(?m)^#\s*INPUT_VARS:(.*)$') {
+            $metadata.InputVars = @($Matches[1] -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+        }
+
+        # Extract the labels used by -Tag to select tasks
+        if ($content -match '(?m)^#\s*TAGS:(.*)$') {
+            $metadata.Tags = @($Matches[1] -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+        }
+
+        # Extract cache inputs and outputs (file globs relative to the project root)
+        if ($content -match '(?m)^#\s*INPUTS:(.*)$') {
+            $metadata.Inputs = @($Matches[1] -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+        }
+        if ($content -match '(?m)^#\s*OUTPUTS:(.*)$') {
+            $metadata.Outputs = @($Matches[1] -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+        }
+
+        # Extract artifacts (file paths relative to the project root shared between tasks)
+        if ($content -match '(?m)^#\s*PRODUCES:(.*)$') {
+            $metadata.Produces = @($Matches[1] -split ',' | ForEach-Object { ($_.Trim() -replace '\\', '/') -replace '^\./', '' } | Where-Object { $_ })
+        }
+        if ($content -match '(?m)^#\s*CONSUMES:(.*)$') {
+            $metadata.Consumes = @($Matches[1] -split ',' | ForEach-Object { ($_.Trim() -replace '\\', '/') -replace '^\./', '' } | Where-Object { $_ })
         }
 
         return $metadata
@@ -1382,6 +2066,9 @@ function Get-ProjectTasks {
     foreach ($file in $buildFiles) {
         $metadata = Get-TaskMetadata -FilePath $file.FullName -TaskNamespace $Namespace
         foreach ($name in $metadata.Names) {
+            if ($tasks.ContainsKey($name) -and $tasks[$name].ScriptPath -ne $file.FullName) {
+                throw "Duplicate task name '$name': defined in both '$($tasks[$name].ScriptPath)' and '$($file.FullName)'"
+            }
             $tasks[$name] = $metadata
         }
     }
@@ -1440,7 +2127,12 @@ function Get-ProjectTasksFromMultipleDirectories {
                 
                 # Update the task metadata to include the prefixed name
                 $taskMetadata.Names = @($prefixedTaskName)
-                
+
+                # A root task can already use the prefixed name (e.g., .build/Invoke-Golang-Build.ps1)
+                if ($allProjectTasks.ContainsKey($prefixedTaskName)) {
+                    throw "Duplicate task name '$prefixedTaskName': defined in both '$($allProjectTasks[$prefixedTaskName].ScriptPath)' and '$($taskMetadata.ScriptPath)'"
+                }
+
                 # Add to all tasks with prefixed name
                 $allProjectTasks[$prefixedTaskName] = $taskMetadata
             }
@@ -1453,723 +2145,9345 @@ function Get-ProjectTasksFromMultipleDirectories {
     return $allProjectTasks
 }
 
-function Get-AllTasks {
+function Get-RemoteIncludeNamespace {
     <#
     .SYNOPSIS
-        Returns all available tasks (core + project-specific)
+        Returns the task namespace for a remote include URL
     .DESCRIPTION
-        When using the default '.build' directory, automatically discovers tasks from
-        all namespaced directories (.build, .build-bicep, .build-golang, etc.).
-        When using a custom -TaskDirectory, only loads tasks from that specific directory.
+        Joins the host name and the directories of the URL path with hyphens, in
+        lowercase, with any other character replaced by a hyphen. The port and the
+        file name are left out, so https://tasks.example.com/ci/Invoke-Lint.ps1 is
+        in the tasks-example-com-ci namespace.
     #>
     param(
-        [string]$TaskDirectory,
-        [string]$ScriptRoot = $PSScriptRoot
+        [Parameter(Mandatory = $true)]
+        [uri]$Url
     )
 
-    $allTasks = @{}
+    $directories = @([uri]::UnescapeDataString($Url.AbsolutePath).Split('/', [StringSplitOptions]::RemoveEmptyEntries) | Select-Object -SkipLast 1)
+    $namespace = ((@($Url.Host) + $directories) -join '-').ToLowerInvariant() -replace '[^a-z0-9]+', '-'
+    return $namespace.Trim('-')
+}
 
-    # Get core tasks
-    $coreTasks = Get-CoreTasks
-    foreach ($key in $coreTasks.Keys) {
-        $allTasks[$key] = $coreTasks[$key]
-    }
+function Get-RemoteIncludeFile {
+    <#
+    .SYNOPSIS
+        Downloads a remote include into .bolt/remote/ and verifies its SHA-256
+    .DESCRIPTION
+        The file is saved as <CacheRoot>/<namespace>/<file name>. A cached copy whose
+        digest matches -Sha256 is used without a download unless -Refresh is set.
+        A download with a different digest is not saved and throws a
+        RemoteIncludeHashMismatch error with the expected and actual digests.
+    .OUTPUTS
+        [string] The path of the verified file
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Url,
 
-    # Determine if we're using the default .build directory or a custom directory
-    $isDefaultDirectory = ($TaskDirectory -eq '.build')
+        [Parameter(Mandatory = $true)]
+        [AllowEmptyString()]
+        [string]$Sha256,
 
-    if ($isDefaultDirectory) {
-        # Use multi-directory discovery for default .build
-        # This automatically scans .build, .build-bicep, .build-golang, etc.
-        $projectTasks = Get-ProjectTasksFromMultipleDirectories -ScriptRoot $ScriptRoot
-    }
-    else {
-        # Custom directory specified - use single directory discovery
-        # SECURITY: Runtime path validation (P1 - Runtime Path Validation)
-        # This is defense-in-depth: parameter validation should catch most issues,
-        # but we validate again at runtime to ensure resolved paths stay within project
+        [Parameter(Mandatory = $true)]
+        [string]$CacheRoot,
 
-        # Resolve the full path
-        if ([System.IO.Path]::IsPathRooted($TaskDirectory)) {
-            $buildPath = $TaskDirectory
-        } else {
-            $buildPath = Join-Path -Path $ScriptRoot -ChildPath $TaskDirectory
-        }
+        [switch]$Refresh
+    )
 
-        # Get the resolved absolute paths for comparison
-        $resolvedPath = [System.IO.Path]::GetFullPath($buildPath)
-        $projectRoot = [System.IO.Path]::GetFullPath($ScriptRoot)
+    $fail = {
+        param([string]$Message, [string]$ErrorId = 'InvalidRemoteInclude')
 
-        # Ensure the resolved path is within project directory
-        if (-not $resolvedPath.StartsWith($projectRoot, [StringComparison]::OrdinalIgnoreCase)) {
-            Write-Warning "TaskDirectory resolves outside project directory: $TaskDirectory"
-            Write-Warning "Project root: $projectRoot"
-            Write-Warning "Resolved path: $resolvedPath"
-            throw "TaskDirectory must resolve to a path within the project directory"
-        }
+        $exception = [System.InvalidOperationException]::new($Message)
+        throw [System.Management.Automation.ErrorRecord]::new($exception, $ErrorId, [System.Management.Automation.ErrorCategory]::InvalidData, $Url)
+    }
 
-        $projectTasks = Get-ProjectTasks -BuildPath $resolvedPath -Namespace $null
+    $uri = $null
+    if (-not [uri]::TryCreate($Url, [UriKind]::Absolute, [ref]$uri) -or $uri.Scheme -notin @('http', 'https')) {
+        & $fail "Remote include '$Url' must be an http:// or https:// URL"
     }
+    # The file runs as a task, so it is only fetched without encryption from this machine
+    if ($uri.Scheme -eq 'http' -and $uri.Host -notin @('localhost', '127.0.0.1', '::1')) {
+        & $fail "Remote include '$Url' must use https"
+    }
+    $fileName = [uri]::UnescapeDataString($uri.Segments[-1])
+    if ($fileName -notmatch '^Invoke-[A-Za-z0-9\-]+\.ps1$') {
+        & $fail "Remote include '$Url' must name an Invoke-*.ps1 task file"
+    }
+    if ($Sha256 -notmatch '^[0-9a-fA-F]{64}$') {
+        & $fail "Remote include '$Url' needs a Sha256 of 64 hex digits"
+    }
+    $expected = $Sha256.ToLowerInvariant()
 
-    # Project tasks override core tasks if there's a naming conflict
-    foreach ($key in $projectTasks.Keys) {
-        if ($allTasks.ContainsKey($key)) {
-            Write-Warning "Project task '$key' is overriding core task"
+    $directory = Join-Path -Path $CacheRoot -ChildPath (Get-RemoteIncludeNamespace -Url $uri)
+    $path = Join-Path -Path $directory -ChildPath $fileName
+
+    # A cached copy is only used while it still matches, so changing Sha256 downloads the new version
+    if (-not $Refresh -and (Test-Path -LiteralPath $path -PathType Leaf)) {
+        if ((Get-FileHash -LiteralPath $path -Algorithm SHA256).Hash.ToLowerInvariant() -eq $expected) {
+            Write-Verbose "Using cached remote include: $path"
+            return $path
         }
-        $allTasks[$key] = $projectTasks[$key]
     }
 
-    return $allTasks
+    New-Item -ItemType Directory -Path $directory -Force | Out-Null
+    $downloadPath = "$path.download"
+    try {
+        Write-Verbose "Downloading remote include: $Url"
+        Invoke-WebRequest -Uri $uri -OutFile $downloadPath -TimeoutSec 30 -ErrorAction Stop
+    }
+    catch {
+        Remove-Item -LiteralPath $downloadPath -Force -ErrorAction SilentlyContinue
+        & $fail "Failed to download remote include '$Url': $($_.Exception.Message)" 'RemoteIncludeUnavailable'
+    }
+
+    $actual = (Get-FileHash -LiteralPath $downloadPath -Algorithm SHA256).Hash.ToLowerInvariant()
+    if ($actual -ne $expected) {
+        Remove-Item -LiteralPath $downloadPath -Force -ErrorAction SilentlyContinue
+        & $fail "Remote include '$Url' failed SHA-256 verification: expected $expected, got $actual" 'RemoteIncludeHashMismatch'
+    }
+
+    Move-Item -LiteralPath $downloadPath -Destination $path -Force
+    return $path
 }
 
-function Show-TaskOutline {
+function Get-RemoteIncludeTasks {
     <#
     .SYNOPSIS
-        Displays the task dependency tree without executing tasks
+        Loads the tasks listed in the RemoteIncludes section of bolt.config.json
+    .DESCRIPTION
+        Each entry has a Url and a Sha256. Every file is fetched with
+        Get-RemoteIncludeFile and its tasks are prefixed with the namespace from
+        Get-RemoteIncludeNamespace, like the tasks in .build/<namespace>/.
+        With -KeepNames the tasks keep the names from their files, with no
+        namespace (for New-HttpTaskStore).
+    .RETURNS
+        Hashtable of tasks by name
     #>
     param(
-        [string[]]$TaskNames,
-        [hashtable]$AllTasks,
-        [bool]$SkipDependencies = $false
-    )
+        [Parameter(Mandatory = $true)]
+        [string]$ScriptRoot,
 
-    function Resolve-DependencyWithNamespace {
-        <#
-        .SYNOPSIS
-            Resolves a dependency name to an actual task, respecting namespace priority
-        #>
-        param(
-            [string]$DependencyName,
-            [string]$CurrentNamespace,
-            [hashtable]$Tasks
-        )
+        [object[]]$Includes = @(),
 
-        # If current task has a namespace, first try namespace-prefixed dependency
-        if ($CurrentNamespace) {
-            $namespacedDep = "$CurrentNamespace-$DependencyName"
-            if ($Tasks.ContainsKey($namespacedDep)) {
-                return $namespacedDep
-            }
-        }
+        [switch]$Refresh,
 
-        # Fall back to non-namespaced dependency
-        if ($Tasks.ContainsKey($DependencyName)) {
-            return $DependencyName
-        }
+        [switch]$KeepNames
+    )
 
-        # Not found
-        return $null
-    }
+    $remoteTasks = @{}
+    $cacheRoot = Join-Path -Path $ScriptRoot -ChildPath '.bolt/remote'
 
-    function Get-ExecutionOrder {
-        param(
-            [string]$TaskName,
-            [hashtable]$Tasks,
-            [hashtable]$Visited = @{},
-            [System.Collections.ArrayList]$Order
-        )
+    foreach ($include in $Includes) {
+        if ($include -isnot [System.Collections.IDictionary] -or -not $include['Url']) {
+            throw "Each RemoteIncludes entry in bolt.config.json needs a Url and a Sha256"
+        }
+        $url = [string]$include['Url']
+        $path = Get-RemoteIncludeFile -Url $url -Sha256 ([string]$include['Sha256']) -CacheRoot $cacheRoot -Refresh:$Refresh
+        $namespace = if ($KeepNames) { $null } else { Get-RemoteIncludeNamespace -Url $url }
+
+        # Other includes can share the namespace directory, so only this file's tasks are kept
+        $tasks = Get-ProjectTasks -BuildPath (Split-Path -Path $path -Parent) -Namespace $namespace
+        foreach ($taskName in $tasks.Keys) {
+            $taskMetadata = $tasks[$taskName]
+            if ([System.IO.Path]::GetFullPath($taskMetadata.ScriptPath) -ne [System.IO.Path]::GetFullPath($path)) {
+                continue
+            }
 
-        if ($Visited.ContainsKey($TaskName)) {
-            return
+            $prefixedTaskName = if ($KeepNames) { $taskName } else { "$namespace-$taskName" }
+            $taskMetadata.Names = @($prefixedTaskName)
+            if ($remoteTasks.ContainsKey($prefixedTaskName)) {
+                throw "Duplicate task name '$prefixedTaskName': defined in both '$($remoteTasks[$prefixedTaskName].ScriptPath)' and '$path'"
+            }
+            $remoteTasks[$prefixedTaskName] = $taskMetadata
         }
+    }
 
-        $Visited[$TaskName] = $true
+    return $remoteTasks
+}
 
-        if ($Tasks.ContainsKey($TaskName)) {
-            $taskInfo = $Tasks[$TaskName]
+function New-FileTaskStore {
+    <#
+    .SYNOPSIS
+        Creates the task store that reads project tasks from Invoke-*.ps1 files
+    .DESCRIPTION
+        Returns a store object with the contract every task store follows:
+          Type      file, http, or memory
+          Load()    returns a hashtable of task name to task metadata, as
+                    Get-ProjectTasks does
+          Watch()   returns a subscription whose Receive(timeoutMs) waits for the
+                    task definitions to change and returns the new Load() result,
+                    or $null when the timeout passes first. Dispose() ends it.
+
+        With the default .build task directory, Load scans .build and its namespace
+        subdirectories with Get-ProjectTasksFromMultipleDirectories. Any other
+        -TaskDirectory is scanned on its own and must resolve inside -ScriptRoot.
+        Watch uses a FileSystemWatcher on the *.ps1 files in the task directory.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$ScriptRoot,
 
-            # Process dependencies first with namespace awareness
-            foreach ($dep in $taskInfo.Dependencies) {
-                $resolvedDep = Resolve-DependencyWithNamespace -DependencyName $dep -CurrentNamespace $taskInfo.Namespace -Tasks $Tasks
-                if ($resolvedDep) {
-                    Get-ExecutionOrder -TaskName $resolvedDep -Tasks $Tasks -Visited $Visited -Order $Order
-                }
-            }
+        [string]$TaskDirectory = '.build'
+    )
 
-            # Add current task
-            [void]$Order.Add($TaskName)
-        }
+    $store = [PSCustomObject]@{
+        Type          = 'file'
+        ScriptRoot    = $ScriptRoot
+        TaskDirectory = $TaskDirectory
     }
 
-    function Show-DependencyTree {
-        param(
-            [string]$TaskName,
-            [hashtable]$Tasks,
-            [int]$Indent = 0,
-            [bool]$IsLast = $true,
-            [string]$Prefix = ""
-        )
+    $store | Add-Member -MemberType ScriptMethod -Name Load -Value {
+        if ($this.TaskDirectory -eq '.build') {
+            # Use multi-directory discovery for default .build
+            # This automatically scans .build and its namespace subdirectories
+            return Get-ProjectTasksFromMultipleDirectories -ScriptRoot $this.ScriptRoot
+        }
 
-        $taskInfo = $Tasks[$TaskName]
-        $primaryName = $taskInfo.Names[0]
+        # Custom directory specified - use single directory discovery
+        # SECURITY: Runtime path validation (P1 - Runtime Path Validation)
+        # This is defense-in-depth: parameter validation should catch most issues,
+        # but we validate again at runtime to ensure resolved paths stay within project
+        $resolvedPath = $this.GetBuildPath()
+        $projectRoot = [System.IO.Path]::GetFullPath($this.ScriptRoot)
 
-        # Determine tree characters
-        if ($Indent -eq 0) {
-            $branch = ""
-            $connector = ""
-        } else {
-            $branch = if ($IsLast) { "└── " } else { "├── " }
-            $connector = if ($IsLast) { "    " } else { "│   " }
+        # Ensure the resolved path is within project directory
+        if (-not $resolvedPath.StartsWith($projectRoot, [StringComparison]::OrdinalIgnoreCase)) {
+            Write-Warning "TaskDirectory resolves outside project directory: $($this.TaskDirectory)"
+            Write-Warning "Project root: $projectRoot"
+            Write-Warning "Resolved path: $resolvedPath"
+            throw "TaskDirectory must resolve to a path within the project directory"
         }
 
-        # Display task name with description
-        $taskDisplay = $primaryName
-        if (-not [string]::IsNullOrWhiteSpace($taskInfo.Description)) {
-            Write-Host "$Prefix$branch" -NoNewline -ForegroundColor Gray
-            Write-Host $taskDisplay -NoNewline -ForegroundColor Cyan
-            Write-Host " ($($taskInfo.Description))" -ForegroundColor Gray
-        } else {
-            Write-Host "$Prefix$branch" -NoNewline -ForegroundColor Gray
-            Write-Host $taskDisplay -ForegroundColor Cyan
+        return Get-ProjectTasks -BuildPath $resolvedPath -Namespace $null
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name GetBuildPath -Value {
+        if ([System.IO.Path]::IsPathRooted($this.TaskDirectory)) {
+            return [System.IO.Path]::GetFullPath($this.TaskDirectory)
         }
+        return [System.IO.Path]::GetFullPath((Join-Path -Path $this.ScriptRoot -ChildPath $this.TaskDirectory))
+    }
 
-        # Show dependencies recursively
-        if ($taskInfo.Dependencies.Count -gt 0 -and -not $SkipDependencies) {
-            $depCount = $taskInfo.Dependencies.Count
-            for ($i = 0; $i -lt $depCount; $i++) {
-                $dep = $taskInfo.Dependencies[$i]
-                $isLastDep = ($i -eq $depCount - 1)
+    $store | Add-Member -MemberType ScriptMethod -Name Watch -Value {
+        $buildPath = $this.GetBuildPath()
+        if (-not (Test-Path -LiteralPath $buildPath -PathType Container)) {
+            throw "Task directory '$buildPath' does not exist"
+        }
 
-                # Resolve dependency with namespace awareness
-                $resolvedDep = Resolve-DependencyWithNamespace -DependencyName $dep -CurrentNamespace $taskInfo.Namespace -Tasks $Tasks
+        # Changes are queued as PowerShell events from the moment Watch returns
+        $watcher = [System.IO.FileSystemWatcher]::new($buildPath, '*.ps1')
+        $watcher.IncludeSubdirectories = $true
+        $watcher.NotifyFilter = [System.IO.NotifyFilters]'FileName, DirectoryName, LastWrite, Size'
+        $sourceIdentifiers = @(
+            foreach ($eventName in @('Created', 'Changed', 'Deleted', 'Renamed')) {
+                $sourceIdentifier = "Bolt.TaskStore.$eventName.$([guid]::NewGuid().ToString('N'))"
+                Register-ObjectEvent -InputObject $watcher -EventName $eventName -SourceIdentifier $sourceIdentifier | Out-Null
+                $sourceIdentifier
+            }
+        )
+        $watcher.EnableRaisingEvents = $true
 
-                if ($resolvedDep) {
-                    Show-DependencyTree -TaskName $resolvedDep -Tasks $Tasks -Indent ($Indent + 1) -IsLast $isLastDep -Prefix "$Prefix$connector"
-                } else {
-                    # Missing dependency
-                    $depBranch = if ($isLastDep) { "└── " } else { "├── " }
-                    Write-Host "$Prefix$connector$depBranch" -NoNewline -ForegroundColor Gray
-                    Write-Host $dep -NoNewline -ForegroundColor Red
-                    Write-Host " (NOT FOUND)" -ForegroundColor Red
+        $subscription = [PSCustomObject]@{
+            Store             = $this
+            Watcher           = $watcher
+            SourceIdentifiers = $sourceIdentifiers
+        }
+        $subscription | Add-Member -MemberType ScriptMethod -Name Receive -Value {
+            param([int]$TimeoutMs)
+
+            $stopwatch = [System.Diagnostics.Stopwatch]::StartNew()
+            while ($true) {
+                $changes = @(Get-Event | Where-Object { $this.SourceIdentifiers -contains $_.SourceIdentifier })
+                if ($changes.Count -gt 0) {
+                    $changes | Remove-Event
+                    return $this.Store.Load()
+                }
+                if ($stopwatch.ElapsedMilliseconds -ge $TimeoutMs) {
+                    return $null
                 }
+                Start-Sleep -Milliseconds 25
             }
-        } elseif ($SkipDependencies -and $taskInfo.Dependencies.Count -gt 0) {
-            Write-Host "$Prefix$connector" -NoNewline -ForegroundColor Gray
-            Write-Host "(Dependencies skipped: $($taskInfo.Dependencies -join ', '))" -ForegroundColor Yellow
         }
+        $subscription | Add-Member -MemberType ScriptMethod -Name Dispose -Value {
+            $this.Watcher.EnableRaisingEvents = $false
+            foreach ($sourceIdentifier in $this.SourceIdentifiers) {
+                Unregister-Event -SourceIdentifier $sourceIdentifier -ErrorAction SilentlyContinue
+                Get-Event -SourceIdentifier $sourceIdentifier -ErrorAction SilentlyContinue | Remove-Event -ErrorAction SilentlyContinue
+            }
+            $this.Watcher.Dispose()
+        }
+
+        return $subscription
     }
 
-    # Header
-    Write-Host ""
-    Write-Host "Task execution plan for: " -NoNewline -ForegroundColor Cyan
-    Write-Host ($TaskNames -join ', ') -ForegroundColor White
+    return $store
+}
 
-    if ($SkipDependencies) {
-        Write-Host "(Dependencies will be skipped with -Only flag)" -ForegroundColor Yellow
+function New-HttpTaskStore {
+    <#
+    .SYNOPSIS
+        Creates a task store that reads its task list from a URL
+    .DESCRIPTION
+        The URL returns a JSON array of { "Url": ..., "Sha256": ... } entries, like
+        RemoteIncludes in bolt.config.json. Relative entry URLs are resolved against
+        the list's URL. Load fetches the list and every file with
+        Get-RemoteIncludeFile, so each one is verified against its SHA-256 and cached
+        in .bolt/remote/. The tasks keep the names from their files. With -Refresh
+        the files are downloaded again, as -RefreshRemotes does for RemoteIncludes.
+
+        Watch fetches the list again every -PollIntervalMs and reloads when its text
+        changes. Receive only fetches once the interval has passed, so a short
+        timeout returns at once between polls. The list decides which files run, so it
+        must use https unless it is on this machine. Follows the contract described in
+        New-FileTaskStore.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Url,
+
+        [Parameter(Mandatory = $true)]
+        [string]$ScriptRoot,
+
+        [int]$PollIntervalMs = 30000,
+
+        [switch]$Refresh
+    )
+
+    $uri = $null
+    if (-not [uri]::TryCreate($Url, [UriKind]::Absolute, [ref]$uri) -or $uri.Scheme -notin @('http', 'https')) {
+        throw "TaskStoreURL must start with http:// or https://"
+    }
+    if ($uri.Scheme -eq 'http' -and $uri.Host -notin @('localhost', '127.0.0.1', '::1')) {
+        throw "TaskStoreURL must use https"
     }
 
-    Write-Host ""
+    $store = [PSCustomObject]@{
+        Type           = 'http'
+        Url            = $uri
+        ScriptRoot     = $ScriptRoot
+        PollIntervalMs = $PollIntervalMs
+        Refresh        = [bool]$Refresh
+    }
 
-    # Show dependency tree for each task
-    foreach ($taskName in $TaskNames) {
-        if ($AllTasks.ContainsKey($taskName)) {
-            Show-DependencyTree -TaskName $taskName -Tasks $AllTasks
-            Write-Host ""
-        } else {
-            Write-Host "$taskName " -NoNewline -ForegroundColor Red
-            Write-Host "(NOT FOUND)" -ForegroundColor Red
-            Write-Host ""
+    $store | Add-Member -MemberType ScriptMethod -Name Fetch -Value {
+        try {
+            $content = (Invoke-WebRequest -Uri $this.Url -TimeoutSec 30 -ErrorAction Stop).Content
+        } catch {
+            $exception = [System.InvalidOperationException]::new("Failed to fetch the task list from '$($this.Url)': $($_.Exception.Message)")
+            throw [System.Management.Automation.ErrorRecord]::new($exception, 'TaskStoreUnavailable', [System.Management.Automation.ErrorCategory]::ConnectionError, $this.Url)
         }
+
+        # Responses that are not served as text come back as bytes
+        if ($content -is [byte[]]) {
+            return [System.Text.Encoding]::UTF8.GetString($content)
+        }
+        return [string]$content
     }
 
-    # Calculate and show execution order
-    $executionOrder = New-Object System.Collections.ArrayList
-    $visited = @{}
+    $store | Add-Member -MemberType ScriptMethod -Name LoadFrom -Value {
+        param([string]$Content)
 
-    foreach ($taskName in $TaskNames) {
-        if ($AllTasks.ContainsKey($taskName)) {
-            if ($SkipDependencies) {
-                [void]$executionOrder.Add($taskName)
-            } else {
-                Get-ExecutionOrder -TaskName $taskName -Tasks $AllTasks -Visited $visited -Order $executionOrder
-            }
+        try {
+            $entries = @(ConvertFrom-Json -InputObject $Content -AsHashtable -ErrorAction Stop)
+        } catch {
+            throw "The task list from '$($this.Url)' is not valid JSON: $($_.Exception.Message)"
         }
+
+        $includes = @(
+            foreach ($entry in $entries) {
+                if ($entry -isnot [System.Collections.IDictionary] -or -not $entry['Url']) {
+                    throw "Each entry in the task list from '$($this.Url)' needs a Url and a Sha256"
+                }
+                @{ Url = [uri]::new($this.Url, [string]$entry['Url']).AbsoluteUri; Sha256 = [string]$entry['Sha256'] }
+            }
+        )
+
+        return Get-RemoteIncludeTasks -ScriptRoot $this.ScriptRoot -Includes $includes -KeepNames -Refresh:$this.Refresh
     }
 
-    if ($executionOrder.Count -gt 0) {
-        Write-Host "Execution order:" -ForegroundColor Cyan
-        for ($i = 0; $i -lt $executionOrder.Count; $i++) {
-            Write-Host "  $($i + 1). $($executionOrder[$i])" -ForegroundColor White
+    $store | Add-Member -MemberType ScriptMethod -Name Load -Value {
+        return $this.LoadFrom($this.Fetch())
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name Watch -Value {
+        $subscription = [PSCustomObject]@{
+            Store    = $this
+            Content  = $this.Fetch()
+            NextPoll = [DateTime]::UtcNow.AddMilliseconds($this.PollIntervalMs)
         }
-        Write-Host ""
+        $subscription | Add-Member -MemberType ScriptMethod -Name Receive -Value {
+            param([int]$TimeoutMs)
+
+            $deadline = [DateTime]::UtcNow.AddMilliseconds($TimeoutMs)
+            while ($true) {
+                if ([DateTime]::UtcNow -ge $this.NextPoll) {
+                    $this.NextPoll = [DateTime]::UtcNow.AddMilliseconds($this.Store.PollIntervalMs)
+                    try {
+                        $content = $this.Store.Fetch()
+                        if ($content -cne $this.Content) {
+                            $this.Content = $content
+                            return $this.Store.LoadFrom($content)
+                        }
+                    } catch {
+                        Write-Verbose "$($_.Exception.Message); keeping the current tasks"
+                    }
+                }
+
+                $remainingMs = [int][Math]::Max(0, ($deadline - [DateTime]::UtcNow).TotalMilliseconds)
+                if ($remainingMs -eq 0) {
+                    return $null
+                }
+                $untilPollMs = [int][Math]::Max(1, ($this.NextPoll - [DateTime]::UtcNow).TotalMilliseconds)
+                Start-Sleep -Milliseconds ([Math]::Min($untilPollMs, $remainingMs))
+            }
+        }
+        $subscription | Add-Member -MemberType ScriptMethod -Name Dispose -Value { }
+
+        return $subscription
     }
+
+    return $store
 }
 
-function Test-TaskMetadata {
+function New-InMemoryTaskStore {
     <#
     .SYNOPSIS
-        Validates task file metadata and structure
+        Creates a task store that holds task metadata in memory
     .DESCRIPTION
-        Checks task files for required metadata (TASK, DESCRIPTION) and proper exit codes.
-        Returns a hashtable with validation results.
+        Load returns a copy of the hashtable of task name to task metadata given
+        with -Tasks. Set(tasks) replaces the tasks and wakes every Watch
+        subscription. Used to test discovery and scheduling without task files.
+        Follows the contract described in New-FileTaskStore.
     #>
-    [CmdletBinding()]
     param(
-        [Parameter(Mandatory)]
-        [string]$FilePath
+        [hashtable]$Tasks = @{}
     )
 
-    $result = @{
-        FilePath = $FilePath
-        FileName = Split-Path -Path $FilePath -Leaf
-        TaskName = $null
-        HasTaskMetadata = $false
-        HasDescription = $false
-        DescriptionValue = $null
-        HasExitCode = $false
-        HasDependsMetadata = $false
-        DependenciesValue = $null
-        TaskNameValid = $true
-        Issues = @()
-        Status = 'Pass'
+    $store = [PSCustomObject]@{
+        Type    = 'memory'
+        Tasks   = $Tasks.Clone()
+        Version = 0
     }
 
-    try {
-        # Read first 30 lines for metadata
-        $lines = Get-Content -Path $FilePath -First 30 -ErrorAction Stop
-        $content = $lines -join "`n"
+    $store | Add-Member -MemberType ScriptMethod -Name Load -Value {
+        return $this.Tasks.Clone()
+    }
 
-        # Read entire file to check for exit codes
-        $fullContent = Get-Content -Path $FilePath -Raw -ErrorAction Stop
+    $store | Add-Member -MemberType ScriptMethod -Name Set -Value {
+        param([hashtable]$Tasks)
 
-        # Check for TASK metadata
-        if ($content -match '(?m)^#\s*TASK:\s*(.+)$') {
-            $result.HasTaskMetadata = $true
-            $taskNames = $Matches[1].Trim()
-            $result.TaskName = $taskNames -split ',' | ForEach-Object { $_.Trim() } | Select-Object -First 1
+        $this.Tasks = $Tasks.Clone()
+        $this.Version++
+    }
 
-            # Validate task name format
-            $firstTaskName = $result.TaskName
-            if ($firstTaskName -cnotmatch '^[a-z0-9][a-z0-9\-]*$') {
-                $result.TaskNameValid = $false
-                $result.Issues += "Invalid task name format: '$firstTaskName' (only lowercase letters, numbers, and hyphens allowed)"
-                $result.Status = 'Fail'
-            }
-            if ($firstTaskName.Length -gt 50) {
-                $result.TaskNameValid = $false
-                $result.Issues += "Task name too long: '$firstTaskName' (max 50 characters)"
-                $result.Status = 'Fail'
-            }
+    $store | Add-Member -MemberType ScriptMethod -Name Watch -Value {
+        $subscription = [PSCustomObject]@{
+            Store   = $this
+            Version = $this.Version
         }
-        else {
-            # Check if using filename fallback
-            $parts = (Get-Item -Path $FilePath).BaseName -split '-'
-            if ($parts.Count -gt 1) {
-                $result.TaskName = ($parts[1..($parts.Count-1)] -join '-').ToLower()
-            } else {
-                $result.TaskName = $parts[0].ToLower()
-            }
-            $result.Issues += "Missing TASK metadata (using filename fallback: '$($result.TaskName)')"
-            if ($result.Status -eq 'Pass') {
-                $result.Status = 'Warning'
+        $subscription | Add-Member -MemberType ScriptMethod -Name Receive -Value {
+            param([int]$TimeoutMs)
+
+            $stopwatch = [System.Diagnostics.Stopwatch]::StartNew()
+            while ($this.Store.Version -eq $this.Version) {
+                if ($stopwatch.ElapsedMilliseconds -ge $TimeoutMs) {
+                    return $null
+                }
+                Start-Sleep -Milliseconds 10
             }
+            $this.Version = $this.Store.Version
+            return $this.Store.Load()
         }
+        $subscription | Add-Member -MemberType ScriptMethod -Name Dispose -Value { }
 
-        # Check for DESCRIPTION metadata
-        if ($content -match '(?m)^#\s*DESCRIPTION:[ \t]*([^\r\n]*)') {
-            $result.HasDescription = $true
-            $result.DescriptionValue = $Matches[1].Trim()
-            
-            # Check if description is placeholder or empty
-            if ([string]::IsNullOrWhiteSpace($result.DescriptionValue) -or 
-                $result.DescriptionValue -match 'TODO|Add description') {
-                $result.Issues += "Description is placeholder or empty"
-                if ($result.Status -eq 'Pass') {
-                    $result.Status = 'Warning'
-                }
-            }
-        }
-        else {
-            $result.Issues += "Missing DESCRIPTION metadata"
-            if ($result.Status -eq 'Pass') {
-                $result.Status = 'Warning'
-            }
-        }
+        return $subscription
+    }
 
-        # Check for DEPENDS metadata (presence check, not required to have dependencies)
-        if ($content -match '(?m)^#\s*DEPENDS:(.*)$') {
-            $result.HasDependsMetadata = $true
-            $depString = $Matches[1].Trim()
-            if (-not [string]::IsNullOrWhiteSpace($depString)) {
-                $result.DependenciesValue = $depString
-            }
-        }
-        else {
-            $result.Issues += "Missing DEPENDS metadata"
-            if ($result.Status -eq 'Pass') {
-                $result.Status = 'Warning'
-            }
-        }
+    return $store
+}
 
-        # Check for exit code
-        if ($fullContent -match '(?m)^\s*exit\s+[01]\s*$') {
-            $result.HasExitCode = $true
-        }
-        else {
-            $result.Issues += "Missing explicit exit code (exit 0 or exit 1)"
-            if ($result.Status -eq 'Pass') {
-                $result.Status = 'Warning'
-            }
-        }
+function Get-TaskStore {
+    <#
+    .SYNOPSIS
+        Returns the store that project tasks are loaded from
+    .DESCRIPTION
+        Uses New-HttpTaskStore when TaskStoreURL is set in bolt.config.json, and
+        New-FileTaskStore for the task directory otherwise. -RefreshRemotes makes the
+        HTTP store download its task files again.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$ScriptRoot,
 
-    }
-    catch {
-        $result.Status = 'Error'
-        $result.Issues += "Error reading file: $_"
+        [string]$TaskDirectory = '.build',
+
+        [switch]$RefreshRemotes
+    )
+
+    $config = Get-BoltConfigFile -ScriptRoot $ScriptRoot -TaskDirectory $TaskDirectory
+    if ($config['TaskStoreURL']) {
+        # SECURITY: Log where task definitions come from when it is not the project (P0 - Security Event Logging)
+        Write-SecurityLog -Event "TaskStoreUsage" -Details "TaskStoreURL: $($config['TaskStoreURL'])" -Severity "Info"
+        return New-HttpTaskStore -Url ([string]$config['TaskStoreURL']) -ScriptRoot $ScriptRoot -Refresh:$RefreshRemotes
     }
 
-    return $result
+    return New-FileTaskStore -ScriptRoot $ScriptRoot -TaskDirectory $TaskDirectory
 }
 
-function Show-ValidationReport {
+function Get-AllTasks {
     <#
     .SYNOPSIS
-        Displays validation results in a formatted report
+        Returns all available tasks (core + project-specific)
     .DESCRIPTION
-        Shows validation results for all task files in a table format with color-coding
+        Project tasks come from -Store, a task store like New-FileTaskStore. Without
+        -Store, Get-TaskStore picks one for -ScriptRoot and -TaskDirectory: with the
+        default '.build' directory, tasks are discovered from .build and its namespace
+        subdirectories, and a custom -TaskDirectory only loads that directory.
+        Tasks listed in RemoteIncludes in bolt.config.json are added in every case.
     #>
-    [CmdletBinding()]
     param(
-        [Parameter(Mandatory)]
-        [hashtable]$AllTasks,
-        
-        [Parameter(Mandatory)]
-        [string]$TaskDirectory
+        [string]$TaskDirectory,
+        [string]$ScriptRoot = $PSScriptRoot,
+        [switch]$RefreshRemotes,
+        [PSCustomObject]$Store = $null
     )
 
-    Write-Host ""
-    Write-Host "Task Validation Report" -ForegroundColor Cyan
-    Write-Host ("=" * 80) -ForegroundColor Gray
-    Write-Host ""
+    $allTasks = @{}
 
-    # Get all unique task files (excluding core tasks)
-    $taskFiles = @{}
-    foreach ($taskKey in $AllTasks.Keys) {
-        $taskInfo = $AllTasks[$taskKey]
-        if (-not $taskInfo.IsCore -and $taskInfo.ScriptPath) {
-            $taskFiles[$taskInfo.ScriptPath] = $taskInfo
+    # Get core tasks
+    $coreTasks = Get-CoreTasks
+    foreach ($key in $coreTasks.Keys) {
+        $allTasks[$key] = $coreTasks[$key]
+    }
+
+    if ($null -eq $Store) {
+        $Store = Get-TaskStore -ScriptRoot $ScriptRoot -TaskDirectory $TaskDirectory -RefreshRemotes:$RefreshRemotes
+    }
+    $projectTasks = $Store.Load()
+
+    # Project tasks override core tasks if there's a naming conflict
+    foreach ($key in $projectTasks.Keys) {
+        if ($allTasks.ContainsKey($key)) {
+            Write-Warning "Project task '$key' is overriding core task"
         }
+        $allTasks[$key] = $projectTasks[$key]
     }
 
-    if ($taskFiles.Count -eq 0) {
-        Write-Host "No project tasks found in '$TaskDirectory' directory." -ForegroundColor Yellow
-        Write-Host ""
-        return
+    # Tasks from RemoteIncludes, cached in .bolt/remote/
+    $config = Get-BoltConfigFile -ScriptRoot $ScriptRoot -TaskDirectory $TaskDirectory
+    if ($config['RemoteIncludes']) {
+        $remoteTasks = Get-RemoteIncludeTasks -ScriptRoot $ScriptRoot -Includes @($config['RemoteIncludes']) -Refresh:$RefreshRemotes
+        foreach ($key in $remoteTasks.Keys) {
+            if ($allTasks.ContainsKey($key)) {
+                if (-not $allTasks[$key].IsCore) {
+                    throw "Duplicate task name '$key': defined in both '$($allTasks[$key].ScriptPath)' and '$($remoteTasks[$key].ScriptPath)'"
+                }
+                Write-Warning "Remote task '$key' is overriding core task"
+            }
+            $allTasks[$key] = $remoteTasks[$key]
+        }
     }
 
-    # Validate each task file
-    $validationResults = @()
-    foreach ($filePath in $taskFiles.Keys) {
-        $validationResults += Test-TaskMetadata -FilePath $filePath
+    Expand-TaskMatrix -Tasks $allTasks
+    Add-ArtifactDependencies -Tasks $allTasks
+
+    return $allTasks
+}
+
+function Add-ArtifactDependencies {
+    <#
+    .SYNOPSIS
+        Makes every task that consumes an artifact depend on the task that produces it
+    .DESCRIPTION
+        Matches the paths in # CONSUMES: against the paths in # PRODUCES: and adds the
+        producer to the consumer's dependencies. Each consumer gets an ArtifactProducers
+        table of path to producer names, so Get-TaskExecutionOrder and -ValidateTasks
+        can report artifacts with no producer or with more than one.
+
+        A producer that the consumer cannot name, because a task in the consumer's
+        namespace has the same name, is not added. The problem is kept in the
+        consumer's ArtifactConflicts table (path to message) for the same checks, so
+        one bad task file does not stop task discovery.
+
+        Matrix tasks are skipped in favor of their instances.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$Tasks
+    )
+
+    # Each task is stored once per name, so collect the unique task objects first
+    $uniqueTasks = @{}
+    foreach ($taskInfo in $Tasks.Values) {
+        if (-not $taskInfo.IsCore -and -not $taskInfo.MatrixInstances) {
+            $uniqueTasks[$taskInfo.Names[0]] = $taskInfo
+        }
     }
 
-    # Display results in table format
-    $passCount = 0
-    $warningCount = 0
-    $failCount = 0
+    $producers = @{}
+    foreach ($taskName in ($uniqueTasks.Keys | Sort-Object)) {
+        foreach ($artifact in $uniqueTasks[$taskName].Produces) {
+            $producers[$artifact] = @($producers[$artifact] | Where-Object { $_ }) + $taskName
+        }
+    }
 
-    foreach ($result in $validationResults | Sort-Object FileName) {
-        # Status indicator with color
-        $statusDisplay = switch ($result.Status) {
-            'Pass' { 
-                $passCount++
-                "✓ PASS"
+    foreach ($taskName in $uniqueTasks.Keys) {
+        $taskInfo = $uniqueTasks[$taskName]
+        $taskInfo.ArtifactProducers = [ordered]@{}
+        $taskInfo.ArtifactConflicts = [ordered]@{}
+
+        foreach ($artifact in $taskInfo.Consumes) {
+            $artifactProducers = @($producers[$artifact] | Where-Object { $_ })
+            $taskInfo.ArtifactProducers[$artifact] = $artifactProducers
+            if ($artifactProducers.Count -ne 1 -or $artifactProducers[0] -eq $taskName) {
+                continue
             }
-            'Warning' { 
-                $warningCount++
-                "⚠ WARN"
+
+            # Write the dependency so Resolve-TaskDependency finds exactly this producer
+            $producer = $Tasks[$artifactProducers[0]]
+            $dependencyName = if ($producer.Namespace) {
+                "$($producer.Namespace):$($artifactProducers[0].Substring($producer.Namespace.Length + 1))"
+            } else {
+                $artifactProducers[0]
             }
-            'Fail' { 
-                $failCount++
-                "✗ FAIL"
+
+            $resolved = Resolve-TaskDependency -DependencyName $dependencyName -CurrentNamespace $taskInfo.Namespace -Tasks $Tasks
+            if ($resolved -ne $artifactProducers[0]) {
+                $taskInfo.ArtifactConflicts[$artifact] = "'$artifact' is produced by '$($artifactProducers[0])', but '$resolved' in namespace '$($taskInfo.Namespace)' has the same name"
+                continue
             }
-            'Error' { 
-                $failCount++
-                "✗ ERROR"
+
+            $alreadyDepends = $taskInfo.Dependencies | Where-Object {
+                (Resolve-TaskDependency -DependencyName $_ -CurrentNamespace $taskInfo.Namespace -Tasks $Tasks) -eq $resolved
+            }
+            if (-not $alreadyDepends) {
+                $taskInfo.Dependencies = @($taskInfo.Dependencies) + $dependencyName
             }
         }
+    }
+}
 
-        $statusColor = switch ($result.Status) {
-            'Pass' { 'Green' }
-            'Warning' { 'Yellow' }
-            'Fail' { 'Red' }
-            'Error' { 'Red' }
-        }
+function Expand-TaskMatrix {
+    <#
+    .SYNOPSIS
+        Adds one task instance per combination of a task's MATRIX values
+    .DESCRIPTION
+        For a task with '# MATRIX: GO_VERSION=1.21, 1.22' and '# MATRIX: OS=linux, windows',
+        adds four instances named like 'test[GO_VERSION=1.21,OS=linux]'. Each instance
+        is a copy of the task with the matrix values added to its Env, so they are set
+        as environment variables when the instance runs.
+
+        The matrix task itself keeps its name and gets a MatrixInstances list. Running it
+        runs all of its instances; other tasks can only depend on a single instance.
+    .PARAMETER Tasks
+        Hashtable of all tasks, updated in place
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$Tasks
+    )
 
-        # Task info line
-        Write-Host "File: " -NoNewline -ForegroundColor Gray
-        Write-Host $result.FileName -NoNewline -ForegroundColor White
-        Write-Host " | Task: " -NoNewline -ForegroundColor Gray
-        Write-Host $result.TaskName -NoNewline -ForegroundColor White
-        Write-Host " | " -NoNewline -ForegroundColor Gray
-        Write-Host $statusDisplay -ForegroundColor $statusColor
+    $matrixTasks = @($Tasks.Values | Where-Object { -not $_.IsCore -and $_.Matrix.Count -gt 0 } | Sort-Object -Property { $_.Names[0] } -Unique)
+    foreach ($taskInfo in $matrixTasks) {
+        $primaryName = $taskInfo.Names[0]
 
-        # Metadata checks
-        Write-Host "  TASK: " -NoNewline -ForegroundColor Gray
-        Write-Host $(if ($result.HasTaskMetadata) { "✓" } else { "✗" }) -ForegroundColor $(if ($result.HasTaskMetadata) { "Green" } else { "Red" })
-        
-        Write-Host "  DESCRIPTION: " -NoNewline -ForegroundColor Gray
-        Write-Host $(if ($result.HasDescription) { "✓" } else { "✗" }) -NoNewline -ForegroundColor $(if ($result.HasDescription) { "Green" } else { "Red" })
-        if ($result.HasDescription -and $result.DescriptionValue) {
-            Write-Host " ($($result.DescriptionValue.Substring(0, [Math]::Min(50, $result.DescriptionValue.Length)))...)" -ForegroundColor DarkGray
-        } else {
-            Write-Host ""
+        # Cartesian product of the matrix values, in declaration order
+        $combinations = @([ordered]@{})
+        foreach ($entry in $taskInfo.Matrix.GetEnumerator()) {
+            $combinations = @(foreach ($combination in $combinations) {
+                foreach ($value in $entry.Value) {
+                    $next = [ordered]@{}
+                    foreach ($key in $combination.Keys) { $next[$key] = $combination[$key] }
+                    $next[$entry.Key] = $value
+                    $next
+                }
+            })
         }
 
-        Write-Host "  DEPENDS: " -NoNewline -ForegroundColor Gray
-        Write-Host $(if ($result.HasDependsMetadata) { "✓" } else { "✗" }) -NoNewline -ForegroundColor $(if ($result.HasDependsMetadata) { "Green" } else { "Red" })
-        if ($result.DependenciesValue) {
-            Write-Host " ($($result.DependenciesValue))" -ForegroundColor DarkGray
-        } else {
-            Write-Host ""
+        $instanceNames = foreach ($combination in $combinations) {
+            $instanceName = "$primaryName[$(($combination.GetEnumerator() | ForEach-Object { "$($_.Key)=$($_.Value)" }) -join ',')]"
+
+            $instance = $taskInfo.Clone()
+            $instance.Names = @($instanceName)
+            $instance.Matrix = [ordered]@{}
+            $instance.MatrixParent = $primaryName
+            $instance.MatrixValues = $combination
+            $instance.Env = [ordered]@{}
+            foreach ($key in $taskInfo.Env.Keys) { $instance.Env[$key] = $taskInfo.Env[$key] }
+            foreach ($key in $combination.Keys) { $instance.Env[$key] = $combination[$key] }
+
+            $Tasks[$instanceName] = $instance
+            $instanceName
         }
 
-        Write-Host "  Exit Code: " -NoNewline -ForegroundColor Gray
-        Write-Host $(if ($result.HasExitCode) { "✓" } else { "✗" }) -ForegroundColor $(if ($result.HasExitCode) { "Green" } else { "Red" })
+        $taskInfo.MatrixInstances = @($instanceNames)
+    }
+}
 
-        # Show issues if any
-        if ($result.Issues.Count -gt 0) {
-            foreach ($issue in $result.Issues) {
-                Write-Host "  Issue: " -NoNewline -ForegroundColor Yellow
-                Write-Host $issue -ForegroundColor Gray
-            }
+function Resolve-TaskDependency {
+    <#
+    .SYNOPSIS
+        Resolves a dependency name to an actual task, respecting namespace priority
+    .DESCRIPTION
+        When the dependent task belongs to a namespace, the namespace-prefixed task
+        (e.g., golang-format) is preferred over a root-level task with the same name.
+        A 'namespace:task' name (e.g., golang:format) always refers to the task in that
+        namespace, so any task can depend on a task in another namespace.
+        Returns the resolved task name, or $null if the dependency does not exist.
+    .PARAMETER DependencyName
+        The dependency name as written in the DEPENDS metadata
+    .PARAMETER CurrentNamespace
+        The namespace of the task that declares the dependency (may be empty)
+    .PARAMETER Tasks
+        Hashtable of all available tasks
+    #>
+    param(
+        [string]$DependencyName,
+        [string]$CurrentNamespace,
+        [hashtable]$Tasks
+    )
+
+    # Fully-qualified reference to a task in a specific namespace
+    if ($DependencyName -cmatch '^([a-z0-9][a-z0-9\-]*):([a-z0-9][a-z0-9\-]*(\[[^\]]*\])?)$') {
+        $qualifiedDep = "$($Matches[1])-$($Matches[2])"
+        if ($Tasks.ContainsKey($qualifiedDep) -and $Tasks[$qualifiedDep].Namespace -eq $Matches[1]) {
+            return $qualifiedDep
         }
+        return $null
+    }
 
-        Write-Host ""
+    # If current task has a namespace, first try namespace-prefixed dependency
+    if ($CurrentNamespace) {
+        $namespacedDep = "$CurrentNamespace-$DependencyName"
+        if ($Tasks.ContainsKey($namespacedDep)) {
+            return $namespacedDep
+        }
     }
 
-    # Summary
-    Write-Host ("=" * 80) -ForegroundColor Gray
-    Write-Host "Summary: " -NoNewline -ForegroundColor Cyan
-    Write-Host "$($validationResults.Count) task file(s) validated" -ForegroundColor White
-    Write-Host "  " -NoNewline
-    Write-Host "✓ Pass: $passCount" -NoNewline -ForegroundColor Green
-    Write-Host "  " -NoNewline
-    Write-Host "⚠ Warnings: $warningCount" -NoNewline -ForegroundColor Yellow
-    Write-Host "  " -NoNewline
-    Write-Host "✗ Failures: $failCount" -ForegroundColor Red
-    Write-Host ""
+    # Fall back to non-namespaced dependency
+    if ($Tasks.ContainsKey($DependencyName)) {
+        return $DependencyName
+    }
 
-    # Return exit code based on failures
-    if ($failCount -gt 0) {
-        return 1
+    # Not found
+    return $null
+}
+
+function Get-TaggedTask {
+    <#
+    .SYNOPSIS
+        Returns the tasks that have any of the given # TAGS:
+    .DESCRIPTION
+        Tags match case-sensitively. Each task is returned once by its primary name,
+        sorted by name, so the list can be run as if the names were given. A matrix
+        task is returned instead of its instances.
+    .PARAMETER Tag
+        The tags to look for; a task matches when it has at least one of them
+    .OUTPUTS
+        [string[]] Primary task names
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string[]]$Tag,
+
+        [Parameter(Mandatory = $true)]
+        [hashtable]$AllTasks
+    )
+
+    $tagged = @{}
+    foreach ($taskInfo in $AllTasks.Values) {
+        if ($taskInfo.MatrixParent -or -not $taskInfo.Tags) {
+            continue
+        }
+        if ($taskInfo.Tags | Where-Object { $_ -cin $Tag }) {
+            $tagged[$taskInfo.Names[0]] = $true
+        }
     }
-    return 0
+
+    return @($tagged.Keys | Sort-Object)
 }
 
-function Invoke-Task {
+function Get-TaskExecutionOrder {
     <#
     .SYNOPSIS
-        Executes a task with dependency resolution
+        Builds the task dependency graph and returns the topological execution order
+    .DESCRIPTION
+        Walks the dependency graph depth-first, starting from the requested tasks, and
+        returns task names in the order they must run (dependencies before dependents).
+        Shared dependencies appear only once. Dependencies that cannot be resolved are
+        left out; callers report them as missing.
+
+        Throws an error with the ErrorId 'CyclicDependency' when the graph contains a
+        cycle. The error message and TargetObject contain the cycle path, for example:
+        Circular dependency detected: deploy -> build -> deploy
+
+        A requested matrix task is replaced by all of its instances. Depending on a
+        matrix task instead of one of its instances throws a 'MatrixDependency' error.
+        Consuming an artifact that has no producer, more than one, or a producer
+        hidden by a task of the same name (see Add-ArtifactDependencies) throws an
+        'ArtifactProducer' error.
+    .PARAMETER TaskNames
+        The tasks requested by the user
+    .PARAMETER AllTasks
+        Hashtable of all available tasks
+    .PARAMETER SkipDependencies
+        When true, only the requested tasks are returned (the -Only flag)
+    .OUTPUTS
+        Array of primary task names in execution order
+    .EXAMPLE
+        $order = Get-TaskExecutionOrder -TaskNames @('build') -AllTasks $availableTasks
+        Returns @('format', 'lint', 'build') for the default project tasks
     #>
+    [CmdletBinding()]
     param(
-        [hashtable]$TaskInfo,
+        [Parameter(Mandatory)]
+        [string[]]$TaskNames,
+
+        [Parameter(Mandatory)]
         [hashtable]$AllTasks,
-        [array]$Arguments,
-        [hashtable]$ExecutedTasks = @{},
+
         [bool]$SkipDependencies = $false
     )
 
-    $primaryName = $TaskInfo.Names[0]
+    $order = [System.Collections.Generic.List[string]]::new()
 
-    # Check if already executed (prevent circular dependencies)
-    if ($ExecutedTasks.ContainsKey($primaryName)) {
-        return $true
-    }
+    # Visit state per task: 'Visiting' while its dependencies are walked, 'Done' after
+    $state = @{}
 
-    # Mark as executed BEFORE processing dependencies to prevent circular loops
+    # Current walk path, used to report the cycle when one is found
+    $path = [System.Collections.Generic.List[string]]::new()
+
+    function Invoke-Visit {
+        param([string]$Name)
+
+        $taskInfo = $AllTasks[$Name]
+        $primaryName = $taskInfo.Names[0]
+
+        if ($state[$primaryName] -eq 'Done') {
+            return
+        }
+
+        if ($state[$primaryName] -eq 'Visiting') {
+            $cycleStart = $path.IndexOf($primaryName)
+            $cycle = @($path.GetRange($cycleStart, $path.Count - $cycleStart)) + $primaryName
+            $cyclePath = $cycle -join ' -> '
+            $exception = [System.InvalidOperationException]::new("Circular dependency detected: $cyclePath")
+            throw [ErrorRecord]::new($exception, 'CyclicDependency', [ErrorCategory]::InvalidOperation, $cycle)
+        }
+
+        $state[$primaryName] = 'Visiting'
+        $path.Add($primaryName)
+
+        # Every consumed artifact needs exactly one producer
+        if ($taskInfo.ArtifactProducers) {
+            foreach ($artifact in $taskInfo.ArtifactProducers.Keys) {
+                $artifactProducers = @($taskInfo.ArtifactProducers[$artifact])
+                if ($artifactProducers.Count -ne 1) {
+                    $problem = if ($artifactProducers.Count -eq 0) { 'no task produces it' } else { "it is produced by more than one task: $($artifactProducers -join ', ')" }
+                    $exception = [System.InvalidOperationException]::new("Task '$primaryName' consumes '$artifact', but $problem")
+                    throw [ErrorRecord]::new($exception, 'ArtifactProducer', [ErrorCategory]::InvalidOperation, $artifact)
+                }
+            }
+        }
+        if ($taskInfo.ArtifactConflicts) {
+            foreach ($artifact in $taskInfo.ArtifactConflicts.Keys) {
+                $exception = [System.InvalidOperationException]::new("Task '$primaryName' consumes $($taskInfo.ArtifactConflicts[$artifact])")
+                throw [ErrorRecord]::new($exception, 'ArtifactProducer', [ErrorCategory]::InvalidOperation, $artifact)
+            }
+        }
+
+        foreach ($dep in $taskInfo.Dependencies) {
+            $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $taskInfo.Namespace -Tasks $AllTasks
+            if ($resolvedDep -and $AllTasks[$resolvedDep].MatrixInstances) {
+                $exception = [System.InvalidOperationException]::new("Task '$primaryName' depends on matrix task '$resolvedDep'. Depend on one of its instances instead: $($AllTasks[$resolvedDep].MatrixInstances -join ', ')")
+                throw [ErrorRecord]::new($exception, 'MatrixDependency', [ErrorCategory]::InvalidOperation, $resolvedDep)
+            }
+            if ($resolvedDep) {
+                Invoke-Visit -Name $resolvedDep
+            }
+        }
+
+        $path.RemoveAt($path.Count - 1)
+        $state[$primaryName] = 'Done'
+        $order.Add($primaryName)
+    }
+
+    foreach ($requestedName in $TaskNames) {
+        if (-not $AllTasks.ContainsKey($requestedName)) {
+            continue
+        }
+
+        # A matrix task runs as all of its instances
+        $instanceNames = if ($AllTasks[$requestedName].MatrixInstances) { $AllTasks[$requestedName].MatrixInstances } else { @($requestedName) }
+
+        foreach ($taskName in $instanceNames) {
+            if ($SkipDependencies) {
+                $primaryName = $AllTasks[$taskName].Names[0]
+                if (-not $order.Contains($primaryName)) {
+                    $order.Add($primaryName)
+                }
+            }
+            else {
+                Invoke-Visit -Name $taskName
+            }
+        }
+    }
+
+    return , $order.ToArray()
+}
+
+function Show-TaskOutline {
+    <#
+    .SYNOPSIS
+        Displays the task dependency tree without executing tasks
+    #>
+    param(
+        [string[]]$TaskNames,
+        [hashtable]$AllTasks,
+        [bool]$SkipDependencies = $false
+    )
+
+    function Show-DependencyTree {
+        param(
+            [string]$TaskName,
+            [hashtable]$Tasks,
+            [int]$Indent = 0,
+            [bool]$IsLast = $true,
+            [string]$Prefix = "",
+            [string[]]$Ancestors = @()
+        )
+
+        $taskInfo = $Tasks[$TaskName]
+        $primaryName = $taskInfo.Names[0]
+
+        # Determine tree characters
+        if ($Indent -eq 0) {
+            $branch = ""
+            $connector = ""
+        } else {
+            $branch = if ($IsLast) { "└── " } else { "├── " }
+            $connector = if ($IsLast) { "    " } else { "│   " }
+        }
+
+        # Display task name with description
+        $taskDisplay = $primaryName
+        if (-not [string]::IsNullOrWhiteSpace($taskInfo.Description)) {
+            Write-Host "$Prefix$branch" -NoNewline -ForegroundColor Gray
+            Write-Host $taskDisplay -NoNewline -ForegroundColor Cyan
+            Write-Host " ($($taskInfo.Description))" -ForegroundColor Gray
+        } else {
+            Write-Host "$Prefix$branch" -NoNewline -ForegroundColor Gray
+            Write-Host $taskDisplay -ForegroundColor Cyan
+        }
+
+        # Show dependencies recursively
+        if ($taskInfo.Dependencies.Count -gt 0 -and -not $SkipDependencies) {
+            $depCount = $taskInfo.Dependencies.Count
+            for ($i = 0; $i -lt $depCount; $i++) {
+                $dep = $taskInfo.Dependencies[$i]
+                $isLastDep = ($i -eq $depCount - 1)
+
+                # Resolve dependency with namespace awareness
+                $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $taskInfo.Namespace -Tasks $Tasks
+
+                if ($resolvedDep -and ($Ancestors + $primaryName) -contains $Tasks[$resolvedDep].Names[0]) {
+                    # Circular dependency - stop descending to avoid infinite recursion
+                    $depBranch = if ($isLastDep) { "└── " } else { "├── " }
+                    Write-Host "$Prefix$connector$depBranch" -NoNewline -ForegroundColor Gray
+                    Write-Host $resolvedDep -NoNewline -ForegroundColor Red
+                    Write-Host " (CIRCULAR)" -ForegroundColor Red
+                } elseif ($resolvedDep) {
+                    Show-DependencyTree -TaskName $resolvedDep -Tasks $Tasks -Indent ($Indent + 1) -IsLast $isLastDep -Prefix "$Prefix$connector" -Ancestors ($Ancestors + $primaryName)
+                } else {
+                    # Missing dependency
+                    $depBranch = if ($isLastDep) { "└── " } else { "├── " }
+                    Write-Host "$Prefix$connector$depBranch" -NoNewline -ForegroundColor Gray
+                    Write-Host $dep -NoNewline -ForegroundColor Red
+                    Write-Host " (NOT FOUND)" -ForegroundColor Red
+                }
+            }
+        } elseif ($SkipDependencies -and $taskInfo.Dependencies.Count -gt 0) {
+            Write-Host "$Prefix$connector" -NoNewline -ForegroundColor Gray
+            Write-Host "(Dependencies skipped: $($taskInfo.Dependencies -join ', '))" -ForegroundColor Yellow
+        }
+    }
+
+    # Header
+    Write-Host ""
+    Write-Host "Task execution plan for: " -NoNewline -ForegroundColor Cyan
+    Write-Host ($TaskNames -join ', ') -ForegroundColor White
+
+    if ($SkipDependencies) {
+        Write-Host "(Dependencies will be skipped with -Only flag)" -ForegroundColor Yellow
+    }
+
+    Write-Host ""
+
+    # Show dependency tree for each task
+    foreach ($taskName in $TaskNames) {
+        if ($AllTasks.ContainsKey($taskName)) {
+            Show-DependencyTree -TaskName $taskName -Tasks $AllTasks
+            Write-Host ""
+        } else {
+            Write-Host "$taskName " -NoNewline -ForegroundColor Red
+            Write-Host "(NOT FOUND)" -ForegroundColor Red
+            Write-Host ""
+        }
+    }
+
+    # Calculate and show execution order
+    try {
+        $executionOrder = Get-TaskExecutionOrder -TaskNames $TaskNames -AllTasks $AllTasks -SkipDependencies $SkipDependencies
+    }
+    catch {
+        if ($_.FullyQualifiedErrorId -notin @('CyclicDependency', 'MatrixDependency', 'ArtifactProducer')) {
+            throw
+        }
+        Write-Host $_.Exception.Message -ForegroundColor Red
+        Write-Host ""
+        return 1
+    }
+
+    if ($executionOrder.Count -gt 0) {
+        Write-Host "Execution order:" -ForegroundColor Cyan
+        for ($i = 0; $i -lt $executionOrder.Count; $i++) {
+            Write-Host "  $($i + 1). $($executionOrder[$i])" -ForegroundColor White
+        }
+        Write-Host ""
+    }
+
+    return 0
+}
+
+function Get-TaskGraph {
+    <#
+    .SYNOPSIS
+        Returns the task dependency graph as nodes and edges
+    .DESCRIPTION
+        Each task is one node, named by its primary name. Edges point from a
+        dependency to the task that depends on it. A matrix task gets an edge from
+        each of its instances, because running it runs them. Tasks without
+        dependencies or dependents are kept as isolated nodes.
+
+        With -TaskNames only those tasks and everything they depend on are returned.
+    .PARAMETER AllTasks
+        Hashtable of all available tasks
+    .PARAMETER TaskNames
+        Optional tasks to start from
+    .OUTPUTS
+        PSCustomObject with Nodes (Name, Description) and Edges (From, To), sorted by name
+    #>
+    param(
+        [Parameter(Mandatory)]
+        [hashtable]$AllTasks,
+
+        [string[]]$TaskNames = @()
+    )
+
+    # Dependencies of every task, by primary name
+    $dependenciesOf = @{}
+    $descriptions = @{}
+    foreach ($taskInfo in $AllTasks.Values) {
+        $primaryName = $taskInfo.Names[0]
+        if ($dependenciesOf.ContainsKey($primaryName)) {
+            continue
+        }
+        $descriptions[$primaryName] = $taskInfo.Description
+        $dependenciesOf[$primaryName] = @(
+            if ($taskInfo.MatrixInstances) {
+                $taskInfo.MatrixInstances
+            } else {
+                foreach ($dep in $taskInfo.Dependencies) {
+                    $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $taskInfo.Namespace -Tasks $AllTasks
+                    if ($resolvedDep) {
+                        $AllTasks[$resolvedDep].Names[0]
+                    }
+                }
+            }
+        ) | Select-Object -Unique
+    }
+
+    $included = [System.Collections.Generic.HashSet[string]]::new()
+    if ($TaskNames.Count -eq 0) {
+        foreach ($name in $dependenciesOf.Keys) { [void]$included.Add($name) }
+    } else {
+        # Walk the dependencies of the requested tasks
+        $pending = [System.Collections.Generic.Queue[string]]::new()
+        foreach ($name in $TaskNames) {
+            $pending.Enqueue($AllTasks[$name].Names[0])
+        }
+        while ($pending.Count -gt 0) {
+            $name = $pending.Dequeue()
+            if ($included.Add($name)) {
+                foreach ($dep in $dependenciesOf[$name]) { $pending.Enqueue($dep) }
+            }
+        }
+    }
+
+    $nodes = @(
+        foreach ($name in ($included | Sort-Object)) {
+            [PSCustomObject]@{ Name = $name; Description = $descriptions[$name] }
+        }
+    )
+    $edges = @(
+        foreach ($name in ($included | Sort-Object)) {
+            foreach ($dep in ($dependenciesOf[$name] | Sort-Object)) {
+                [PSCustomObject]@{ From = $dep; To = $name }
+            }
+        }
+    )
+
+    return [PSCustomObject]@{
+        Nodes = $nodes
+        Edges = $edges
+    }
+}
+
+function Format-TaskGraph {
+    <#
+    .SYNOPSIS
+        Writes a task graph from Get-TaskGraph as Graphviz DOT or a Mermaid diagram
+    .DESCRIPTION
+        Nodes are labelled with the task name and description. Mermaid node ids are
+        generated (n1, n2, ...) because task names can contain characters Mermaid
+        does not allow in ids, like the brackets of matrix instances.
+    .OUTPUTS
+        [string]
+    #>
+    param(
+        [Parameter(Mandatory)]
+        $Graph,
+
+        [ValidateSet('Dot', 'Mermaid')]
+        [string]$Format = 'Dot'
+    )
+
+    $lines = [System.Collections.Generic.List[string]]::new()
+
+    if ($Format -eq 'Dot') {
+        $escape = { param([string]$Text) $Text -replace '\\', '\\' -replace '"', '\"' }
+
+        $lines.Add('digraph bolt {')
+        $lines.Add('    node [shape=box];')
+        foreach ($node in $Graph.Nodes) {
+            # \n in a DOT label is a line break
+            $label = & $escape $node.Name
+            if ($node.Description) {
+                $label += '\n' + (& $escape $node.Description)
+            }
+            $lines.Add("    `"$(& $escape $node.Name)`" [label=`"$label`"];")
+        }
+        foreach ($edge in $Graph.Edges) {
+            $lines.Add("    `"$(& $escape $edge.From)`" -> `"$(& $escape $edge.To)`";")
+        }
+        $lines.Add('}')
+    } else {
+        $ids = @{}
+        $index = 0
+        foreach ($node in $Graph.Nodes) {
+            $index++
+            $ids[$node.Name] = "n$index"
+        }
+        $escape = { param([string]$Text) $Text -replace '&', '#amp;' -replace '"', '#quot;' -replace '<', '#lt;' -replace '>', '#gt;' }
+
+        $lines.Add('graph TD')
+        foreach ($node in $Graph.Nodes) {
+            $label = & $escape $node.Name
+            if ($node.Description) {
+                $label += "<br/>$(& $escape $node.Description)"
+            }
+            $lines.Add("    $($ids[$node.Name])[`"$label`"]")
+        }
+        foreach ($edge in $Graph.Edges) {
+            $lines.Add("    $($ids[$edge.From]) --> $($ids[$edge.To])")
+        }
+    }
+
+    return ($lines -join "`n")
+}
+
+function Get-TaskPlan {
+    <#
+    .SYNOPSIS
+        Describes what a run would do without running any task
+    .DESCRIPTION
+        For each task in the execution order, returns its parallel group, resolved
+        dependencies, script command line, container image, declared environment variables, hooks
+        and rollback commands with ${NAME} placeholders replaced, and whether the cache would skip it.
+        SkipReason is platform for tasks whose # PLATFORM: does not include this
+        machine, condition for tasks whose # WHEN: is false, and git for tasks that
+        -Since skips.
+        Group 1 holds tasks with no dependencies in the run, and every other task
+        is in the group after its last dependency, so the tasks of one group can run
+        at the same time with -Parallel.
+    .OUTPUTS
+        Ordered hashtable with Tasks, Groups, and Errors
+    #>
+    param(
+        [string[]]$ExecutionOrder,
+        [hashtable]$AllTasks,
+        [array]$Arguments
+    )
+
+    $taskPlans = [System.Collections.Generic.List[object]]::new()
+    $errors = [System.Collections.Generic.List[string]]::new()
+    $groupOf = @{}
+
+    foreach ($taskName in $ExecutionOrder) {
+        $taskInfo = $AllTasks[$taskName]
+
+        # Only dependencies that are part of this run count (-Only drops the rest)
+        $dependencies = @(
+            foreach ($dep in $taskInfo.Dependencies) {
+                $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $taskInfo.Namespace -Tasks $AllTasks
+                if ($resolvedDep -and $ExecutionOrder -contains $AllTasks[$resolvedDep].Names[0]) {
+                    $AllTasks[$resolvedDep].Names[0]
+                }
+            }
+        )
+        $group = 1
+        foreach ($dep in $dependencies) {
+            $group = [Math]::Max($group, $groupOf[$dep] + 1)
+        }
+        $groupOf[$taskName] = $group
+
+        $environment = [ordered]@{}
+        $hooks = [ordered]@{ Before = @(); After = @() }
+        $rollback = @()
+        $command = $null
+        $container = $null
+        $cached = $false
+        $skipReason = if ($script:SinceSkipped -and $script:SinceSkipped.ContainsKey($taskName)) { 'git' } else { $null }
+
+        if (-not $taskInfo.IsCore) {
+            $relativeScript = [System.IO.Path]::GetRelativePath($script:EffectiveScriptRoot, $taskInfo.ScriptPath) -replace '\\', '/'
+            $command = (@($relativeScript) + @($Arguments | Where-Object { $_ })) -join ' '
+            if ($taskInfo.Type -eq 'go-test') {
+                try {
+                    $command = (@('go') + (Get-GoTestArguments -TaskInfo $taskInfo).Arguments + @($Arguments | Where-Object { $_ })) -join ' '
+                } catch {
+                    $errors.Add("Task '$taskName' go test settings: $($_.Exception.Message)")
+                }
+            } elseif ($taskInfo.Type -like 'plugin/*') {
+                $command = "$($taskInfo.Type) $command"
+            } elseif ($taskInfo.Type -eq 'group') {
+                $command = $null
+            }
+            $cached = [bool](Test-TaskCached -TaskInfo $taskInfo -Arguments $Arguments)
+            if ($taskInfo.Platform.Count -gt 0) {
+                try {
+                    if (-not (Test-TaskPlatform -Platform $taskInfo.Platform)) {
+                        $skipReason = 'platform'
+                    }
+                } catch {
+                    $errors.Add("Task '$taskName' platform: $($_.Exception.Message)")
+                }
+            }
+            if ($taskInfo.When -and -not $skipReason) {
+                try {
+                    if (-not (Test-TaskCondition -Expression $taskInfo.When -Environment (Get-TaskEnvironment -TaskInfo $taskInfo))) {
+                        $skipReason = 'condition'
+                    }
+                } catch {
+                    $errors.Add("Task '$taskName' condition: $($_.Exception.Message)")
+                }
+            }
+
+            $declared = Get-TaskEnvironment -TaskInfo $taskInfo -DeclaredOnly
+            foreach ($key in ($declared.Keys | Sort-Object)) {
+                $environment[$key] = $declared[$key]
+            }
+
+            $fullEnvironment = Get-TaskEnvironment -TaskInfo $taskInfo
+            if ($taskInfo.Container) {
+                try {
+                    $container = Expand-TaskVariables -Value $taskInfo.Container -Environment (Get-TaskEnvironment -TaskInfo $taskInfo -IncludeParent) -UndefinedVars $UndefinedVars
+                } catch {
+                    $errors.Add("Task '$taskName' container '$($taskInfo.Container)': $($_.Exception.Message)")
+                    $container = $taskInfo.Container
+                }
+            }
+            foreach ($phase in @('Before', 'After')) {
+                $hooks[$phase] = @(
+                    foreach ($hook in $taskInfo[$phase]) {
+                        $hookTask = if ($hook -cmatch '^([a-z0-9][a-z0-9\-]*:)?[a-z0-9][a-z0-9\-]*$') {
+                            Resolve-TaskDependency -DependencyName $hook -CurrentNamespace $taskInfo.Namespace -Tasks $AllTasks
+                        }
+
+                        if ($hookTask) {
+                            [ordered]@{ Type = 'task'; Command = $hookTask }
+                        } else {
+                            try {
+                                [ordered]@{ Type = 'inline'; Command = (Expand-TaskVariables -Value $hook -Environment $fullEnvironment -UndefinedVars $UndefinedVars) }
+                            } catch {
+                                $errors.Add("Task '$taskName' $($phase.ToLower()) hook '$hook': $($_.Exception.Message)")
+                                [ordered]@{ Type = 'inline'; Command = $hook; Error = $_.Exception.Message }
+                            }
+                        }
+                    }
+                )
+            }
+            $rollback = @(
+                foreach ($rollbackCommand in $taskInfo.Rollback) {
+                    try {
+                        Expand-TaskVariables -Value $rollbackCommand -Environment $fullEnvironment -UndefinedVars $UndefinedVars
+                    } catch {
+                        $errors.Add("Task '$taskName' rollback '$rollbackCommand': $($_.Exception.Message)")
+                        $rollbackCommand
+                    }
+                }
+            )
+            # In the order they would run, last declared first
+            [array]::Reverse($rollback)
+        }
+
+        $taskPlans.Add([ordered]@{
+            Name         = $taskName
+            Group        = $group
+            Source       = if ($taskInfo.IsCore) { 'core' } else { 'project' }
+            Dependencies = $dependencies
+            Command      = $command
+            Container    = $container
+            Env          = $environment
+            Before       = $hooks['Before']
+            After        = $hooks['After']
+            Rollback     = $rollback
+            Timeout      = $taskInfo.Timeout
+            Retry        = $taskInfo.Retry
+            Cached       = $cached
+            When         = $taskInfo.When
+            Platform     = $taskInfo.Platform
+            Snapshot     = $taskInfo.Snapshot
+            WorkDir      = $taskInfo.WorkDir
+            SkipReason   = $skipReason
+        })
+    }
+
+    $groupCount = ($groupOf.Values | Measure-Object -Maximum).Maximum
+    $groups = @(
+        for ($i = 1; $i -le $groupCount; $i++) {
+            , @($taskPlans | Where-Object { $_.Group -eq $i } | ForEach-Object { $_.Name })
+        }
+    )
+
+    return [ordered]@{
+        Tasks  = $taskPlans.ToArray()
+        Groups = $groups
+        Errors = $errors.ToArray()
+    }
+}
+
+function Show-TaskPlan {
+    <#
+    .SYNOPSIS
+        Prints the result of Get-TaskPlan for -DryRun
+    #>
+    param(
+        [string[]]$TaskNames,
+        $Plan
+    )
+
+    Write-Host ""
+    Write-Host "Dry run for: " -NoNewline -ForegroundColor Cyan
+    Write-Host ($TaskNames -join ', ') -ForegroundColor White
+    Write-Host "(nothing is executed)" -ForegroundColor DarkGray
+    Write-Host ""
+
+    $index = 0
+    for ($i = 0; $i -lt $Plan.Groups.Count; $i++) {
+        Write-Host "Group $($i + 1):" -ForegroundColor Yellow
+        foreach ($taskPlan in ($Plan.Tasks | Where-Object { $_.Group -eq $i + 1 })) {
+            $index++
+            Write-Host "  $index. " -NoNewline -ForegroundColor Gray
+            Write-Host $taskPlan.Name -NoNewline -ForegroundColor Cyan
+            if ($taskPlan.SkipReason) {
+                Write-Host " (SKIPPED($($taskPlan.SkipReason)))" -ForegroundColor DarkGreen
+            } elseif ($taskPlan.Cached) {
+                Write-Host " (CACHED)" -ForegroundColor DarkGreen
+            } elseif ($taskPlan.Source -eq 'core') {
+                Write-Host " [core]" -ForegroundColor DarkGray
+            } else {
+                Write-Host ""
+            }
+
+            if ($taskPlan.Command) {
+                Write-Host "     Command: $($taskPlan.Command)" -ForegroundColor Gray
+            }
+            if ($taskPlan.Container) {
+                Write-Host "     Container: $($taskPlan.Container)" -ForegroundColor Gray
+            }
+            if ($taskPlan.Dependencies.Count -gt 0) {
+                Write-Host "     Depends on: $($taskPlan.Dependencies -join ', ')" -ForegroundColor Gray
+            }
+            foreach ($key in $taskPlan.Env.Keys) {
+                Write-Host "     Env: $key=$($taskPlan.Env[$key])" -ForegroundColor Gray
+            }
+            foreach ($phase in @('Before', 'After')) {
+                foreach ($hook in $taskPlan[$phase]) {
+                    $hookText = if ($hook.Type -eq 'task') { "$($hook.Command) (task)" } else { $hook.Command }
+                    if ($hook['Error']) {
+                        Write-Host "     $($phase): $hookText (ERROR: $($hook.Error))" -ForegroundColor Red
+                    } else {
+                        Write-Host "     $($phase): $hookText" -ForegroundColor Gray
+                    }
+                }
+            }
+            foreach ($rollbackCommand in $taskPlan.Rollback) {
+                Write-Host "     Rollback: $rollbackCommand" -ForegroundColor Gray
+            }
+            if ($taskPlan.When) {
+                Write-Host "     When: $($taskPlan.When)" -ForegroundColor Gray
+            }
+            if ($taskPlan.Platform.Count -gt 0) {
+                Write-Host "     Platform: $($taskPlan.Platform -join ', ')" -ForegroundColor Gray
+            }
+            if ($taskPlan.Snapshot) {
+                Write-Host "     Snapshot: $($taskPlan.Snapshot)" -ForegroundColor Gray
+            }
+            if ($taskPlan.WorkDir) {
+                Write-Host "     WorkDir: $($taskPlan.WorkDir)" -ForegroundColor Gray
+            }
+            if ($taskPlan.Timeout) {
+                Write-Host "     Timeout: $($taskPlan.Timeout)" -ForegroundColor Gray
+            }
+            if ($taskPlan.Retry) {
+                Write-Host "     Retry: $($taskPlan.Retry)" -ForegroundColor Gray
+            }
+        }
+        Write-Host ""
+    }
+
+    $cacheHits = @($Plan.Tasks | Where-Object { $_.Cached }).Count
+    Write-Host "Tasks: $($Plan.Tasks.Count), groups: $($Plan.Groups.Count), cache hits: $cacheHits" -ForegroundColor Cyan
+    Write-Host ""
+}
+
+function Test-TaskMetadata {
+    <#
+    .SYNOPSIS
+        Validates task file metadata and structure
+    .DESCRIPTION
+        Checks task files for required metadata (TASK, DESCRIPTION) and proper exit codes.
+        Returns a hashtable with validation results.
+    #>
+    [CmdletBinding()]
+    param(
+        [Parameter(Mandatory)]
+        [string]$FilePath
+    )
+
+    $result = @{
+        FilePath = $FilePath
+        FileName = Split-Path -Path $FilePath -Leaf
+        TaskName = $null
+        HasTaskMetadata = $false
+        HasDescription = $false
+        DescriptionValue = $null
+        HasExitCode = $false
+        HasDependsMetadata = $false
+        DependenciesValue = $null
+        TaskNameValid = $true
+        Issues = @()
+        Status = 'Pass'
+    }
+
+    try {
+        # Read first 30 lines for metadata
+        $lines = Get-Content -Path $FilePath -First 30 -ErrorAction Stop
+        $content = $lines -join "`n"
+
+        # Read entire file to check for exit codes
+        $fullContent = Get-Content -Path $FilePath -Raw -ErrorAction Stop
+
+        # Check for TASK metadata
+        if ($content -match '(?m)^#\s*TASK:\s*(.+)$') {
+            $result.HasTaskMetadata = $true
+            $taskNames = $Matches[1].Trim()
+            $result.TaskName = $taskNames -split ',' | ForEach-Object { $_.Trim() } | Select-Object -First 1
+
+            # Validate task name format
+            $firstTaskName = $result.TaskName
+            if ($firstTaskName -cnotmatch '^[a-z0-9][a-z0-9\-]*$') {
+                $result.TaskNameValid = $false
+                $result.Issues += "Invalid task name format: '$firstTaskName' (only lowercase letters, numbers, and hyphens allowed)"
+                $result.Status = 'Fail'
+            }
+            if ($firstTaskName.Length -gt 50) {
+                $result.TaskNameValid = $false
+                $result.Issues += "Task name too long: '$firstTaskName' (max 50 characters)"
+                $result.Status = 'Fail'
+            }
+        }
+        else {
+            # Check if using filename fallback
+            $parts = (Get-Item -Path $FilePath).BaseName -split '-'
+            if ($parts.Count -gt 1) {
+                $result.TaskName = ($parts[1..($parts.Count-1)] -join '-').ToLower()
+            } else {
+                $result.TaskName = $parts[0].ToLower()
+            }
+            $result.Issues += "Missing TASK metadata (using filename fallback: '$($result.TaskName)')"
+            if ($result.Status -eq 'Pass') {
+                $result.Status = 'Warning'
+            }
+        }
+
+        # Check for DESCRIPTION metadata
+        if ($content -match '(?m)^#\s*DESCRIPTION:[ \t]*([^\r\n]*)') {
+            $result.HasDescription = $true
+            $result.DescriptionValue = $Matches[1].Trim()
+            
+            # Check if description is placeholder or empty
+            if ([string]::IsNullOrWhiteSpace($result.DescriptionValue) -or 
+                $result.DescriptionValue -match 'TODO|Add description') {
+                $result.Issues += "Description is placeholder or empty"
+                if ($result.Status -eq 'Pass') {
+                    $result.Status = 'Warning'
+                }
+            }
+        }
+        else {
+            $result.Issues += "Missing DESCRIPTION metadata"
+            if ($result.Status -eq 'Pass') {
+                $result.Status = 'Warning'
+            }
+        }
+
+        # Check for DEPENDS metadata (presence check, not required to have dependencies)
+        if ($content -match '(?m)^#\s*DEPENDS:(.*)$') {
+            $result.HasDependsMetadata = $true
+            $depString = $Matches[1].Trim()
+            if (-not [string]::IsNullOrWhiteSpace($depString)) {
+                $result.DependenciesValue = $depString
+            }
+        }
+        else {
+            $result.Issues += "Missing DEPENDS metadata"
+            if ($result.Status -eq 'Pass') {
+                $result.Status = 'Warning'
+            }
+        }
+
+        # Check for exit code (go-test, group, and plugin tasks do not run the script)
+        if ($fullContent -match '(?m)^\s*exit\s+[01]\s*$' -or $content -match '(?m)^#\s*TYPE:[ \t]*(go-test|group|plugin/\S+)[ \t]*$') {
+            $result.HasExitCode = $true
+        }
+        else {
+            $result.Issues += "Missing explicit exit code (exit 0 or exit 1)"
+            if ($result.Status -eq 'Pass') {
+                $result.Status = 'Warning'
+            }
+        }
+
+    }
+    catch {
+        $result.Status = 'Error'
+        $result.Issues += "Error reading file: $_"
+    }
+
+    return $result
+}
+
+function Get-TaskValidationIssues {
+    <#
+    .SYNOPSIS
+        Checks loaded task metadata for problems that break or change task execution
+    .DESCRIPTION
+        For each project task file, checks that every DEPENDS entry names an existing
+        task (and not a matrix task, only its instances), TIMEOUT parses as a duration, RETRY settings are valid, WHEN parses as a condition, PLATFORM entries are known GOOS and GOARCH names, TAGS are valid names, every INPUT_VARS name has exactly one producer in the dependency chain, INPUTS and OUTPUTS globs are valid paths
+        relative to the project root, every CONSUMES artifact has exactly one producer,
+        CONTAINER volumes and ENV_PASSTHROUGH and SECRET_ENV names are valid, TYPE and its go test
+        settings are valid, plugin tasks name a registered plugin, and the script has
+        at least one command (unless it has a TYPE).
+        With -Strict, a missing or empty DESCRIPTION is also reported.
+    .OUTPUTS
+        PSCustomObject rows with Task, Field, and Issue
+    #>
+    [CmdletBinding()]
+    param(
+        [Parameter(Mandatory)]
+        [hashtable]$AllTasks,
+
+        [switch]$Strict
+    )
+
+    $issues = [System.Collections.Generic.List[object]]::new()
+
+    # Check each task file once, even when it declares several task names
+    $taskFiles = @{}
+    foreach ($taskInfo in $AllTasks.Values) {
+        if (-not $taskInfo.IsCore -and $taskInfo.ScriptPath -and -not $taskInfo.MatrixParent) {
+            $taskFiles[$taskInfo.ScriptPath] = $taskInfo
+        }
+    }
+
+    foreach ($taskInfo in $taskFiles.Values) {
+        $taskName = $taskInfo.Names[0]
+
+        foreach ($dep in $taskInfo.Dependencies) {
+            $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $taskInfo.Namespace -Tasks $AllTasks
+            if (-not $resolvedDep) {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'DEPENDS'; Issue = "Dependency '$dep' does not exist" })
+            } elseif ($AllTasks[$resolvedDep].MatrixInstances) {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'DEPENDS'; Issue = "Dependency '$dep' is a matrix task; depend on one of its instances, like '$($AllTasks[$resolvedDep].MatrixInstances[0])'" })
+            }
+        }
+
+        if ($taskInfo.Timeout) {
+            try {
+                if ((ConvertFrom-Duration -Duration $taskInfo.Timeout).TotalMilliseconds -le 0) {
+                    $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'TIMEOUT'; Issue = "Timeout '$($taskInfo.Timeout)' must be greater than zero" })
+                }
+            } catch {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'TIMEOUT'; Issue = "$_" })
+            }
+        }
+
+        if ($taskInfo.Retry) {
+            try {
+                Get-TaskRetryPolicy -TaskInfo $taskInfo | Out-Null
+            } catch {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'RETRY'; Issue = "$_" })
+            }
+        }
+
+        if ($taskInfo.When) {
+            try {
+                ConvertFrom-TaskCondition -Expression $taskInfo.When | Out-Null
+            } catch {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'WHEN'; Issue = $_.Exception.Message })
+            }
+        }
+
+        foreach ($varSetting in @(@('OutputVars', 'OUTPUT_VARS'), @('InputVars', 'INPUT_VARS'))) {
+            foreach ($name in $taskInfo[$varSetting[0]]) {
+                if ($name -notmatch '^[A-Za-z_][A-Za-z0-9_]*$') {
+                    $issues.Add([PSCustomObject]@{ Task = $taskName; Field = $varSetting[1]; Issue = "'$name' is not a valid environment variable name" })
+                }
+            }
+        }
+        $inputVarProducers = Get-TaskInputVarProducers -TaskInfo $taskInfo -AllTasks $AllTasks
+        foreach ($name in $inputVarProducers.Keys) {
+            $varProducers = @($inputVarProducers[$name])
+            if ($varProducers.Count -eq 0) {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'INPUT_VARS'; Issue = "No task in the dependency chain declares '$name' in OUTPUT_VARS" })
+            } elseif ($varProducers.Count -gt 1) {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'INPUT_VARS'; Issue = "'$name' is declared in OUTPUT_VARS by more than one task in the dependency chain: $($varProducers -join ', ')" })
+            }
+        }
+
+        foreach ($tagName in $taskInfo.Tags) {
+            if ($tagName -cnotmatch '^[a-z0-9][a-z0-9\-]*$') {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'TAGS'; Issue = "Tag '$tagName' must use only lowercase letters, numbers, and hyphens" })
+            }
+        }
+
+        foreach ($entry in $taskInfo.Platform) {
+            try {
+                ConvertFrom-TaskPlatform -Entry $entry | Out-Null
+            } catch {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'PLATFORM'; Issue = $_.Exception.Message })
+            }
+        }
+
+        if ($taskInfo.Resources.CpuQuota -or $taskInfo.Resources.MemoryLimitMb) {
+            try {
+                Get-TaskResourceLimits -TaskInfo $taskInfo | Out-Null
+            } catch {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'RESOURCES'; Issue = $_.Exception.Message })
+            }
+        }
+
+        if ($taskInfo.WorkDir) {
+            try {
+                Get-TaskWorkingDirectory -TaskInfo $taskInfo | Out-Null
+            } catch {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'WORKDIR'; Issue = $_.Exception.Message })
+            }
+        }
+
+        if ($taskInfo.Snapshot) {
+            try {
+                Get-TaskSnapshotPath -TaskInfo $taskInfo | Out-Null
+            } catch {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'SNAPSHOT'; Issue = $_.Exception.Message })
+            }
+            if ($taskInfo.Type -like 'plugin/*') {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'SNAPSHOT'; Issue = 'Plugin tasks run in the Bolt process and cannot use SNAPSHOT' })
+            }
+        }
+
+        foreach ($artifact in $taskInfo.Produces) {
+            if ([System.IO.Path]::IsPathRooted($artifact) -or $artifact -match '(^|/)\.\.(/|$)') {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'PRODUCES'; Issue = "Artifact '$artifact' must be a path inside the project root" })
+            }
+        }
+
+        if ($taskInfo.ArtifactProducers) {
+            foreach ($artifact in $taskInfo.ArtifactProducers.Keys) {
+                $artifactProducers = @($taskInfo.ArtifactProducers[$artifact])
+                if ($artifactProducers.Count -eq 0) {
+                    $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'CONSUMES'; Issue = "No task produces '$artifact'" })
+                } elseif ($artifactProducers.Count -gt 1) {
+                    $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'CONSUMES'; Issue = "'$artifact' is produced by more than one task: $($artifactProducers -join ', ')" })
+                }
+            }
+        }
+        if ($taskInfo.ArtifactConflicts) {
+            foreach ($artifact in $taskInfo.ArtifactConflicts.Keys) {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'CONSUMES'; Issue = $taskInfo.ArtifactConflicts[$artifact] })
+            }
+        }
+
+        if (-not $taskInfo.Container -and ($taskInfo.Volumes.Count -gt 0 -or $taskInfo.EnvPassthrough.Count -gt 0)) {
+            $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'CONTAINER'; Issue = 'VOLUMES and ENV_PASSTHROUGH have no effect without CONTAINER' })
+        }
+        foreach ($volume in $taskInfo.Volumes) {
+            try {
+                ConvertFrom-ContainerVolume -Volume $volume | Out-Null
+            } catch {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'VOLUMES'; Issue = "$_" })
+            }
+        }
+        foreach ($name in $taskInfo.EnvPassthrough) {
+            if ($name -notmatch '^[A-Za-z_][A-Za-z0-9_]*$') {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'ENV_PASSTHROUGH'; Issue = "'$name' is not a valid environment variable name" })
+            }
+        }
+        foreach ($name in $taskInfo.SecretEnv) {
+            if ($name -notmatch '^[A-Za-z_][A-Za-z0-9_]*$') {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'SECRET_ENV'; Issue = "'$name' is not a valid environment variable name" })
+            } elseif ($taskInfo.Env.Contains($name)) {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'SECRET_ENV'; Issue = "'$name' is also set with # ENV:; the secret replaces it" })
+            }
+        }
+
+        $goTestSettings = @($taskInfo.GoTest.Keys | Where-Object { $taskInfo.GoTest[$_] })
+        if ($taskInfo.Type -like 'plugin/*') {
+            try {
+                Get-TaskExecutor -TaskInfo $taskInfo | Out-Null
+            } catch {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'TYPE'; Issue = $_.Exception.Message })
+            }
+            if ($taskInfo.Timeout -or $taskInfo.Container) {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'TYPE'; Issue = 'Plugin tasks run in the Bolt process and cannot use TIMEOUT or CONTAINER' })
+            }
+            if ($taskInfo.OutputVars.Count -gt 0) {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'OUTPUT_VARS'; Issue = 'Plugin tasks run in the Bolt process and cannot set OUTPUT_VARS' })
+            }
+        } elseif ($taskInfo.Type -eq 'group') {
+            if ($taskInfo.Dependencies.Count -eq 0) {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'DEPENDS'; Issue = 'Group tasks need their members in DEPENDS' })
+            }
+            $unusedFields = @(
+                foreach ($field in @('Inputs', 'Outputs', 'Before', 'After', 'Rollback', 'Timeout', 'Retry', 'Container', 'Snapshot', 'Platform', 'OutputVars')) {
+                    if ($taskInfo[$field]) { ($field -creplace '(?<=.)([A-Z])', '_$1').ToUpper() }
+                }
+            )
+            if ($unusedFields.Count -gt 0) {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'TYPE'; Issue = "Group tasks have no command of their own and cannot use $($unusedFields -join ', ')" })
+            }
+        } elseif ($taskInfo.Type -and $taskInfo.Type -ne 'go-test') {
+            $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'TYPE'; Issue = "Unknown task type '$($taskInfo.Type)' (supported: go-test, group, plugin/<name>)" })
+        } elseif ($taskInfo.Type -eq 'go-test') {
+            if ($taskInfo.Container) {
+                $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'TYPE'; Issue = 'go-test tasks run go on the host and cannot use CONTAINER' })
+            }
+            foreach ($flag in @('Race', 'Cover')) {
+                if ($taskInfo.GoTest[$flag] -and $taskInfo.GoTest[$flag] -notin @('true', 'false')) {
+                    $issues.Add([PSCustomObject]@{ Task = $taskName; Field = $flag.ToUpper(); Issue = "$($flag.ToUpper()) must be true or false: $($taskInfo.GoTest[$flag])" })
+                }
+            }
+        } elseif ($goTestSettings.Count -gt 0) {
+            $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'TYPE'; Issue = 'PACKAGES, RACE, COVER, and COVERPROFILE have no effect without TYPE: go-test' })
+        }
+
+        foreach ($field in @('Inputs', 'Outputs')) {
+            foreach ($glob in $taskInfo[$field]) {
+                $normalized = $glob -replace '\\', '/'
+                $problem = $null
+                if ([System.IO.Path]::IsPathRooted($normalized) -or $normalized -match '(^|/)\.\.(/|$)') {
+                    $problem = "Glob '$glob' must be relative to the project root"
+                } elseif ($normalized.IndexOfAny([System.IO.Path]::GetInvalidPathChars()) -ge 0) {
+                    $problem = "Glob '$glob' contains characters that are not valid in a path"
+                } else {
+                    try {
+                        [void][regex]::new((ConvertTo-GlobRegex -Pattern $normalized))
+                    } catch {
+                        $problem = "Glob '$glob' does not compile: $($_.Exception.Message)"
+                    }
+                }
+                if ($problem) {
+                    $issues.Add([PSCustomObject]@{ Task = $taskName; Field = $field.ToUpper(); Issue = $problem })
+                }
+            }
+        }
+
+        # A script with only comments and blank lines does nothing when run
+        $scriptContent = Get-Content -Path $taskInfo.ScriptPath -Raw -ErrorAction SilentlyContinue
+        $commands = [regex]::Replace([string]$scriptContent, '(?s)<#.*?#>', '') -split '\r?\n' |
+            Where-Object { $_.Trim() -and -not $_.Trim().StartsWith('#') }
+        if (-not $commands -and -not $taskInfo.Type) {
+            $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'script'; Issue = 'Task script has no commands' })
+        } elseif ($commands -and $taskInfo.Type -eq 'group') {
+            $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'script'; Issue = 'Group tasks do not run their script; move the commands into a member task' })
+        }
+
+        if ($Strict -and [string]::IsNullOrWhiteSpace($taskInfo.Description)) {
+            $issues.Add([PSCustomObject]@{ Task = $taskName; Field = 'DESCRIPTION'; Issue = 'Missing description (-Strict)' })
+        }
+    }
+
+    return , $issues
+}
+
+function Get-TaskPlatformWarnings {
+    <#
+    .SYNOPSIS
+        Finds dependencies that # PLATFORM: always skips on this machine
+    .DESCRIPTION
+        A task that runs on -Current but depends on a task whose # PLATFORM: does
+        not include -Current runs without that dependency's work, because skipped
+        dependencies count as done. Tasks with invalid PLATFORM entries are left to
+        Get-TaskValidationIssues.
+    .OUTPUTS
+        PSCustomObject rows with Task, Field, and Issue
+    #>
+    param(
+        [Parameter(Mandatory)]
+        [hashtable]$AllTasks,
+
+        [PSCustomObject]$Current = (Get-BoltPlatform)
+    )
+
+    $warnings = [System.Collections.Generic.List[object]]::new()
+    $runsHere = {
+        param($TaskInfo)
+
+        try {
+            Test-TaskPlatform -Platform $TaskInfo.Platform -Current $Current
+        } catch {
+            $null
+        }
+    }
+
+    $taskFiles = @{}
+    foreach ($taskInfo in $AllTasks.Values) {
+        if (-not $taskInfo.IsCore -and $taskInfo.ScriptPath -and -not $taskInfo.MatrixParent) {
+            $taskFiles[$taskInfo.ScriptPath] = $taskInfo
+        }
+    }
+
+    foreach ($taskInfo in $taskFiles.Values) {
+        if ((& $runsHere $taskInfo) -ne $true) {
+            continue
+        }
+        foreach ($dep in $taskInfo.Dependencies) {
+            $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $taskInfo.Namespace -Tasks $AllTasks
+            if ($resolvedDep -and (& $runsHere $AllTasks[$resolvedDep]) -eq $false) {
+                $warnings.Add([PSCustomObject]@{
+                    Task  = $taskInfo.Names[0]
+                    Field = 'DEPENDS'
+                    Issue = "Dependency '$dep' only runs on $($AllTasks[$resolvedDep].Platform -join ', ') and is always skipped on $($Current.Os)/$($Current.Arch), where this task runs"
+                })
+            }
+        }
+    }
+
+    return , $warnings
+}
+
+function Show-ValidationReport {
+    <#
+    .SYNOPSIS
+        Displays validation results in a formatted report
+    .DESCRIPTION
+        Shows validation results for all task files in a table format with color-coding
+    #>
+    [CmdletBinding()]
+    param(
+        [Parameter(Mandatory)]
+        [hashtable]$AllTasks,
+        
+        [Parameter(Mandatory)]
+        [string]$TaskDirectory,
+
+        [switch]$Strict
+    )
+
+    Write-Host ""
+    Write-Host "Task Validation Report" -ForegroundColor Cyan
+    Write-Host ("=" * 80) -ForegroundColor Gray
+    Write-Host ""
+
+    # Get all unique task files (excluding core tasks)
+    $taskFiles = @{}
+    foreach ($taskKey in $AllTasks.Keys) {
+        $taskInfo = $AllTasks[$taskKey]
+        if (-not $taskInfo.IsCore -and $taskInfo.ScriptPath) {
+            $taskFiles[$taskInfo.ScriptPath] = $taskInfo
+        }
+    }
+
+    if ($taskFiles.Count -eq 0) {
+        Write-Host "No project tasks found in '$TaskDirectory' directory." -ForegroundColor Yellow
+        Write-Host ""
+        return
+    }
+
+    # Validate each task file
+    $validationResults = @()
+    foreach ($filePath in $taskFiles.Keys) {
+        $validationResults += Test-TaskMetadata -FilePath $filePath
+    }
+
+    # Display results in table format
+    $passCount = 0
+    $warningCount = 0
+    $failCount = 0
+
+    foreach ($result in $validationResults | Sort-Object FileName) {
+        # Status indicator with color
+        $statusDisplay = switch ($result.Status) {
+            'Pass' { 
+                $passCount++
+                "✓ PASS"
+            }
+            'Warning' { 
+                $warningCount++
+                "⚠ WARN"
+            }
+            'Fail' { 
+                $failCount++
+                "✗ FAIL"
+            }
+            'Error' { 
+                $failCount++
+                "✗ ERROR"
+            }
+        }
+
+        $statusColor = switch ($result.Status) {
+            'Pass' { 'Green' }
+            'Warning' { 'Yellow' }
+            'Fail' { 'Red' }
+            'Error' { 'Red' }
+        }
+
+        # Task info line
+        Write-Host "File: " -NoNewline -ForegroundColor Gray
+        Write-Host $result.FileName -NoNewline -ForegroundColor White
+        Write-Host " | Task: " -NoNewline -ForegroundColor Gray
+        Write-Host $result.TaskName -NoNewline -ForegroundColor White
+        Write-Host " | " -NoNewline -ForegroundColor Gray
+        Write-Host $statusDisplay -ForegroundColor $statusColor
+
+        # Metadata checks
+        Write-Host "  TASK: " -NoNewline -ForegroundColor Gray
+        Write-Host $(if ($result.HasTaskMetadata) { "✓" } else { "✗" }) -ForegroundColor $(if ($result.HasTaskMetadata) { "Green" } else { "Red" })
+        
+        Write-Host "  DESCRIPTION: " -NoNewline -ForegroundColor Gray
+        Write-Host $(if ($result.HasDescription) { "✓" } else { "✗" }) -NoNewline -ForegroundColor $(if ($result.HasDescription) { "Green" } else { "Red" })
+        if ($result.HasDescription -and $result.DescriptionValue) {
+            Write-Host " ($($result.DescriptionValue.Substring(0, [Math]::Min(50, $result.DescriptionValue.Length)))...)" -ForegroundColor DarkGray
+        } else {
+            Write-Host ""
+        }
+
+        Write-Host "  DEPENDS: " -NoNewline -ForegroundColor Gray
+        Write-Host $(if ($result.HasDependsMetadata) { "✓" } else { "✗" }) -NoNewline -ForegroundColor $(if ($result.HasDependsMetadata) { "Green" } else { "Red" })
+        if ($result.DependenciesValue) {
+            Write-Host " ($($result.DependenciesValue))" -ForegroundColor DarkGray
+        } else {
+            Write-Host ""
+        }
+
+        Write-Host "  Exit Code: " -NoNewline -ForegroundColor Gray
+        Write-Host $(if ($result.HasExitCode) { "✓" } else { "✗" }) -ForegroundColor $(if ($result.HasExitCode) { "Green" } else { "Red" })
+
+        # Show issues if any
+        if ($result.Issues.Count -gt 0) {
+            foreach ($issue in $result.Issues) {
+                Write-Host "  Issue: " -NoNewline -ForegroundColor Yellow
+                Write-Host $issue -ForegroundColor Gray
+            }
+        }
+
+        Write-Host ""
+    }
+
+    # Summary
+    Write-Host ("=" * 80) -ForegroundColor Gray
+    Write-Host "Summary: " -NoNewline -ForegroundColor Cyan
+    Write-Host "$($validationResults.Count) task file(s) validated" -ForegroundColor White
+    Write-Host "  " -NoNewline
+    Write-Host "✓ Pass: $passCount" -NoNewline -ForegroundColor Green
+    Write-Host "  " -NoNewline
+    Write-Host "⚠ Warnings: $warningCount" -NoNewline -ForegroundColor Yellow
+    Write-Host "  " -NoNewline
+    Write-Host "✗ Failures: $failCount" -ForegroundColor Red
+    Write-Host ""
+
+    # Problems that break or change task execution, as Task | Field | Issue rows
+    $issueRows = [System.Collections.Generic.List[object]]::new()
+    foreach ($result in $validationResults | Where-Object { -not $_.TaskNameValid }) {
+        foreach ($issue in $result.Issues | Where-Object { $_ -match '^(Invalid task name format|Task name too long)' }) {
+            $issueRows.Add([PSCustomObject]@{ Task = $result.TaskName; Field = 'TASK'; Issue = $issue })
+        }
+    }
+    foreach ($row in Get-TaskValidationIssues -AllTasks $AllTasks -Strict:$Strict) {
+        $issueRows.Add($row)
+    }
+
+    if ($issueRows.Count -gt 0) {
+        $taskWidth = [Math]::Max(4, ($issueRows.Task | Measure-Object -Property Length -Maximum).Maximum)
+        $fieldWidth = [Math]::Max(5, ($issueRows.Field | Measure-Object -Property Length -Maximum).Maximum)
+
+        Write-Host "Issues:" -ForegroundColor Red
+        Write-Host "$('Task'.PadRight($taskWidth)) | $('Field'.PadRight($fieldWidth)) | Issue"
+        Write-Host "$('-' * $taskWidth) | $('-' * $fieldWidth) | -----"
+        foreach ($row in $issueRows | Sort-Object Task, Field) {
+            Write-Host "$($row.Task.PadRight($taskWidth)) | $($row.Field.PadRight($fieldWidth)) | $($row.Issue)"
+        }
+        Write-Host ""
+    }
+
+    # Dependencies that # PLATFORM: skips here are reported without failing validation
+    $warningRows = Get-TaskPlatformWarnings -AllTasks $AllTasks
+    if ($warningRows.Count -gt 0) {
+        $taskWidth = [Math]::Max(4, ($warningRows.Task | Measure-Object -Property Length -Maximum).Maximum)
+        $fieldWidth = [Math]::Max(5, ($warningRows.Field | Measure-Object -Property Length -Maximum).Maximum)
+
+        Write-Host "Warnings:" -ForegroundColor Yellow
+        Write-Host "$('Task'.PadRight($taskWidth)) | $('Field'.PadRight($fieldWidth)) | Warning"
+        Write-Host "$('-' * $taskWidth) | $('-' * $fieldWidth) | -------"
+        foreach ($row in $warningRows | Sort-Object Task, Issue) {
+            Write-Host "$($row.Task.PadRight($taskWidth)) | $($row.Field.PadRight($fieldWidth)) | $($row.Issue)"
+        }
+        Write-Host ""
+    }
+
+    # Return exit code based on failures
+    if ($failCount -gt 0 -or $issueRows.Count -gt 0) {
+        return 1
+    }
+    return 0
+}
+
+function ConvertTo-GlobRegex {
+    <#
+    .SYNOPSIS
+        Converts a glob pattern to an anchored regular expression
+    .DESCRIPTION
+        '**/' matches zero or more directories, '**' matches anything, '*' matches
+        within one path segment, and '?' matches one character. Paths use '/'.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Pattern
+    )
+
+    $regex = [System.Text.StringBuilder]::new('^')
+    for ($i = 0; $i -lt $Pattern.Length; $i++) {
+        $char = $Pattern[$i]
+        if ($char -eq '*' -and $i + 1 -lt $Pattern.Length -and $Pattern[$i + 1] -eq '*') {
+            if ($i + 2 -lt $Pattern.Length -and $Pattern[$i + 2] -eq '/') {
+                [void]$regex.Append('(?:.*/)?')
+                $i += 2
+            } else {
+                [void]$regex.Append('.*')
+                $i += 1
+            }
+        } elseif ($char -eq '*') {
+            [void]$regex.Append('[^/]*')
+        } elseif ($char -eq '?') {
+            [void]$regex.Append('[^/]')
+        } else {
+            [void]$regex.Append([regex]::Escape([string]$char))
+        }
+    }
+    [void]$regex.Append('$')
+    return $regex.ToString()
+}
+
+function Resolve-TaskFileGlob {
+    <#
+    .SYNOPSIS
+        Returns the files that match a glob pattern
+    .DESCRIPTION
+        Supports '*' and '?' within a path segment and '**' across segments
+        (e.g., 'src/**/*.ps1'). A pattern without wildcards matches a single file, or
+        every file below it when it names a directory. Files under .git and .bolt are
+        never matched.
+    .PARAMETER Pattern
+        Glob pattern relative to BasePath
+    .PARAMETER BasePath
+        Directory the pattern is relative to
+    .OUTPUTS
+        Full paths of the matching files
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Pattern,
+
+        [Parameter(Mandatory = $true)]
+        [string]$BasePath
+    )
+
+    $normalized = ($Pattern.Trim() -replace '\\', '/') -replace '^(\./)+', ''
+    $segments = @($normalized -split '/' | Where-Object { $_ })
+
+    # Walk only the directory part that has no wildcards
+    $staticSegments = @()
+    foreach ($segment in $segments) {
+        if ($segment -match '[\*\?]') {
+            break
+        }
+        $staticSegments += $segment
+    }
+
+    if ($staticSegments.Count -eq $segments.Count) {
+        $literalPath = Join-Path -Path $BasePath -ChildPath $normalized
+        if (Test-Path -LiteralPath $literalPath -PathType Leaf) {
+            return @((Get-Item -LiteralPath $literalPath -Force).FullName)
+        }
+        if (-not (Test-Path -LiteralPath $literalPath -PathType Container)) {
+            return @()
+        }
+        $normalized = "$($normalized.TrimEnd('/'))/**"
+    }
+
+    $searchRoot = if ($staticSegments.Count -gt 0) { Join-Path -Path $BasePath -ChildPath ($staticSegments -join '/') } else { $BasePath }
+    if (-not (Test-Path -LiteralPath $searchRoot -PathType Container)) {
+        return @()
+    }
+
+    $globRegex = ConvertTo-GlobRegex -Pattern $normalized
+
+    return @(
+        Get-ChildItem -LiteralPath $searchRoot -File -Recurse -Force -ErrorAction SilentlyContinue | ForEach-Object {
+            $relativePath = [System.IO.Path]::GetRelativePath($BasePath, $_.FullName) -replace '\\', '/'
+            if ($relativePath -notmatch '^\.(git|bolt)/' -and $relativePath -match $globRegex) {
+                $_.FullName
+            }
+        } | Sort-Object -Unique
+    )
+}
+
+function Get-TaskCacheKey {
+    <#
+    .SYNOPSIS
+        Computes the content-addressed cache key for a task
+    .DESCRIPTION
+        The key is a SHA-256 over the task script contents, the arguments passed to the
+        task, the matrix values of a matrix instance, and the path and SHA-256 of every
+        file matched by the task's INPUTS globs.
+    .OUTPUTS
+        PSCustomObject with Key and Inputs (relative path to file hash)
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [array]$Arguments = @(),
+
+        [Parameter(Mandatory = $true)]
+        [string]$BasePath
+    )
+
+    Start-BoltProfilePhase -Name 'cache-key'
+
+    $inputHashes = [ordered]@{}
+    $inputFiles = @(foreach ($pattern in $TaskInfo.Inputs) { Resolve-TaskFileGlob -Pattern $pattern -BasePath $BasePath }) | Sort-Object -Unique
+    foreach ($file in $inputFiles) {
+        $relativePath = [System.IO.Path]::GetRelativePath($BasePath, $file) -replace '\\', '/'
+        $inputHashes[$relativePath] = (Get-FileHash -LiteralPath $file -Algorithm SHA256).Hash
+    }
+
+    $keySource = [System.Text.StringBuilder]::new()
+    [void]$keySource.AppendLine("script:$((Get-FileHash -LiteralPath $TaskInfo.ScriptPath -Algorithm SHA256).Hash)")
+    [void]$keySource.AppendLine("arguments:$($Arguments -join ' ')")
+    foreach ($key in $TaskInfo.MatrixValues.Keys) {
+        [void]$keySource.AppendLine("matrix:$key=$($TaskInfo.MatrixValues[$key])")
+    }
+    foreach ($entry in $inputHashes.GetEnumerator()) {
+        [void]$keySource.AppendLine("$($entry.Key):$($entry.Value)")
+    }
+
+    $sha256 = [System.Security.Cryptography.SHA256]::Create()
+    try {
+        $keyBytes = $sha256.ComputeHash([System.Text.Encoding]::UTF8.GetBytes($keySource.ToString()))
+    } finally {
+        $sha256.Dispose()
+    }
+
+    Stop-BoltProfilePhase -Name 'cache-key'
+
+    return [PSCustomObject]@{
+        Key    = [System.BitConverter]::ToString($keyBytes) -replace '-', ''
+        Inputs = $inputHashes
+    }
+}
+
+function New-LocalDirCache {
+    <#
+    .SYNOPSIS
+        Creates a task cache that stores one JSON manifest per task in a directory
+    .DESCRIPTION
+        Returns a cache object with two methods, which is the contract every cache
+        backend follows:
+          Get(taskName, key)    returns the stored manifest or $null
+          Set(taskName, entry)  stores the manifest (entry.Key holds the cache key)
+
+        The local cache keeps one manifest per task and ignores the key in Get, so
+        Test-TaskCached can report which inputs changed since the last run.
+    .PARAMETER Path
+        Directory for the manifests (default for Bolt: .bolt/cache)
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Path
+    )
+
+    $cache = [PSCustomObject]@{
+        Type = 'local'
+        Path = $Path
+    }
+
+    $cache | Add-Member -MemberType ScriptMethod -Name Get -Value {
+        param([string]$TaskName, [string]$Key)
+
+        $entryPath = Join-Path -Path $this.Path -ChildPath "$TaskName.json"
+        if (-not (Test-Path -LiteralPath $entryPath -PathType Leaf)) {
+            return $null
+        }
+
+        try {
+            return Get-Content -LiteralPath $entryPath -Raw | ConvertFrom-Json
+        } catch {
+            Write-Verbose "Ignoring unreadable cache manifest '$entryPath': $_"
+            return $null
+        }
+    }
+
+    $cache | Add-Member -MemberType ScriptMethod -Name Set -Value {
+        param([string]$TaskName, $Entry)
+
+        if (-not (Test-Path -LiteralPath $this.Path)) {
+            New-Item -ItemType Directory -Path $this.Path -Force | Out-Null
+        }
+        $Entry | ConvertTo-Json -Depth 5 | Set-Content -LiteralPath (Join-Path -Path $this.Path -ChildPath "$TaskName.json") -Encoding utf8
+    }
+
+    return $cache
+}
+
+function Get-CIIdentityProvider {
+    <#
+    .SYNOPSIS
+        Names the CI provider that can issue an OIDC identity token for this job
+    .DESCRIPTION
+        Returns 'github' when ACTIONS_ID_TOKEN_REQUEST_URL and
+        ACTIONS_ID_TOKEN_REQUEST_TOKEN are set (a GitHub Actions job with
+        'permissions: id-token: write'), 'gitlab' when CI_JOB_JWT_V2 is set, and
+        $null otherwise.
+    #>
+    if ($env:ACTIONS_ID_TOKEN_REQUEST_URL -and $env:ACTIONS_ID_TOKEN_REQUEST_TOKEN) {
+        return 'github'
+    }
+    if ($env:CI_JOB_JWT_V2) {
+        return 'gitlab'
+    }
+    return $null
+}
+
+function Request-RemoteCacheToken {
+    <#
+    .SYNOPSIS
+        Exchanges the CI job's OIDC identity token for a remote cache token
+    .DESCRIPTION
+        Gets the identity token from the provider named by Get-CIIdentityProvider.
+        On GitHub Actions it is requested from ACTIONS_ID_TOKEN_REQUEST_URL, for
+        -Audience when it is set. On GitLab it is CI_JOB_JWT_V2. The token is sent to
+        -AuthUrl:
+          POST <AuthUrl>   {"provider": "github", "token": "<identity token>"}
+        which answers with {"access_token": "...", "expires_in": 300}. "token" is
+        accepted instead of "access_token", and without expires_in the token is
+        used for the whole run. Failures throw, and the message never contains a token.
+    .OUTPUTS
+        PSCustomObject with Token and ExpiresAt (UTC)
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$AuthUrl,
+
+        [Parameter(Mandatory = $true)]
+        [ValidateSet('github', 'gitlab')]
+        [string]$Provider,
+
+        [string]$Audience
+    )
+
+    if ($Provider -eq 'github') {
+        $requestUrl = $env:ACTIONS_ID_TOKEN_REQUEST_URL
+        if ($Audience) {
+            $separator = if ($requestUrl.Contains('?')) { '&' } else { '?' }
+            $requestUrl += "${separator}audience=$([uri]::EscapeDataString($Audience))"
+        }
+        try {
+            $identity = Invoke-RestMethod -Method Get -Uri $requestUrl -Headers @{ Authorization = "Bearer $env:ACTIONS_ID_TOKEN_REQUEST_TOKEN" } -TimeoutSec 10 -ErrorAction Stop
+            $identityToken = [string]$identity.value
+        } catch {
+            throw "Could not get a GitHub Actions OIDC token: $($_.Exception.Message)"
+        }
+    } else {
+        $identityToken = $env:CI_JOB_JWT_V2
+    }
+
+    $body = [ordered]@{ provider = $Provider; token = $identityToken } | ConvertTo-Json -Compress
+    try {
+        $response = Invoke-RestMethod -Method Post -Uri $AuthUrl -Body $body -ContentType 'application/json' -TimeoutSec 10 -ErrorAction Stop
+    } catch {
+        throw "OIDC token exchange at '$AuthUrl' failed: $($_.Exception.Message)"
+    }
+
+    $token = if ($response.access_token) { $response.access_token } else { $response.token }
+    if (-not $token) {
+        throw "OIDC token exchange at '$AuthUrl' returned no access_token"
+    }
+    $expiresAt = if ($response.expires_in) { [DateTime]::UtcNow.AddSeconds([double]$response.expires_in) } else { [DateTime]::MaxValue }
+
+    return [PSCustomObject]@{
+        Token     = [string]$token
+        ExpiresAt = $expiresAt
+    }
+}
+
+function New-RemoteCache {
+    <#
+    .SYNOPSIS
+        Creates a task cache that stores manifests on an HTTP server
+    .DESCRIPTION
+        Follows the same Get/Set contract as New-LocalDirCache and talks to a simple
+        REST API:
+          GET <Url>/cache/<key>   200 with the manifest JSON, or 404 when not cached
+          PUT <Url>/cache/<key>   stores the manifest JSON sent in the body
+
+        When -Token is set it is sent as 'Authorization: Bearer <token>'. With -Oidc,
+        the bearer token comes from Request-RemoteCacheToken instead: it is
+        exchanged before the first request and again shortly before it expires, and
+        a failed exchange counts as the server not being reachable. Manifests
+        older than -Ttl are treated as a miss. Every manifest is also written to the
+        -Fallback cache. When the server cannot be reached, a warning is written once
+        and the fallback cache is used for the rest of the run.
+    .PARAMETER Url
+        Base URL of the cache server (http or https)
+    .PARAMETER Token
+        Bearer token for the cache server
+    .PARAMETER Oidc
+        AuthUrl, Provider, and Audience for Request-RemoteCacheToken
+    .PARAMETER Ttl
+        How long a remote manifest stays valid ([TimeSpan]::Zero means no limit)
+    .PARAMETER Fallback
+        Cache used when the server cannot be reached (usually New-LocalDirCache)
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Url,
+
+        [string]$Token,
+
+        [hashtable]$Oidc,
+
+        [TimeSpan]$Ttl = [TimeSpan]::Zero,
+
+        [Parameter(Mandatory = $true)]
+        $Fallback
+    )
+
+    $headers = @{}
+    if ($Token) {
+        $headers['Authorization'] = "Bearer $Token"
+    }
+
+    $cache = [PSCustomObject]@{
+        Type           = 'remote'
+        Url            = $Url.TrimEnd('/')
+        Headers        = $headers
+        Oidc           = $Oidc
+        TokenExpiresAt = [DateTime]::MinValue
+        Ttl            = $Ttl
+        Fallback       = $Fallback
+        Offline        = $false
+    }
+
+    # Network errors switch the cache to offline mode instead of failing the build
+    $cache | Add-Member -MemberType ScriptMethod -Name SetOffline -Value {
+        param($ErrorRecord)
+
+        if (-not $this.Offline) {
+            $this.Offline = $true
+            Write-Warning "Remote cache at '$($this.Url)' is not reachable, using the local cache: $($ErrorRecord.Exception.Message)"
+        }
+    }
+
+    # Exchanges a new OIDC token when there is none yet or it is about to expire
+    $cache | Add-Member -MemberType ScriptMethod -Name Authorize -Value {
+        if (-not $this.Oidc -or [DateTime]::UtcNow -lt $this.TokenExpiresAt) {
+            return
+        }
+
+        $oidc = $this.Oidc
+        $issued = Request-RemoteCacheToken @oidc
+        $this.Headers['Authorization'] = "Bearer $($issued.Token)"
+        $this.TokenExpiresAt = if ($issued.ExpiresAt -eq [DateTime]::MaxValue) { $issued.ExpiresAt } else { $issued.ExpiresAt.AddSeconds(-30) }
+    }
+
+    $cache | Add-Member -MemberType ScriptMethod -Name Get -Value {
+        param([string]$TaskName, [string]$Key)
+
+        if ($this.Offline -or -not $Key) {
+            return $this.Fallback.Get($TaskName, $Key)
+        }
+
+        # Kept apart from the GET, so an error from the token exchange is not read as a cache miss
+        try {
+            $this.Authorize()
+        } catch {
+            $this.SetOffline($_)
+            return $this.Fallback.Get($TaskName, $Key)
+        }
+
+        try {
+            $entry = Invoke-RestMethod -Method Get -Uri "$($this.Url)/cache/$Key" -Headers $this.Headers -TimeoutSec 10 -ErrorAction Stop
+        } catch {
+            if ($_.Exception.Response -and [int]$_.Exception.Response.StatusCode -eq 404) {
+                Write-Verbose "Remote cache has no entry for '$TaskName' ($Key)"
+                return $null
+            }
+            $this.SetOffline($_)
+            return $this.Fallback.Get($TaskName, $Key)
+        }
+
+        if ($this.Ttl -gt [TimeSpan]::Zero -and $entry.CreatedAt) {
+            $age = [DateTime]::UtcNow - ([DateTime]::Parse($entry.CreatedAt, [cultureinfo]::InvariantCulture, [System.Globalization.DateTimeStyles]::RoundtripKind)).ToUniversalTime()
+            if ($age -gt $this.Ttl) {
+                Write-Verbose "Remote cache entry for '$TaskName' expired ($([int]$age.TotalSeconds)s old)"
+                return $null
+            }
+        }
+
+        return $entry
+    }
+
+    $cache | Add-Member -MemberType ScriptMethod -Name Set -Value {
+        param([string]$TaskName, $Entry)
+
+        # Keep a local copy so the next run works offline
+        $this.Fallback.Set($TaskName, $Entry)
+
+        if ($this.Offline) {
+            return
+        }
+
+        try {
+            $this.Authorize()
+            $body = $Entry | ConvertTo-Json -Depth 5
+            Invoke-RestMethod -Method Put -Uri "$($this.Url)/cache/$($Entry.Key)" -Headers $this.Headers -Body $body -ContentType 'application/json' -TimeoutSec 10 -ErrorAction Stop | Out-Null
+        } catch {
+            $this.SetOffline($_)
+        }
+    }
+
+    return $cache
+}
+
+function Get-TaskCache {
+    <#
+    .SYNOPSIS
+        Returns the cache used for task results
+    .DESCRIPTION
+        Uses the local cache in .bolt/cache, or a remote cache in front of it when
+        RemoteCacheURL is set in bolt.config.json. RemoteCacheToken can reference an
+        environment variable, like "${BOLT_CACHE_TOKEN}". RemoteCacheTTL is a duration
+        like "24h". The cache is created once per run.
+
+        With "RemoteCacheAuth": "oidc", a CI job exchanges its OIDC identity token at
+        RemoteCacheAuthURL (for the optional RemoteCacheAudience) for a short-lived
+        token. Outside CI, where there is no identity token, RemoteCacheToken is used
+        when it is set, and no authentication otherwise.
+    #>
+    if ($script:TaskCache) {
+        return $script:TaskCache
+    }
+
+    $localCache = New-LocalDirCache -Path (Join-Path -Path $script:EffectiveScriptRoot -ChildPath '.bolt/cache')
+    $script:TaskCache = $localCache
+
+    $config = Get-BoltConfigFile -ScriptRoot $script:EffectiveScriptRoot -TaskDirectory $TaskDirectory
+    if (-not $config['RemoteCacheURL']) {
+        return $script:TaskCache
+    }
+
+    try {
+        $remoteUrl = [string]$config['RemoteCacheURL']
+        if ($remoteUrl -notmatch '^https?://') {
+            throw "RemoteCacheURL must start with http:// or https://"
+        }
+
+        $token = if ($config['RemoteCacheToken']) {
+            Expand-TaskVariables -Value ([string]$config['RemoteCacheToken']) -Environment ([Environment]::GetEnvironmentVariables()) -UndefinedVars Empty
+        }
+        if ($token -and $remoteUrl -match '^http://' -and ([uri]$remoteUrl).Host -notin @('localhost', '127.0.0.1', '::1')) {
+            Write-Warning "RemoteCacheToken is sent without encryption to '$remoteUrl'; use https"
+        }
+
+        $ttl = if ($config['RemoteCacheTTL']) { ConvertFrom-Duration -Duration ([string]$config['RemoteCacheTTL']) } else { [TimeSpan]::Zero }
+
+        $authType = if ($config['RemoteCacheAuth']) { ([string]$config['RemoteCacheAuth']).ToLowerInvariant() } else { 'token' }
+        if ($authType -notin @('token', 'oidc')) {
+            throw "RemoteCacheAuth must be token or oidc"
+        }
+        $oidc = $null
+        if ($authType -eq 'oidc') {
+            $authUrl = [string]$config['RemoteCacheAuthURL']
+            if ($authUrl -notmatch '^https?://') {
+                throw "RemoteCacheAuthURL must start with http:// or https:// when RemoteCacheAuth is oidc"
+            }
+            # The identity token proves who the job is, so it is never sent without encryption
+            if ($authUrl -match '^http://' -and ([uri]$authUrl).Host -notin @('localhost', '127.0.0.1', '::1')) {
+                throw "RemoteCacheAuthURL must use https"
+            }
+            # The cache token it is exchanged for deserves the same protection
+            if ($remoteUrl -match '^http://' -and ([uri]$remoteUrl).Host -notin @('localhost', '127.0.0.1', '::1')) {
+                throw "RemoteCacheURL must use https when RemoteCacheAuth is oidc"
+            }
+
+            $provider = Get-CIIdentityProvider
+            if ($provider) {
+                $oidc = @{ AuthUrl = $authUrl; Provider = $provider; Audience = [string]$config['RemoteCacheAudience'] }
+            } else {
+                Write-Verbose "No CI identity token, so the remote cache uses $(if ($token) { 'RemoteCacheToken' } else { 'no authentication' })"
+            }
+        }
+
+        $script:TaskCache = New-RemoteCache -Url $remoteUrl -Token $token -Oidc $oidc -Ttl $ttl -Fallback $localCache
+    } catch {
+        Write-Warning "Ignoring remote cache settings in bolt.config.json: $_"
+    }
+
+    return $script:TaskCache
+}
+
+function Test-TaskCached {
+    <#
+    .SYNOPSIS
+        Checks whether a task can be skipped because its inputs have not changed
+    .DESCRIPTION
+        A task is cached when it declares INPUTS, -NoCache was not used, the stored
+        manifest has the same key, and every output recorded in the manifest still exists.
+        With -UpdateSnapshot, tasks with a # SNAPSHOT: always run. A task with
+        # OUTPUT_VARS: is cached only when the manifest has all of its variables.
+        This only checks, so -DryRun can call it; a caller that skips the task calls
+        Restore-TaskCacheOutputVars.
+    .OUTPUTS
+        $true when the task can be skipped
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [array]$Arguments = @()
+    )
+
+    if ($TaskInfo.IsCore -or $TaskInfo.Inputs.Count -eq 0 -or $NoCache -or ($UpdateSnapshot -and $TaskInfo.Snapshot)) {
+        return $false
+    }
+
+    $primaryName = $TaskInfo.Names[0]
+    $current = Get-TaskCacheKey -TaskInfo $TaskInfo -Arguments $Arguments -BasePath $script:EffectiveScriptRoot
+    $entry = (Get-TaskCache).Get($primaryName, $current.Key)
+    if (-not $entry) {
+        Write-Verbose "Cache miss for '$primaryName': no previous run"
+        return $false
+    }
+
+    if ($entry.Key -ne $current.Key) {
+        $changedInputs = @($current.Inputs.Keys | Where-Object { $entry.Inputs.$_ -ne $current.Inputs[$_] })
+        Write-Verbose "Cache miss for '$primaryName': inputs changed ($($changedInputs -join ', '))"
+        return $false
+    }
+
+    foreach ($output in $entry.Outputs) {
+        if (-not (Test-Path -LiteralPath (Join-Path -Path $script:EffectiveScriptRoot -ChildPath $output) -PathType Leaf)) {
+            Write-Verbose "Cache miss for '$primaryName': output '$output' is missing"
+            return $false
+        }
+    }
+
+    foreach ($name in $TaskInfo.OutputVars) {
+        if (-not $entry.OutputVars -or $null -eq $entry.OutputVars.$name) {
+            Write-Verbose "Cache miss for '$primaryName': output variable '$name' was not saved"
+            return $false
+        }
+    }
+
+    return $true
+}
+
+function Restore-TaskCacheOutputVars {
+    <#
+    .SYNOPSIS
+        Puts the # OUTPUT_VARS: values saved with a cached task in the shared variable store
+    .DESCRIPTION
+        Called when Test-TaskCached lets a task be skipped, so the tasks that read
+        its variables get the values as if it had run.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [array]$Arguments = @()
+    )
+
+    if ($TaskInfo.OutputVars.Count -eq 0) {
+        return
+    }
+
+    $primaryName = $TaskInfo.Names[0]
+    $current = Get-TaskCacheKey -TaskInfo $TaskInfo -Arguments $Arguments -BasePath $script:EffectiveScriptRoot
+    $entry = (Get-TaskCache).Get($primaryName, $current.Key)
+    foreach ($name in $TaskInfo.OutputVars) {
+        (Get-SharedVariableStore).Set($primaryName, $name, [string]$entry.OutputVars.$name)
+    }
+}
+
+function Save-TaskCache {
+    <#
+    .SYNOPSIS
+        Records a successful task run in the cache
+    .DESCRIPTION
+        The key is computed after the task ran, so a task that rewrites its own inputs
+        (like a formatter) is cached on the next run. The cache keeps # OUTPUT_VARS:
+        values as they are, so a run where one contains a -Secrets value is not saved.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [array]$Arguments = @(),
+
+        [string[]]$Secrets = @()
+    )
+
+    if ($TaskInfo.IsCore -or $TaskInfo.Inputs.Count -eq 0) {
+        return
+    }
+
+    $primaryName = $TaskInfo.Names[0]
+    $current = Get-TaskCacheKey -TaskInfo $TaskInfo -Arguments $Arguments -BasePath $script:EffectiveScriptRoot
+    $outputs = @(
+        foreach ($pattern in $TaskInfo.Outputs) {
+            Resolve-TaskFileGlob -Pattern $pattern -BasePath $script:EffectiveScriptRoot | ForEach-Object {
+                [System.IO.Path]::GetRelativePath($script:EffectiveScriptRoot, $_) -replace '\\', '/'
+            }
+        }
+    ) | Sort-Object -Unique
+
+    # The values a cache hit restores for the tasks that read them
+    $outputVars = [ordered]@{}
+    foreach ($name in $TaskInfo.OutputVars) {
+        $value = (Get-SharedVariableStore).Get($name)
+        if ($null -ne $value) {
+            if ((Protect-SecretText -Text ([string]$value) -Secret $Secrets) -cne [string]$value) {
+                Write-Verbose "Not caching '$primaryName': output variable '$name' contains a secret"
+                return
+            }
+            $outputVars[$name] = $value
+        }
+    }
+
+    try {
+        (Get-TaskCache).Set($primaryName, [ordered]@{
+            Task       = $primaryName
+            Key        = $current.Key
+            Inputs     = $current.Inputs
+            Outputs    = @($outputs)
+            OutputVars = $outputVars
+            CreatedAt  = (Get-Date).ToUniversalTime().ToString('o')
+        })
+    } catch {
+        Write-Warning "Could not save cache entry for '$primaryName': $_"
+    }
+}
+
+function Get-TaskWorkingDirectory {
+    <#
+    .SYNOPSIS
+        Returns the full path of the directory a task runs in
+    .DESCRIPTION
+        Tasks run in the directory of their script unless they declare # WORKDIR:.
+        A relative WORKDIR is resolved from -BaseDir when it was given, and from the
+        project root otherwise. A WORKDIR that is not an existing directory throws an
+        InvalidWorkingDirectory error, and so does one outside the project for a
+        # CONTAINER: task, because only the project is mounted in the container.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo
+    )
+
+    if (-not $TaskInfo.WorkDir) {
+        return [System.IO.Path]::GetDirectoryName($TaskInfo.ScriptPath)
+    }
+
+    $baseDir = if ($script:TaskBaseDir) { $script:TaskBaseDir } else { $script:EffectiveScriptRoot }
+    $path = [System.IO.Path]::GetFullPath([System.IO.Path]::Combine($baseDir, $TaskInfo.WorkDir))
+    if (-not [System.IO.Directory]::Exists($path)) {
+        $exception = [System.IO.DirectoryNotFoundException]::new("Working directory '$($TaskInfo.WorkDir)' does not exist: $path")
+        throw [ErrorRecord]::new($exception, 'InvalidWorkingDirectory', [ErrorCategory]::ObjectNotFound, $TaskInfo.WorkDir)
+    }
+
+    if ($TaskInfo.Container) {
+        $relative = [System.IO.Path]::GetRelativePath($script:EffectiveScriptRoot, $path) -replace '\\', '/'
+        if ($relative -eq '..' -or $relative.StartsWith('../') -or [System.IO.Path]::IsPathRooted($relative)) {
+            $exception = [System.ArgumentException]::new("Working directory '$($TaskInfo.WorkDir)' must be inside the project root for a CONTAINER task: $path")
+            throw [ErrorRecord]::new($exception, 'InvalidWorkingDirectory', [ErrorCategory]::InvalidArgument, $TaskInfo.WorkDir)
+        }
+    }
+
+    return $path
+}
+
+function Get-TaskScriptContent {
+    <#
+    .SYNOPSIS
+        Builds the script that runs a project task with Bolt context injected
+    .DESCRIPTION
+        Validates the task script path, then returns PowerShell source that injects
+        $BoltConfig and the utility functions before dot-sourcing the task script from
+        its own directory. The calling scope must provide $Arguments.
+        Used by Invoke-Task (in-process) and Start-TaskProcess (child process).
+
+        With -ContainerRoot, paths inside the project (the script path, ProjectRoot,
+        TaskDirectoryPath, TaskScriptRoot, GitRoot, and the working directory) are
+        rewritten to the place the project is mounted in the container.
+
+        The script runs in the directory from Get-TaskWorkingDirectory.
+    #>
+    param(
+        [hashtable]$TaskInfo,
+        [string]$TaskName,
+        [string]$ContainerRoot
+    )
+
+    # SECURITY: Validate script path before interpolation (P0 - Path Sanitization)
+    $scriptPath = $TaskInfo.ScriptPath
+
+    # Check for dangerous characters that could enable code injection
+    if ($scriptPath -match '[`$();{}\[\]|&<>]') {
+        throw "Script path contains potentially dangerous characters: $scriptPath"
+    }
+
+    # Validate path is within project directory
+    $fullScriptPath = [System.IO.Path]::GetFullPath($scriptPath)
+    $projectRoot = [System.IO.Path]::GetFullPath($script:EffectiveScriptRoot)
+
+    if (-not $fullScriptPath.StartsWith($projectRoot + [System.IO.Path]::DirectorySeparatorChar, [StringComparison]::OrdinalIgnoreCase) -and $fullScriptPath -ne $projectRoot) {
+        throw "Script path is outside project directory: $scriptPath"
+    }
+
+    # Get utility functions from Bolt
+    $utilities = Get-BoltUtilities
+
+    # Build function definitions for injection
+    $utilityDefinitions = @()
+    foreach ($util in $utilities.GetEnumerator()) {
+        $funcDef = $util.Value.ToString()
+        $utilityDefinitions += "function $($util.Key) { $funcDef }"
+    }
+
+    # Get Bolt configuration with task context
+    $taskScriptRoot = [System.IO.Path]::GetDirectoryName($TaskInfo.ScriptPath)
+    $boltConfig = Get-BoltConfig -ScriptRoot $script:EffectiveScriptRoot -TaskDirectory $TaskDirectory -TaskScriptRoot $taskScriptRoot -TaskName $TaskName
+
+    $taskScriptPath = $TaskInfo.ScriptPath
+    $workingDirectory = Get-TaskWorkingDirectory -TaskInfo $TaskInfo
+    if ($ContainerRoot) {
+        foreach ($property in @('ProjectRoot', 'TaskDirectoryPath', 'TaskScriptRoot', 'GitRoot')) {
+            if ($boltConfig.$property) {
+                $boltConfig.$property = ConvertTo-ContainerPath -Path $boltConfig.$property -ContainerRoot $ContainerRoot
+            }
+        }
+        $taskScriptRoot = $boltConfig.TaskScriptRoot
+        $taskScriptPath = ConvertTo-ContainerPath -Path $taskScriptPath -ContainerRoot $ContainerRoot
+        $workingDirectory = ConvertTo-ContainerPath -Path $workingDirectory -ContainerRoot $ContainerRoot
+    }
+    $workingDirectoryEscaped = $workingDirectory -replace "'", "''"
+
+    # Serialize config to JSON for injection
+    $configJson = $boltConfig | ConvertTo-Json -Depth 10 -Compress
+
+    # Escape single quotes in JSON for PowerShell string literal
+    $configJsonEscaped = $configJson -replace "'", "''"
+
+    $stopwatchConfig = [System.Diagnostics.Stopwatch]::StartNew()
+    Write-Verbose "Configuration prepared for task '$TaskName' in $($stopwatchConfig.ElapsedMilliseconds)ms"
+
+    # Create the complete script that:
+    # 1. Injects BoltConfig object
+    # 2. Defines utility functions
+    # 3. Sets up task context variables
+    # 4. Executes the original task script
+    $scriptContent = @"
+# Injected Bolt configuration
+`$BoltConfig = '$configJsonEscaped' | ConvertFrom-Json
+
+# Injected Bolt utility functions
+$($utilityDefinitions -join "`n")
+
+# Set task context variables
+`$TaskScriptRoot = '$taskScriptRoot'
+
+# Execute the original task script in its working directory
+Push-Location '$workingDirectoryEscaped'
+try {
+    . '$taskScriptPath' @Arguments
+} finally {
+    Pop-Location
+}
+"@
+
+    return $scriptContent
+}
+
+function Get-CleanEnvAllowlist {
+    <#
+    .SYNOPSIS
+        Returns the process variables that -CleanEnv keeps
+    .DESCRIPTION
+        Without these, tools on PATH are not found and pwsh cannot find the home or
+        temp directory (or, on Windows, the system directory). Env and # ENV: values
+        with the same names still override them.
+    .OUTPUTS
+        [string[]]
+    #>
+
+    $names = @('PATH', 'HOME', 'USERPROFILE', 'SystemRoot', 'TEMP', 'TMP', 'TMPDIR')
+    if ($IsWindows) {
+        $names += @('PATHEXT', 'ComSpec')
+    }
+    return $names
+}
+
+function Merge-TaskEnvironment {
+    <#
+    .SYNOPSIS
+        Merges the process, config, and task environment variables for a task
+    .DESCRIPTION
+        Layers the global Env values from bolt.config.json and the task's # ENV: values
+        on top of the parent process environment. Task values override global values.
+
+        ${NAME} in a value is replaced with NAME from the layer below: global values
+        read the parent process environment and task values read the process
+        environment merged with the global values. References are expanded once and
+        never recurse, so PATH=${PATH}:./bin appends to the inherited PATH. Unknown
+        names expand to an empty string.
+
+        With -Clean the parent process variables are left out of the result, except
+        the ones named in -Keep, but ${NAME} still reads them so a task can pass
+        selected variables through.
+    .OUTPUTS
+        Dictionary of environment variable names and values
+    #>
+    param(
+        [System.Collections.IDictionary]$Parent,
+        [System.Collections.IDictionary]$Global,
+        [System.Collections.IDictionary]$Task,
+        [switch]$Clean,
+        [string[]]$Keep = @()
+    )
+
+    # Environment variable names are case-insensitive on Windows only
+    $comparer = if ($IsWindows) { [StringComparer]::OrdinalIgnoreCase } else { [StringComparer]::Ordinal }
+
+    # Replace ${NAME} with the value from the layer below (single pass, no recursion)
+    $expand = {
+        param([string]$Value, $Source)
+        $Value -replace '\$\{([A-Za-z_][A-Za-z0-9_]*)\}', {
+            $resolved = $null
+            if ($Source.TryGetValue($_.Groups[1].Value, [ref]$resolved)) { $resolved } else { '' }
+        }
+    }
+
+    $parentEnv = [System.Collections.Generic.Dictionary[string, string]]::new($comparer)
+    foreach ($key in $Parent.Keys) { $parentEnv[[string]$key] = [string]$Parent[$key] }
+
+    $globalEnv = [System.Collections.Generic.Dictionary[string, string]]::new($comparer)
+    foreach ($key in $Global.Keys) { $globalEnv[[string]$key] = & $expand $Global[$key] $parentEnv }
+
+    $taskSource = [System.Collections.Generic.Dictionary[string, string]]::new($parentEnv, $comparer)
+    foreach ($entry in $globalEnv.GetEnumerator()) { $taskSource[$entry.Key] = $entry.Value }
+
+    $taskEnv = [System.Collections.Generic.Dictionary[string, string]]::new($comparer)
+    foreach ($key in $Task.Keys) { $taskEnv[[string]$key] = & $expand $Task[$key] $taskSource }
+
+    $result = $taskSource
+    if ($Clean) {
+        $result = [System.Collections.Generic.Dictionary[string, string]]::new($comparer)
+        foreach ($name in $Keep) {
+            if ($parentEnv.ContainsKey($name)) { $result[$name] = $parentEnv[$name] }
+        }
+        foreach ($entry in $globalEnv.GetEnumerator()) { $result[$entry.Key] = $entry.Value }
+    }
+    foreach ($entry in $taskEnv.GetEnumerator()) { $result[$entry.Key] = $entry.Value }
+
+    return , $result
+}
+
+function New-SharedVariableStore {
+    <#
+    .SYNOPSIS
+        Creates the store that passes # OUTPUT_VARS: values to later tasks in a run
+    .DESCRIPTION
+        Returns a store object with two methods:
+          Set(taskName, name, value)  records a value a task wrote with ::set-output
+          Get(name)                   returns the value, or $null when no task set it
+        Values holds the values by name and Producers the task that set each one.
+        A later Set of the same name replaces the value.
+    #>
+
+    $store = [PSCustomObject]@{
+        Values    = [ordered]@{}
+        Producers = @{}
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name Set -Value {
+        param([string]$TaskName, [string]$Name, [string]$Value)
+
+        $this.Values[$Name] = $Value
+        $this.Producers[$Name] = $TaskName
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name Get -Value {
+        param([string]$Name)
+
+        if ($this.Values.Contains($Name)) {
+            return $this.Values[$Name]
+        }
+        return $null
+    }
+
+    return $store
+}
+
+function Get-SharedVariableStore {
+    <#
+    .SYNOPSIS
+        Returns the shared variable store of this run, creating it on first use
+    #>
+    if (-not $script:SharedVariableStore) {
+        $script:SharedVariableStore = New-SharedVariableStore
+    }
+    return $script:SharedVariableStore
+}
+
+function ConvertFrom-SetOutputLine {
+    <#
+    .SYNOPSIS
+        Parses a ::set-output name=NAME::value line from a task's standard output
+    .DESCRIPTION
+        The annotation has the GitHub Actions form and must be the whole line. The
+        value is everything after the second '::', and may be empty.
+    .OUTPUTS
+        PSCustomObject with Name and Value, or $null when the line is not an annotation
+    #>
+    param(
+        [AllowEmptyString()]
+        [string]$Line
+    )
+
+    if ($Line -cmatch '^::set-output name=([A-Za-z_][A-Za-z0-9_]*)::(.*)$') {
+        return [PSCustomObject]@{
+            Name  = $Matches[1]
+            Value = $Matches[2]
+        }
+    }
+    return $null
+}
+
+function Publish-TaskOutputVars {
+    <#
+    .SYNOPSIS
+        Stores the # OUTPUT_VARS: values a task set, for the tasks that run after it
+    .DESCRIPTION
+        Declared variables the task did not set are reported with a warning, and the
+        tasks that read them do not get them.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [Parameter(Mandatory = $true)]
+        [string]$TaskName,
+
+        [System.Collections.IDictionary]$Values = @{}
+    )
+
+    $store = Get-SharedVariableStore
+    foreach ($name in $TaskInfo.OutputVars) {
+        if ($Values.Contains($name)) {
+            $store.Set($TaskName, $name, $Values[$name])
+        } else {
+            Write-Warning "Task '$TaskName' declares '$name' in OUTPUT_VARS but did not write ::set-output name=$name::<value>"
+        }
+    }
+}
+
+function Get-TaskInputVarProducers {
+    <#
+    .SYNOPSIS
+        Finds the tasks in a task's dependency chain that declare its # INPUT_VARS:
+    .DESCRIPTION
+        Follows DEPENDS transitively and returns, for each input variable, the
+        primary names of the tasks that list it in # OUTPUT_VARS:. Only these tasks
+        are certain to run before the task, so a value from any other task is not
+        passed on reliably.
+    .OUTPUTS
+        Ordered hashtable of variable name to an array of task names
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [Parameter(Mandatory = $true)]
+        [hashtable]$AllTasks
+    )
+
+    $chain = [ordered]@{}
+    $queue = [System.Collections.Generic.Queue[hashtable]]::new()
+    $queue.Enqueue($TaskInfo)
+    while ($queue.Count -gt 0) {
+        $current = $queue.Dequeue()
+        foreach ($dep in $current.Dependencies) {
+            $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $current.Namespace -Tasks $AllTasks
+            if ($resolvedDep -and -not $chain.Contains($AllTasks[$resolvedDep].Names[0])) {
+                $chain[$AllTasks[$resolvedDep].Names[0]] = $AllTasks[$resolvedDep]
+                $queue.Enqueue($AllTasks[$resolvedDep])
+            }
+        }
+    }
+
+    $producers = [ordered]@{}
+    foreach ($name in $TaskInfo.InputVars) {
+        $producers[$name] = @($chain.Keys | Where-Object { $chain[$_].OutputVars -ccontains $name })
+    }
+    return $producers
+}
+
+function Get-TaskEnvironment {
+    <#
+    .SYNOPSIS
+        Returns the environment variables a project task runs with
+    .DESCRIPTION
+        Reads the Env section of bolt.config.json and passes it to Merge-TaskEnvironment
+        with the task's # ENV: values. Honors -CleanEnv, which keeps the variables from
+        Get-CleanEnvAllowlist. With -DeclaredOnly only the config and task variables
+        are returned, without the allowlist. With -IncludeParent
+        the parent process variables are kept even with -CleanEnv. The task's
+        # INPUT_VARS: that an earlier task has set in this run are added last.
+    #>
+    param(
+        [hashtable]$TaskInfo,
+
+        [switch]$DeclaredOnly,
+
+        [switch]$IncludeParent
+    )
+
+    $config = Get-BoltConfigFile -ScriptRoot $script:EffectiveScriptRoot -TaskDirectory $TaskDirectory
+    $globalEnv = $null
+    if ($config.ContainsKey('Env')) {
+        if ($config['Env'] -is [System.Collections.IDictionary]) {
+            $globalEnv = $config['Env']
+        } else {
+            Write-Warning "Ignoring 'Env' in bolt.config.json because it is not an object"
+        }
+    }
+
+    $keep = if ($DeclaredOnly) { @() } else { Get-CleanEnvAllowlist }
+    $environment = Merge-TaskEnvironment -Parent ([Environment]::GetEnvironmentVariables()) -Global $globalEnv -Task $TaskInfo.Env -Clean:(($CleanEnv -and -not $IncludeParent) -or $DeclaredOnly) -Keep $keep
+    foreach ($name in $TaskInfo.InputVars) {
+        $value = (Get-SharedVariableStore).Get($name)
+        if ($null -ne $value) {
+            $environment[$name] = $value
+        }
+    }
+    return , $environment
+}
+
+function Set-TaskEnvironment {
+    <#
+    .SYNOPSIS
+        Sets environment variables in the current process
+    .DESCRIPTION
+        Sets each variable that differs from its current value and returns the previous
+        values, so passing the result back to Set-TaskEnvironment restores them.
+        A null value removes the variable.
+    #>
+    param(
+        [System.Collections.IDictionary]$Environment
+    )
+
+    $previous = @{}
+    foreach ($key in $Environment.Keys) {
+        $current = [Environment]::GetEnvironmentVariable($key)
+        if ($current -cne $Environment[$key]) {
+            $previous[$key] = $current
+            [Environment]::SetEnvironmentVariable($key, $Environment[$key])
+        }
+    }
+
+    return $previous
+}
+
+function Invoke-SecretCommand {
+    <#
+    .SYNOPSIS
+        Runs a keychain command line tool with optional standard input
+    .DESCRIPTION
+        Secret values are only passed on standard input, never as arguments, so they
+        do not show up in the process list.
+    .OUTPUTS
+        PSCustomObject with ExitCode, Output, and Error
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$FilePath,
+
+        [string[]]$ArgumentList = @(),
+
+        [string]$InputText = $null
+    )
+
+    $startInfo = [System.Diagnostics.ProcessStartInfo]::new($FilePath)
+    foreach ($argument in $ArgumentList) {
+        $startInfo.ArgumentList.Add($argument)
+    }
+    $startInfo.UseShellExecute = $false
+    $startInfo.RedirectStandardInput = $true
+    $startInfo.RedirectStandardOutput = $true
+    $startInfo.RedirectStandardError = $true
+    $startInfo.StandardOutputEncoding = [System.Text.Encoding]::UTF8
+    $startInfo.StandardErrorEncoding = [System.Text.Encoding]::UTF8
+
+    $process = [System.Diagnostics.Process]::Start($startInfo)
+    try {
+        $errorRead = $process.StandardError.ReadToEndAsync()
+        if ($InputText) {
+            $process.StandardInput.Write($InputText)
+        }
+        $process.StandardInput.Close()
+        $output = $process.StandardOutput.ReadToEnd()
+        $process.WaitForExit()
+
+        return [PSCustomObject]@{
+            ExitCode = $process.ExitCode
+            Output   = $output
+            Error    = $errorRead.Result.Trim()
+        }
+    } finally {
+        $process.Dispose()
+    }
+}
+
+function New-SecretToolStore {
+    <#
+    .SYNOPSIS
+        Creates a secret store backed by the Secret Service keyring on Linux (secret-tool)
+    .DESCRIPTION
+        Returns a store object with three methods, which is the contract every secret
+        store follows:
+          Get(key)           returns the value or $null when it is not set
+          Set(key, value)    stores the value, replacing an existing one
+          Delete(key)        removes the value; returns $false when it was not set
+
+        Values are stored with the attributes service=<Service> and account=<key>.
+    #>
+    param(
+        [string]$Service = 'bolt',
+
+        [Parameter(Mandatory = $true)]
+        [string]$Tool
+    )
+
+    $store = [PSCustomObject]@{
+        Type    = 'secret-tool'
+        Service = $Service
+        Tool    = $Tool
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name Get -Value {
+        param([string]$Key)
+
+        $result = Invoke-SecretCommand -FilePath $this.Tool -ArgumentList @('lookup', 'service', $this.Service, 'account', $Key)
+        # lookup exits with 1 and prints nothing when there is no such secret
+        if ($result.ExitCode -ne 0) {
+            if ($result.Error) {
+                throw "secret-tool lookup failed: $($result.Error)"
+            }
+            return $null
+        }
+        return $result.Output
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name Set -Value {
+        param([string]$Key, [string]$Value)
+
+        $result = Invoke-SecretCommand -FilePath $this.Tool -ArgumentList @('store', "--label=$($this.Service): $Key", 'service', $this.Service, 'account', $Key) -InputText $Value
+        if ($result.ExitCode -ne 0) {
+            throw "secret-tool store failed: $($result.Error)"
+        }
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name Delete -Value {
+        param([string]$Key)
+
+        if ($null -eq $this.Get($Key)) {
+            return $false
+        }
+        $result = Invoke-SecretCommand -FilePath $this.Tool -ArgumentList @('clear', 'service', $this.Service, 'account', $Key)
+        if ($result.ExitCode -ne 0) {
+            throw "secret-tool clear failed: $($result.Error)"
+        }
+        return $true
+    }
+
+    return $store
+}
+
+function New-MacKeychainStore {
+    <#
+    .SYNOPSIS
+        Creates a secret store backed by the macOS login keychain (security)
+    .DESCRIPTION
+        Follows the Get/Set/Delete contract of New-SecretToolStore. Values are generic
+        passwords with service <Service> and account <key>. They are written through
+        security -i on standard input, base64 encoded so any value survives its quoting.
+    #>
+    param(
+        [string]$Service = 'bolt',
+
+        [Parameter(Mandatory = $true)]
+        [string]$Tool
+    )
+
+    $store = [PSCustomObject]@{
+        Type    = 'keychain'
+        Service = $Service
+        Tool    = $Tool
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name Get -Value {
+        param([string]$Key)
+
+        $result = Invoke-SecretCommand -FilePath $this.Tool -ArgumentList @('find-generic-password', '-s', $this.Service, '-a', $Key, '-w')
+        # 44 is errSecItemNotFound
+        if ($result.ExitCode -eq 44) {
+            return $null
+        }
+        if ($result.ExitCode -ne 0) {
+            throw "security find-generic-password failed: $($result.Error)"
+        }
+
+        $stored = $result.Output.TrimEnd("`r`n".ToCharArray())
+        if ($stored.StartsWith('bolt-base64:')) {
+            return [System.Text.Encoding]::UTF8.GetString([Convert]::FromBase64String($stored.Substring(12)))
+        }
+        return $stored
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name Set -Value {
+        param([string]$Key, [string]$Value)
+
+        $encoded = 'bolt-base64:' + [Convert]::ToBase64String([System.Text.Encoding]::UTF8.GetBytes($Value))
+        $result = Invoke-SecretCommand -FilePath $this.Tool -ArgumentList @('-i') -InputText "add-generic-password -U -s $($this.Service) -a $Key -w $encoded`n"
+        if ($result.ExitCode -ne 0 -or $result.Error) {
+            throw "security add-generic-password failed: $($result.Error)"
+        }
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name Delete -Value {
+        param([string]$Key)
+
+        $result = Invoke-SecretCommand -FilePath $this.Tool -ArgumentList @('delete-generic-password', '-s', $this.Service, '-a', $Key)
+        if ($result.ExitCode -eq 44) {
+            return $false
+        }
+        if ($result.ExitCode -ne 0) {
+            throw "security delete-generic-password failed: $($result.Error)"
+        }
+        return $true
+    }
+
+    return $store
+}
+
+function New-WindowsCredentialStore {
+    <#
+    .SYNOPSIS
+        Creates a secret store backed by the Windows Credential Manager
+    .DESCRIPTION
+        Follows the Get/Set/Delete contract of New-SecretToolStore. Values are generic
+        credentials named <Service>:<key>, read and written with CredReadW, CredWriteW,
+        and CredDeleteW from advapi32.
+    #>
+    param(
+        [string]$Service = 'bolt'
+    )
+
+    if (-not ('Bolt.CredentialManager' -as [type])) {
+        Add-Type -TypeDefinition @'
+using System;
+using System.ComponentModel;
+using System.Runtime.InteropServices;
+using System.Text;
+
+namespace Bolt
+{
+    public static class CredentialManager
+    {
+        private const int CredTypeGeneric = 1;
+        private const int CredPersistLocalMachine = 2;
+        private const int ErrorNotFound = 1168;
+
+        [StructLayout(LayoutKind.Sequential, CharSet = CharSet.Unicode)]
+        private struct Credential
+        {
+            public int Flags;
+            public int Type;
+            public string TargetName;
+            public string Comment;
+            public System.Runtime.InteropServices.ComTypes.FILETIME LastWritten;
+            public int CredentialBlobSize;
+            public IntPtr CredentialBlob;
+            public int Persist;
+            public int AttributeCount;
+            public IntPtr Attributes;
+            public string TargetAlias;
+            public string UserName;
+        }
+
+        [DllImport("advapi32.dll", CharSet = CharSet.Unicode, SetLastError = true)]
+        private static extern bool CredWriteW(ref Credential credential, int flags);
+
+        [DllImport("advapi32.dll", CharSet = CharSet.Unicode, SetLastError = true)]
+        private static extern bool CredReadW(string target, int type, int flags, out IntPtr credential);
+
+        [DllImport("advapi32.dll", CharSet = CharSet.Unicode, SetLastError = true)]
+        private static extern bool CredDeleteW(string target, int type, int flags);
+
+        [DllImport("advapi32.dll")]
+        private static extern void CredFree(IntPtr buffer);
+
+        public static string Read(string target)
+        {
+            IntPtr pointer;
+            if (!CredReadW(target, CredTypeGeneric, 0, out pointer))
+            {
+                int error = Marshal.GetLastWin32Error();
+                if (error == ErrorNotFound) { return null; }
+                throw new Win32Exception(error);
+            }
+            try
+            {
+                Credential credential = Marshal.PtrToStructure<Credential>(pointer);
+                if (credential.CredentialBlobSize == 0) { return string.Empty; }
+                return Marshal.PtrToStringUni(credential.CredentialBlob, credential.CredentialBlobSize / 2);
+            }
+            finally
+            {
+                CredFree(pointer);
+            }
+        }
+
+        public static void Write(string target, string userName, string secret)
+        {
+            byte[] blob = Encoding.Unicode.GetBytes(secret);
+            Credential credential = new Credential
+            {
+                Type = CredTypeGeneric,
+                TargetName = target,
+                UserName = userName,
+                CredentialBlobSize = blob.Length,
+                CredentialBlob = Marshal.AllocHGlobal(Math.Max(blob.Length, 1)),
+                Persist = CredPersistLocalMachine
+            };
+            try
+            {
+                Marshal.Copy(blob, 0, credential.CredentialBlob, blob.Length);
+                if (!CredWriteW(ref credential, 0)) { throw new Win32Exception(Marshal.GetLastWin32Error()); }
+            }
+            finally
+            {
+                Marshal.FreeHGlobal(credential.CredentialBlob);
+            }
+        }
+
+        public static bool Delete(string target)
+        {
+            if (CredDeleteW(target, CredTypeGeneric, 0)) { return true; }
+            int error = Marshal.GetLastWin32Error();
+            if (error == ErrorNotFound) { return false; }
+            throw new Win32Exception(error);
+        }
+    }
+}
+'@
+    }
+
+    $store = [PSCustomObject]@{
+        Type    = 'credential-manager'
+        Service = $Service
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name Get -Value {
+        param([string]$Key)
+
+        return [Bolt.CredentialManager]::Read("$($this.Service):$Key")
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name Set -Value {
+        param([string]$Key, [string]$Value)
+
+        [Bolt.CredentialManager]::Write("$($this.Service):$Key", $Key, $Value)
+    }
+
+    $store | Add-Member -MemberType ScriptMethod -Name Delete -Value {
+        param([string]$Key)
+
+        return [Bolt.CredentialManager]::Delete("$($this.Service):$Key")
+    }
+
+    return $store
+}
+
+function Get-SecretStore {
+    <#
+    .SYNOPSIS
+        Returns the OS keychain used for -Secret and # SECRET_ENV:
+    .DESCRIPTION
+        Uses the Windows Credential Manager, the macOS keychain, or the Secret Service
+        keyring through secret-tool on Linux. The store is created once per run and
+        kept in $script:SecretStore, so tests can replace it with any object that has
+        the Get/Set/Delete methods.
+
+        Throws a SecretStoreUnavailable error when the keychain tool is not installed.
+    #>
+    if ($script:SecretStore) {
+        return $script:SecretStore
+    }
+
+    if ($IsWindows) {
+        $script:SecretStore = New-WindowsCredentialStore
+        return $script:SecretStore
+    }
+
+    $toolName = if ($IsMacOS) { 'security' } else { 'secret-tool' }
+    $tool = Get-Command -Name $toolName -CommandType Application -ErrorAction SilentlyContinue | Select-Object -First 1
+    if (-not $tool) {
+        $hint = if ($IsMacOS) { '' } else { ' Install libsecret-tools (Debian, Ubuntu) or libsecret (Fedora, Arch).' }
+        $exception = [System.InvalidOperationException]::new("Secrets need $toolName, but it was not found on PATH.$hint")
+        throw [ErrorRecord]::new($exception, 'SecretStoreUnavailable', [ErrorCategory]::ObjectNotFound, $toolName)
+    }
+
+    $script:SecretStore = if ($IsMacOS) { New-MacKeychainStore -Tool $tool.Source } else { New-SecretToolStore -Tool $tool.Source }
+    return $script:SecretStore
+}
+
+function Get-TaskSecretEnvironment {
+    <#
+    .SYNOPSIS
+        Reads the secrets named by a task's # SECRET_ENV: from the OS keychain
+    .DESCRIPTION
+        Each name is both the keychain key and the environment variable the value is
+        set in. Secrets are read when the task starts and are only added to the task
+        process environment: they are not part of Get-TaskEnvironment, so they never
+        appear in -DryRun plans, structured logs, or the run summary.
+
+        Throws a SecretNotFound error when a secret is not set. The error does not name
+        the secret, since it ends up in the run summary; the name is only shown on the
+        console.
+    .OUTPUTS
+        Ordered dictionary of name to value (empty when the task has no # SECRET_ENV:)
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [string]$TaskName = $TaskInfo.Names[0]
+    )
+
+    $secrets = [ordered]@{}
+    if ($TaskInfo.SecretEnv.Count -eq 0) {
+        return , $secrets
+    }
+
+    $store = Get-SecretStore
+    foreach ($name in $TaskInfo.SecretEnv) {
+        $value = $store.Get($name)
+        if ($null -eq $value) {
+            Write-Host "Task '$TaskName' needs secret '$name'. Store it with: .\bolt.ps1 -Secret Set -Key $name" -ForegroundColor Yellow
+            $exception = [System.InvalidOperationException]::new("Task '$TaskName' needs a # SECRET_ENV: secret that is not set in the OS keychain")
+            throw [ErrorRecord]::new($exception, 'SecretNotFound', [ErrorCategory]::ObjectNotFound, $TaskName)
+        }
+        $secrets[$name] = $value
+    }
+
+    return , $secrets
+}
+
+function Protect-SecretText {
+    <#
+    .SYNOPSIS
+        Replaces secret values in a line of task output with ***
+    #>
+    param(
+        [AllowEmptyString()]
+        [string]$Text,
+
+        [string[]]$Secret = @()
+    )
+
+    # Longest first, so a secret that contains another is masked whole
+    foreach ($value in ($Secret | Where-Object { $_ } | Sort-Object -Property Length -Descending)) {
+        $Text = $Text.Replace($value, '***')
+    }
+    return $Text
+}
+
+function Expand-TaskVariables {
+    <#
+    .SYNOPSIS
+        Replaces ${NAME} placeholders in a string with environment variable values
+    .DESCRIPTION
+        Looks up each ${NAME} in -Environment (usually from Get-TaskEnvironment).
+        A value that contains placeholders is expanded as well, up to 10 levels deep;
+        deeper nesting throws a CircularReference error.
+
+        -UndefinedVars controls names that are not in the environment: Error (default)
+        throws an UndefinedVariable error, Empty replaces them with an empty string, and
+        Passthrough leaves the ${NAME} text unchanged.
+    .OUTPUTS
+        [string]
+    #>
+    param(
+        [string]$Value,
+
+        [System.Collections.IDictionary]$Environment,
+
+        [ValidateSet('Error', 'Empty', 'Passthrough')]
+        [string]$UndefinedVars = 'Error',
+
+        [string[]]$Chain = @()
+    )
+
+    $maxDepth = 10
+    $expanded = [System.Text.StringBuilder]::new()
+    $position = 0
+
+    foreach ($match in [regex]::Matches($Value, '\$\{([A-Za-z_][A-Za-z0-9_]*)\}')) {
+        [void]$expanded.Append($Value, $position, $match.Index - $position)
+        $position = $match.Index + $match.Length
+        $name = $match.Groups[1].Value
+
+        if (-not $Environment.Contains($name)) {
+            switch ($UndefinedVars) {
+                'Error' {
+                    $exception = [System.InvalidOperationException]::new("Undefined variable '`${$name}'")
+                    throw [ErrorRecord]::new($exception, 'UndefinedVariable', [ErrorCategory]::ObjectNotFound, $name)
+                }
+                'Passthrough' { [void]$expanded.Append($match.Value) }
+            }
+            continue
+        }
+
+        if ($Chain.Count -ge $maxDepth) {
+            $exception = [System.InvalidOperationException]::new("Variable references are nested more than $maxDepth levels deep: $(($Chain + $name) -join ' -> ')")
+            throw [ErrorRecord]::new($exception, 'CircularReference', [ErrorCategory]::InvalidData, $name)
+        }
+
+        [void]$expanded.Append((Expand-TaskVariables -Value ([string]$Environment[$name]) -Environment $Environment -UndefinedVars $UndefinedVars -Chain ($Chain + $name)))
+    }
+
+    [void]$expanded.Append($Value, $position, $Value.Length - $position)
+    return $expanded.ToString()
+}
+
+function ConvertFrom-TaskCondition {
+    <#
+    .SYNOPSIS
+        Parses a # WHEN: expression into a condition tree
+    .DESCRIPTION
+        Expressions are parsed by this small grammar and never run as PowerShell:
+
+          or      = and { '||' and }
+          and     = unary { '&&' unary }
+          unary   = '!' unary | primary
+          primary = '(' or ')' | value [ ('==' | '!=') value ]
+          value   = env.NAME | exists('path') | 'text' | "text" | true | false
+
+        Each node is a hashtable with a Kind: Or, And, Not, Equal, NotEqual, Env,
+        Exists, or Literal. Invalid expressions throw an InvalidCondition error that
+        names the column of the first token that does not fit.
+    .EXAMPLE
+        ConvertFrom-TaskCondition -Expression "env.CI == 'true' && exists('go.sum')"
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Expression
+    )
+
+    $fail = {
+        param([string]$Message)
+
+        $exception = [System.FormatException]::new("Invalid condition '$Expression': $Message")
+        throw [System.Management.Automation.ErrorRecord]::new($exception, 'InvalidCondition', [System.Management.Automation.ErrorCategory]::InvalidArgument, $Expression)
+    }
+
+    # Split the expression into tokens; anything else is an error
+    $tokenRegex = [regex]::new('\G(?:(?<space>\s+)|(?<op>&&|\|\||==|!=|!|\(|\)|,)|(?<string>''[^'']*''|"[^"]*")|(?<word>[A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)?))')
+    $tokens = [System.Collections.Generic.List[hashtable]]::new()
+    $position = 0
+    while ($position -lt $Expression.Length) {
+        $match = $tokenRegex.Match($Expression, $position)
+        if (-not $match.Success) {
+            & $fail "unexpected '$($Expression[$position])' at column $($position + 1)"
+        }
+        foreach ($type in @('op', 'string', 'word')) {
+            if ($match.Groups[$type].Success) {
+                $tokens.Add(@{ Type = $type; Text = $match.Value; Column = $position + 1 })
+            }
+        }
+        $position += $match.Length
+    }
+    $tokens.Add(@{ Type = 'end'; Text = ''; Column = $Expression.Length + 1 })
+
+    $state = @{ Index = 0 }
+    $next = {
+        $token = $tokens[$state.Index]
+        if ($token.Type -ne 'end') {
+            $state.Index++
+        }
+        return $token
+    }
+    $describe = {
+        param($Token)
+
+        if ($Token.Type -eq 'end') { 'end of expression' } else { "'$($Token.Text)' at column $($Token.Column)" }
+    }
+    $expect = {
+        param([string]$Text)
+
+        $token = & $next
+        if ($token.Type -ne 'op' -or $token.Text -ne $Text) {
+            & $fail "expected '$Text' but found $(& $describe $token)"
+        }
+    }
+
+    # Recursive descent, one script block per grammar rule
+    $parseValue = {
+        $token = & $next
+        if ($token.Type -eq 'string') {
+            return @{ Kind = 'Literal'; Value = $token.Text.Substring(1, $token.Text.Length - 2) }
+        }
+        if ($token.Type -eq 'word') {
+            if ($token.Text -cin @('true', 'false')) {
+                return @{ Kind = 'Literal'; Value = $token.Text -ceq 'true' }
+            }
+            if ($token.Text -cmatch '^env\.([A-Za-z_][A-Za-z0-9_]*)$') {
+                return @{ Kind = 'Env'; Name = $Matches[1] }
+            }
+            if ($token.Text -ceq 'exists') {
+                & $expect '('
+                $pathToken = & $next
+                if ($pathToken.Type -ne 'string') {
+                    & $fail "exists() takes one quoted path but found $(& $describe $pathToken)"
+                }
+                & $expect ')'
+                return @{ Kind = 'Exists'; Path = $pathToken.Text.Substring(1, $pathToken.Text.Length - 2) }
+            }
+        }
+        & $fail "unexpected $(& $describe $token) (expected env.NAME, exists('path'), a quoted string, true, or false)"
+    }
+    $parsePrimary = {
+        $token = $tokens[$state.Index]
+        if ($token.Type -eq 'op' -and $token.Text -eq '(') {
+            $null = & $next
+            $node = & $parseOr
+            & $expect ')'
+            return $node
+        }
+
+        $left = & $parseValue
+        $operator = $tokens[$state.Index]
+        if ($operator.Type -eq 'op' -and $operator.Text -in @('==', '!=')) {
+            $null = & $next
+            $right = & $parseValue
+            return @{ Kind = $(if ($operator.Text -eq '==') { 'Equal' } else { 'NotEqual' }); Left = $left; Right = $right }
+        }
+        return $left
+    }
+    $parseUnary = {
+        $token = $tokens[$state.Index]
+        if ($token.Type -eq 'op' -and $token.Text -eq '!') {
+            $null = & $next
+            return @{ Kind = 'Not'; Operand = (& $parseUnary) }
+        }
+        return (& $parsePrimary)
+    }
+    $parseAnd = {
+        $node = & $parseUnary
+        while ($tokens[$state.Index].Type -eq 'op' -and $tokens[$state.Index].Text -eq '&&') {
+            $null = & $next
+            $node = @{ Kind = 'And'; Left = $node; Right = (& $parseUnary) }
+        }
+        return $node
+    }
+    $parseOr = {
+        $node = & $parseAnd
+        while ($tokens[$state.Index].Type -eq 'op' -and $tokens[$state.Index].Text -eq '||') {
+            $null = & $next
+            $node = @{ Kind = 'Or'; Left = $node; Right = (& $parseAnd) }
+        }
+        return $node
+    }
+
+    $tree = & $parseOr
+    $rest = $tokens[$state.Index]
+    if ($rest.Type -ne 'end') {
+        & $fail "unexpected $(& $describe $rest)"
+    }
+
+    return $tree
+}
+
+function Test-TaskCondition {
+    <#
+    .SYNOPSIS
+        Evaluates a # WHEN: expression
+    .DESCRIPTION
+        Parses -Expression with ConvertFrom-TaskCondition and evaluates it. env.NAME is
+        the value of NAME in -Environment, or an empty string when it is not set.
+        exists('path') is true when the file or directory exists, relative to -Root.
+        == and != compare text case-sensitively, with true and false as 'true' and
+        'false'. Where a condition is expected, a text value is true when it is not
+        empty. An empty expression is always true.
+    .OUTPUTS
+        [bool]
+    #>
+    param(
+        [AllowEmptyString()]
+        [string]$Expression = '',
+
+        [System.Collections.IDictionary]$Environment = @{},
+
+        [string]$Root = $script:EffectiveScriptRoot
+    )
+
+    if ([string]::IsNullOrWhiteSpace($Expression)) {
+        return $true
+    }
+
+    $condition = ConvertFrom-TaskCondition -Expression $Expression
+
+    $toText = {
+        param($Value)
+
+        if ($Value -is [bool]) { "$Value".ToLowerInvariant() } else { [string]$Value }
+    }
+    $toBool = {
+        param($Value)
+
+        if ($Value -is [bool]) { $Value } else { -not [string]::IsNullOrEmpty($Value) }
+    }
+    $evaluate = {
+        param([hashtable]$Node)
+
+        switch ($Node.Kind) {
+            'Literal' { return $Node.Value }
+            'Env' { return [string]$Environment[$Node.Name] }
+            'Exists' { return (Test-Path -LiteralPath ([System.IO.Path]::Combine($Root, $Node.Path))) }
+            'Not' { return -not (& $toBool (& $evaluate $Node.Operand)) }
+            'And' { return (& $toBool (& $evaluate $Node.Left)) -and (& $toBool (& $evaluate $Node.Right)) }
+            'Or' { return (& $toBool (& $evaluate $Node.Left)) -or (& $toBool (& $evaluate $Node.Right)) }
+            'Equal' { return (& $toText (& $evaluate $Node.Left)) -ceq (& $toText (& $evaluate $Node.Right)) }
+            'NotEqual' { return (& $toText (& $evaluate $Node.Left)) -cne (& $toText (& $evaluate $Node.Right)) }
+        }
+    }
+
+    return [bool](& $toBool (& $evaluate $condition))
+}
+
+function Get-BoltPlatform {
+    <#
+    .SYNOPSIS
+        Returns the host platform as Go GOOS and GOARCH names for # PLATFORM:
+    .DESCRIPTION
+        The operating system is windows, darwin, linux, or freebsd, and the
+        architecture is the GOARCH name of the processor, like amd64 or arm64. This is
+        the machine Bolt runs on, not a GOOS or GOARCH set for cross-compiling.
+    .OUTPUTS
+        PSCustomObject with Os and Arch
+    #>
+
+    $os = if ($IsWindows) { 'windows' } elseif ($IsMacOS) { 'darwin' } elseif ($IsLinux) { 'linux' }
+    elseif ([System.Runtime.InteropServices.RuntimeInformation]::IsOSPlatform([System.Runtime.InteropServices.OSPlatform]::FreeBSD)) { 'freebsd' }
+    else { 'unknown' }
+
+    $arch = switch ([string][System.Runtime.InteropServices.RuntimeInformation]::OSArchitecture) {
+        'X64' { 'amd64' }
+        'X86' { '386' }
+        'Arm' { 'arm' }
+        'Armv6' { 'arm' }
+        'Arm64' { 'arm64' }
+        'LoongArch64' { 'loong64' }
+        'Ppc64le' { 'ppc64le' }
+        'RiscV64' { 'riscv64' }
+        'S390x' { 's390x' }
+        'Wasm' { 'wasm' }
+        default { $_.ToLowerInvariant() }
+    }
+
+    return [PSCustomObject]@{
+        Os   = $os
+        Arch = $arch
+    }
+}
+
+function ConvertFrom-TaskPlatform {
+    <#
+    .SYNOPSIS
+        Parses one # PLATFORM: entry, like linux/amd64 or darwin
+    .DESCRIPTION
+        An entry is a GOOS name, optionally followed by /GOARCH. Names are the ones
+        'go tool dist list' prints. An unknown name throws an InvalidPlatform error.
+    .OUTPUTS
+        PSCustomObject with Os and Arch ($null when the entry has no architecture)
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [AllowEmptyString()]
+        [string]$Entry
+    )
+
+    $knownOs = @('aix', 'android', 'darwin', 'dragonfly', 'freebsd', 'illumos', 'ios', 'js', 'linux', 'netbsd', 'openbsd', 'plan9', 'solaris', 'wasip1', 'windows')
+    $knownArch = @('386', 'amd64', 'arm', 'arm64', 'loong64', 'mips', 'mips64', 'mips64le', 'mipsle', 'ppc64', 'ppc64le', 'riscv64', 's390x', 'wasm')
+
+    $fail = {
+        param([string]$Message)
+
+        $exception = [System.FormatException]::new($Message)
+        throw [System.Management.Automation.ErrorRecord]::new($exception, 'InvalidPlatform', [System.Management.Automation.ErrorCategory]::InvalidArgument, $Entry)
+    }
+
+    $parts = $Entry.Trim() -split '/'
+    if ($parts.Count -gt 2) {
+        & $fail "Platform '$Entry' must be GOOS or GOOS/GOARCH"
+    }
+    if ($parts[0] -cnotin $knownOs) {
+        & $fail "Platform '$Entry' has an unknown GOOS '$($parts[0])' (expected one of: $($knownOs -join ', '))"
+    }
+    if ($parts.Count -eq 2 -and $parts[1] -cnotin $knownArch) {
+        & $fail "Platform '$Entry' has an unknown GOARCH '$($parts[1])' (expected one of: $($knownArch -join ', '))"
+    }
+
+    return [PSCustomObject]@{
+        Os   = $parts[0]
+        Arch = if ($parts.Count -eq 2) { $parts[1] } else { $null }
+    }
+}
+
+function Test-TaskPlatform {
+    <#
+    .SYNOPSIS
+        Tests whether a task's # PLATFORM: list includes a platform
+    .DESCRIPTION
+        An entry with only a GOOS matches every architecture of that operating
+        system. An empty list matches every platform.
+    .PARAMETER Platform
+        The # PLATFORM: entries, like linux/amd64 or darwin
+    .PARAMETER Current
+        The platform to test, as returned by Get-BoltPlatform (defaults to the host)
+    .OUTPUTS
+        [bool]
+    #>
+    param(
+        [string[]]$Platform = @(),
+
+        [PSCustomObject]$Current = (Get-BoltPlatform)
+    )
+
+    if ($Platform.Count -eq 0) {
+        return $true
+    }
+
+    foreach ($entry in $Platform) {
+        $parsed = ConvertFrom-TaskPlatform -Entry $entry
+        if ($parsed.Os -eq $Current.Os -and (-not $parsed.Arch -or $parsed.Arch -eq $Current.Arch)) {
+            return $true
+        }
+    }
+    return $false
+}
+
+function ConvertFrom-Duration {
+    <#
+    .SYNOPSIS
+        Parses a duration string such as '30s', '1m30s', '500ms', or '1.5h'
+    .DESCRIPTION
+        Uses the same format as Go's time.ParseDuration: one or more decimal numbers,
+        each followed by a unit (ns, us, ms, s, m, h). '0' is also accepted.
+    .OUTPUTS
+        [TimeSpan]
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Duration
+    )
+
+    $text = $Duration.Trim()
+    if ($text -eq '0') {
+        return [TimeSpan]::Zero
+    }
+
+    if ($text -notmatch '^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+$') {
+        throw "Invalid duration '$Duration' (expected a value like 30s, 5m, 1h30m, or 500ms)"
+    }
+
+    $unitMilliseconds = @{ 'ns' = 0.000001; 'us' = 0.001; 'µs' = 0.001; 'ms' = 1; 's' = 1000; 'm' = 60000; 'h' = 3600000 }
+    $totalMilliseconds = 0.0
+    foreach ($part in [regex]::Matches($text, '(\d+(?:\.\d+)?)(ns|us|µs|ms|s|m|h)')) {
+        $totalMilliseconds += [double]::Parse($part.Groups[1].Value, [System.Globalization.CultureInfo]::InvariantCulture) * $unitMilliseconds[$part.Groups[2].Value]
+    }
+
+    return [TimeSpan]::FromMilliseconds($totalMilliseconds)
+}
+
+function Get-TaskTimeoutMs {
+    <#
+    .SYNOPSIS
+        Returns a task's # TIMEOUT: in milliseconds, or 0 when it has none
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo
+    )
+
+    if (-not $TaskInfo.Timeout) {
+        return 0
+    }
+
+    $timeout = ConvertFrom-Duration -Duration $TaskInfo.Timeout
+    if ($timeout -le [TimeSpan]::Zero) {
+        throw "Timeout must be greater than zero: $($TaskInfo.Timeout)"
+    }
+    return [long]$timeout.TotalMilliseconds
+}
+
+function Get-TaskRetryPolicy {
+    <#
+    .SYNOPSIS
+        Parses a task's # RETRY: metadata
+    .DESCRIPTION
+        Accepts a number of attempts, or comma-separated settings like
+        'attempts=3, delay=2s, backoff=1.5'. Attempts is the total number of runs
+        (1 to 10), delay is a duration (default 1s), and backoff multiplies the delay
+        after each failed attempt (default 2). A task without # RETRY: runs once.
+    .OUTPUTS
+        PSCustomObject with Attempts, Delay ([TimeSpan]), and BackoffFactor
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo
+    )
+
+    $policy = [PSCustomObject]@{
+        Attempts      = 1
+        Delay         = [TimeSpan]::FromSeconds(1)
+        BackoffFactor = 2.0
+    }
+
+    if (-not $TaskInfo.Retry) {
+        return $policy
+    }
+
+    foreach ($setting in ($TaskInfo.Retry -split ',' | ForEach-Object { $_.Trim() } | Where-Object { $_ })) {
+        if ($setting -match '^\d+$') {
+            $settingName = 'attempts'
+            $settingValue = $setting
+        } elseif ($setting -match '^([A-Za-z_]+)\s*=\s*(.+)$') {
+            $settingName = $Matches[1].ToLowerInvariant()
+            $settingValue = $Matches[2].Trim()
+        } else {
+            throw "Invalid RETRY setting '$setting' (expected attempts=<n>, delay=<duration>, or backoff=<factor>)"
+        }
+
+        switch ($settingName) {
+            'attempts' {
+                if ($settingValue -notmatch '^\d+$' -or [int]$settingValue -lt 1 -or [int]$settingValue -gt 10) {
+                    throw "RETRY attempts must be a number from 1 to 10: $settingValue"
+                }
+                $policy.Attempts = [int]$settingValue
+            }
+            'delay' {
+                $policy.Delay = ConvertFrom-Duration -Duration $settingValue
+            }
+            'backoff' {
+                $factor = 0.0
+                if (-not [double]::TryParse($settingValue, [System.Globalization.NumberStyles]::Float, [System.Globalization.CultureInfo]::InvariantCulture, [ref]$factor) -or $factor -lt 1) {
+                    throw "RETRY backoff must be a number of at least 1: $settingValue"
+                }
+                $policy.BackoffFactor = $factor
+            }
+            default {
+                throw "Unknown RETRY setting '$settingName' (expected attempts, delay, or backoff)"
+            }
+        }
+    }
+
+    return $policy
+}
+
+function Get-TaskRetryDelay {
+    <#
+    .SYNOPSIS
+        Returns how long to wait before the next run after a failed attempt
+    .DESCRIPTION
+        The wait after attempt n is Delay * BackoffFactor^(n - 1), so with the
+        defaults the waits are 1s, 2s, 4s, and so on.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        $Policy,
+
+        [Parameter(Mandatory = $true)]
+        [int]$Attempt
+    )
+
+    return [TimeSpan]::FromMilliseconds($Policy.Delay.TotalMilliseconds * [Math]::Pow($Policy.BackoffFactor, $Attempt - 1))
+}
+
+function Get-TaskSnapshotPath {
+    <#
+    .SYNOPSIS
+        Resolves the # SNAPSHOT: file of a task
+    .DESCRIPTION
+        The path is relative to the project root and must stay inside it. Other paths
+        throw an InvalidSnapshotPath error.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo
+    )
+
+    $snapshot = $TaskInfo.Snapshot
+    if ([System.IO.Path]::IsPathRooted($snapshot) -or $snapshot -match '(^|[\\/])\.\.([\\/]|$)') {
+        $exception = [System.ArgumentException]::new("Snapshot '$snapshot' must be a path inside the project root")
+        throw [ErrorRecord]::new($exception, 'InvalidSnapshotPath', [ErrorCategory]::InvalidArgument, $snapshot)
+    }
+
+    return [System.IO.Path]::GetFullPath((Join-Path -Path $script:EffectiveScriptRoot -ChildPath $snapshot))
+}
+
+function Get-UnifiedDiff {
+    <#
+    .SYNOPSIS
+        Compares two lists of lines and returns a unified diff
+    .DESCRIPTION
+        Lines are matched with a longest common subsequence, so the diff has the
+        fewest removed (-) and added (+) lines. The common first and last lines are
+        skipped before matching, which keeps small changes to long outputs fast.
+        Changes less than 2 * -Context lines apart share a hunk.
+
+        The matching table has one cell per pair of changed lines. When that is more
+        than -MaxComparisons cells, only the line counts are returned after the
+        headers, so two large outputs that have little in common cannot use up the
+        memory and time of the run.
+    .OUTPUTS
+        [string[]] diff lines starting with the --- and +++ headers, or nothing when
+        the lines are the same
+    #>
+    param(
+        [AllowEmptyCollection()]
+        [string[]]$Expected = @(),
+
+        [AllowEmptyCollection()]
+        [string[]]$Actual = @(),
+
+        [string]$ExpectedLabel = 'expected',
+
+        [string]$ActualLabel = 'actual',
+
+        [int]$Context = 3,
+
+        [long]$MaxComparisons = 1000000
+    )
+
+    $prefix = 0
+    while ($prefix -lt $Expected.Count -and $prefix -lt $Actual.Count -and $Expected[$prefix] -ceq $Actual[$prefix]) {
+        $prefix++
+    }
+    $suffix = 0
+    while ($suffix -lt ($Expected.Count - $prefix) -and $suffix -lt ($Actual.Count - $prefix) -and
+        $Expected[$Expected.Count - 1 - $suffix] -ceq $Actual[$Actual.Count - 1 - $suffix]) {
+        $suffix++
+    }
+    $n = $Expected.Count - $prefix - $suffix
+    $m = $Actual.Count - $prefix - $suffix
+    if ($n -eq 0 -and $m -eq 0) {
+        return
+    }
+    if ([long]$n * [long]$m -gt $MaxComparisons) {
+        return @("--- $ExpectedLabel", "+++ $ActualLabel", "files differ ($($Expected.Count) vs $($Actual.Count) lines, $n vs $m changed lines are too many to compare)")
+    }
+
+    # lengths[i, j] is the length of the longest common subsequence of the lines
+    # after the first i changed expected lines and the first j changed actual lines
+    $lengths = [int[,]]::new($n + 1, $m + 1)
+    for ($i = $n - 1; $i -ge 0; $i--) {
+        for ($j = $m - 1; $j -ge 0; $j--) {
+            $lengths[$i, $j] = if ($Expected[$prefix + $i] -ceq $Actual[$prefix + $j]) {
+                $lengths[($i + 1), ($j + 1)] + 1
+            } else {
+                [Math]::Max($lengths[($i + 1), $j], $lengths[$i, ($j + 1)])
+            }
+        }
+    }
+
+    # Each edit keeps the 0-based line numbers before it, for the hunk headers
+    $edits = [System.Collections.Generic.List[hashtable]]::new()
+    for ($k = 0; $k -lt $prefix; $k++) {
+        $edits.Add(@{ Op = ' '; Text = $Expected[$k]; Old = $k; New = $k })
+    }
+    $i = 0
+    $j = 0
+    while ($i -lt $n -or $j -lt $m) {
+        if ($i -lt $n -and $j -lt $m -and $Expected[$prefix + $i] -ceq $Actual[$prefix + $j]) {
+            $edits.Add(@{ Op = ' '; Text = $Expected[$prefix + $i]; Old = $prefix + $i; New = $prefix + $j })
+            $i++
+            $j++
+        } elseif ($i -lt $n -and ($j -ge $m -or $lengths[($i + 1), $j] -ge $lengths[$i, ($j + 1)])) {
+            $edits.Add(@{ Op = '-'; Text = $Expected[$prefix + $i]; Old = $prefix + $i; New = $prefix + $j })
+            $i++
+        } else {
+            $edits.Add(@{ Op = '+'; Text = $Actual[$prefix + $j]; Old = $prefix + $i; New = $prefix + $j })
+            $j++
+        }
+    }
+    for ($k = 0; $k -lt $suffix; $k++) {
+        $edits.Add(@{ Op = ' '; Text = $Expected[$prefix + $n + $k]; Old = $prefix + $n + $k; New = $prefix + $m + $k })
+    }
+
+    $changes = @(for ($k = 0; $k -lt $edits.Count; $k++) { if ($edits[$k].Op -ne ' ') { $k } })
+    $diff = [System.Collections.Generic.List[string]]::new()
+    $diff.Add("--- $ExpectedLabel")
+    $diff.Add("+++ $ActualLabel")
+    $index = 0
+    while ($index -lt $changes.Count) {
+        $last = $index
+        while ($last + 1 -lt $changes.Count -and $changes[$last + 1] - $changes[$last] -le 2 * $Context) {
+            $last++
+        }
+        $start = [Math]::Max(0, $changes[$index] - $Context)
+        $end = [Math]::Min($edits.Count - 1, $changes[$last] + $Context)
+        $hunk = $edits.GetRange($start, $end - $start + 1)
+
+        # A hunk without old (or new) lines starts at the line before it, like diff -u
+        $oldCount = @($hunk | Where-Object { $_.Op -ne '+' }).Count
+        $newCount = @($hunk | Where-Object { $_.Op -ne '-' }).Count
+        $oldStart = $edits[$start].Old + [int]($oldCount -gt 0)
+        $newStart = $edits[$start].New + [int]($newCount -gt 0)
+        $diff.Add("@@ -$oldStart,$oldCount +$newStart,$newCount @@")
+        foreach ($edit in $hunk) {
+            $diff.Add("$($edit.Op)$($edit.Text)")
+        }
+        $index = $last + 1
+    }
+
+    return $diff.ToArray()
+}
+
+function Compare-TaskSnapshot {
+    <#
+    .SYNOPSIS
+        Compares the standard output of a task with its # SNAPSHOT: file
+    .DESCRIPTION
+        The first run writes the output to the snapshot file, and so does every run
+        with -Update. Line endings do not count, and neither does a newline at the end.
+    .OUTPUTS
+        PSCustomObject with Status (match, changed, created, or updated), Path, and
+        Diff (the unified diff when Status is changed)
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [AllowEmptyString()]
+        [string]$Output = '',
+
+        [switch]$Update
+    )
+
+    $path = Get-TaskSnapshotPath -TaskInfo $TaskInfo
+    $actual = @(if ($Output) { $Output -replace '\r?\n\z', '' -split '\r?\n' })
+    $exists = Test-Path -LiteralPath $path -PathType Leaf
+
+    if ($Update -or -not $exists) {
+        New-Item -ItemType Directory -Path (Split-Path -Path $path -Parent) -Force | Out-Null
+        [System.IO.File]::WriteAllText($path, $(if ($actual.Count -gt 0) { ($actual -join "`n") + "`n" } else { '' }))
+        return [PSCustomObject]@{
+            Status = if ($exists) { 'updated' } else { 'created' }
+            Path   = $path
+            Diff   = @()
+        }
+    }
+
+    $expectedText = [System.IO.File]::ReadAllText($path)
+    $expected = @(if ($expectedText) { $expectedText -replace '\r?\n\z', '' -split '\r?\n' })
+    $diff = @(Get-UnifiedDiff -Expected $expected -Actual $actual -ExpectedLabel $TaskInfo.Snapshot -ActualLabel "$($TaskInfo.Names[0]) output")
+
+    return [PSCustomObject]@{
+        Status = if ($diff.Count -gt 0) { 'changed' } else { 'match' }
+        Path   = $path
+        Diff   = $diff
+    }
+}
+
+function Write-SnapshotDiff {
+    <#
+    .SYNOPSIS
+        Writes a unified diff with removed lines in red and added lines in green
+    #>
+    param(
+        [string[]]$Diff = @(),
+
+        [string]$Prefix = '',
+
+        [string]$PrefixColor = 'Gray'
+    )
+
+    foreach ($line in $Diff) {
+        $color = switch -Regex ($line) {
+            '^(---|\+\+\+) ' { 'White'; break }
+            '^@@' { 'Cyan'; break }
+            '^-' { 'Red'; break }
+            '^\+' { 'Green'; break }
+            default { 'Gray' }
+        }
+        if ($Prefix) {
+            Write-Host "$Prefix " -NoNewline -ForegroundColor $PrefixColor
+        }
+        Write-Host $line -ForegroundColor $color
+    }
+}
+
+function New-BoltProfiler {
+    <#
+    .SYNOPSIS
+        Creates the profiler for -CpuProfile and -MemProfile
+    .DESCRIPTION
+        Returns a profiler object with two methods:
+          Start(name)    starts timing a phase of the run
+          Stop(name)     adds the time since Start to the phase
+
+        Each phase counts how often it ran, its wall-clock time, and the processor
+        time of the bolt process while it ran. Tasks that run in child processes are
+        not part of the processor time, so phases show bolt's own overhead. Bolt
+        keeps the profiler in $script:BoltProfiler and times these phases:
+          discover     finding and parsing task files
+          plan         the dependency graph and the checks before any task runs
+          lock         waiting for .bolt/bolt.lock
+          run          running the tasks
+          cache-key    hashing a task's script and # INPUTS: files (inside run)
+    #>
+    $process = [System.Diagnostics.Process]::GetCurrentProcess()
+    $profiler = [PSCustomObject]@{
+        Process   = $process
+        Stopwatch = [System.Diagnostics.Stopwatch]::StartNew()
+        CpuStart  = $process.TotalProcessorTime
+        Phases    = [ordered]@{}
+    }
+
+    $profiler | Add-Member -MemberType ScriptMethod -Name Start -Value {
+        param([string]$Name)
+
+        if (-not $this.Phases.Contains($Name)) {
+            $this.Phases[$Name] = @{ Count = 0; WallTicks = 0L; CpuTicks = 0L; StartedAt = $null; CpuAt = $null }
+        }
+        $this.Process.Refresh()
+        $phase = $this.Phases[$Name]
+        $phase.StartedAt = $this.Stopwatch.Elapsed
+        $phase.CpuAt = $this.Process.TotalProcessorTime
+    }
+
+    $profiler | Add-Member -MemberType ScriptMethod -Name Stop -Value {
+        param([string]$Name)
+
+        $phase = $this.Phases[$Name]
+        if (-not $phase -or $null -eq $phase.StartedAt) {
+            return
+        }
+        $this.Process.Refresh()
+        $phase.Count++
+        $phase.WallTicks += ($this.Stopwatch.Elapsed - $phase.StartedAt).Ticks
+        $phase.CpuTicks += ($this.Process.TotalProcessorTime - $phase.CpuAt).Ticks
+        $phase.StartedAt = $null
+    }
+
+    return $profiler
+}
+
+function Start-BoltProfilePhase {
+    <#
+    .SYNOPSIS
+        Starts timing a phase when -CpuProfile or -MemProfile is set
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Name
+    )
+
+    if ($script:BoltProfiler) {
+        $script:BoltProfiler.Start($Name)
+    }
+}
+
+function Stop-BoltProfilePhase {
+    <#
+    .SYNOPSIS
+        Stops timing a phase when -CpuProfile or -MemProfile is set
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Name
+    )
+
+    if ($script:BoltProfiler) {
+        $script:BoltProfiler.Stop($Name)
+    }
+}
+
+function Save-BoltProfile {
+    <#
+    .SYNOPSIS
+        Writes the -CpuProfile and -MemProfile files at the end of a run
+    .DESCRIPTION
+        The CPU profile is a JSON object with the wall-clock and processor time of
+        the whole run and of each phase timed by New-BoltProfiler, in the order the
+        phases first started. Phases still running are stopped first.
+
+        The memory profile is a JSON object with the managed heap size, the bytes
+        allocated since bolt started, the working set and its peak, and the number
+        of garbage collections per generation, read when the run ends.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        $Profiler,
+
+        [string]$CpuPath,
+
+        [string]$MemPath
+    )
+
+    $Profiler.Process.Refresh()
+
+    if ($CpuPath) {
+        foreach ($name in @($Profiler.Phases.Keys)) {
+            $Profiler.Stop($name)
+        }
+        $cpuProfile = [ordered]@{
+            Type   = 'cpu'
+            WallMs = [long]$Profiler.Stopwatch.Elapsed.TotalMilliseconds
+            CpuMs  = [long]($Profiler.Process.TotalProcessorTime - $Profiler.CpuStart).TotalMilliseconds
+            Phases = @(
+                foreach ($name in $Profiler.Phases.Keys) {
+                    $phase = $Profiler.Phases[$name]
+                    [ordered]@{
+                        Name   = $name
+                        Count  = $phase.Count
+                        WallMs = [long][TimeSpan]::FromTicks($phase.WallTicks).TotalMilliseconds
+                        CpuMs  = [long][TimeSpan]::FromTicks($phase.CpuTicks).TotalMilliseconds
+                    }
+                }
+            )
+        }
+        Set-Content -LiteralPath $CpuPath -Value ($cpuProfile | ConvertTo-Json -Depth 4) -Encoding utf8
+    }
+
+    if ($MemPath) {
+        $memProfile = [ordered]@{
+            Type                = 'memory'
+            HeapBytes           = [GC]::GetTotalMemory($false)
+            TotalAllocatedBytes = [GC]::GetTotalAllocatedBytes($false)
+            WorkingSetBytes     = $Profiler.Process.WorkingSet64
+            PeakWorkingSetBytes = $Profiler.Process.PeakWorkingSet64
+            GcCollections       = [ordered]@{ Gen0 = [GC]::CollectionCount(0); Gen1 = [GC]::CollectionCount(1); Gen2 = [GC]::CollectionCount(2) }
+        }
+        Set-Content -LiteralPath $MemPath -Value ($memProfile | ConvertTo-Json -Depth 4) -Encoding utf8
+    }
+}
+
+function Get-GitHeadSha {
+    <#
+    .SYNOPSIS
+        Returns the commit that HEAD points to, or $null without git or a repository
+    #>
+    param(
+        [string]$Root = $script:EffectiveScriptRoot
+    )
+
+    if (-not (Get-Command git -ErrorAction SilentlyContinue)) {
+        return $null
+    }
+
+    $sha = git -C $Root rev-parse HEAD 2>$null
+    if ($LASTEXITCODE -ne 0 -or -not $sha) {
+        return $null
+    }
+    return ([string]$sha).Trim()
+}
+
+function New-AuditLog {
+    <#
+    .SYNOPSIS
+        Creates the task audit log for -AuditLog
+    .DESCRIPTION
+        Returns an object with a Write(task, status, durationMs, exitCode) method
+        that appends one JSON record per line to -Path:
+
+          {"timestamp":"...","task":"build","user":"alice","hostname":"ci-01",
+           "git_sha":"4f02f09...","status":"success","duration_ms":1204,"exit_code":0}
+
+        The user, host name, and git commit are read once, when the log is created.
+        git_sha is null outside a git repository.
+
+        .NET does not open files with O_APPEND on every platform, so each record
+        takes the file exclusively, seeks to the end, and writes the whole line at
+        once. Concurrent bolt runs wait for each other instead of overwriting
+        records. Bolt keeps the log in $script:TaskAuditLog.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Path,
+
+        [string]$GitSha = $null
+    )
+
+    $directory = Split-Path -Path $Path -Parent
+    if ($directory -and -not (Test-Path -LiteralPath $directory -PathType Container)) {
+        New-Item -ItemType Directory -Path $directory -Force | Out-Null
+    }
+
+    $auditLog = [PSCustomObject]@{
+        Path     = $Path
+        User     = [Environment]::UserName
+        Hostname = [Environment]::MachineName
+        GitSha   = $GitSha
+    }
+
+    $auditLog | Add-Member -MemberType ScriptMethod -Name Write -Value {
+        param([string]$Task, [string]$Status, [long]$DurationMs, [int]$ExitCode)
+
+        $record = [ordered]@{
+            timestamp   = [DateTimeOffset]::UtcNow.ToString('yyyy-MM-ddTHH:mm:ss.fffZ', [System.Globalization.CultureInfo]::InvariantCulture)
+            task        = $Task
+            user        = $this.User
+            hostname    = $this.Hostname
+            git_sha     = $this.GitSha
+            status      = $Status
+            duration_ms = $DurationMs
+            exit_code   = $ExitCode
+        }
+        $bytes = [System.Text.Encoding]::UTF8.GetBytes(($record | ConvertTo-Json -Compress) + "`n")
+
+        $deadline = [DateTime]::UtcNow.AddSeconds(5)
+        $stream = $null
+        while (-not $stream) {
+            try {
+                $stream = [System.IO.FileStream]::new($this.Path, [System.IO.FileMode]::Append, [System.IO.FileAccess]::Write, [System.IO.FileShare]::None)
+            }
+            catch [System.IO.IOException] {
+                # Another bolt run is writing its record
+                if ([DateTime]::UtcNow -gt $deadline) {
+                    throw
+                }
+                Start-Sleep -Milliseconds 10
+            }
+        }
+        try {
+            $stream.Write($bytes, 0, $bytes.Length)
+        }
+        finally {
+            $stream.Dispose()
+        }
+    }
+
+    return $auditLog
+}
+
+function Get-AuditRecords {
+    <#
+    .SYNOPSIS
+        Reads the last records of a task audit log
+    .DESCRIPTION
+        Lines that are not JSON records are skipped with a warning. Throws an
+        AuditLogNotFound error when -Path does not exist.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Path,
+
+        [int]$Last = 10
+    )
+
+    if (-not (Test-Path -LiteralPath $Path -PathType Leaf)) {
+        $exception = [System.IO.FileNotFoundException]::new("Audit log '$Path' does not exist")
+        throw [System.Management.Automation.ErrorRecord]::new($exception, 'AuditLogNotFound', [System.Management.Automation.ErrorCategory]::ObjectNotFound, $Path)
+    }
+
+    # A run that is writing a record holds the file for a moment
+    $deadline = [DateTime]::UtcNow.AddSeconds(5)
+    while ($true) {
+        try {
+            $lines = @(Get-Content -LiteralPath $Path -Tail $Last -ErrorAction Stop)
+            break
+        }
+        catch [System.IO.IOException] {
+            if ([DateTime]::UtcNow -gt $deadline) {
+                throw
+            }
+            Start-Sleep -Milliseconds 10
+        }
+    }
+
+    foreach ($line in $lines) {
+        if (-not $line.Trim()) {
+            continue
+        }
+        try {
+            $line | ConvertFrom-Json -ErrorAction Stop
+        }
+        catch {
+            Write-Warning "Skipping a line in '$Path' that is not an audit record: $line"
+        }
+    }
+}
+
+function Write-AuditRecords {
+    <#
+    .SYNOPSIS
+        Prints audit records as a table for -Audit Tail
+    #>
+    param(
+        [object[]]$Records = @()
+    )
+
+    if ($Records.Count -eq 0) {
+        Write-Host "No audit records" -ForegroundColor Gray
+        return
+    }
+
+    $taskWidth = [Math]::Max(4, ($Records | ForEach-Object { ([string]$_.task).Length } | Measure-Object -Maximum).Maximum)
+    Write-Host ("{0,-20}  {1,-$taskWidth}  {2,-8}  {3,9}  {4,4}  {5}" -f 'TIME (UTC)', 'TASK', 'STATUS', 'DURATION', 'EXIT', 'USER@HOST  COMMIT') -ForegroundColor Cyan
+
+    foreach ($record in $Records) {
+        # ConvertFrom-Json turns ISO 8601 strings into dates
+        $time = if ($record.timestamp -is [datetime]) { $record.timestamp.ToUniversalTime().ToString('yyyy-MM-dd HH:mm:ss') } else { [string]$record.timestamp }
+        $duration = ([double]$record.duration_ms / 1000).ToString('0.00', [System.Globalization.CultureInfo]::InvariantCulture) + 's'
+        $commit = if ($record.git_sha) { ([string]$record.git_sha).Substring(0, [Math]::Min(7, ([string]$record.git_sha).Length)) } else { '-' }
+        $color = switch ($record.status) {
+            'success' { 'Green' }
+            'skipped' { 'Gray' }
+            default { 'Red' }
+        }
+        Write-Host ("{0,-20}  {1,-$taskWidth}  {2,-8}  {3,9}  {4,4}  {5}@{6}  {7}" -f $time, $record.task, $record.status, $duration, $record.exit_code, $record.user, $record.hostname, $commit) -ForegroundColor $color
+    }
+}
+
+function Get-BoltLatestRelease {
+    <#
+    .SYNOPSIS
+        Reads the latest bolt release from the GitHub Releases API for -Upgrade
+    .DESCRIPTION
+        Returns the release version (the tag without its leading 'v') and a table of
+        asset names to download URLs. A failed request throws a
+        BoltReleaseUnavailable error.
+    .OUTPUTS
+        PSCustomObject with Tag, Version, and Assets
+    #>
+    param(
+        [string]$ApiUrl = 'https://api.github.com/repos/motowilliams/bolt/releases/latest'
+    )
+
+    try {
+        $release = Invoke-RestMethod -Method Get -Uri $ApiUrl -Headers @{ Accept = 'application/vnd.github+json'; 'User-Agent' = 'bolt' } -TimeoutSec 30 -ErrorAction Stop
+    }
+    catch {
+        $exception = [System.InvalidOperationException]::new("Failed to read the latest bolt release from '$ApiUrl': $($_.Exception.Message)")
+        throw [System.Management.Automation.ErrorRecord]::new($exception, 'BoltReleaseUnavailable', [System.Management.Automation.ErrorCategory]::ConnectionError, $ApiUrl)
+    }
+
+    $assets = @{}
+    foreach ($asset in @($release.assets)) {
+        if ($asset.name) {
+            $assets[[string]$asset.name] = [string]$asset.browser_download_url
+        }
+    }
+
+    return [PSCustomObject]@{
+        Tag     = [string]$release.tag_name
+        Version = ([string]$release.tag_name) -replace '^v', ''
+        Assets  = $assets
+    }
+}
+
+function Test-NewerBoltVersion {
+    <#
+    .SYNOPSIS
+        Tests whether -Candidate is a later semantic version than -Current
+    .DESCRIPTION
+        Both versions are compared as semantic versions, so 1.0.0-beta is older than
+        1.0.0. A leading 'v' is ignored. A version that is not a semantic version
+        throws an InvalidBoltVersion error.
+    .OUTPUTS
+        [bool]
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Current,
+
+        [Parameter(Mandatory = $true)]
+        [string]$Candidate
+    )
+
+    $parsed = @{}
+    foreach ($entry in @(@('Current', $Current), @('Candidate', $Candidate))) {
+        $version = $null
+        if (-not [System.Management.Automation.SemanticVersion]::TryParse(($entry[1] -replace '^v', ''), [ref]$version)) {
+            $exception = [System.FormatException]::new("'$($entry[1])' is not a semantic version")
+            throw [System.Management.Automation.ErrorRecord]::new($exception, 'InvalidBoltVersion', [System.Management.Automation.ErrorCategory]::InvalidData, $entry[1])
+        }
+        $parsed[$entry[0]] = $version
+    }
+
+    return $parsed['Candidate'] -gt $parsed['Current']
+}
+
+function Get-BoltUpgradeTarget {
+    <#
+    .SYNOPSIS
+        Decides what -Upgrade Apply may replace
+    .DESCRIPTION
+        Throws a BoltUpgradeRefused error for copies that are not released copies:
+        a bolt.ps1 with the 0.0.0-dev version from the repository, or one inside a
+        git work tree, where the upgrade belongs in a commit. A bolt.ps1 next to the
+        Bolt.psm1 that New-BoltModule.ps1 generates is a module install. The module
+        script forwards its parameters to bolt.ps1, so the whole module directory is
+        replaced to keep the two in step.
+    .OUTPUTS
+        PSCustomObject with Path (bolt.ps1, or the module directory) and Module
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$ScriptPath,
+
+        [Parameter(Mandatory = $true)]
+        [string]$Version
+    )
+
+    $refuse = {
+        param([string]$Message)
+
+        $exception = [System.InvalidOperationException]::new($Message)
+        throw [System.Management.Automation.ErrorRecord]::new($exception, 'BoltUpgradeRefused', [System.Management.Automation.ErrorCategory]::InvalidOperation, $ScriptPath)
+    }
+
+    if ($Version -eq '0.0.0-dev') {
+        & $refuse "$ScriptPath is a development copy (version 0.0.0-dev), not a release. Update it from where it was copied instead of using -Upgrade Apply"
+    }
+
+    $directory = Split-Path -Path $ScriptPath -Parent
+    $git = Get-Command -Name git -CommandType Application -ErrorAction SilentlyContinue | Select-Object -First 1
+    if ($git) {
+        $workTree = & $git.Source -C $directory rev-parse --show-toplevel 2>$null
+        if ($LASTEXITCODE -eq 0 -and $workTree) {
+            & $refuse "$ScriptPath is in the git work tree at $workTree. Commit the new bolt.ps1 there instead of using -Upgrade Apply, so the change can be reviewed"
+        }
+    }
+
+    if (Test-Path -LiteralPath (Join-Path -Path $directory -ChildPath 'Bolt.psm1') -PathType Leaf) {
+        return [PSCustomObject]@{ Path = $directory; Module = $true }
+    }
+    return [PSCustomObject]@{ Path = $ScriptPath; Module = $false }
+}
+
+function Install-BoltRelease {
+    <#
+    .SYNOPSIS
+        Replaces bolt.ps1 with the copy from a release for -Upgrade Apply
+    .DESCRIPTION
+        Downloads the Bolt-<version>.zip asset of -Release and its .sha256 checksum
+        file, and checks the archive against the checksum before it is opened. The
+        bolt.ps1 from the archive is written next to -TargetPath and renamed over it,
+        so -TargetPath is never left half written. Missing assets throw a
+        BoltReleaseUnavailable error and a checksum mismatch throws a
+        BoltReleaseHashMismatch error; -TargetPath is unchanged in both cases.
+
+        With -Module, -TargetPath is a module directory and the Bolt directory of the
+        archive replaces all of it. The new directory is staged next to it, then the
+        old one is moved aside and the new one moved in; the old one is put back if
+        that second move fails.
+    .OUTPUTS
+        [string] The path of the replaced file or directory
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [PSCustomObject]$Release,
+
+        [Parameter(Mandatory = $true)]
+        [string]$TargetPath,
+
+        [switch]$Module
+    )
+
+    $fail = {
+        param([string]$Message, [string]$ErrorId = 'BoltReleaseUnavailable')
+
+        $exception = [System.InvalidOperationException]::new($Message)
+        throw [System.Management.Automation.ErrorRecord]::new($exception, $ErrorId, [System.Management.Automation.ErrorCategory]::InvalidData, $Release.Tag)
+    }
+
+    $archiveName = "Bolt-$($Release.Version).zip"
+    foreach ($assetName in @($archiveName, "$archiveName.sha256")) {
+        $assetUrl = $Release.Assets[$assetName]
+        $uri = $null
+        if (-not $assetUrl) {
+            & $fail "Release $($Release.Tag) has no $assetName asset"
+        }
+        # The file replaces bolt.ps1, so it is only fetched without encryption from this machine
+        if (-not [uri]::TryCreate($assetUrl, [UriKind]::Absolute, [ref]$uri) -or $uri.Scheme -notin @('http', 'https') -or
+            ($uri.Scheme -eq 'http' -and $uri.Host -notin @('localhost', '127.0.0.1', '::1'))) {
+            & $fail "Release asset '$assetUrl' must be an https:// URL"
+        }
+    }
+
+    $workPath = Join-Path -Path ([System.IO.Path]::GetTempPath()) -ChildPath "bolt-upgrade-$([guid]::NewGuid().ToString('N'))"
+    New-Item -ItemType Directory -Path $workPath -Force | Out-Null
+    $stagedPath = "$TargetPath.upgrade"
+    $previousPath = "$TargetPath.previous"
+    try {
+        $archivePath = Join-Path -Path $workPath -ChildPath $archiveName
+        try {
+            Invoke-WebRequest -Uri $Release.Assets[$archiveName] -OutFile $archivePath -TimeoutSec 120 -ErrorAction Stop
+            $checksumContent = (Invoke-WebRequest -Uri $Release.Assets["$archiveName.sha256"] -TimeoutSec 30 -ErrorAction Stop).Content
+        }
+        catch {
+            & $fail "Failed to download release $($Release.Tag): $($_.Exception.Message)"
+        }
+        if ($checksumContent -is [byte[]]) {
+            $checksumContent = [System.Text.Encoding]::UTF8.GetString($checksumContent)
+        }
+
+        # The checksum file is "<hash>  <file name>" as written by .scripts/release/Create-Archive.ps1
+        if ([string]$checksumContent -notmatch '([0-9a-fA-F]{64})') {
+            & $fail "Release asset '$archiveName.sha256' does not contain a SHA-256 digest"
+        }
+        $expected = $Matches[1].ToLowerInvariant()
+        $actual = (Get-FileHash -LiteralPath $archivePath -Algorithm SHA256).Hash.ToLowerInvariant()
+        if ($actual -ne $expected) {
+            & $fail "Release archive '$archiveName' failed SHA-256 verification: expected $expected, got $actual" 'BoltReleaseHashMismatch'
+        }
+
+        $extractPath = Join-Path -Path $workPath -ChildPath 'extracted'
+        Expand-Archive -LiteralPath $archivePath -DestinationPath $extractPath -Force
+        $releaseScript = Join-Path -Path $extractPath -ChildPath 'Bolt' | Join-Path -ChildPath 'bolt.ps1'
+        if (-not (Test-Path -LiteralPath $releaseScript -PathType Leaf)) {
+            & $fail "Release archive '$archiveName' does not contain Bolt/bolt.ps1"
+        }
+
+        # Stage next to the target so the rename stays on one file system
+        if ($Module) {
+            $releaseModule = Join-Path -Path $extractPath -ChildPath 'Bolt'
+            if (-not (Test-Path -LiteralPath (Join-Path -Path $releaseModule -ChildPath 'Bolt.psm1') -PathType Leaf)) {
+                & $fail "Release archive '$archiveName' does not contain Bolt/Bolt.psm1"
+            }
+            Copy-Item -LiteralPath $releaseModule -Destination $stagedPath -Recurse -Force
+            [System.IO.Directory]::Move($TargetPath, $previousPath)
+            try {
+                [System.IO.Directory]::Move($stagedPath, $TargetPath)
+            }
+            catch {
+                [System.IO.Directory]::Move($previousPath, $TargetPath)
+                throw
+            }
+        } else {
+            Copy-Item -LiteralPath $releaseScript -Destination $stagedPath -Force
+            [System.IO.File]::Move($stagedPath, $TargetPath, $true)
+        }
+    }
+    finally {
+        Remove-Item -LiteralPath $stagedPath -Recurse -Force -ErrorAction SilentlyContinue
+        # The old module is only deleted once a module is back in place
+        if (Test-Path -LiteralPath $TargetPath) {
+            Remove-Item -LiteralPath $previousPath -Recurse -Force -ErrorAction SilentlyContinue
+        }
+        Remove-Item -LiteralPath $workPath -Recurse -Force -ErrorAction SilentlyContinue
+    }
+
+    return $TargetPath
+}
+
+function New-TaskTracer {
+    <#
+    .SYNOPSIS
+        Creates the OpenTelemetry tracer for -OtelEndpoint
+    .DESCRIPTION
+        Returns an object with two methods:
+          AddTaskSpan(name, status, durationMs, exitCode, cached)   records a task span
+          Shutdown()                                               sends the trace
+
+        The run is the root span, named 'bolt run', and every task result is a
+        child span that ends when it is recorded and starts durationMs earlier.
+        Task spans have the attributes bolt.task.name, bolt.task.status,
+        bolt.task.exit_code, bolt.task.cached, and bolt.task.duration_ms. Failed
+        and timed out tasks, and the run they are part of, get an error status.
+
+        Spans are kept in memory and Shutdown posts them in one OTLP/HTTP JSON
+        request to <endpoint>/v1/traces, so tracing needs no module and adds no
+        request per task. Shutdown sends the trace once; later calls do nothing.
+        Bolt keeps the tracer in $script:TaskTracer.
+
+        Tests can pass a -Send script block that receives the URL and the JSON body.
+        Throws an InvalidOtelEndpoint error when -Endpoint is not an http or https URL.
+    .PARAMETER Endpoint
+        The collector's OTLP/HTTP base URL, like http://localhost:4318, or the full
+        /v1/traces URL
+    .PARAMETER ServiceName
+        The service.name resource attribute
+    .PARAMETER TaskNames
+        The tasks the run was started with, for the bolt.tasks attribute of the root span
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Endpoint,
+
+        [string]$ServiceName = 'bolt',
+
+        [string[]]$TaskNames = @(),
+
+        [scriptblock]$Send = {
+            param([string]$Url, [string]$Body)
+            Invoke-RestMethod -Method Post -Uri $Url -Body $Body -ContentType 'application/json' -TimeoutSec 10 -ErrorAction Stop | Out-Null
+        }
+    )
+
+    $uri = $null
+    if (-not [Uri]::TryCreate($Endpoint, [UriKind]::Absolute, [ref]$uri) -or $uri.Scheme -notin @('http', 'https')) {
+        $exception = [System.ArgumentException]::new("-OtelEndpoint '$Endpoint' must be an http:// or https:// URL")
+        throw [System.Management.Automation.ErrorRecord]::new($exception, 'InvalidOtelEndpoint', [System.Management.Automation.ErrorCategory]::InvalidArgument, $Endpoint)
+    }
+    $url = $Endpoint.TrimEnd('/')
+    if (-not $url.EndsWith('/v1/traces')) {
+        $url += '/v1/traces'
+    }
+
+    $tracer = [PSCustomObject]@{
+        Url         = $url
+        ServiceName = $ServiceName
+        TaskNames   = @($TaskNames)
+        Send        = $Send
+        TraceId     = [Convert]::ToHexString([System.Security.Cryptography.RandomNumberGenerator]::GetBytes(16)).ToLowerInvariant()
+        RootSpanId  = [Convert]::ToHexString([System.Security.Cryptography.RandomNumberGenerator]::GetBytes(8)).ToLowerInvariant()
+        StartTime   = [DateTimeOffset]::UtcNow
+        Spans       = [System.Collections.Generic.List[object]]::new()
+        Done        = $false
+    }
+
+    $tracer | Add-Member -MemberType ScriptMethod -Name UnixNano -Value {
+        param([DateTimeOffset]$Time)
+
+        # OTLP JSON carries 64-bit integers as strings
+        return [string](($Time.UtcTicks - [DateTimeOffset]::UnixEpoch.UtcTicks) * 100)
+    }
+
+    $tracer | Add-Member -MemberType ScriptMethod -Name Attribute -Value {
+        param([string]$Key, [object]$Value)
+
+        $typed = if ($Value -is [bool]) {
+            @{ boolValue = $Value }
+        } elseif ($Value -is [int] -or $Value -is [long]) {
+            @{ intValue = [string]$Value }
+        } else {
+            @{ stringValue = [string]$Value }
+        }
+        return [ordered]@{ key = $Key; value = $typed }
+    }
+
+    $tracer | Add-Member -MemberType ScriptMethod -Name AddTaskSpan -Value {
+        param([string]$Name, [string]$Status, [long]$DurationMs, [int]$ExitCode, [bool]$Cached)
+
+        $end = [DateTimeOffset]::UtcNow
+        $failed = $Status -in @('failure', 'timeout')
+        $this.Spans.Add([ordered]@{
+            traceId           = $this.TraceId
+            spanId            = [Convert]::ToHexString([System.Security.Cryptography.RandomNumberGenerator]::GetBytes(8)).ToLowerInvariant()
+            parentSpanId      = $this.RootSpanId
+            name              = $Name
+            kind              = 1
+            startTimeUnixNano = $this.UnixNano($end.AddMilliseconds(-$DurationMs))
+            endTimeUnixNano   = $this.UnixNano($end)
+            attributes        = @(
+                $this.Attribute('bolt.task.name', $Name)
+                $this.Attribute('bolt.task.status', $Status)
+                $this.Attribute('bolt.task.exit_code', $ExitCode)
+                $this.Attribute('bolt.task.cached', $Cached)
+                $this.Attribute('bolt.task.duration_ms', $DurationMs)
+            )
+            status            = if ($failed) { [ordered]@{ code = 2; message = $Status } } else { [ordered]@{ code = 0 } }
+        })
+    }
+
+    $tracer | Add-Member -MemberType ScriptMethod -Name Shutdown -Value {
+        if ($this.Done) {
+            return
+        }
+        $this.Done = $true
+
+        $failed = @($this.Spans | Where-Object { $_.status.code -eq 2 }).Count -gt 0
+        $root = [ordered]@{
+            traceId           = $this.TraceId
+            spanId            = $this.RootSpanId
+            name              = 'bolt run'
+            kind              = 1
+            startTimeUnixNano = $this.UnixNano($this.StartTime)
+            endTimeUnixNano   = $this.UnixNano([DateTimeOffset]::UtcNow)
+            attributes        = @(
+                $this.Attribute('bolt.tasks', ($this.TaskNames -join ','))
+            )
+            status            = if ($failed) { [ordered]@{ code = 2; message = 'failure' } } else { [ordered]@{ code = 0 } }
+        }
+        $body = [ordered]@{
+            resourceSpans = @(
+                [ordered]@{
+                    resource   = [ordered]@{
+                        attributes = @(
+                            $this.Attribute('service.name', $this.ServiceName)
+                            $this.Attribute('service.version', $script:BoltVersion)
+                        )
+                    }
+                    scopeSpans = @(
+                        [ordered]@{
+                            scope = [ordered]@{ name = 'bolt' }
+                            spans = @($root) + $this.Spans.ToArray()
+                        }
+                    )
+                }
+            )
+        }
+
+        & $this.Send $this.Url ($body | ConvertTo-Json -Depth 10 -Compress)
+    }
+
+    return $tracer
+}
+
+function Add-TaskResult {
+    <#
+    .SYNOPSIS
+        Records the result of one task for the run summary
+    .DESCRIPTION
+        Results are collected in $script:TaskResults and written as a RunSummary
+        by Write-RunSummary when -OutputFormat Json is used. Each result is also a
+        task finish entry in the structured log (Error level for failures), a
+        record in the -AuditLog file when one is set, and a task span in the
+        -OtelEndpoint trace when one is set.
+    .PARAMETER Status
+        skipped, success, failure, or timeout
+    .PARAMETER GoTestSummary
+        Test counts and coverage of a go-test task, added to the result when given
+    .PARAMETER SkipReason
+        Why a skipped task did not run (platform for # PLATFORM:, condition for # WHEN:, git for -Since,
+        dependency for a failed dependency with -NoFailFast), added when given
+    .PARAMETER SnapshotDiff
+        The unified diff of a task whose output does not match its # SNAPSHOT:, added when given
+    .PARAMETER RollbackStatus
+        success or failure when the task's # ROLLBACK: commands ran, empty otherwise
+    .PARAMETER Members
+        The member tasks of a # TYPE: group task, added when given
+    .PARAMETER Cached
+        The task was skipped because its inputs match the last successful run, added as Cached when given
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Name,
+
+        [Parameter(Mandatory = $true)]
+        [ValidateSet('skipped', 'success', 'failure', 'timeout')]
+        [string]$Status,
+
+        [long]$DurationMs = 0,
+
+        [int]$ExitCode = 0,
+
+        [string]$Stderr = '',
+
+        [string[]]$HookErrors = @(),
+
+        [int]$Attempts = 1,
+
+        [System.Collections.Specialized.OrderedDictionary]$GoTestSummary = $null,
+
+        [string]$SkipReason = '',
+
+        [string]$SnapshotDiff = '',
+
+        [ValidateSet('', 'success', 'failure')]
+        [string]$RollbackStatus = '',
+
+        [string[]]$Members = @(),
+
+        [switch]$Cached
+    )
+
+    if ($null -eq $script:TaskResults) {
+        $script:TaskResults = [System.Collections.Generic.List[object]]::new()
+    }
+
+    $result = [ordered]@{
+        Name           = $Name
+        Status         = $Status
+        DurationMs     = $DurationMs
+        ExitCode       = $ExitCode
+        Stderr         = $Stderr
+        HookErrors     = @($HookErrors)
+        Attempts       = $Attempts
+        RollbackStatus = $RollbackStatus
+    }
+    if ($GoTestSummary) {
+        $result['GoTestSummary'] = $GoTestSummary
+    }
+    if ($SkipReason) {
+        $result['SkipReason'] = $SkipReason
+    }
+    if ($SnapshotDiff) {
+        $result['SnapshotDiff'] = $SnapshotDiff
+    }
+    if ($Members.Count -gt 0) {
+        $result['Members'] = @($Members)
+    }
+    if ($Cached) {
+        $result['Cached'] = $true
+    }
+    $script:TaskResults.Add($result)
+
+    $logFields = [ordered]@{ task = $Name; status = $Status; exit_code = $ExitCode; duration_ms = $DurationMs; attempts = $Attempts }
+    if ($SkipReason) {
+        $logFields['skip_reason'] = $SkipReason
+    }
+    if ($RollbackStatus) {
+        $logFields['rollback_status'] = $RollbackStatus
+    }
+    Write-BoltLog -Level $(if ($Status -in @('failure', 'timeout')) { 'Error' } else { 'Info' }) -Message 'task finish' -Fields $logFields
+
+    if ($script:TaskAuditLog) {
+        try {
+            $script:TaskAuditLog.Write($Name, $Status, $DurationMs, $ExitCode)
+        }
+        catch {
+            Write-Warning "Could not write to audit log '$($script:TaskAuditLog.Path)': $($_.Exception.GetBaseException().Message)"
+        }
+    }
+
+    if ($script:TaskTracer) {
+        $script:TaskTracer.AddTaskSpan($Name, $Status, $DurationMs, $ExitCode, $Cached.IsPresent)
+    }
+}
+
+function Write-RunSummary {
+    <#
+    .SYNOPSIS
+        Writes the RunSummary JSON object to stdout
+    .DESCRIPTION
+        Tasks in the execution order that never ran (for example after an earlier
+        failure) are reported as skipped, except # TYPE: group tasks, which get
+        their status from their members with Get-TaskGroupStatus. Each task result gets a MatrixValues object
+        with the matrix values of a matrix instance (empty for other tasks). Artifacts
+        lists every # PRODUCES: path of the tasks in the run with its producer and
+        SHA-256 digest ($null when the file does not exist). FailedTasks lists the
+        tasks that failed or timed out, in execution order. Results of go-test tasks
+        also have a GoTestSummary with test counts and coverage. The summary is serialized
+        once and written in a single call so stdout always holds one complete JSON
+        document.
+    #>
+    param(
+        [string[]]$ExecutionOrder,
+        [long]$DurationMs,
+        [hashtable]$AllTasks = @{}
+    )
+
+    $results = @(if ($script:TaskResults) { $script:TaskResults })
+    $taskResults = [System.Collections.Generic.List[object]]::new()
+    foreach ($taskName in $ExecutionOrder) {
+        $result = $results | Where-Object { $_.Name -eq $taskName } | Select-Object -Last 1
+        if (-not $result -and $AllTasks[$taskName].Type -eq 'group') {
+            # The run stopped before the group, so its members decide its status
+            $group = Get-TaskGroupStatus -TaskInfo $AllTasks[$taskName] -AllTasks $AllTasks -Results ($results + $taskResults.ToArray())
+            $failed = $group.Status -eq 'failure'
+            $result = [ordered]@{ Name = $taskName; Status = $group.Status; DurationMs = $group.DurationMs; ExitCode = $(if ($failed) { 1 } else { 0 }); Stderr = $(if ($failed) { "Failed members: $($group.FailedMembers -join ', ')" } else { '' }); HookErrors = @(); Attempts = 0; RollbackStatus = ''; Members = $group.Members }
+        }
+        if (-not $result) {
+            $result = [ordered]@{ Name = $taskName; Status = 'skipped'; DurationMs = 0; ExitCode = 0; Stderr = ''; HookErrors = @(); Attempts = 0; RollbackStatus = '' }
+        }
+        $result['MatrixValues'] = if ($AllTasks[$taskName].MatrixValues) { $AllTasks[$taskName].MatrixValues } else { [ordered]@{} }
+        $taskResults.Add($result)
+    }
+
+    $artifacts = @(
+        foreach ($taskName in $ExecutionOrder) {
+            foreach ($artifact in $AllTasks[$taskName].Produces) {
+                $artifactPath = Join-Path -Path $script:EffectiveScriptRoot -ChildPath $artifact
+                [ordered]@{
+                    Path     = $artifact
+                    Producer = $taskName
+                    Sha256   = if (Test-Path -LiteralPath $artifactPath -PathType Leaf) { (Get-FileHash -LiteralPath $artifactPath -Algorithm SHA256).Hash.ToLowerInvariant() } else { $null }
+                }
+            }
+        }
+    )
+
+    $summary = [ordered]@{
+        Success     = -not ($taskResults | Where-Object { $_.Status -in @('failure', 'timeout') })
+        FailedTasks = @($taskResults | Where-Object { $_.Status -in @('failure', 'timeout') } | ForEach-Object { $_.Name })
+        DurationMs  = $DurationMs
+        Tasks       = $taskResults.ToArray()
+        Artifacts   = $artifacts
+    }
+
+    Write-Output ($summary | ConvertTo-Json -Depth 6)
+}
+
+function Invoke-TaskHook {
+    <#
+    .SYNOPSIS
+        Runs the before or after hooks of a task
+    .DESCRIPTION
+        Each hook is either the name of another task, which runs without its
+        dependencies, or an inline PowerShell command, which runs in the task's
+        directory. ${NAME} placeholders in inline commands are replaced by
+        Expand-TaskVariables using the task's environment. A hook fails when it throws,
+        references an undefined variable, or leaves a non-zero $LASTEXITCODE.
+
+        Before hooks stop at the first failure. After hooks all run, like a finally
+        block. Failures are added to -HookErrors so they can be reported separately
+        from the task's own result.
+    .OUTPUTS
+        $true when every hook that ran succeeded
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [Parameter(Mandatory = $true)]
+        [ValidateSet('Before', 'After')]
+        [string]$Phase,
+
+        [hashtable]$AllTasks,
+
+        [array]$Arguments,
+
+        [System.Collections.Generic.List[string]]$HookErrors
+    )
+
+    $succeeded = $true
+    foreach ($hook in $TaskInfo[$Phase]) {
+        if (-not $succeeded -and $Phase -eq 'Before') {
+            break
+        }
+
+        Write-Host "Running $($Phase.ToLower()) hook: $hook" -ForegroundColor Gray
+        $hookError = $null
+
+        $hookTask = if ($hook -cmatch '^([a-z0-9][a-z0-9\-]*:)?[a-z0-9][a-z0-9\-]*$') {
+            Resolve-TaskDependency -DependencyName $hook -CurrentNamespace $TaskInfo.Namespace -Tasks $AllTasks
+        }
+
+        if ($hookTask) {
+            if (-not (Invoke-Task -TaskInfo $AllTasks[$hookTask] -AllTasks $AllTasks -Arguments $Arguments -SkipDependencies $true)) {
+                $hookError = "$($Phase.ToLower()) hook '$hook' failed"
+            }
+        } else {
+            Push-Location (Get-TaskWorkingDirectory -TaskInfo $TaskInfo)
+            try {
+                $global:LASTEXITCODE = 0
+                $command = Expand-TaskVariables -Value $hook -Environment (Get-TaskEnvironment -TaskInfo $TaskInfo) -UndefinedVars $UndefinedVars
+                $hookOutput = & ([ScriptBlock]::Create($command)) | Out-String
+                if ($hookOutput.Trim()) {
+                    Write-Host $hookOutput.TrimEnd()
+                }
+                if ($global:LASTEXITCODE -ne 0) {
+                    $hookError = "$($Phase.ToLower()) hook '$hook' exited with code $global:LASTEXITCODE"
+                }
+            } catch {
+                $hookError = "$($Phase.ToLower()) hook '$hook' failed: $_"
+            } finally {
+                Pop-Location
+            }
+        }
+
+        if ($hookError) {
+            $succeeded = $false
+            Write-Host $hookError -ForegroundColor Red
+            if ($null -ne $HookErrors) {
+                $HookErrors.Add($hookError)
+            }
+        }
+    }
+
+    # Hooks must not leak their exit code into the task result
+    $global:LASTEXITCODE = 0
+    return $succeeded
+}
+
+function Invoke-TaskRollback {
+    <#
+    .SYNOPSIS
+        Runs the # ROLLBACK: commands of a task that failed
+    .DESCRIPTION
+        Commands run in reverse order, last declared first, so each one undoes the
+        work of the steps before it. Every command is an inline PowerShell command
+        that runs in a new child process from Start-TaskProcess -Command, in the
+        directory and container the task had. -Environment is the environment the task
+        started with, so changes the task made to this process do not reach the
+        commands; without it the environment comes from Get-TaskEnvironment. ${NAME}
+        placeholders are replaced by Expand-TaskVariables first, as in inline hooks.
+
+        A command fails when it throws, exits with a non-zero code, or references an
+        undefined variable. The failure is written out and the remaining commands
+        still run. Rollback is best-effort: nothing is undone if a command fails.
+
+        Invoke-TaskParallel passes the tasks that are still running as -Background, so
+        their output is relayed and their timeouts are enforced while it waits for
+        each rollback command.
+    .OUTPUTS
+        success when every command succeeded, failure when one failed, or an empty
+        string when the task has no rollback commands
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [Parameter(Mandatory = $true)]
+        [string]$TaskName,
+
+        [array]$Arguments,
+
+        [System.Collections.IDictionary]$Environment = $null,
+
+        [string]$Prefix = '',
+
+        [string]$PrefixColor = 'Cyan',
+
+        [object[]]$Background = @()
+    )
+
+    if ($TaskInfo.Rollback.Count -eq 0) {
+        return ''
+    }
+    if ($null -eq $Environment) {
+        $Environment = Get-TaskEnvironment -TaskInfo $TaskInfo -IncludeParent:([bool]$TaskInfo.Container)
+    }
+
+    $commands = @($TaskInfo.Rollback)
+    [array]::Reverse($commands)
+    $failures = 0
+
+    Write-SecurityLog -Event "TaskRollback" -Details "Task: $TaskName ($($commands.Count) rollback command(s))" -Severity "Warning"
+    foreach ($rollback in $commands) {
+        if ($Prefix) {
+            Write-Host "$Prefix " -NoNewline -ForegroundColor $PrefixColor
+        }
+        Write-Host "Running rollback: $rollback" -ForegroundColor Yellow
+        $rollbackError = $null
+
+        try {
+            $command = Expand-TaskVariables -Value $rollback -Environment $Environment -UndefinedVars $UndefinedVars
+            $run = Start-TaskProcess -TaskInfo $TaskInfo -TaskName $TaskName -Arguments $Arguments -Prefix $Prefix -PrefixColor $PrefixColor -Command $command -Environment $Environment
+            $exitCode = Complete-TaskProcess -Run $run -Background $Background
+            if ($exitCode -ne 0) {
+                $rollbackError = "rollback '$rollback' exited with code $exitCode"
+            }
+        } catch {
+            $rollbackError = "rollback '$rollback' failed: $_"
+        }
+
+        if ($rollbackError) {
+            $failures++
+            if ($Prefix) {
+                Write-Host "$Prefix " -NoNewline -ForegroundColor $PrefixColor
+            }
+            Write-Host $rollbackError -ForegroundColor Red
+            Write-SecurityLog -Event "TaskRollback" -Details "Task: $TaskName ($rollbackError)" -Severity "Error"
+            Write-BoltLog -Level Error -Message 'rollback failure' -Fields ([ordered]@{ task = $TaskName; command = $rollback; error = $rollbackError })
+        }
+    }
+
+    return $(if ($failures -eq 0) { 'success' } else { 'failure' })
+}
+
+function New-ShellExecutor {
+    <#
+    .SYNOPSIS
+        Creates the built-in executor that runs a task script in this process
+    .DESCRIPTION
+        Returns an executor object with the contract every plugin follows:
+          Name                      the name used in # TYPE: plugin/<name>
+          Execute(task, environment)  runs the task; throw to fail it, or leave a
+                                      non-zero exit code in $LASTEXITCODE
+
+        The shell executor is registered as 'shell' by Get-BoltPluginRegistry and
+        runs every task without a TYPE. Execute builds the script with
+        Get-TaskScriptContent, applies the environment while it runs, and returns
+        with the task's exit code in $LASTEXITCODE. Tasks that need a child process
+        (JSON output, timeouts, -CleanEnv, containers, go-test) use
+        Start-TaskProcess instead.
+    #>
+
+    $executor = [PSCustomObject]@{
+        Name = 'shell'
+    }
+
+    $executor | Add-Member -MemberType ScriptMethod -Name Execute -Value {
+        param($Task, [hashtable]$Environment)
+
+        $scriptBlock = [ScriptBlock]::Create((Get-TaskScriptContent -TaskInfo $Task.Info -TaskName $Task.Name))
+
+        # The task script reads its arguments from $Arguments
+        $Arguments = $Task.Arguments
+        $previousEnvironment = Set-TaskEnvironment -Environment $Environment
+        try {
+            & $scriptBlock
+        } finally {
+            Set-TaskEnvironment -Environment $previousEnvironment | Out-Null
+        }
+    }
+
+    return $executor
+}
+
+function Get-BoltPluginRegistry {
+    <#
+    .SYNOPSIS
+        Returns the registered task executors, by name
+    .DESCRIPTION
+        Creates the registry the first time it is called, with the built-in shell
+        executor from New-ShellExecutor. The registry is a ConcurrentDictionary, so
+        plugins can be registered from any thread or runspace.
+    #>
+
+    if (-not $script:BoltPlugins) {
+        $registry = [System.Collections.Concurrent.ConcurrentDictionary[string, object]]::new()
+        [void]$registry.TryAdd('shell', (New-ShellExecutor))
+        $script:BoltPlugins = $registry
+    }
+
+    return $script:BoltPlugins
+}
+
+function Register-BoltPlugin {
+    <#
+    .SYNOPSIS
+        Registers a task executor for tasks with # TYPE: plugin/<name>
+    .DESCRIPTION
+        Called by the plugin scripts that Import-BoltPlugin loads. A plugin is an
+        object with a Name and an Execute(task, environment) script method, the same
+        contract as the shell executor from New-ShellExecutor. Names follow the task
+        name rules. Registering a name that is already taken, including 'shell',
+        throws PluginAlreadyRegistered.
+    .PARAMETER Plugin
+        The plugin object
+    .EXAMPLE
+        $plugin = [PSCustomObject]@{ Name = 'print' }
+        $plugin | Add-Member -MemberType ScriptMethod -Name Execute -Value { param($Task, $Environment) Write-Host $Task.Name }
+        Register-BoltPlugin -Plugin $plugin
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [PSObject]$Plugin
+    )
+
+    $name = [string]$Plugin.Name
+    if ($name -cnotmatch '^[a-z0-9][a-z0-9\-]*$') {
+        $exception = [System.ArgumentException]::new("Plugin name '$name' is not valid (only lowercase letters, numbers, and hyphens are allowed)")
+        throw [ErrorRecord]::new($exception, 'InvalidPluginName', [ErrorCategory]::InvalidArgument, $Plugin)
+    }
+    if (-not $Plugin.PSObject.Methods['Execute']) {
+        $exception = [System.ArgumentException]::new("Plugin '$name' has no Execute method")
+        throw [ErrorRecord]::new($exception, 'InvalidPlugin', [ErrorCategory]::InvalidArgument, $Plugin)
+    }
+
+    if (-not (Get-BoltPluginRegistry).TryAdd($name, $Plugin)) {
+        $exception = [System.InvalidOperationException]::new("A plugin named '$name' is already registered")
+        throw [ErrorRecord]::new($exception, 'PluginAlreadyRegistered', [ErrorCategory]::ResourceExists, $name)
+    }
+    Write-Verbose "Registered plugin '$name'"
+}
+
+function Import-BoltPlugin {
+    <#
+    .SYNOPSIS
+        Loads the plugin scripts listed in the Plugins setting of bolt.config.json
+    .DESCRIPTION
+        Paths are relative to the project root and must stay inside it. Each script
+        is dot-sourced once per run and calls Register-BoltPlugin. Plugins are only
+        loaded when a plugin task runs or -ValidateTasks checks one. A script that
+        does not exist or throws while loading fails with PluginLoadFailed, and the
+        same error is returned for the rest of the run.
+    .OUTPUTS
+        The registry from Get-BoltPluginRegistry
+    #>
+
+    $registry = Get-BoltPluginRegistry
+    if ($script:BoltPluginLoadError) {
+        throw $script:BoltPluginLoadError
+    }
+    if ($script:BoltPluginsImported) {
+        return $registry
+    }
+    $script:BoltPluginsImported = $true
+
+    $config = Get-BoltConfigFile -ScriptRoot $script:EffectiveScriptRoot -TaskDirectory $TaskDirectory
+    $projectRoot = [System.IO.Path]::GetFullPath($script:EffectiveScriptRoot)
+    foreach ($pluginPath in @($config['Plugins'] | Where-Object { $_ })) {
+        $fullPath = [System.IO.Path]::GetFullPath((Join-Path -Path $projectRoot -ChildPath $pluginPath))
+        $loadError = $null
+        if (-not $fullPath.StartsWith($projectRoot + [System.IO.Path]::DirectorySeparatorChar, [StringComparison]::OrdinalIgnoreCase)) {
+            $loadError = "Plugin script '$pluginPath' must be inside the project root"
+        } elseif (-not (Test-Path -LiteralPath $fullPath -PathType Leaf)) {
+            $loadError = "Plugin script '$pluginPath' does not exist"
+        } else {
+            try {
+                . $fullPath
+            } catch {
+                $loadError = "Plugin script '$pluginPath' failed to load: $($_.Exception.Message)"
+            }
+        }
+
+        if ($loadError) {
+            $script:BoltPluginLoadError = [ErrorRecord]::new([System.InvalidOperationException]::new($loadError), 'PluginLoadFailed', [ErrorCategory]::InvalidData, $pluginPath)
+            throw $script:BoltPluginLoadError
+        }
+    }
+
+    return $registry
+}
+
+function Get-TaskExecutor {
+    <#
+    .SYNOPSIS
+        Returns the executor for a task: the plugin named by # TYPE: plugin/<name>, or shell
+    .DESCRIPTION
+        Throws PluginNotFound when no plugin with that name is registered.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo
+    )
+
+    if ($TaskInfo.Type -notlike 'plugin/*') {
+        return (Get-BoltPluginRegistry)['shell']
+    }
+
+    $name = $TaskInfo.Type.Substring('plugin/'.Length)
+    $registry = Import-BoltPlugin
+    $plugin = $null
+    if (-not $registry.TryGetValue($name, [ref]$plugin)) {
+        $registered = @($registry.Keys | Where-Object { $_ -ne 'shell' } | Sort-Object)
+        $registeredText = if ($registered.Count -gt 0) { $registered -join ', ' } else { 'none' }
+        $exception = [System.InvalidOperationException]::new("Plugin '$name' is not registered (registered plugins: $registeredText). Add its script to Plugins in bolt.config.json")
+        throw [ErrorRecord]::new($exception, 'PluginNotFound', [ErrorCategory]::ObjectNotFound, $name)
+    }
+
+    return $plugin
+}
+
+function Invoke-TaskExecutor {
+    <#
+    .SYNOPSIS
+        Runs a task with an executor from Get-TaskExecutor
+    .DESCRIPTION
+        Passes the executor a task object with Name, Description, ScriptPath,
+        WorkingDirectory, Arguments, Metadata (every # KEY: value line in the first
+        30 lines of the script), and Info (the task metadata Bolt uses), plus the
+        task environment from Get-TaskEnvironment. Execute runs with the working
+        directory as the current location. Pipeline output of Execute is discarded, like the
+        pipeline output of task scripts. $LASTEXITCODE is reset first, so an executor
+        that never sets it succeeds unless it throws.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [PSObject]$Executor,
+
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [Parameter(Mandatory = $true)]
+        [string]$TaskName,
+
+        [array]$Arguments
+    )
+
+    $metadata = [ordered]@{}
+    foreach ($line in @(Get-Content -LiteralPath $TaskInfo.ScriptPath -TotalCount 30 -ErrorAction SilentlyContinue)) {
+        if ($line -match '^#\s*([A-Z][A-Z0-9_]*):[ \t]*(.*)$') {
+            $metadata[$Matches[1]] = $Matches[2].Trim()
+        }
+    }
+
+    $task = [PSCustomObject]@{
+        Name             = $TaskName
+        Description      = $TaskInfo.Description
+        ScriptPath       = $TaskInfo.ScriptPath
+        WorkingDirectory = Get-TaskWorkingDirectory -TaskInfo $TaskInfo
+        Arguments        = @($Arguments | Where-Object { $null -ne $_ })
+        Metadata         = $metadata
+        Info             = $TaskInfo
+    }
+
+    $environment = Get-TaskEnvironment -TaskInfo $TaskInfo
+    if (Test-BoltLogLevel -Level Debug) {
+        Write-BoltLog -Level Debug -Message 'executor start' -Fields ([ordered]@{
+            task     = $TaskName
+            executor = $Executor.Name
+            script   = $TaskInfo.ScriptPath
+            args     = $task.Arguments
+            dir      = $task.WorkingDirectory
+            env_keys = @($environment.Keys | Sort-Object)
+        })
+    }
+    foreach ($entry in (Get-TaskSecretEnvironment -TaskInfo $TaskInfo -TaskName $TaskName).GetEnumerator()) {
+        $environment[$entry.Key] = $entry.Value
+    }
+
+    $global:LASTEXITCODE = 0
+    Push-Location -LiteralPath $task.WorkingDirectory
+    try {
+        $null = $Executor.Execute($task, $environment)
+    } catch [System.Management.Automation.MethodInvocationException] {
+        # Report the error thrown inside Execute instead of "Exception calling Execute"
+        if ($_.Exception.InnerException -is [System.Management.Automation.IContainsErrorRecord]) {
+            throw $_.Exception.InnerException.ErrorRecord
+        }
+        throw
+    } finally {
+        Pop-Location
+    }
+}
+
+function Get-TaskGroupStatus {
+    <#
+    .SYNOPSIS
+        Derives the status of a # TYPE: group task from the results of its members
+    .DESCRIPTION
+        The members of a group are its DEPENDS. The group is failure when a member
+        failed or timed out, skipped when a member did not finish (it never ran, a
+        failure elsewhere cancelled it, or -NoFailFast skipped it), and success otherwise. Members skipped by
+        the cache, # WHEN:, or -Since count as finished. DurationMs is the sum of
+        the members' durations.
+    .PARAMETER Results
+        The task results recorded so far, in the shape Add-TaskResult writes
+    .OUTPUTS
+        PSCustomObject with Status, Members, FailedMembers, PendingMembers, and DurationMs
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [Parameter(Mandatory = $true)]
+        [hashtable]$AllTasks,
+
+        [object[]]$Results = @()
+    )
+
+    $members = @(
+        foreach ($dep in $TaskInfo.Dependencies) {
+            $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $TaskInfo.Namespace -Tasks $AllTasks
+            if ($resolvedDep) { $AllTasks[$resolvedDep].Names[0] } else { $dep }
+        }
+    )
+
+    $failedMembers = [System.Collections.Generic.List[string]]::new()
+    $pendingMembers = [System.Collections.Generic.List[string]]::new()
+    $durationMs = 0
+    foreach ($member in $members) {
+        $result = $Results | Where-Object { $_.Name -eq $member } | Select-Object -Last 1
+        if (-not $result) {
+            $pendingMembers.Add($member)
+            continue
+        }
+
+        $durationMs += $result.DurationMs
+        # Cancelled tasks are recorded as skipped after they started, and skipped groups
+        # and tasks skipped for a failed dependency did not finish either
+        if ($result.Status -in @('failure', 'timeout')) {
+            $failedMembers.Add($member)
+        } elseif ($result.Status -eq 'skipped' -and ($result.Attempts -gt 0 -or $result['Members'] -or $result['SkipReason'] -eq 'dependency')) {
+            $pendingMembers.Add($member)
+        }
+    }
+
+    $status = if ($failedMembers.Count -gt 0) { 'failure' } elseif ($pendingMembers.Count -gt 0) { 'skipped' } else { 'success' }
+    return [PSCustomObject]@{
+        Status         = $status
+        Members        = $members
+        FailedMembers  = $failedMembers.ToArray()
+        PendingMembers = $pendingMembers.ToArray()
+        DurationMs     = $durationMs
+    }
+}
+
+function Complete-TaskGroup {
+    <#
+    .SYNOPSIS
+        Records and prints the result of a # TYPE: group task after its members ran
+    .PARAMETER Label
+        How the group is named in the output line, like "Group 'ci'" or a -Parallel prefix
+    .OUTPUTS
+        [bool] $false when a member failed
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [Parameter(Mandatory = $true)]
+        [hashtable]$AllTasks,
+
+        [Parameter(Mandatory = $true)]
+        [string]$Label
+    )
+
+    $primaryName = $TaskInfo.Names[0]
+    $group = Get-TaskGroupStatus -TaskInfo $TaskInfo -AllTasks $AllTasks -Results @(if ($script:TaskResults) { $script:TaskResults })
+
+    switch ($group.Status) {
+        'success' {
+            Write-Host "$Label succeeded ($($group.Members -join ', '))" -ForegroundColor Green
+            Write-SecurityLog -Event "TaskCompletion" -Details "Group: $primaryName (succeeded)" -Severity "Info"
+        }
+        'failure' {
+            Write-Host "$Label failed (failed members: $($group.FailedMembers -join ', '))" -ForegroundColor Red
+            Write-SecurityLog -Event "TaskCompletion" -Details "Group: $primaryName (failed members: $($group.FailedMembers -join ', '))" -Severity "Error"
+        }
+        'skipped' {
+            Write-Host "$Label skipped (members that did not run: $($group.PendingMembers -join ', '))" -ForegroundColor Yellow
+        }
+    }
+
+    $stderr = if ($group.Status -eq 'failure') { "Failed members: $($group.FailedMembers -join ', ')" } else { '' }
+    Add-TaskResult -Name $primaryName -Status $group.Status -DurationMs $group.DurationMs -ExitCode $(if ($group.Status -eq 'failure') { 1 } else { 0 }) -Stderr $stderr -Attempts 0 -Members $group.Members
+
+    return $group.Status -ne 'failure'
+}
+
+function Invoke-Task {
+    <#
+    .SYNOPSIS
+        Executes a task with dependency resolution
+    #>
+    param(
+        [hashtable]$TaskInfo,
+        [hashtable]$AllTasks,
+        [array]$Arguments,
+        [hashtable]$ExecutedTasks = @{},
+        [bool]$SkipDependencies = $false
+    )
+
+    $primaryName = $TaskInfo.Names[0]
+
+    # Check if already executed (prevent circular dependencies); a task that failed
+    # or was skipped for a failed dependency earlier in the run still counts as failed
+    if ($ExecutedTasks.ContainsKey($primaryName)) {
+        $previous = @(if ($script:TaskResults) { $script:TaskResults }) | Where-Object { $_.Name -eq $primaryName } | Select-Object -Last 1
+        return -not ($previous -and ($previous.Status -in @('failure', 'timeout') -or $previous['SkipReason'] -eq 'dependency' -or
+            ($previous.Status -eq 'skipped' -and $previous['Members'])))
+    }
+
+    # Mark as executed BEFORE processing dependencies to prevent circular loops
     $ExecutedTasks[$primaryName] = $true
 
-    # Execute dependencies first (unless skipped)
-    if ($TaskInfo.Dependencies.Count -gt 0) {
-        if ($SkipDependencies) {
-            Write-Host "Skipping dependencies for '$primaryName': $($TaskInfo.Dependencies -join ', ')" -ForegroundColor Yellow
-        } else {
-            Write-Host "Dependencies for '$primaryName': $($TaskInfo.Dependencies -join ', ')" -ForegroundColor Gray
-            foreach ($dep in $TaskInfo.Dependencies) {
-                # Resolve dependency with namespace priority
-                # If current task has a namespace, first try namespace-prefixed dependency
-                $resolvedDep = $null
-                $depTaskInfo = $null
-
-                if ($TaskInfo.Namespace) {
-                    # Try namespace-prefixed dependency first (e.g., golang-format)
-                    $namespacedDep = "$($TaskInfo.Namespace)-$dep"
-                    if ($AllTasks.ContainsKey($namespacedDep)) {
-                        $resolvedDep = $namespacedDep
-                        $depTaskInfo = $AllTasks[$namespacedDep]
-                        Write-Verbose "Resolved dependency '$dep' to namespaced task '$namespacedDep'"
-                    }
-                }
+    # Execute dependencies first (unless skipped)
+    $failedDependencies = @()
+    if ($TaskInfo.Dependencies.Count -gt 0) {
+        if ($SkipDependencies) {
+            Write-Host "Skipping dependencies for '$primaryName': $($TaskInfo.Dependencies -join ', ')" -ForegroundColor Yellow
+        } else {
+            Write-Host "Dependencies for '$primaryName': $($TaskInfo.Dependencies -join ', ')" -ForegroundColor Gray
+            foreach ($dep in $TaskInfo.Dependencies) {
+                # Resolve dependency with namespace priority
+                # If current task has a namespace, the namespace-prefixed dependency wins (e.g., golang-format)
+                $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $TaskInfo.Namespace -Tasks $AllTasks
+                $depTaskInfo = if ($resolvedDep) { $AllTasks[$resolvedDep] } else { $null }
+
+                if ($resolvedDep -and $resolvedDep -ne $dep) {
+                    Write-Verbose "Resolved dependency '$dep' to namespaced task '$resolvedDep'"
+                }
+
+                if ($depTaskInfo) {
+                    Write-Host "`nExecuting dependency: $resolvedDep" -ForegroundColor Yellow
+                    $depResult = Invoke-Task -TaskInfo $depTaskInfo -AllTasks $AllTasks -Arguments $Arguments -ExecutedTasks $ExecutedTasks
+                    if (-not $depResult) {
+                        Write-Host "Dependency '$resolvedDep' failed" -ForegroundColor Red
+                        $stopped = $script:TaskStopSignal -and $script:TaskStopSignal.Signal.Value -ne 0
+                        # -NoFailFast runs the other dependencies, and the task itself is skipped below
+                        if ($NoFailFast -and -not $stopped) {
+                            $failedDependencies += $resolvedDep
+                            continue
+                        }
+                        # Stop on dependency failure unless ErrorAction permits continuing
+                        if ($FailFast -or $ErrorActionPreference -eq 'Stop' -or $stopped) {
+                            return $false
+                        }
+                        Write-Host "Continuing despite dependency failure due to -ErrorAction $ErrorActionPreference..." -ForegroundColor Yellow
+                    }
+                } else {
+                    Write-Warning "Dependency '$dep' not found, skipping"
+                }
+            }
+            Write-Host ""
+        }
+    }
+
+    # A group still gets its status from its members, other tasks cannot run without their dependencies
+    if ($failedDependencies.Count -gt 0 -and $TaskInfo.Type -ne 'group') {
+        Write-Host "Task '$primaryName' skipped because $($failedDependencies -join ', ') failed (SKIPPED(dependency))" -ForegroundColor Yellow
+        Add-TaskResult -Name $primaryName -Status 'skipped' -Attempts 0 -SkipReason 'dependency'
+        return $false
+    }
+
+    # Execute the task
+    $taskStopwatch = [System.Diagnostics.Stopwatch]::StartNew()
+    if ($TaskInfo.IsCore) {
+        # SECURITY: Log core task execution (P0 - Security Event Logging)
+        Write-SecurityLog -Event "TaskExecution" -Details "Core task: $primaryName" -Severity "Info"
+        Write-BoltLog -Level Info -Message 'task start' -Fields ([ordered]@{ task = $primaryName; source = 'core' })
+
+        # Execute core task function
+        $result = & $TaskInfo.Function
+
+        # Log completion
+        $status = if ($result) { "succeeded" } else { "failed" }
+        Write-SecurityLog -Event "TaskCompletion" -Details "Core task: $primaryName ($status)" -Severity $(if ($result) { "Info" } else { "Error" })
+        Add-TaskResult -Name $primaryName -Status $(if ($result) { 'success' } else { 'failure' }) -DurationMs $taskStopwatch.ElapsedMilliseconds -ExitCode $(if ($result) { 0 } else { 1 })
+
+        return $result
+    } elseif ($TaskInfo.Type -eq 'group') {
+        # A group has no command of its own; its members ran above as its dependencies
+        # A group whose members were skipped for a failed dependency does not let its dependents run
+        $groupSucceeded = Complete-TaskGroup -TaskInfo $TaskInfo -AllTasks $AllTasks -Label "Group '$primaryName'"
+        return ($groupSucceeded -and $failedDependencies.Count -eq 0)
+    } else {
+        # A # PLATFORM: list without this machine skips the task, and its dependents still run
+        if (-not (Test-TaskPlatform -Platform $TaskInfo.Platform)) {
+            $platform = Get-BoltPlatform
+            Write-Host "Task '$primaryName' does not run on $($platform.Os)/$($platform.Arch): $($TaskInfo.Platform -join ', ') (SKIPPED(platform))" -ForegroundColor DarkGreen
+            Add-TaskResult -Name $primaryName -Status 'skipped' -Attempts 0 -SkipReason 'platform'
+            return $true
+        }
+
+        # A false # WHEN: condition skips the task, and its dependents still run
+        if ($TaskInfo.When -and -not (Test-TaskCondition -Expression $TaskInfo.When -Environment (Get-TaskEnvironment -TaskInfo $TaskInfo))) {
+            Write-Host "Task '$primaryName' condition is false: $($TaskInfo.When) (SKIPPED(condition))" -ForegroundColor DarkGreen
+            Add-TaskResult -Name $primaryName -Status 'skipped' -Attempts 0 -SkipReason 'condition'
+            return $true
+        }
+
+        if ($script:SinceSkipped -and $script:SinceSkipped.ContainsKey($primaryName)) {
+            Write-Host "Task '$primaryName' has no changes since $($script:SinceRef) (SKIPPED(git))" -ForegroundColor DarkGreen
+            Add-TaskResult -Name $primaryName -Status 'skipped' -Attempts 0 -SkipReason 'git'
+            return $true
+        }
+
+        # Skip the task when its inputs match the last successful run
+        if (Test-TaskCached -TaskInfo $TaskInfo -Arguments $Arguments) {
+            Restore-TaskCacheOutputVars -TaskInfo $TaskInfo -Arguments $Arguments
+            Write-Host "Task '$primaryName' is up to date (CACHED)" -ForegroundColor DarkGreen
+            Write-SecurityLog -Event "TaskCompletion" -Details "Task: $primaryName (cached)" -Severity "Info"
+            Add-TaskResult -Name $primaryName -Status 'skipped' -DurationMs $taskStopwatch.ElapsedMilliseconds -Attempts 0 -Cached
+            return $true
+        }
+
+        # SECURITY: Log task execution (P0 - Security Event Logging)
+        Write-SecurityLog -Event "TaskExecution" -Details "Task: $primaryName, Script: $($TaskInfo.ScriptPath)" -Severity "Info"
+        Write-BoltLog -Level Info -Message 'task start' -Fields ([ordered]@{ task = $primaryName; source = 'project' })
+
+        try {
+            $timeoutMs = Get-TaskTimeoutMs -TaskInfo $TaskInfo
+        } catch {
+            Write-Host "Task '$primaryName' has an invalid TIMEOUT: $_" -ForegroundColor Red
+            Add-TaskResult -Name $primaryName -Status 'failure' -ExitCode 1 -Stderr "$_" -Attempts 0
+            return $false
+        }
+        $hasResourceLimits = [bool]($TaskInfo.Resources.CpuQuota -or $TaskInfo.Resources.MemoryLimitMb)
+        if ($TaskInfo.Type -like 'plugin/*' -and ($timeoutMs -gt 0 -or $TaskInfo.Container -or $TaskInfo.Snapshot -or $hasResourceLimits)) {
+            $pluginError = "Plugin tasks run in the Bolt process and cannot use TIMEOUT, CONTAINER, SNAPSHOT, CPU_QUOTA, or MEMORY_LIMIT_MB"
+            Write-Host "Task '$primaryName': $pluginError" -ForegroundColor Red
+            Add-TaskResult -Name $primaryName -Status 'failure' -ExitCode 1 -Stderr $pluginError -Attempts 0
+            return $false
+        }
+
+        try {
+            $retryPolicy = Get-TaskRetryPolicy -TaskInfo $TaskInfo
+        } catch {
+            Write-Host "Task '$primaryName' has an invalid RETRY: $_" -ForegroundColor Red
+            Add-TaskResult -Name $primaryName -Status 'failure' -ExitCode 1 -Stderr "$_" -Attempts 0
+            return $false
+        }
+
+        $taskStderr = ''
+        $taskStdout = ''
+        $taskExitCode = 0
+        $taskError = $null
+        $timedOut = $false
+        $taskSignal = 0
+        $taskGoTestSummary = $null
+        $taskOutputVars = $null
+        $taskSecrets = @()
+        $attempt = 0
+        $hookErrors = [System.Collections.Generic.List[string]]::new()
+
+        $rollbackEnvironment = $null
+
+        if (Invoke-TaskHook -TaskInfo $TaskInfo -Phase 'Before' -AllTasks $AllTasks -Arguments $Arguments -HookErrors $hookErrors) {
+            # Rollback commands get the environment the task starts with, not what an in-process task leaves behind
+            if ($TaskInfo.Rollback.Count -gt 0) {
+                $rollbackEnvironment = Get-TaskEnvironment -TaskInfo $TaskInfo -IncludeParent:([bool]$TaskInfo.Container)
+            }
+
+            # Hooks run once; only the task itself is retried
+            while ($true) {
+                $attempt++
+                $taskStderr = ''
+                $taskExitCode = 0
+                $taskError = $null
+                $timedOut = $false
+                $attemptStopwatch = [System.Diagnostics.Stopwatch]::StartNew()
+                if ($attempt -gt 1) {
+                    # Do not let the failed attempt's exit code leak into this one
+                    $global:LASTEXITCODE = 0
+                }
+
+                if ($TaskInfo.Type -notlike 'plugin/*' -and ($OutputFormat -eq 'Json' -or $script:TaskLogDir -or $timeoutMs -gt 0 -or $CleanEnv -or $TaskInfo.Container -or $TaskInfo.Type -eq 'go-test' -or $TaskInfo.Snapshot -or $hasResourceLimits -or $TaskInfo.OutputVars.Count -gt 0 -or $TaskInfo.SecretEnv.Count -gt 0)) {
+                    # Run in a child process so task output cannot mix with the JSON summary on stdout,
+                    # so the whole process tree can be killed when the timeout fires, so
+                    # -CleanEnv does not have to clear the environment of this process,
+                    # so a CONTAINER task can be started with docker run and a
+                    # go-test task with go test, so a SNAPSHOT task's output can be read,
+                    # so output can be copied to a LogDir file, so ::set-output lines can be read,
+                    # so # SECRET_ENV: values can be masked in the output,
+                    # and so CPU and memory limits apply to the task only
+                    try {
+                        $run = Start-TaskProcess -TaskInfo $TaskInfo -TaskName $primaryName -Arguments $Arguments -Prefix '' -TimeoutMs $timeoutMs
+                        $taskExitCode = Complete-TaskProcess -Run $run
+                        $taskStderr = $run.Stderr.ToString()
+                        $taskStdout = if ($null -ne $run.Stdout) { $run.Stdout.ToString() } else { '' }
+                        $timedOut = $run.TimedOut
+                        $taskSignal = $run.Signal
+                        $taskGoTestSummary = $run.GoTestSummary
+                        $taskOutputVars = $run.OutputVars
+                        $taskSecrets = $run.Secrets
+                    } catch {
+                        $taskError = $_
+                    }
+                } else {
+                    # Run in this process with the shell executor, or the plugin named by # TYPE: plugin/<name>
+                    try {
+                        $executor = Get-TaskExecutor -TaskInfo $TaskInfo
+                        Invoke-TaskExecutor -Executor $executor -TaskInfo $TaskInfo -TaskName $primaryName -Arguments $Arguments
+
+                        $taskExitCode = if ($null -ne $LASTEXITCODE) { $LASTEXITCODE } else { 0 }
+                    } catch {
+                        $taskError = $_
+                    }
+                }
+
+                $attemptFailed = $taskError -or $timedOut -or $taskExitCode -ne 0
+                if ($retryPolicy.Attempts -le 1 -or $taskSignal) {
+                    break
+                }
+
+                $elapsed = '{0:N1}s' -f $attemptStopwatch.Elapsed.TotalSeconds
+                if (-not $attemptFailed) {
+                    Write-Host "Task '$primaryName' attempt $attempt of $($retryPolicy.Attempts) succeeded ($elapsed)" -ForegroundColor Green
+                    break
+                }
+
+                $reason = if ($taskError) { "error: $taskError" } elseif ($timedOut) { "timed out" } else { "exit code $taskExitCode" }
+                if ($attempt -ge $retryPolicy.Attempts) {
+                    Write-Host "Task '$primaryName' attempt $attempt of $($retryPolicy.Attempts) failed with $reason ($elapsed), no attempts left" -ForegroundColor Red
+                    break
+                }
+
+                $retryDelay = Get-TaskRetryDelay -Policy $retryPolicy -Attempt $attempt
+                Write-Host "Task '$primaryName' attempt $attempt of $($retryPolicy.Attempts) failed with $reason ($elapsed), retrying in $('{0:N1}s' -f $retryDelay.TotalSeconds)" -ForegroundColor Yellow
+                Write-SecurityLog -Event "TaskRetry" -Details "Task: $primaryName (attempt $attempt of $($retryPolicy.Attempts) failed with $reason)" -Severity "Warning"
+                Write-BoltLog -Level Warn -Message 'task retry' -Fields ([ordered]@{ task = $primaryName; attempt = $attempt; attempts = $retryPolicy.Attempts; reason = $reason })
+                Start-Sleep -Milliseconds ([int]$retryDelay.TotalMilliseconds)
+            }
+        } else {
+            Write-Host "Skipping task '$primaryName' because a before hook failed" -ForegroundColor Red
+            $taskExitCode = 1
+        }
+
+        # After hooks always run, even when the task or a before hook failed
+        $afterHooksSucceeded = Invoke-TaskHook -TaskInfo $TaskInfo -Phase 'After' -AllTasks $AllTasks -Arguments $Arguments -HookErrors $hookErrors
+
+        # A task that ran and failed undoes its partial work; not when bolt itself is being stopped
+        $rollbackStatus = ''
+        if ($attempt -gt 0 -and -not $taskSignal -and ($taskError -or $timedOut -or $taskExitCode -ne 0)) {
+            $rollbackStatus = Invoke-TaskRollback -TaskInfo $TaskInfo -TaskName $primaryName -Arguments $Arguments -Environment $rollbackEnvironment
+        }
+
+        if ($taskError) {
+            Write-SecurityLog -Event "TaskCompletion" -Details "Task: $primaryName (failed with error: $taskError)" -Severity "Error"
+            Add-TaskResult -Name $primaryName -Status 'failure' -DurationMs $taskStopwatch.ElapsedMilliseconds -ExitCode 1 -Stderr "$taskError" -HookErrors $hookErrors -Attempts $attempt -RollbackStatus $rollbackStatus
+            if ($OutputFormat -ne 'Json') {
+                Write-Error "Error executing task '$primaryName': $taskError"
+            }
+            return $false
+        }
+
+        if ($timedOut) {
+            Write-Host "Task '$primaryName' timed out after $($TaskInfo.Timeout)" -ForegroundColor Red
+            Write-SecurityLog -Event "TaskCompletion" -Details "Task: $primaryName (timed out after $($TaskInfo.Timeout))" -Severity "Error"
+            Add-TaskResult -Name $primaryName -Status 'timeout' -DurationMs $run.Stopwatch.ElapsedMilliseconds -ExitCode $taskExitCode -Stderr $taskStderr -HookErrors $hookErrors -Attempts $attempt -GoTestSummary $taskGoTestSummary -RollbackStatus $rollbackStatus
+            return $false
+        }
+
+        if ($taskSignal) {
+            Write-Host "Task '$primaryName' was stopped by $(ConvertTo-SignalName -Signal $taskSignal)" -ForegroundColor Red
+            Write-SecurityLog -Event "TaskCompletion" -Details "Task: $primaryName (stopped by $(ConvertTo-SignalName -Signal $taskSignal))" -Severity "Warning"
+            Add-TaskResult -Name $primaryName -Status 'failure' -DurationMs $run.Stopwatch.ElapsedMilliseconds -ExitCode $taskExitCode -Stderr $taskStderr -HookErrors $hookErrors -Attempts $attempt -GoTestSummary $taskGoTestSummary
+            return $false
+        }
+
+        # Check exit code
+        if ($taskExitCode -ne 0 -or -not $afterHooksSucceeded) {
+            Write-SecurityLog -Event "TaskCompletion" -Details "Task: $primaryName (failed with exit code: $taskExitCode)" -Severity "Error"
+            Add-TaskResult -Name $primaryName -Status 'failure' -DurationMs $taskStopwatch.ElapsedMilliseconds -ExitCode $taskExitCode -Stderr $taskStderr -HookErrors $hookErrors -Attempts $attempt -GoTestSummary $taskGoTestSummary -RollbackStatus $rollbackStatus
+            return $false
+        }
+
+        if ($TaskInfo.Snapshot) {
+            $snapshot = Compare-TaskSnapshot -TaskInfo $TaskInfo -Output $taskStdout -Update:$UpdateSnapshot
+            if ($snapshot.Status -eq 'changed') {
+                Write-Host "Task '$primaryName' output does not match snapshot $($TaskInfo.Snapshot) (run with -UpdateSnapshot to accept it)" -ForegroundColor Red
+                Write-SnapshotDiff -Diff $snapshot.Diff
+                $rollbackStatus = Invoke-TaskRollback -TaskInfo $TaskInfo -TaskName $primaryName -Arguments $Arguments -Environment $rollbackEnvironment
+                Write-SecurityLog -Event "TaskCompletion" -Details "Task: $primaryName (output does not match snapshot)" -Severity "Error"
+                Add-TaskResult -Name $primaryName -Status 'failure' -DurationMs $taskStopwatch.ElapsedMilliseconds -ExitCode $taskExitCode -Stderr $taskStderr -HookErrors $hookErrors -Attempts $attempt -GoTestSummary $taskGoTestSummary -SnapshotDiff ($snapshot.Diff -join "`n") -RollbackStatus $rollbackStatus
+                return $false
+            }
+            if ($snapshot.Status -ne 'match') {
+                Write-Host "Task '$primaryName' snapshot $($snapshot.Status): $($TaskInfo.Snapshot)" -ForegroundColor Yellow
+            }
+        }
+
+        Write-SecurityLog -Event "TaskCompletion" -Details "Task: $primaryName (succeeded)" -Severity "Info"
+        if ($TaskInfo.OutputVars.Count -gt 0) {
+            Publish-TaskOutputVars -TaskInfo $TaskInfo -TaskName $primaryName -Values $(if ($taskOutputVars) { $taskOutputVars } else { @{} })
+        }
+        Save-TaskCache -TaskInfo $TaskInfo -Arguments $Arguments -Secrets $taskSecrets
+        Add-TaskResult -Name $primaryName -Status 'success' -DurationMs $taskStopwatch.ElapsedMilliseconds -Stderr $taskStderr -HookErrors $hookErrors -Attempts $attempt -GoTestSummary $taskGoTestSummary
+        return $true
+    }
+}
+
+function ConvertTo-ContainerPath {
+    <#
+    .SYNOPSIS
+        Maps a path inside the project to where the project is mounted in a container
+    .DESCRIPTION
+        Paths outside the project root are returned unchanged.
+    #>
+    param(
+        [string]$Path,
+        [string]$ContainerRoot
+    )
+
+    $relative = [System.IO.Path]::GetRelativePath($script:EffectiveScriptRoot, $Path) -replace '\\', '/'
+    if ($relative -eq '.') {
+        return $ContainerRoot
+    }
+    if ($relative -eq '..' -or $relative.StartsWith('../') -or [System.IO.Path]::IsPathRooted($relative)) {
+        return $Path
+    }
+    return "$ContainerRoot/$relative"
+}
+
+function ConvertFrom-ContainerVolume {
+    <#
+    .SYNOPSIS
+        Turns a # VOLUMES: entry into a docker run -v value
+    .DESCRIPTION
+        Entries look like source:/container/path or source:/container/path:ro.
+        A source starting with '.' is resolved relative to the project root, a rooted
+        source is used as is, and a plain name (like go-cache) is a named Docker volume.
+    #>
+    param(
+        [string]$Volume
+    )
+
+    if ($Volume -notmatch '^(?<source>.+?):(?<target>/[^:]*)(?::(?<mode>ro|rw))?$') {
+        throw "Volume '$Volume' must look like source:/container/path or source:/container/path:ro"
+    }
+    $source = $Matches['source']
+    $target = $Matches['target']
+    $mode = $Matches['mode']
+
+    if ($source.StartsWith('.')) {
+        $source = [System.IO.Path]::GetFullPath((Join-Path -Path $script:EffectiveScriptRoot -ChildPath $source))
+    } elseif (-not [System.IO.Path]::IsPathRooted($source) -and $source -notmatch '^[A-Za-z0-9][A-Za-z0-9_.\-]*$') {
+        throw "Volume '$Volume' must use a path starting with '.' or '/', or a Docker volume name"
+    }
+
+    if ($mode) {
+        return "${source}:${target}:$mode"
+    }
+    return "${source}:$target"
+}
+
+function Get-TaskContainerArguments {
+    <#
+    .SYNOPSIS
+        Builds the docker run arguments for a task with # CONTAINER:
+    .DESCRIPTION
+        Mounts the project root at -ContainerRoot and the task wrapper script, adds the
+        # VOLUMES: entries, and passes the ENV variables, the Env section of
+        bolt.config.json, and the # ENV_PASSTHROUGH: and # SECRET_ENV: names with -e
+        so docker copies their values in. ${NAME} in the image is replaced with the task environment.
+        # CPU_QUOTA: and # MEMORY_LIMIT_MB: become --cpus and --memory.
+
+        The container gets a unique --name, so Stop-TaskContainer can stop it when
+        the task is cancelled, and --init, so the task receives signals and its child
+        processes are reaped.
+
+        Throws a DockerNotFound error when docker is not on the PATH.
+    .OUTPUTS
+        PSCustomObject with Docker (path to the docker executable), Image, Name, and Arguments
+    #>
+    param(
+        [hashtable]$TaskInfo,
+        [string]$TaskName,
+        [string]$WrapperPath,
+        [string]$ContainerRoot
+    )
+
+    $docker = Get-Command -Name docker -CommandType Application -ErrorAction SilentlyContinue | Select-Object -First 1
+    if (-not $docker) {
+        $exception = [System.InvalidOperationException]::new("Task '$TaskName' runs in container '$($TaskInfo.Container)', but docker was not found on PATH. Install Docker: https://docs.docker.com/get-docker/")
+        throw [ErrorRecord]::new($exception, 'DockerNotFound', [ErrorCategory]::ObjectNotFound, 'docker')
+    }
+
+    $image = Expand-TaskVariables -Value $TaskInfo.Container -Environment (Get-TaskEnvironment -TaskInfo $TaskInfo -IncludeParent) -UndefinedVars $UndefinedVars
+    $workingDirectory = ConvertTo-ContainerPath -Path (Get-TaskWorkingDirectory -TaskInfo $TaskInfo) -ContainerRoot $ContainerRoot
+    # Container names allow letters, digits, '_', '.', and '-'; matrix instance names have brackets
+    $name = "bolt-$($TaskName -replace '[^a-zA-Z0-9_.-]', '-')-$([guid]::NewGuid().ToString('N'))"
+
+    $arguments = @(
+        'run', '--rm', '--init'
+        '--name', $name
+        '-v', "$([System.IO.Path]::GetFullPath($script:EffectiveScriptRoot)):$ContainerRoot"
+        '-v', "${WrapperPath}:/tmp/bolt-task.ps1:ro"
+        '-w', $workingDirectory
+    )
+    foreach ($volume in $TaskInfo.Volumes) {
+        $arguments += '-v', (ConvertFrom-ContainerVolume -Volume $volume)
+    }
+
+    $limits = Get-TaskResourceLimits -TaskInfo $TaskInfo
+    if ($null -ne $limits.CpuQuota) {
+        $arguments += '--cpus', $limits.CpuQuota.ToString([System.Globalization.CultureInfo]::InvariantCulture)
+    }
+    if ($null -ne $limits.MemoryLimitMb) {
+        $arguments += '--memory', "$($limits.MemoryLimitMb)m"
+    }
+
+    $passthrough = @((Get-TaskEnvironment -TaskInfo $TaskInfo -DeclaredOnly).Keys) + @($TaskInfo.EnvPassthrough) + @($TaskInfo.SecretEnv) | Select-Object -Unique
+    foreach ($name in $passthrough) {
+        $arguments += '-e', $name
+    }
+
+    $arguments += $image, 'pwsh', '-NoProfile', '-NonInteractive', '-File', '/tmp/bolt-task.ps1'
+
+    return [PSCustomObject]@{
+        Docker    = $docker.Source
+        Image     = $image
+        Name      = $name
+        Arguments = $arguments
+    }
+}
+
+function Get-GoTestArguments {
+    <#
+    .SYNOPSIS
+        Builds the go test arguments for a task with # TYPE: go-test
+    .DESCRIPTION
+        Runs go test -json, with -race, -cover, and -coverprofile from # RACE:,
+        # COVER:, and # COVERPROFILE:, for the # PACKAGES: (./... by default).
+        go test runs in the # WORKDIR: directory when the task has one, then in the
+        GoPath directory from bolt.config.json when it is set, and in the project
+        root otherwise. COVERPROFILE is relative to the project root.
+    .OUTPUTS
+        PSCustomObject with WorkingDirectory, Arguments, and CoverProfile (full path, or $null)
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo
+    )
+
+    $settings = $TaskInfo.GoTest
+    $flags = @{}
+    foreach ($flag in @('Race', 'Cover')) {
+        $value = $settings[$flag]
+        if ($value -and $value -notin @('true', 'false')) {
+            throw "$($flag.ToUpper()) must be true or false: $value"
+        }
+        $flags[$flag] = $value -eq 'true'
+    }
+
+    $boltConfig = Get-BoltConfig -ScriptRoot $script:EffectiveScriptRoot -TaskDirectory $TaskDirectory
+    $workingDirectory = [System.IO.Path]::GetFullPath($script:EffectiveScriptRoot)
+    if ($TaskInfo.WorkDir) {
+        $workingDirectory = Get-TaskWorkingDirectory -TaskInfo $TaskInfo
+    } elseif ($boltConfig.GoPath) {
+        $workingDirectory = [System.IO.Path]::GetFullPath((Join-Path -Path $script:EffectiveScriptRoot -ChildPath $boltConfig.GoPath))
+    }
+
+    $arguments = @('test', '-json')
+    if ($flags.Race) {
+        $arguments += '-race'
+    }
+    if ($flags.Cover) {
+        $arguments += '-cover'
+    }
+    $coverProfile = $null
+    if ($settings.CoverProfile) {
+        $coverProfile = [System.IO.Path]::GetFullPath((Join-Path -Path $script:EffectiveScriptRoot -ChildPath $settings.CoverProfile))
+        $arguments += "-coverprofile=$coverProfile"
+    }
+    $arguments += if ($settings.Packages.Count -gt 0) { $settings.Packages } else { @('./...') }
+
+    return [PSCustomObject]@{
+        WorkingDirectory = $workingDirectory
+        Arguments        = $arguments
+        CoverProfile     = $coverProfile
+    }
+}
+
+function New-GoTestSummary {
+    <#
+    .SYNOPSIS
+        Returns an empty GoTestSummary for Read-GoTestEvent to fill in
+    #>
+
+    return [ordered]@{
+        Passed      = 0
+        Failed      = 0
+        Skipped     = 0
+        Coverage    = $null
+        FailedTests = [System.Collections.Generic.List[string]]::new()
+        Packages    = [System.Collections.Generic.List[object]]::new()
+    }
+}
+
+function Read-GoTestEvent {
+    <#
+    .SYNOPSIS
+        Adds one line of go test -json output to a GoTestSummary
+    .DESCRIPTION
+        Each line is a TestEvent from cmd/test2json (Time, Action, Package, Test,
+        Elapsed, Output). The pass, fail, and skip events of a test are counted, the
+        same events without a test set the package Status, and "coverage: N% of
+        statements" output sets the package Coverage. Lines that are not JSON, like
+        build errors, are returned unchanged.
+    .OUTPUTS
+        The text to show for the line, or $null when there is nothing to show
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [System.Collections.Specialized.OrderedDictionary]$Summary,
+
+        [AllowEmptyString()]
+        [string]$Line
+    )
+
+    if (-not $Line.StartsWith('{')) {
+        return $Line
+    }
+    try {
+        $testEvent = $Line | ConvertFrom-Json -ErrorAction Stop
+    } catch {
+        return $Line
+    }
+    if (-not $testEvent.Action) {
+        return $Line
+    }
+
+    $package = $null
+    if ($testEvent.Package) {
+        $package = $Summary.Packages | Where-Object { $_.Name -eq $testEvent.Package } | Select-Object -First 1
+        if (-not $package) {
+            $package = [ordered]@{ Name = $testEvent.Package; Status = 'run'; Coverage = $null; Elapsed = 0 }
+            $Summary.Packages.Add($package)
+        }
+    }
+
+    switch ($testEvent.Action) {
+        'output' {
+            if ($package -and -not $testEvent.Test -and $testEvent.Output -match 'coverage: (\d+(?:\.\d+)?)% of statements') {
+                $package.Coverage = [double]::Parse($Matches[1], [System.Globalization.CultureInfo]::InvariantCulture)
+            }
+            return ([string]$testEvent.Output).TrimEnd("`r`n".ToCharArray())
+        }
+        { $_ -in @('pass', 'fail', 'skip') } {
+            if ($testEvent.Test) {
+                switch ($testEvent.Action) {
+                    'pass' { $Summary.Passed++ }
+                    'skip' { $Summary.Skipped++ }
+                    'fail' {
+                        $Summary.Failed++
+                        $Summary.FailedTests.Add("$($testEvent.Package).$($testEvent.Test)")
+                    }
+                }
+            } elseif ($package) {
+                $package.Status = $testEvent.Action
+                if ($null -ne $testEvent.Elapsed) {
+                    $package.Elapsed = $testEvent.Elapsed
+                }
+            }
+        }
+    }
+
+    return $null
+}
+
+function Complete-GoTestSummary {
+    <#
+    .SYNOPSIS
+        Sets the overall Coverage of a GoTestSummary
+    .DESCRIPTION
+        With a coverage profile, Coverage is the percentage of statements in the
+        profile that ran at least once. Without one it is the average of the package
+        coverage values. It stays $null when go test reported no coverage.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [System.Collections.Specialized.OrderedDictionary]$Summary,
+
+        [string]$CoverProfile
+    )
+
+    if ($CoverProfile -and (Test-Path -LiteralPath $CoverProfile -PathType Leaf)) {
+        # The same block can be listed once per test binary, so count it once
+        $blocks = @{}
+        foreach ($line in [System.IO.File]::ReadLines($CoverProfile)) {
+            if ($line -notmatch '^(?<block>.+:\d+\.\d+,\d+\.\d+) (?<statements>\d+) (?<count>\d+)$') {
+                continue
+            }
+            $block = $Matches['block']
+            $covered = [long]$Matches['count'] -gt 0
+            if ($blocks.ContainsKey($block)) {
+                $blocks[$block].Covered = $blocks[$block].Covered -or $covered
+            } else {
+                $blocks[$block] = @{ Statements = [long]$Matches['statements']; Covered = $covered }
+            }
+        }
+
+        $totalStatements = 0
+        $coveredStatements = 0
+        foreach ($entry in $blocks.Values) {
+            $totalStatements += $entry.Statements
+            if ($entry.Covered) {
+                $coveredStatements += $entry.Statements
+            }
+        }
+        if ($totalStatements -gt 0) {
+            $Summary.Coverage = [Math]::Round(100.0 * $coveredStatements / $totalStatements, 1)
+            return
+        }
+    }
+
+    $packageCoverage = @($Summary.Packages | Where-Object { $null -ne $_.Coverage } | ForEach-Object { $_.Coverage })
+    if ($packageCoverage.Count -gt 0) {
+        $Summary.Coverage = [Math]::Round(($packageCoverage | Measure-Object -Average).Average, 1)
+    }
+}
+
+function Get-TaskResourceLimits {
+    <#
+    .SYNOPSIS
+        Reads the CPU and memory limits of a task
+    .DESCRIPTION
+        # CPU_QUOTA: is the fraction of one core the task may use, greater than 0 and
+        at most 1.0. # MEMORY_LIMIT_MB: is the most memory the task may use, in
+        megabytes. Invalid values throw an InvalidResourceLimit error.
+    .OUTPUTS
+        PSCustomObject with CpuQuota ([double] or $null) and MemoryLimitMb ([long] or $null)
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo
+    )
+
+    $fail = {
+        param([string]$Message)
+
+        $exception = [System.FormatException]::new($Message)
+        throw [ErrorRecord]::new($exception, 'InvalidResourceLimit', [ErrorCategory]::InvalidArgument, $TaskInfo.Names[0])
+    }
+
+    $limits = [PSCustomObject]@{
+        CpuQuota      = $null
+        MemoryLimitMb = $null
+    }
+
+    $cpuQuota = $TaskInfo.Resources.CpuQuota
+    if ($cpuQuota) {
+        $value = 0.0
+        if (-not [double]::TryParse($cpuQuota, [System.Globalization.NumberStyles]::Float, [System.Globalization.CultureInfo]::InvariantCulture, [ref]$value) -or $value -le 0 -or $value -gt 1) {
+            & $fail "CPU_QUOTA must be a number greater than 0 and at most 1.0: $cpuQuota"
+        }
+        $limits.CpuQuota = $value
+    }
+
+    $memoryLimit = $TaskInfo.Resources.MemoryLimitMb
+    if ($memoryLimit) {
+        $value = 0L
+        if (-not [long]::TryParse($memoryLimit, [System.Globalization.NumberStyles]::None, [System.Globalization.CultureInfo]::InvariantCulture, [ref]$value) -or $value -le 0) {
+            & $fail "MEMORY_LIMIT_MB must be a whole number of megabytes greater than 0: $memoryLimit"
+        }
+        $limits.MemoryLimitMb = $value
+    }
+
+    return $limits
+}
+
+function New-TaskCgroup {
+    <#
+    .SYNOPSIS
+        Creates the cgroup v2 group a task with CPU or memory limits runs in
+    .DESCRIPTION
+        The group is <Root>/<task name>, where Root is BOLT_CGROUP_ROOT or
+        /sys/fs/cgroup/bolt. The cpu and memory controllers are enabled for the
+        children of Root (and of its parent, when Bolt may), then the limits are
+        written to cpu.max (quota and period in microseconds) and memory.max (bytes).
+        With a memory limit, memory.swap.max is set to 0 so the task cannot swap
+        instead. An existing group of the same name is reused.
+
+        Throws a CgroupUnavailable error when the group cannot be created or its
+        limits cannot be written, usually because Bolt has no write access to Root.
+    .OUTPUTS
+        The full path of the group
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$TaskName,
+
+        [Parameter(Mandatory = $true)]
+        [PSCustomObject]$Limits,
+
+        [string]$Root = $(if ($env:BOLT_CGROUP_ROOT) { $env:BOLT_CGROUP_ROOT } else { '/sys/fs/cgroup/bolt' })
+    )
+
+    # Matrix instance names contain brackets and namespaces a colon
+    $path = Join-Path -Path $Root -ChildPath ($TaskName -replace '[^A-Za-z0-9_.\-]', '_')
+
+    try {
+        New-Item -ItemType Directory -Path $Root -Force -ErrorAction Stop | Out-Null
+        foreach ($directory in @((Split-Path -Path $Root -Parent), $Root)) {
+            $subtreeControl = Join-Path -Path $directory -ChildPath 'cgroup.subtree_control'
+            if (-not (Test-Path -LiteralPath $subtreeControl)) {
+                continue
+            }
+            try {
+                [System.IO.File]::WriteAllText($subtreeControl, '+cpu +memory')
+            } catch {
+                # The parent of Root is often not writable; its controllers may already be enabled
+                if ($directory -eq $Root) {
+                    throw
+                }
+                Write-Verbose "Could not enable the cpu and memory controllers in ${directory}: $($_.Exception.Message)"
+            }
+        }
+
+        New-Item -ItemType Directory -Path $path -Force -ErrorAction Stop | Out-Null
+        if ($null -ne $Limits.CpuQuota) {
+            $period = 100000
+            # The kernel does not accept a quota below 1ms
+            $quota = [Math]::Max(1000, [long][Math]::Round($Limits.CpuQuota * $period))
+            [System.IO.File]::WriteAllText((Join-Path -Path $path -ChildPath 'cpu.max'), "$quota $period")
+        }
+        if ($null -ne $Limits.MemoryLimitMb) {
+            [System.IO.File]::WriteAllText((Join-Path -Path $path -ChildPath 'memory.max'), "$($Limits.MemoryLimitMb * 1MB)")
+            $swapMax = Join-Path -Path $path -ChildPath 'memory.swap.max'
+            if (Test-Path -LiteralPath $swapMax) {
+                [System.IO.File]::WriteAllText($swapMax, '0')
+            }
+        }
+    } catch {
+        $exception = [System.InvalidOperationException]::new("Cannot set the CPU and memory limits of task '$TaskName' in cgroup ${path}: $($_.Exception.Message). Bolt needs write access to $Root; set BOLT_CGROUP_ROOT to a cgroup delegated to your user (for example one created by systemd-run --user --scope -p Delegate=yes).")
+        throw [ErrorRecord]::new($exception, 'CgroupUnavailable', [ErrorCategory]::PermissionDenied, $path)
+    }
+
+    return $path
+}
+
+function Remove-TaskCgroup {
+    <#
+    .SYNOPSIS
+        Removes the cgroup of a task that has exited
+    .DESCRIPTION
+        A cgroup can only be removed when no process is left in it, so a failure is
+        only written to the verbose stream; the next run of the task reuses the group.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Path
+    )
+
+    try {
+        [System.IO.Directory]::Delete($Path)
+    } catch {
+        Write-Verbose "Could not remove cgroup ${Path}: $($_.Exception.Message)"
+    }
+}
+
+function Clear-TaskLogIndex {
+    <#
+    .SYNOPSIS
+        Starts a new last-run.txt in LogDir, the list of log files -ListLogs prints
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$LogDir
+    )
+
+    New-Item -ItemType Directory -Path $LogDir -Force | Out-Null
+    Set-Content -LiteralPath (Join-Path -Path $LogDir -ChildPath 'last-run.txt') -Value @() -Encoding utf8
+}
+
+function New-TaskLog {
+    <#
+    .SYNOPSIS
+        Creates the LogDir file that a child task's output is copied to
+    .DESCRIPTION
+        The file is <LogDir>/<task>-<yyyyMMdd-HHmmss-fff>.log and its path is added
+        to last-run.txt for -ListLogs. Receive-TaskProcessOutput writes standard
+        output and standard error lines to it in the order they arrive, through a
+        buffered writer. Complete-TaskProcess disposes the writer in a finally block,
+        so the buffer is flushed even when the wait for the task is interrupted.
+    .OUTPUTS
+        PSCustomObject with Path and Writer
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$TaskName,
+
+        [Parameter(Mandatory = $true)]
+        [string]$LogDir
+    )
+
+    New-Item -ItemType Directory -Path $LogDir -Force | Out-Null
+
+    # Matrix instance names contain brackets and commas, which do not belong in file names
+    $timestamp = [DateTime]::Now.ToString('yyyyMMdd-HHmmss-fff', [System.Globalization.CultureInfo]::InvariantCulture)
+    $path = Join-Path -Path $LogDir -ChildPath "$($TaskName -replace '[^A-Za-z0-9\-]+', '_')-$timestamp.log"
+    $writer = [System.IO.StreamWriter]::new($path, $false, [System.Text.UTF8Encoding]::new($false))
+
+    Add-Content -LiteralPath (Join-Path -Path $LogDir -ChildPath 'last-run.txt') -Value $path -Encoding utf8
+
+    return [PSCustomObject]@{
+        Path   = $path
+        Writer = $writer
+    }
+}
+
+function Start-TaskProcess {
+    <#
+    .SYNOPSIS
+        Starts a project task in a child PowerShell process
+    .DESCRIPTION
+        Writes the task script from Get-TaskScriptContent to a temporary wrapper file and
+        runs it with the current pwsh executable and the environment from
+        Get-TaskEnvironment. Standard output and standard error are
+        redirected and read one line at a time by Receive-TaskProcessOutput, which
+        also keeps the standard error text in the run object's Stderr.
+
+        With -TimeoutMs, Complete-TaskProcess (or the parallel scheduler) kills the
+        process tree once the task has run that long and sets TimedOut.
+
+        A task with # CONTAINER: runs the same wrapper with pwsh inside the image,
+        using the docker run arguments from Get-TaskContainerArguments. The run's
+        Container has the docker path and container name for Stop-TaskContainer.
+
+        A task with # TYPE: go-test runs go test -json with the arguments from
+        Get-GoTestArguments instead of the wrapper. Receive-TaskProcessOutput reads
+        its events into the run's GoTestSummary.
+
+        The # SECRET_ENV: values from Get-TaskSecretEnvironment are added to the
+        process environment only, and Receive-TaskProcessOutput masks them in output.
+        For a task with # SNAPSHOT:, the run's Stdout collects the standard output.
+        For a task with # OUTPUT_VARS:, the run's OutputVars collects the values of
+        its ::set-output lines.
+        With LogDir in bolt.config.json, the run's Log from New-TaskLog gets a copy
+        of every output line.
+
+        On Linux, a task with # CPU_QUOTA: or # MEMORY_LIMIT_MB: (and no CONTAINER)
+        starts through /bin/sh, which moves itself into the cgroup from New-TaskCgroup
+        before it runs the task, so every process the task starts has the limits.
+        Elsewhere the limits are ignored, unless -StrictResources makes them an error.
+
+        When bolt receives SIGINT or SIGTERM while it waits for the task,
+        Stop-TaskProcessGracefully stops it and sets Signal.
+
+        With -Command, the wrapper runs that PowerShell command instead of the task
+        script, with the same directory, container, and limits. This is how
+        Invoke-TaskRollback runs # ROLLBACK: commands. -Environment replaces the
+        environment from Get-TaskEnvironment, and the run's Environment is the one the
+        process got (without secrets).
+    .OUTPUTS
+        PSCustomObject describing the running task
+    #>
+    param(
+        [hashtable]$TaskInfo,
+        [string]$TaskName,
+        [array]$Arguments,
+        [string]$Prefix = "[$TaskName]",
+        [string]$PrefixColor = 'Cyan',
+        [long]$TimeoutMs = 0,
+        [string]$Command = '',
+        [System.Collections.IDictionary]$Environment = $null
+    )
+
+    # A container only gets the variables passed with -e, so docker itself keeps the parent environment
+    $taskEnvironment = if ($null -ne $Environment) { $Environment } else { Get-TaskEnvironment -TaskInfo $TaskInfo -IncludeParent:([bool]$TaskInfo.Container) }
+    $secretEnvironment = Get-TaskSecretEnvironment -TaskInfo $TaskInfo -TaskName $TaskName
+
+    $startInfo = [System.Diagnostics.ProcessStartInfo]::new()
+    $startInfo.WorkingDirectory = Get-TaskWorkingDirectory -TaskInfo $TaskInfo
+    $wrapperPath = $null
+    $goTest = $null
+    $container = $null
+
+    if ($TaskInfo.Type -eq 'go-test' -and -not $Command) {
+        $go = Get-Command -Name go -CommandType Application -ErrorAction SilentlyContinue | Select-Object -First 1
+        if (-not $go) {
+            $exception = [System.InvalidOperationException]::new("Task '$TaskName' runs go test, but go was not found on PATH. Install Go: https://go.dev/doc/install")
+            throw [ErrorRecord]::new($exception, 'GoNotFound', [ErrorCategory]::ObjectNotFound, 'go')
+        }
+        $goTest = Get-GoTestArguments -TaskInfo $TaskInfo
+        $startInfo.FileName = $go.Source
+        $startInfo.WorkingDirectory = $goTest.WorkingDirectory
+        $processArguments = $goTest.Arguments
+    } else {
+        # A CONTAINER task sees the project mounted at /workspace
+        $containerRoot = if ($TaskInfo.Container) { '/workspace' } else { $null }
+        # The process already starts in the task's directory (docker run -w in a container)
+        $scriptContent = if ($Command) { $Command } else { Get-TaskScriptContent -TaskInfo $TaskInfo -TaskName $TaskName -ContainerRoot $containerRoot }
+
+        # The wrapper exits with the task's exit code so the parent can read it from the process
+        $wrapperContent = @"
+param([Parameter(ValueFromRemainingArguments)][string[]]`$Arguments)
+[Console]::OutputEncoding = [System.Text.Encoding]::UTF8
+$scriptContent
+if (`$null -ne `$LASTEXITCODE -and `$LASTEXITCODE -ne 0) { exit `$LASTEXITCODE }
+exit 0
+"@
+
+        # Matrix instance names contain brackets, which are wildcards for -Path and -File
+        $wrapperPath = Join-Path -Path ([System.IO.Path]::GetTempPath()) -ChildPath "bolt-$($TaskName -replace '[^a-z0-9\-]', '_')-$([guid]::NewGuid().ToString('N')).ps1"
+        Set-Content -LiteralPath $wrapperPath -Value $wrapperContent -Encoding utf8
+
+        if ($TaskInfo.Container) {
+            try {
+                $container = Get-TaskContainerArguments -TaskInfo $TaskInfo -TaskName $TaskName -WrapperPath $wrapperPath -ContainerRoot $containerRoot
+            } catch {
+                Remove-Item -LiteralPath $wrapperPath -Force -ErrorAction SilentlyContinue
+                throw
+            }
+            $startInfo.FileName = $container.Docker
+            $processArguments = $container.Arguments
+        } else {
+            $startInfo.FileName = (Get-Process -Id $PID).Path
+            $processArguments = @('-NoProfile', '-NonInteractive', '-File', $wrapperPath)
+        }
+    }
+
+    # A CONTAINER task gets its limits from docker run
+    $cgroupPath = $null
+    if (($TaskInfo.Resources.CpuQuota -or $TaskInfo.Resources.MemoryLimitMb) -and -not $TaskInfo.Container) {
+        try {
+            $limits = Get-TaskResourceLimits -TaskInfo $TaskInfo
+            if ($IsLinux) {
+                $cgroupPath = New-TaskCgroup -TaskName $TaskName -Limits $limits
+            } elseif ($StrictResources) {
+                $exception = [System.PlatformNotSupportedException]::new("Task '$TaskName' sets CPU_QUOTA or MEMORY_LIMIT_MB, which only work on Linux or in a # CONTAINER: task")
+                throw [ErrorRecord]::new($exception, 'ResourceLimitsUnsupported', [ErrorCategory]::NotImplemented, $TaskName)
+            } else {
+                Write-Verbose "Ignoring the CPU and memory limits of task '$TaskName' (cgroups need Linux)"
+            }
+        } catch {
+            if ($wrapperPath) {
+                Remove-Item -LiteralPath $wrapperPath -Force -ErrorAction SilentlyContinue
+            }
+            throw
+        }
+    }
+    if ($cgroupPath) {
+        # $0 is the cgroup and "$@" the task command line
+        $processArguments = @('-c', 'echo $$ > "$0/cgroup.procs" && exec "$@"', $cgroupPath, $startInfo.FileName) + $processArguments
+        $startInfo.FileName = '/bin/sh'
+    }
+
+    foreach ($argument in $processArguments + @($Arguments | Where-Object { $null -ne $_ })) {
+        $startInfo.ArgumentList.Add([string]$argument)
+    }
+    $startInfo.UseShellExecute = $false
+    $startInfo.RedirectStandardOutput = $true
+    $startInfo.RedirectStandardError = $true
+    $startInfo.StandardOutputEncoding = [System.Text.Encoding]::UTF8
+    $startInfo.StandardErrorEncoding = [System.Text.Encoding]::UTF8
+
+    # Replace the inherited environment with the merged config and task environment
+    $startInfo.Environment.Clear()
+    foreach ($entry in $taskEnvironment.GetEnumerator()) {
+        $startInfo.Environment[$entry.Key] = $entry.Value
+    }
+    foreach ($entry in $secretEnvironment.GetEnumerator()) {
+        $startInfo.Environment[$entry.Key] = $entry.Value
+    }
+
+    try {
+        $process = [System.Diagnostics.Process]::Start($startInfo)
+    } catch {
+        if ($wrapperPath) {
+            Remove-Item -LiteralPath $wrapperPath -Force -ErrorAction SilentlyContinue
+        }
+        throw
+    }
+
+    # Environment keys only; values can hold secrets. Secret names are left out
+    # (docker run gets them with -e)
+    if (Test-BoltLogLevel -Level Debug) {
+        Write-BoltLog -Level Debug -Message 'process start' -Fields ([ordered]@{
+            task     = $TaskName
+            pid      = $process.Id
+            argv     = @(@($startInfo.FileName) + @($startInfo.ArgumentList) | ForEach-Object { if ($TaskInfo.SecretEnv -ccontains $_) { '***' } else { $_ } })
+            dir      = $startInfo.WorkingDirectory
+            env_keys = @($taskEnvironment.Keys | Sort-Object)
+            started  = [DateTimeOffset]::Now.ToString('o')
+        })
+    }
+
+    # A copy of the output for LogDir in bolt.config.json
+    $taskLog = $null
+    if ($script:TaskLogDir) {
+        try {
+            $taskLog = New-TaskLog -TaskName $TaskName -LogDir $script:TaskLogDir
+        } catch {
+            Write-Warning "Could not create a log file for task '$TaskName' in '$($script:TaskLogDir)': $($_.Exception.Message)"
+        }
+    }
+
+    return [PSCustomObject]@{
+        Name          = $TaskName
+        Prefix        = $Prefix
+        PrefixColor   = $PrefixColor
+        Process       = $process
+        WrapperPath   = $wrapperPath
+        StdoutRead    = $process.StandardOutput.ReadLineAsync()
+        StderrRead    = $process.StandardError.ReadLineAsync()
+        Stderr        = [System.Text.StringBuilder]::new()
+        TimeoutMs     = $TimeoutMs
+        TimedOut      = $false
+        Signal        = 0
+        GoTestSummary = if ($goTest) { New-GoTestSummary } else { $null }
+        CoverProfile  = if ($goTest) { $goTest.CoverProfile } else { $null }
+        Secrets       = @($secretEnvironment.Values)
+        Environment   = $taskEnvironment
+        Stdout        = if ($TaskInfo.Snapshot) { [System.Text.StringBuilder]::new() } else { $null }
+        OutputVars    = if ($TaskInfo.OutputVars.Count -gt 0 -and -not $Command) { [ordered]@{} } else { $null }
+        DeclaredVars  = @($TaskInfo.OutputVars)
+        LastLine      = ''
+        Log           = $taskLog
+        Cgroup        = $cgroupPath
+        Container     = if ($container) { [PSCustomObject]@{ Docker = $container.Docker; Name = $container.Name } } else { $null }
+        Stopwatch     = [System.Diagnostics.Stopwatch]::StartNew()
+    }
+}
+
+function Receive-TaskProcessOutput {
+    <#
+    .SYNOPSIS
+        Writes the complete output lines a child task has produced so far
+    .DESCRIPTION
+        Drains every line that is already available on standard output and standard
+        error without blocking. Each line is prefixed with the task's prefix (if any)
+        so output from concurrent tasks stays readable. Secret values are shown as ***.
+        The last line is kept in LastLine for the progress display, and every line
+        is also written to the run's Log file (without the prefix) when it has one.
+        When the run collects OutputVars, ::set-output lines are stored there instead
+        of being shown, logged, or compared with a # SNAPSHOT:.
+    .OUTPUTS
+        $true if at least one line was read
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [PSCustomObject]$Run
+    )
+
+    $activity = $false
+
+    while ($null -ne $Run.StdoutRead -and $Run.StdoutRead.IsCompleted) {
+        $line = $Run.StdoutRead.Result
+        if ($null -eq $line) {
+            $Run.StdoutRead = $null
+            break
+        }
+        if ($Run.GoTestSummary) {
+            # go test -json writes one TestEvent per line; show only the test output
+            $line = Read-GoTestEvent -Summary $Run.GoTestSummary -Line $line
+        }
+        $setOutput = if ($null -ne $Run.OutputVars) { ConvertFrom-SetOutputLine -Line $line }
+        if ($setOutput) {
+            if ($Run.DeclaredVars -ccontains $setOutput.Name) {
+                $Run.OutputVars[$setOutput.Name] = $setOutput.Value
+            } else {
+                Write-Warning "Task '$($Run.Name)' set '$($setOutput.Name)', which is not in its OUTPUT_VARS; the value is ignored"
+            }
+            $line = $null
+        }
+        if ($null -ne $line -and $Run.Secrets) {
+            $line = Protect-SecretText -Text $line -Secret $Run.Secrets
+        }
+        if ($null -ne $line) {
+            if ($null -ne $Run.Stdout) {
+                [void]$Run.Stdout.AppendLine($line)
+            }
+            $Run.LastLine = $line
+            if ($Run.Log) {
+                $Run.Log.Writer.WriteLine($line)
+            }
+            if ($Run.Prefix) {
+                Write-Host "$($Run.Prefix) " -NoNewline -ForegroundColor $Run.PrefixColor
+            }
+            Write-Host $line
+        }
+        $Run.StdoutRead = $Run.Process.StandardOutput.ReadLineAsync()
+        $activity = $true
+    }
+
+    while ($null -ne $Run.StderrRead -and $Run.StderrRead.IsCompleted) {
+        $line = $Run.StderrRead.Result
+        if ($null -eq $line) {
+            $Run.StderrRead = $null
+            break
+        }
+        if ($Run.Secrets) {
+            $line = Protect-SecretText -Text $line -Secret $Run.Secrets
+        }
+        [void]$Run.Stderr.AppendLine($line)
+        $Run.LastLine = $line
+        if ($Run.Log) {
+            $Run.Log.Writer.WriteLine($line)
+        }
+        if ($Run.Prefix) {
+            Write-Host "$($Run.Prefix) " -NoNewline -ForegroundColor $Run.PrefixColor
+        }
+        Write-Host $line -ForegroundColor Red
+        $Run.StderrRead = $Run.Process.StandardError.ReadLineAsync()
+        $activity = $true
+    }
+
+    return $activity
+}
+
+function Complete-TaskProcess {
+    <#
+    .SYNOPSIS
+        Waits for a child task to exit, writes its remaining output, and cleans up
+    .DESCRIPTION
+        A SIGINT or SIGTERM received while waiting stops the task with
+        Stop-TaskProcessGracefully. If the wait is interrupted some other way (for
+        example when the PowerShell host stops the script on Ctrl+C), the task is
+        still stopped before this function returns.
+
+        -Background are other runs that keep going while this one is waited for, as
+        in Invoke-TaskParallel. Their output is relayed and their timeouts are
+        enforced during the wait, but they are left running.
+    .OUTPUTS
+        The process exit code, or 128 + the signal number when the task was stopped by a signal
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [PSCustomObject]$Run,
+
+        [object[]]$Background = @()
+    )
+
+    $stopSignal = Register-TaskStopSignal
+    $stopSignal.Waiting.Value++
+    try {
+        # Keep reading while waiting so a chatty task cannot block on a full pipe
+        while (-not $Run.Process.HasExited -or $null -ne $Run.StdoutRead -or $null -ne $Run.StderrRead) {
+            if ($stopSignal.Signal.Value -ne 0 -and $Run.Signal -eq 0) {
+                Stop-TaskProcessGracefully -Run $Run -Signal $stopSignal.Signal.Value
+                continue
+            }
+            if (Test-TaskProcessTimeout -Run $Run) {
+                continue
+            }
+            $activity = $false
+            foreach ($backgroundRun in $Background) {
+                if (Receive-TaskProcessOutput -Run $backgroundRun) {
+                    $activity = $true
+                }
+                if (Test-TaskProcessTimeout -Run $backgroundRun) {
+                    $activity = $true
+                }
+            }
+            if (-not (Receive-TaskProcessOutput -Run $Run) -and -not $activity) {
+                $sleepMs = 10
+                if ($Run.TimeoutMs -gt 0 -and -not $Run.TimedOut) {
+                    $sleepMs = [Math]::Max(1, [Math]::Min(10, $Run.TimeoutMs - $Run.Stopwatch.ElapsedMilliseconds))
+                }
+                Start-Sleep -Milliseconds $sleepMs
+            }
+        }
+    } finally {
+        if (-not $Run.Process.HasExited) {
+            if ($stopSignal.Signal.Value -ne 0) {
+                Stop-TaskProcessGracefully -Run $Run -Signal $stopSignal.Signal.Value
+            } else {
+                Stop-TaskContainer -Run $Run
+                try {
+                    $Run.Process.Kill($true)
+                } catch {
+                    Write-Verbose "Could not stop task '$($Run.Name)': $_"
+                }
+            }
+            if ($Run.WrapperPath) {
+                Remove-Item -LiteralPath $Run.WrapperPath -Force -ErrorAction SilentlyContinue
+            }
+        }
+        $stopSignal.Waiting.Value--
+
+        # Dispose flushes the buffered log writer, also when the wait was interrupted
+        if ($Run.Log) {
+            $Run.Log.Writer.Dispose()
+            $Run.Log = $null
+        }
+    }
+    $Run.Process.WaitForExit()
+
+    $Run.Stopwatch.Stop()
+    $exitCode = $Run.Process.ExitCode
+    if (Test-BoltLogLevel -Level Debug) {
+        Write-BoltLog -Level Debug -Message 'process exit' -Fields ([ordered]@{
+            task        = $Run.Name
+            pid         = $Run.Process.Id
+            exit_code   = $exitCode
+            duration_ms = $Run.Stopwatch.ElapsedMilliseconds
+            stopped     = [DateTimeOffset]::Now.ToString('o')
+        })
+    }
+    $Run.Process.Dispose()
+    if ($Run.WrapperPath) {
+        Remove-Item -LiteralPath $Run.WrapperPath -Force -ErrorAction SilentlyContinue
+    }
+    if ($Run.Cgroup) {
+        Remove-TaskCgroup -Path $Run.Cgroup
+    }
+
+    if ($Run.GoTestSummary) {
+        Complete-GoTestSummary -Summary $Run.GoTestSummary -CoverProfile $Run.CoverProfile
+        $goTestSummary = $Run.GoTestSummary
+        $coverageText = if ($null -ne $goTestSummary.Coverage) { ", $($goTestSummary.Coverage)% coverage" } else { '' }
+        if ($Run.Prefix) {
+            Write-Host "$($Run.Prefix) " -NoNewline -ForegroundColor $Run.PrefixColor
+        }
+        Write-Host "go test: $($goTestSummary.Passed) passed, $($goTestSummary.Failed) failed, $($goTestSummary.Skipped) skipped$coverageText" -ForegroundColor $(if ($goTestSummary.Failed -gt 0 -or $exitCode -ne 0) { 'Red' } else { 'Green' })
+    }
+
+    # Shell convention: 130 for SIGINT, 143 for SIGTERM
+    if ($Run.Signal -gt 0) {
+        $exitCode = 128 + $Run.Signal
+    }
+
+    return $exitCode
+}
+
+function Test-TaskProcessTimeout {
+    <#
+    .SYNOPSIS
+        Kills a child task whose timeout has passed
+    .DESCRIPTION
+        Process.Kill($true) stops the whole process tree on every platform, so tools
+        started by the task are stopped too, and Stop-TaskContainer kills the
+        container of a # CONTAINER: task. The stopwatch is stopped at the kill so
+        the recorded duration shows when the task was cancelled.
+    .OUTPUTS
+        $true when the task was killed by this call
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [PSCustomObject]$Run
+    )
+
+    if ($Run.TimeoutMs -le 0 -or $Run.TimedOut -or $Run.Stopwatch.ElapsedMilliseconds -lt $Run.TimeoutMs -or $Run.Process.HasExited) {
+        return $false
+    }
+
+    Stop-TaskContainer -Run $Run
+    try {
+        $Run.Process.Kill($true)
+    } catch {
+        Write-Verbose "Could not stop task '$($Run.Name)' after its timeout: $_"
+    }
+    $Run.Stopwatch.Stop()
+    $Run.TimedOut = $true
+    return $true
+}
+
+function Stop-TaskContainer {
+    <#
+    .SYNOPSIS
+        Stops the container of a # CONTAINER: task run
+    .DESCRIPTION
+        Killing the docker CLI does not stop the container it started, so every path
+        that stops a task also stops its container by name: docker stop -t with
+        -GracePeriodMs, or docker kill without it. The container was started with
+        --rm, so docker removes it once it stops. Does nothing for other runs.
+
+        With -NoWait the docker command is started and this function returns at
+        once, so several containers can get their grace period at the same time.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [PSCustomObject]$Run,
+
+        [long]$GracePeriodMs = 0,
+
+        [switch]$NoWait
+    )
+
+    if (-not $Run.Container) {
+        return
+    }
+
+    $startInfo = [System.Diagnostics.ProcessStartInfo]::new($Run.Container.Docker)
+    if ($GracePeriodMs -gt 0) {
+        foreach ($argument in @('stop', '-t', [string][math]::Max(1, [math]::Ceiling($GracePeriodMs / 1000)), $Run.Container.Name)) {
+            $startInfo.ArgumentList.Add($argument)
+        }
+    } else {
+        $startInfo.ArgumentList.Add('kill')
+        $startInfo.ArgumentList.Add($Run.Container.Name)
+    }
+    $startInfo.UseShellExecute = $false
+    $startInfo.RedirectStandardOutput = $true
+    $startInfo.RedirectStandardError = $true
+
+    try {
+        $process = [System.Diagnostics.Process]::Start($startInfo)
+        if (-not $NoWait) {
+            $process.WaitForExit(10000) | Out-Null
+        }
+    } catch {
+        Write-Verbose "Could not stop container '$($Run.Container.Name)' of task '$($Run.Name)': $_"
+    }
+}
+
+function Stop-TaskProcess {
+    <#
+    .SYNOPSIS
+        Kills a child task, its process tree, and its container
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [PSCustomObject]$Run
+    )
+
+    try {
+        if (-not $Run.Process.HasExited) {
+            Stop-TaskContainer -Run $Run
+            $Run.Process.Kill($true)
+        }
+    } catch {
+        Write-Verbose "Could not stop task '$($Run.Name)': $_"
+    }
+
+    Complete-TaskProcess -Run $Run | Out-Null
+}
+
+function Register-TaskStopSignal {
+    <#
+    .SYNOPSIS
+        Catches SIGINT and SIGTERM while bolt waits for child task processes
+    .DESCRIPTION
+        Creates $script:TaskStopSignal the first time it is called. Signal holds the
+        number of the signal that was received (2 for SIGINT, 15 for SIGTERM) and
+        Waiting counts the loops that are waiting for child processes. While Waiting
+        is above zero a signal is recorded and cancelled, so the loop can stop its
+        tasks with Stop-TaskProcessGracefully. Otherwise the signal keeps its default
+        behavior.
+
+        The handlers are compiled expression trees instead of script blocks, because
+        .NET calls them on a thread pool thread that has no PowerShell runspace.
+        PosixSignalRegistration needs PowerShell 7.2 or later. On older versions only
+        the PowerShell host handles Ctrl+C.
+    .OUTPUTS
+        PSCustomObject with Signal and Waiting (StrongBox[int])
+    #>
+
+    if ($script:TaskStopSignal) {
+        return $script:TaskStopSignal
+    }
+
+    $state = [PSCustomObject]@{
+        Signal        = [System.Runtime.CompilerServices.StrongBox[int]]::new(0)
+        Waiting       = [System.Runtime.CompilerServices.StrongBox[int]]::new(0)
+        Registrations = @()
+    }
+    $script:TaskStopSignal = $state
+
+    $registrationType = 'System.Runtime.InteropServices.PosixSignalRegistration' -as [type]
+    if (-not $registrationType) {
+        Write-Verbose "PosixSignalRegistration is not available, SIGTERM will not stop child tasks gracefully"
+        return $state
+    }
+
+    try {
+        $signalType = 'System.Runtime.InteropServices.PosixSignal' -as [type]
+        $contextType = 'System.Runtime.InteropServices.PosixSignalContext' -as [type]
+        $expression = [System.Linq.Expressions.Expression]
+
+        foreach ($signal in @(@{ Name = 'SIGINT'; Number = 2 }, @{ Name = 'SIGTERM'; Number = 15 })) {
+            # if (Waiting.Value > 0) { Signal.Value = <number>; context.Cancel = true; }
+            $context = $expression::Parameter($contextType, 'context')
+            $body = $expression::IfThen(
+                $expression::GreaterThan($expression::Field($expression::Constant($state.Waiting), 'Value'), $expression::Constant(0)),
+                $expression::Block(
+                    $expression::Assign($expression::Field($expression::Constant($state.Signal), 'Value'), $expression::Constant($signal.Number)),
+                    $expression::Assign($expression::Property($context, 'Cancel'), $expression::Constant($true))
+                )
+            )
+            $handlerType = [System.Action`1].MakeGenericType($contextType)
+            $handler = $expression::Lambda($handlerType, $body, [System.Linq.Expressions.ParameterExpression[]]@($context)).Compile()
+            $state.Registrations += $registrationType::Create([enum]::Parse($signalType, $signal.Name), $handler)
+        }
+    } catch {
+        Write-Verbose "Could not register signal handlers: $_"
+    }
+
+    return $state
+}
+
+function ConvertTo-SignalName {
+    <#
+    .SYNOPSIS
+        Returns SIGINT or SIGTERM for a signal number recorded by Register-TaskStopSignal
+    #>
+    param(
+        [int]$Signal
+    )
+
+    switch ($Signal) {
+        2 { return 'SIGINT' }
+        15 { return 'SIGTERM' }
+        default { return "signal $Signal" }
+    }
+}
+
+function Get-TaskProcessTree {
+    <#
+    .SYNOPSIS
+        Returns the id of a process and the ids of all of its descendants
+    .DESCRIPTION
+        Reads the parent of every process from ps, so a stop signal reaches the tools
+        a task started and not only the task's own pwsh. Only used on Linux and macOS.
+        Returns just -ProcessId when ps is not available.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [int]$ProcessId
+    )
+
+    $ids = [System.Collections.Generic.List[int]]::new()
+    $ids.Add($ProcessId)
+
+    $ps = Get-Command -Name ps -CommandType Application -ErrorAction SilentlyContinue | Select-Object -First 1
+    if (-not $ps) {
+        return $ids.ToArray()
+    }
+
+    $childrenOf = @{}
+    foreach ($line in @(& $ps.Source -A -o 'pid=' -o 'ppid=' 2>$null)) {
+        $fields = -split $line
+        if ($fields.Count -ne 2) {
+            continue
+        }
+        $parentId = [int]$fields[1]
+        if (-not $childrenOf.ContainsKey($parentId)) {
+            $childrenOf[$parentId] = [System.Collections.Generic.List[int]]::new()
+        }
+        $childrenOf[$parentId].Add([int]$fields[0])
+    }
+
+    # Breadth-first walk; the list grows while it is read
+    for ($index = 0; $index -lt $ids.Count; $index++) {
+        foreach ($childId in $childrenOf[$ids[$index]]) {
+            if (-not $ids.Contains($childId)) {
+                $ids.Add($childId)
+            }
+        }
+    }
+
+    return $ids.ToArray()
+}
+
+function Stop-TaskProcessGracefully {
+    <#
+    .SYNOPSIS
+        Asks child tasks to stop, and kills the ones still running after the grace period
+    .DESCRIPTION
+        On Linux and macOS every process in each task's process tree gets SIGTERM. On
+        Windows the console already sends Ctrl+C and close events to every process
+        attached to it, including the tasks. Sending Ctrl+C again with
+        GenerateConsoleCtrlEvent would also reach bolt, so only the wait and the kill
+        happen there.
+
+        The containers of # CONTAINER: tasks get docker stop with the same grace
+        period, on every platform.
+
+        Output is relayed while waiting. Tasks still running after -GracePeriodMs are
+        killed with their process tree, and on Linux and macOS any process from the
+        tree that ignored SIGTERM gets SIGKILL. Each run's Signal is set, so
+        Complete-TaskProcess returns 128 + signal as the exit code.
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [PSCustomObject[]]$Run,
+
+        [int]$Signal = 15,
+
+        [long]$GracePeriodMs = $script:GracePeriodMs
+    )
+
+    foreach ($taskRun in $Run) {
+        $taskRun.Signal = $Signal
+    }
+
+    $active = @($Run | Where-Object { -not $_.Process.HasExited })
+    if ($active.Count -eq 0) {
+        return
+    }
+
+    $signalledIds = @()
+    $kill = $null
+    if (-not $IsWindows) {
+        $kill = Get-Command -Name kill -CommandType Application -ErrorAction SilentlyContinue | Select-Object -First 1
+        foreach ($taskRun in $active) {
+            $signalledIds += Get-TaskProcessTree -ProcessId $taskRun.Process.Id
+        }
+        if ($kill) {
+            & $kill.Source -s TERM @signalledIds 2>$null
+        }
+    }
+    foreach ($taskRun in $active) {
+        Stop-TaskContainer -Run $taskRun -GracePeriodMs $GracePeriodMs -NoWait
+    }
+
+    $graceStopwatch = [System.Diagnostics.Stopwatch]::StartNew()
+    while (@($active | Where-Object { -not $_.Process.HasExited }).Count -gt 0 -and $graceStopwatch.ElapsedMilliseconds -lt $GracePeriodMs) {
+        $activity = $false
+        foreach ($taskRun in $active) {
+            if (Receive-TaskProcessOutput -Run $taskRun) {
+                $activity = $true
+            }
+        }
+        if (-not $activity) {
+            Start-Sleep -Milliseconds 10
+        }
+    }
+
+    foreach ($taskRun in $active) {
+        if ($taskRun.Process.HasExited) {
+            continue
+        }
+        Write-Host "Task '$($taskRun.Name)' did not stop within $('{0:N1}s' -f ($GracePeriodMs / 1000)), killing it" -ForegroundColor Yellow
+        Stop-TaskContainer -Run $taskRun
+        try {
+            $taskRun.Process.Kill($true)
+        } catch {
+            Write-Verbose "Could not stop task '$($taskRun.Name)': $_"
+        }
+    }
+
+    # Tools that ignored SIGTERM may outlive the task process that started them
+    if ($kill) {
+        $remainingIds = @($signalledIds | Where-Object { Get-Process -Id $_ -ErrorAction SilentlyContinue })
+        if ($remainingIds.Count -gt 0) {
+            & $kill.Source -s KILL @remainingIds 2>$null
+        }
+    }
+}
+
+function Test-InteractiveTerminal {
+    <#
+    .SYNOPSIS
+        Tests whether bolt can draw the -Interactive task menu
+    .DESCRIPTION
+        True when standard input, output, and error are all a terminal, TERM is not
+        dumb, and neither CI nor BOLT_NO_INTERACTIVE is set.
+    .OUTPUTS
+        [bool]
+    #>
+
+    return -not ([Console]::IsInputRedirected -or [Console]::IsOutputRedirected -or [Console]::IsErrorRedirected -or
+        $env:TERM -eq 'dumb' -or $env:CI -or $env:BOLT_NO_INTERACTIVE)
+}
+
+function New-TaskMenu {
+    <#
+    .SYNOPSIS
+        Creates the task menu for -Interactive
+    .DESCRIPTION
+        Returns a menu object with three methods:
+          Render()          draws the menu, replacing the lines drawn before
+          HandleKey(key)    applies a ConsoleKeyInfo and returns 'continue', 'run', or 'cancel'
+          Clear()           erases the menu and shows the cursor again
+
+        Up and Down (or k and j) move the cursor, Space selects or unselects the task
+        under it, Enter runs the selected tasks (the task under the cursor when none
+        is selected), and Escape, q, or Ctrl+C cancels. Selected returns the selected
+        names in menu order.
+
+        Each line shows the task name and its description. When the terminal is too
+        narrow for the names and at least 10 characters of description, only the
+        names are shown. When there are more tasks than rows, the menu scrolls with
+        the cursor. The width and height are read on every Render, so a resized
+        terminal is handled.
+
+        Tests can pass a System.IO.StringWriter as -Writer and a fixed -Width and -Height.
+    .PARAMETER Items
+        Objects with Name and Description, in menu order
+    .PARAMETER Writer
+        Where the menu is drawn. Defaults to standard error.
+    .PARAMETER Width
+        Script block that returns the terminal width in columns
+    .PARAMETER Height
+        Script block that returns the terminal height in rows
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [object[]]$Items,
+
+        [System.IO.TextWriter]$Writer = [Console]::Error,
+
+        [scriptblock]$Width = { [Console]::WindowWidth },
+
+        [scriptblock]$Height = { [Console]::WindowHeight }
+    )
+
+    $menu = [PSCustomObject]@{
+        Items    = $Items
+        Writer   = $Writer
+        Width    = $Width
+        Height   = $Height
+        Cursor   = 0
+        Top      = 0
+        Chosen   = [System.Collections.Generic.HashSet[int]]::new()
+        Drawn    = [System.Collections.Generic.List[int]]::new()
+    }
+
+    $menu | Add-Member -MemberType ScriptMethod -Name Size -Value {
+        param([scriptblock]$Read, [int]$Default)
+
+        try {
+            return [int](& $Read)
+        } catch {
+            return $Default
+        }
+    }
+
+    $menu | Add-Member -MemberType ScriptMethod -Name Lines -Value {
+        $columns = [math]::Max(10, $this.Size($this.Width, 80))
+        $rows = [math]::Max(1, $this.Size($this.Height, 24) - 2)
+
+        # Keep the cursor inside the rows that are shown
+        if ($this.Cursor -lt $this.Top) {
+            $this.Top = $this.Cursor
+        } elseif ($this.Cursor -ge $this.Top + $rows) {
+            $this.Top = $this.Cursor - $rows + 1
+        }
+
+        $nameWidth = ($this.Items.Name | Measure-Object -Property Length -Maximum).Maximum
+        # '> [x] ' before the name, two spaces before the description, and one column spare
+        $showDescriptions = $columns - 1 -ge 6 + $nameWidth + 2 + 10
+
+        $lines = [System.Collections.Generic.List[string]]::new()
+        $lines.Add('Select tasks: Up/Down move, Space select, Enter run, Esc cancel')
+        $last = [math]::Min($this.Items.Count, $this.Top + $rows) - 1
+        for ($i = $this.Top; $i -le $last; $i++) {
+            $item = $this.Items[$i]
+            $line = "$(if ($i -eq $this.Cursor) { '>' } else { ' ' }) [$(if ($this.Chosen.Contains($i)) { 'x' } else { ' ' })] "
+            $line += if ($showDescriptions -and $item.Description) { "$($item.Name.PadRight($nameWidth))  $($item.Description)" } else { $item.Name }
+            $lines.Add($line)
+        }
+        if ($this.Items.Count -gt $rows) {
+            $lines.Add("($($this.Cursor + 1) of $($this.Items.Count))")
+        }
+
+        # Stay one column short of the width so the cursor never wraps
+        for ($i = 0; $i -lt $lines.Count; $i++) {
+            if ($lines[$i].Length -gt $columns - 1) {
+                $lines[$i] = $lines[$i].Substring(0, $columns - 4) + '...'
+            }
+        }
+        return , $lines.ToArray()
+    }
+
+    $menu | Add-Member -MemberType ScriptMethod -Name Clear -Value {
+        if ($this.Drawn.Count -gt 0) {
+            $columns = [math]::Max(10, $this.Size($this.Width, 80))
+            $rows = 0
+            foreach ($length in $this.Drawn) {
+                $rows += [math]::Max(1, [int][math]::Ceiling($length / $columns))
+            }
+            $this.Writer.Write("`e[${rows}A`r`e[J")
+            $this.Drawn.Clear()
+        }
+        $this.Writer.Write("`e[?25h")
+        $this.Writer.Flush()
+    }
+
+    $menu | Add-Member -MemberType ScriptMethod -Name Render -Value {
+        $this.Clear()
+        $this.Writer.Write("`e[?25l")
+        foreach ($line in $this.Lines()) {
+            $this.Writer.WriteLine($line)
+            $this.Drawn.Add($line.Length)
+        }
+        $this.Writer.Flush()
+    }
+
+    $menu | Add-Member -MemberType ScriptMethod -Name HandleKey -Value {
+        param([System.ConsoleKeyInfo]$Key)
+
+        if ($Key.Key -eq [ConsoleKey]::Escape -or $Key.KeyChar -eq 'q' -or
+            ($Key.Key -eq [ConsoleKey]::C -and $Key.Modifiers.HasFlag([ConsoleModifiers]::Control))) {
+            return 'cancel'
+        }
+        if ($Key.Key -eq [ConsoleKey]::UpArrow -or $Key.KeyChar -eq 'k') {
+            $this.Cursor = [math]::Max(0, $this.Cursor - 1)
+        } elseif ($Key.Key -eq [ConsoleKey]::DownArrow -or $Key.KeyChar -eq 'j') {
+            $this.Cursor = [math]::Min($this.Items.Count - 1, $this.Cursor + 1)
+        } elseif ($Key.Key -eq [ConsoleKey]::Spacebar) {
+            if (-not $this.Chosen.Remove($this.Cursor)) {
+                [void]$this.Chosen.Add($this.Cursor)
+            }
+        } elseif ($Key.Key -eq [ConsoleKey]::Enter) {
+            if ($this.Chosen.Count -eq 0) {
+                [void]$this.Chosen.Add($this.Cursor)
+            }
+            return 'run'
+        }
+        return 'continue'
+    }
+
+    $menu | Add-Member -MemberType ScriptMethod -Name Selected -Value {
+        return @(for ($i = 0; $i -lt $this.Items.Count; $i++) {
+            if ($this.Chosen.Contains($i)) { $this.Items[$i].Name }
+        })
+    }
+
+    return $menu
+}
+
+function Select-TaskInteractive {
+    <#
+    .SYNOPSIS
+        Shows the -Interactive task menu and returns the tasks the user picked
+    .DESCRIPTION
+        Lists every task by primary name, with matrix tasks once instead of their
+        instances. The menu is erased before this function returns, so the run's
+        output starts where the menu was.
+    .OUTPUTS
+        [string[]] The selected task names, or an empty array when the menu was cancelled
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$AllTasks
+    )
+
+    $items = @(
+        $AllTasks.Values | Where-Object { -not $_.MatrixParent } | Sort-Object { $_.Names[0] } -Unique | ForEach-Object {
+            [PSCustomObject]@{ Name = $_.Names[0]; Description = [string]$_.Description }
+        }
+    )
+    if ($items.Count -eq 0) {
+        return @()
+    }
+
+    $menu = New-TaskMenu -Items $items
+    $treatControlC = [Console]::TreatControlCAsInput
+    try {
+        # Ctrl+C cancels the menu instead of stopping bolt with the cursor hidden
+        [Console]::TreatControlCAsInput = $true
+        while ($true) {
+            $menu.Render()
+            $action = $menu.HandleKey([Console]::ReadKey($true))
+            if ($action -eq 'run') {
+                return $menu.Selected()
+            }
+            if ($action -eq 'cancel') {
+                return @()
+            }
+        }
+    } finally {
+        $menu.Clear()
+        [Console]::TreatControlCAsInput = $treatControlC
+    }
+}
+
+function New-TaskProgressDisplay {
+    <#
+    .SYNOPSIS
+        Creates the live display of running tasks for -Parallel runs
+    .DESCRIPTION
+        Returns a display object with two methods:
+          Render(entries)   draws one line per entry below the task output
+          Clear()           erases the lines drawn by the last Render
+
+        Each entry has a Name, Elapsed (a TimeSpan), and Text (the task's last
+        output line). A line shows a spinner, the name, the elapsed time, and as
+        much of the text as fits. The width is read again on every Render and
+        Clear, so a resized terminal is handled: Clear counts the rows the lines
+        take up at the current width, including lines that wrapped when the
+        terminal got narrower. Render redraws lines that are still on screen at
+        most every -IntervalMs milliseconds.
+
+        Tests can pass a System.IO.StringWriter as -Writer and a fixed -Width.
+    .PARAMETER Writer
+        Where the display is drawn. Defaults to standard error.
+    .PARAMETER Width
+        Script block that returns the terminal width in columns
+    #>
+    param(
+        [System.IO.TextWriter]$Writer = [Console]::Error,
+
+        [scriptblock]$Width = { [Console]::WindowWidth },
+
+        [int]$IntervalMs = 100
+    )
+
+    $display = [PSCustomObject]@{
+        Writer     = $Writer
+        Width      = $Width
+        IntervalMs = $IntervalMs
+        Frames     = @('-', '\', '|', '/')
+        Frame      = 0
+        Drawn      = [System.Collections.Generic.List[int]]::new()
+        LastRender = [System.Diagnostics.Stopwatch]::new()
+    }
+
+    $display | Add-Member -MemberType ScriptMethod -Name Columns -Value {
+        try {
+            return [math]::Max(10, [int](& $this.Width))
+        } catch {
+            return 80
+        }
+    }
+
+    $display | Add-Member -MemberType ScriptMethod -Name Clear -Value {
+        if ($this.Drawn.Count -eq 0) {
+            return
+        }
+
+        $columns = $this.Columns()
+        $rows = 0
+        foreach ($length in $this.Drawn) {
+            $rows += [math]::Max(1, [int][math]::Ceiling($length / $columns))
+        }
+        # Up to the first drawn row, then erase everything below it
+        $this.Writer.Write("`e[${rows}A`r`e[J")
+        $this.Writer.Flush()
+        $this.Drawn.Clear()
+    }
+
+    $display | Add-Member -MemberType ScriptMethod -Name Render -Value {
+        param([object[]]$Entries)
+
+        if ($this.Drawn.Count -gt 0 -and $this.LastRender.ElapsedMilliseconds -lt $this.IntervalMs) {
+            return
+        }
+        $this.Clear()
+        $this.LastRender.Restart()
+
+        $columns = $this.Columns()
+        $spinner = $this.Frames[$this.Frame++ % $this.Frames.Count]
+        foreach ($entry in $Entries) {
+            # Drop color codes and control characters so the length is the width on screen
+            $text = ([string]$entry.Text -replace "`e\[[0-9;?]*[ -/]*[@-~]", '' -replace '[\x00-\x1f\x7f]', ' ').Trim()
+            $line = "$spinner $($entry.Name) $('{0:N1}s' -f $entry.Elapsed.TotalSeconds)"
+            if ($text) {
+                $line += "  $text"
+            }
+            # Stay one column short of the width so the cursor never wraps
+            if ($line.Length -gt $columns - 1) {
+                $line = $line.Substring(0, $columns - 4) + '...'
+            }
+            $this.Writer.WriteLine($line)
+            $this.Drawn.Add($line.Length)
+        }
+        $this.Writer.Flush()
+    }
+
+    return $display
+}
+
+function Invoke-TaskParallel {
+    <#
+    .SYNOPSIS
+        Runs tasks concurrently while respecting their dependencies
+    .DESCRIPTION
+        Takes the topological order from Get-TaskExecutionOrder and starts each task as
+        soon as all of its dependencies in the run have succeeded, with at most
+        -Parallelism tasks running at once. Project tasks run in child processes and
+        every output line is prefixed with the task name. Core tasks, plugin tasks, and
+        task hooks run in-process, so the running tasks are not read and their
+        timeouts are not checked until they return. # ROLLBACK: commands run in child
+        processes, and the running tasks are relayed while Invoke-TaskRollback waits
+        for them. A task with # RETRY: that fails is started again after its
+        back-off delay and keeps its worker slot while it waits.
+
+        The first failure stops all in-flight tasks and no new tasks are started.
+        With -NoFailFast, the other tasks keep running and new tasks still start
+        once their dependencies succeed; tasks that depend on a failed task are
+        recorded as skipped with SkipReason dependency. SIGINT or SIGTERM stops the running tasks with Stop-TaskProcessGracefully
+        and no new tasks are started.
+
+        When standard error is a terminal, a display from New-TaskProgressDisplay
+        below the output shows each running task with its elapsed time and last
+        output line. It is turned off by -NoProgress, -OutputFormat Json, -LogLevel
+        (which also writes to standard error), and TERM=dumb, and it is erased
+        before the function returns.
+    .PARAMETER ExecutionOrder
+        Task names in topological order
+    .PARAMETER AllTasks
+        Hashtable of all available tasks
+    .PARAMETER Arguments
+        Arguments passed to every task script
+    .PARAMETER Parallelism
+        Maximum number of tasks running at the same time
+    .OUTPUTS
+        PSCustomObject with Success and FailedTasks
+    #>
+    param(
+        [string[]]$ExecutionOrder,
+        [hashtable]$AllTasks,
+        [array]$Arguments,
+        [int]$Parallelism
+    )
+
+    # Only dependencies that are part of this run gate a task (-Only drops the rest)
+    $dependencyMap = @{}
+    foreach ($taskName in $ExecutionOrder) {
+        $taskInfo = $AllTasks[$taskName]
+        $dependencyMap[$taskName] = @(
+            foreach ($dep in $taskInfo.Dependencies) {
+                $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $taskInfo.Namespace -Tasks $AllTasks
+                if ($resolvedDep) {
+                    $depPrimaryName = $AllTasks[$resolvedDep].Names[0]
+                    if ($ExecutionOrder -contains $depPrimaryName) {
+                        $depPrimaryName
+                    }
+                }
+            }
+        )
+    }
+
+    $prefixWidth = ($ExecutionOrder | Measure-Object -Property Length -Maximum).Maximum + 2
+    $palette = @('Cyan', 'Magenta', 'Yellow', 'Green', 'Blue', 'DarkCyan', 'DarkYellow', 'DarkMagenta')
+    $pending = [System.Collections.Generic.List[string]]::new([string[]]$ExecutionOrder)
+    $running = [System.Collections.Generic.List[object]]::new()
+    $retries = [System.Collections.Generic.List[object]]::new()
+    $retryPolicies = @{}
+    $attempts = @{}
+    $succeeded = @{}
+    $failedTasks = @()
+    $blockedTasks = @{}
+    $hookErrors = @{}
+    $startedCount = 0
+    $stopSignal = Register-TaskStopSignal
+
+    Write-Host "Running $($ExecutionOrder.Count) task(s) with parallelism $Parallelism" -ForegroundColor Cyan
+    Write-Host ""
+
+    $taskProgress = $null
+    if (-not $NoProgress -and $OutputFormat -ne 'Json' -and -not $script:BoltLogger -and -not [Console]::IsErrorRedirected -and $env:TERM -ne 'dumb') {
+        $taskProgress = New-TaskProgressDisplay
+
+        # Every line written while tasks run (here and in the functions called from
+        # here) erases the display first, so the display always stays below the output
+        function Write-Host {
+            $taskProgress.Clear()
+            Microsoft.PowerShell.Utility\Write-Host @args
+        }
+    }
+
+    $stopSignal.Waiting.Value++
+    try {
+        while ($pending.Count -gt 0 -or $running.Count -gt 0 -or $retries.Count -gt 0) {
+            $activity = $false
+
+            # Start the next attempt of failed tasks whose back-off delay has passed
+            foreach ($retry in @($retries)) {
+                if (($failedTasks.Count -gt 0 -and -not $NoFailFast) -or $stopSignal.Signal.Value -ne 0 -or [DateTime]::UtcNow -lt $retry.StartAt) {
+                    continue
+                }
+
+                [void]$retries.Remove($retry)
+                $activity = $true
+                $attempts[$retry.Name]++
+                try {
+                    $run = Start-TaskProcess -TaskInfo $AllTasks[$retry.Name] -TaskName $retry.Name -Arguments $Arguments -Prefix $retry.Prefix -PrefixColor $retry.PrefixColor -TimeoutMs $retry.TimeoutMs
+                    $running.Add($run)
+                    Write-Host "$($retry.Prefix) started attempt $($attempts[$retry.Name]) of $($retryPolicies[$retry.Name].Attempts)" -ForegroundColor $retry.PrefixColor
+                } catch {
+                    $failedTasks += $retry.Name
+                    Write-Host "$($retry.Prefix) failed to start: $_" -ForegroundColor Red
+                    Invoke-TaskHook -TaskInfo $AllTasks[$retry.Name] -Phase 'After' -AllTasks $AllTasks -Arguments $Arguments -HookErrors $hookErrors[$retry.Name] | Out-Null
+                    Add-TaskResult -Name $retry.Name -Status 'failure' -ExitCode 1 -Stderr "$_" -HookErrors $hookErrors[$retry.Name] -Attempts $attempts[$retry.Name]
+                    Write-SecurityLog -Event "TaskCompletion" -Details "Task: $($retry.Name) (failed with error: $_)" -Severity "Error"
+                }
+            }
+
+            # Start every task whose dependencies have succeeded, up to the worker limit
+            foreach ($taskName in @($pending)) {
+                if (($failedTasks.Count -gt 0 -and -not $NoFailFast) -or $stopSignal.Signal.Value -ne 0 -or ($running.Count + $retries.Count) -ge $Parallelism) {
+                    break
+                }
+
+                $waitingOn = @($dependencyMap[$taskName] | Where-Object { -not $succeeded.ContainsKey($_) })
+                if ($waitingOn.Count -gt 0) {
+                    # -NoFailFast: a task with a failed dependency never starts, and a group waits for all its members
+                    $failedDependencies = @($waitingOn | Where-Object { $failedTasks -contains $_ -or $blockedTasks.ContainsKey($_) })
+                    if ($NoFailFast -and $failedDependencies.Count -gt 0 -and ($AllTasks[$taskName].Type -ne 'group' -or $failedDependencies.Count -eq $waitingOn.Count)) {
+                        [void]$pending.Remove($taskName)
+                        $activity = $true
+                        $prefix = "[$taskName]".PadRight($prefixWidth)
+                        if ($AllTasks[$taskName].Type -eq 'group') {
+                            if (-not (Complete-TaskGroup -TaskInfo $AllTasks[$taskName] -AllTasks $AllTasks -Label "$prefix group")) {
+                                $failedTasks += $taskName
+                            } else {
+                                $blockedTasks[$taskName] = $true
+                            }
+                        } else {
+                            $blockedTasks[$taskName] = $true
+                            Write-Host "$prefix skipped because $($failedDependencies -join ', ') failed (SKIPPED(dependency))" -ForegroundColor Yellow
+                            Add-TaskResult -Name $taskName -Status 'skipped' -Attempts 0 -SkipReason 'dependency'
+                        }
+                    }
+                    continue
+                }
+
+                [void]$pending.Remove($taskName)
+                $activity = $true
+                $taskInfo = $AllTasks[$taskName]
+                $prefix = "[$taskName]".PadRight($prefixWidth)
+                $prefixColor = $palette[$startedCount % $palette.Count]
+                $startedCount++
+
+                # A group has no command of its own, and its members (its dependencies) have completed
+                if ($taskInfo.Type -eq 'group') {
+                    if (Complete-TaskGroup -TaskInfo $taskInfo -AllTasks $AllTasks -Label "$prefix group") {
+                        $succeeded[$taskName] = $true
+                    } else {
+                        $failedTasks += $taskName
+                    }
+                    continue
+                }
+
+                if ($taskInfo.IsCore -or $taskInfo.Type -like 'plugin/*') {
+                    # Core tasks are functions in this script and plugins are objects in it, so they
+                    # cannot run in a child process. Their dependencies have already completed.
+                    Write-Host "$prefix started" -ForegroundColor $prefixColor
+                    if (Invoke-Task -TaskInfo $taskInfo -AllTasks $AllTasks -Arguments $Arguments -SkipDependencies (-not $taskInfo.IsCore)) {
+                        $succeeded[$taskName] = $true
+                        Write-Host "$prefix completed" -ForegroundColor Green
+                    } else {
+                        $failedTasks += $taskName
+                        Write-Host "$prefix failed" -ForegroundColor Red
+                    }
+                    continue
+                }
+
+                if (-not (Test-TaskPlatform -Platform $taskInfo.Platform)) {
+                    $succeeded[$taskName] = $true
+                    Write-Host "$prefix does not run on this platform (SKIPPED(platform))" -ForegroundColor DarkGreen
+                    Add-TaskResult -Name $taskName -Status 'skipped' -Attempts 0 -SkipReason 'platform'
+                    continue
+                }
+
+                if ($taskInfo.When -and -not (Test-TaskCondition -Expression $taskInfo.When -Environment (Get-TaskEnvironment -TaskInfo $taskInfo))) {
+                    $succeeded[$taskName] = $true
+                    Write-Host "$prefix condition is false (SKIPPED(condition))" -ForegroundColor DarkGreen
+                    Add-TaskResult -Name $taskName -Status 'skipped' -Attempts 0 -SkipReason 'condition'
+                    continue
+                }
+
+                if ($script:SinceSkipped -and $script:SinceSkipped.ContainsKey($taskName)) {
+                    $succeeded[$taskName] = $true
+                    Write-Host "$prefix no changes since $($script:SinceRef) (SKIPPED(git))" -ForegroundColor DarkGreen
+                    Add-TaskResult -Name $taskName -Status 'skipped' -Attempts 0 -SkipReason 'git'
+                    continue
+                }
+
+                if (Test-TaskCached -TaskInfo $taskInfo -Arguments $Arguments) {
+                    Restore-TaskCacheOutputVars -TaskInfo $taskInfo -Arguments $Arguments
+                    $succeeded[$taskName] = $true
+                    Write-Host "$prefix up to date (CACHED)" -ForegroundColor DarkGreen
+                    Add-TaskResult -Name $taskName -Status 'skipped' -Attempts 0 -Cached
+                    continue
+                }
+
+                # SECURITY: Log task execution (P0 - Security Event Logging)
+                Write-SecurityLog -Event "TaskExecution" -Details "Task: $taskName, Script: $($taskInfo.ScriptPath) (parallel)" -Severity "Info"
+                Write-BoltLog -Level Info -Message 'task start' -Fields ([ordered]@{ task = $taskName; source = 'project' })
+
+                # Hooks run in this process, before the child starts and after it exits
+                $hookErrors[$taskName] = [System.Collections.Generic.List[string]]::new()
+                if (-not (Invoke-TaskHook -TaskInfo $taskInfo -Phase 'Before' -AllTasks $AllTasks -Arguments $Arguments -HookErrors $hookErrors[$taskName])) {
+                    $failedTasks += $taskName
+                    Write-Host "$prefix skipped because a before hook failed" -ForegroundColor Red
+                    Invoke-TaskHook -TaskInfo $taskInfo -Phase 'After' -AllTasks $AllTasks -Arguments $Arguments -HookErrors $hookErrors[$taskName] | Out-Null
+                    Add-TaskResult -Name $taskName -Status 'failure' -ExitCode 1 -HookErrors $hookErrors[$taskName] -Attempts 0
+                    continue
+                }
+
+                try {
+                    $timeoutMs = Get-TaskTimeoutMs -TaskInfo $taskInfo
+                    $retryPolicies[$taskName] = Get-TaskRetryPolicy -TaskInfo $taskInfo
+                    $attempts[$taskName] = 1
+                    $run = Start-TaskProcess -TaskInfo $taskInfo -TaskName $taskName -Arguments $Arguments -Prefix $prefix -PrefixColor $prefixColor -TimeoutMs $timeoutMs
+                    $running.Add($run)
+                    Write-Host "$prefix started" -ForegroundColor $prefixColor
+                } catch {
+                    $failedTasks += $taskName
+                    Write-Host "$prefix failed to start: $_" -ForegroundColor Red
+                    Add-TaskResult -Name $taskName -Status 'failure' -ExitCode 1 -Stderr "$_"
+                    Write-SecurityLog -Event "TaskCompletion" -Details "Task: $taskName (failed with error: $_)" -Severity "Error"
+                }
+            }
+
+            # SIGINT or SIGTERM: give the running tasks the grace period to exit
+            $signalledRuns = @($running | Where-Object { $_.Signal -eq 0 })
+            if ($stopSignal.Signal.Value -ne 0 -and $signalledRuns.Count -gt 0) {
+                Write-Host "Received $(ConvertTo-SignalName -Signal $stopSignal.Signal.Value), stopping $($signalledRuns.Count) running task(s)" -ForegroundColor Yellow
+                Stop-TaskProcessGracefully -Run $signalledRuns -Signal $stopSignal.Signal.Value
+                $activity = $true
+            }
+
+            # Relay output and collect finished tasks
+            foreach ($run in @($running)) {
+                if (Receive-TaskProcessOutput -Run $run) {
+                    $activity = $true
+                }
+                if (Test-TaskProcessTimeout -Run $run) {
+                    $activity = $true
+                }
+
+                if (-not $run.Process.HasExited) {
+                    continue
+                }
+
+                $exitCode = Complete-TaskProcess -Run $run
+                [void]$running.Remove($run)
+                $activity = $true
+                $elapsed = '{0:N1}s' -f $run.Stopwatch.Elapsed.TotalSeconds
+                $retryPolicy = $retryPolicies[$run.Name]
+
+                # A failed attempt with attempts left waits for its back-off delay; after hooks run once at the end
+                if (($run.TimedOut -or $exitCode -ne 0) -and $attempts[$run.Name] -lt $retryPolicy.Attempts -and ($failedTasks.Count -eq 0 -or $NoFailFast) -and $run.Signal -eq 0) {
+                    $retryDelay = Get-TaskRetryDelay -Policy $retryPolicy -Attempt $attempts[$run.Name]
+                    $reason = if ($run.TimedOut) { 'timed out' } else { "exit code $exitCode" }
+                    Write-Host "$($run.Prefix) attempt $($attempts[$run.Name]) of $($retryPolicy.Attempts) failed with $reason ($elapsed), retrying in $('{0:N1}s' -f $retryDelay.TotalSeconds)" -ForegroundColor Yellow
+                    Write-SecurityLog -Event "TaskRetry" -Details "Task: $($run.Name) (attempt $($attempts[$run.Name]) of $($retryPolicy.Attempts) failed with $reason)" -Severity "Warning"
+                    Write-BoltLog -Level Warn -Message 'task retry' -Fields ([ordered]@{ task = $run.Name; attempt = $attempts[$run.Name]; attempts = $retryPolicy.Attempts; reason = $reason })
+                    $retries.Add([PSCustomObject]@{
+                        Name        = $run.Name
+                        Prefix      = $run.Prefix
+                        PrefixColor = $run.PrefixColor
+                        TimeoutMs   = $run.TimeoutMs
+                        StartAt     = [DateTime]::UtcNow + $retryDelay
+                    })
+                    continue
+                }
+
+                if ($retryPolicy.Attempts -gt 1) {
+                    $elapsed = "$elapsed, attempt $($attempts[$run.Name]) of $($retryPolicy.Attempts)"
+                }
+
+                $afterHooksSucceeded = Invoke-TaskHook -TaskInfo $AllTasks[$run.Name] -Phase 'After' -AllTasks $AllTasks -Arguments $Arguments -HookErrors $hookErrors[$run.Name]
+                $resultStatus = if ($run.TimedOut) { 'timeout' } elseif ($exitCode -eq 0 -and $afterHooksSucceeded) { 'success' } else { 'failure' }
+                $snapshot = $null
+                if ($resultStatus -eq 'success' -and $AllTasks[$run.Name].Snapshot) {
+                    $snapshot = Compare-TaskSnapshot -TaskInfo $AllTasks[$run.Name] -Output $run.Stdout.ToString() -Update:$UpdateSnapshot
+                    if ($snapshot.Status -eq 'changed') {
+                        $resultStatus = 'failure'
+                    }
+                }
+                $snapshotDiff = if ($snapshot -and $snapshot.Status -eq 'changed') { $snapshot.Diff -join "`n" } else { '' }
+                $rollbackStatus = ''
+                if ($run.Signal -eq 0 -and ($run.TimedOut -or $exitCode -ne 0 -or $snapshotDiff)) {
+                    $rollbackStatus = Invoke-TaskRollback -TaskInfo $AllTasks[$run.Name] -TaskName $run.Name -Arguments $Arguments -Environment $run.Environment -Prefix $run.Prefix -PrefixColor $run.PrefixColor -Background @($running)
+                }
+                Add-TaskResult -Name $run.Name -Status $resultStatus -DurationMs $run.Stopwatch.ElapsedMilliseconds -ExitCode $exitCode -Stderr $run.Stderr.ToString() -HookErrors $hookErrors[$run.Name] -Attempts $attempts[$run.Name] -GoTestSummary $run.GoTestSummary -SnapshotDiff $snapshotDiff -RollbackStatus $rollbackStatus
+
+                if ($resultStatus -eq 'success') {
+                    $succeeded[$run.Name] = $true
+                    if ($snapshot -and $snapshot.Status -ne 'match') {
+                        Write-Host "$($run.Prefix) snapshot $($snapshot.Status): $($AllTasks[$run.Name].Snapshot)" -ForegroundColor Yellow
+                    }
+                    Write-Host "$($run.Prefix) completed ($elapsed)" -ForegroundColor Green
+                    if ($null -ne $run.OutputVars) {
+                        Publish-TaskOutputVars -TaskInfo $AllTasks[$run.Name] -TaskName $run.Name -Values $run.OutputVars
+                    }
+                    Save-TaskCache -TaskInfo $AllTasks[$run.Name] -Arguments $Arguments -Secrets $run.Secrets
+                    Write-SecurityLog -Event "TaskCompletion" -Details "Task: $($run.Name) (succeeded)" -Severity "Info"
+                } elseif ($snapshotDiff) {
+                    $failedTasks += $run.Name
+                    Write-Host "$($run.Prefix) output does not match snapshot $($AllTasks[$run.Name].Snapshot) ($elapsed)" -ForegroundColor Red
+                    Write-SnapshotDiff -Diff $snapshot.Diff -Prefix $run.Prefix -PrefixColor $run.PrefixColor
+                    Write-SecurityLog -Event "TaskCompletion" -Details "Task: $($run.Name) (output does not match snapshot)" -Severity "Error"
+                } elseif ($run.Signal) {
+                    $failedTasks += $run.Name
+                    Write-Host "$($run.Prefix) stopped by $(ConvertTo-SignalName -Signal $run.Signal) ($elapsed)" -ForegroundColor Red
+                    Write-SecurityLog -Event "TaskCompletion" -Details "Task: $($run.Name) (stopped by $(ConvertTo-SignalName -Signal $run.Signal))" -Severity "Warning"
+                } elseif ($run.TimedOut) {
+                    $failedTasks += $run.Name
+                    Write-Host "$($run.Prefix) timed out after $($AllTasks[$run.Name].Timeout)" -ForegroundColor Red
+                    Write-SecurityLog -Event "TaskCompletion" -Details "Task: $($run.Name) (timed out after $($AllTasks[$run.Name].Timeout))" -Severity "Error"
+                } else {
+                    $failedTasks += $run.Name
+                    Write-Host "$($run.Prefix) failed with exit code $exitCode ($elapsed)" -ForegroundColor Red
+                    Write-SecurityLog -Event "TaskCompletion" -Details "Task: $($run.Name) (failed with exit code: $exitCode)" -Severity "Error"
+                }
+            }
+
+            # The first failure (unless -NoFailFast) or a stop signal cancels every task that is still running
+            if (($failedTasks.Count -gt 0 -and -not $NoFailFast) -or $stopSignal.Signal.Value -ne 0) {
+                foreach ($run in @($running)) {
+                    Stop-TaskProcess -Run $run
+                    Write-Host "$($run.Prefix) cancelled" -ForegroundColor Yellow
+                    Invoke-TaskHook -TaskInfo $AllTasks[$run.Name] -Phase 'After' -AllTasks $AllTasks -Arguments $Arguments -HookErrors $hookErrors[$run.Name] | Out-Null
+                    Add-TaskResult -Name $run.Name -Status 'skipped' -DurationMs $run.Stopwatch.ElapsedMilliseconds -Stderr $run.Stderr.ToString() -HookErrors $hookErrors[$run.Name] -Attempts $attempts[$run.Name]
+                }
+                foreach ($retry in @($retries)) {
+                    Write-Host "$($retry.Prefix) cancelled" -ForegroundColor Yellow
+                    Invoke-TaskHook -TaskInfo $AllTasks[$retry.Name] -Phase 'After' -AllTasks $AllTasks -Arguments $Arguments -HookErrors $hookErrors[$retry.Name] | Out-Null
+                    Add-TaskResult -Name $retry.Name -Status 'skipped' -HookErrors $hookErrors[$retry.Name] -Attempts $attempts[$retry.Name]
+                }
+                $running.Clear()
+                $retries.Clear()
+                $pending.Clear()
+            }
+
+            if ($running.Count -eq 0 -and $retries.Count -eq 0 -and $pending.Count -gt 0 -and -not $activity) {
+                # Nothing is running and nothing can start, which means a dependency never succeeded
+                $failedTasks += @($pending)
+                Write-Host "Tasks could not be scheduled: $($pending -join ', ')" -ForegroundColor Red
+                $pending.Clear()
+            }
+
+            if ($taskProgress) {
+                $taskProgress.Render(@(
+                    foreach ($run in $running) {
+                        [PSCustomObject]@{ Name = $run.Prefix; Elapsed = $run.Stopwatch.Elapsed; Text = $run.LastLine }
+                    }
+                    foreach ($retry in $retries) {
+                        [PSCustomObject]@{ Name = $retry.Prefix; Elapsed = [TimeSpan]::Zero; Text = "retrying in $('{0:N1}s' -f [math]::Max(0, ($retry.StartAt - [DateTime]::UtcNow).TotalSeconds))" }
+                    }
+                ))
+            }
+
+            if (-not $activity) {
+                Start-Sleep -Milliseconds 20
+            }
+        }
+    } finally {
+        if ($taskProgress) {
+            $taskProgress.Clear()
+        }
+
+        # Do not leave child processes behind when interrupted
+        if ($running.Count -gt 0 -and $stopSignal.Signal.Value -ne 0) {
+            Stop-TaskProcessGracefully -Run @($running) -Signal $stopSignal.Signal.Value
+        }
+        foreach ($run in @($running)) {
+            Stop-TaskProcess -Run $run
+        }
+        $stopSignal.Waiting.Value--
+    }
+
+    return [PSCustomObject]@{
+        Success     = ($failedTasks.Count -eq 0)
+        FailedTasks = $failedTasks
+    }
+}
+
+function Test-TaskInputMatch {
+    <#
+    .SYNOPSIS
+        Checks whether a project-relative path matches any of a task's INPUTS globs
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$TaskInfo,
+
+        [Parameter(Mandatory = $true)]
+        [string]$RelativePath
+    )
+
+    foreach ($pattern in $TaskInfo.Inputs) {
+        $normalized = ($pattern.Trim() -replace '\\', '/') -replace '^(\./)+', ''
+        if ($normalized -notmatch '[\*\?]') {
+            # A literal path matches the file itself or anything below the directory
+            $literal = $normalized.TrimEnd('/')
+            if ($RelativePath -eq $literal -or $RelativePath.StartsWith("$literal/", [StringComparison]::OrdinalIgnoreCase)) {
+                return $true
+            }
+        } elseif ($RelativePath -match (ConvertTo-GlobRegex -Pattern $normalized)) {
+            return $true
+        }
+    }
+
+    return $false
+}
+
+function Get-GitChangedFile {
+    <#
+    .SYNOPSIS
+        Lists the files that changed since a git ref, for -Since
+    .DESCRIPTION
+        Runs git diff --name-only --relative <ref> in the project root, so the paths
+        are relative to the project root like # INPUTS: globs. Throws GitNotFound when
+        git is not on PATH and GitDiffFailed when git diff fails, for example for a
+        ref that does not exist.
+    .OUTPUTS
+        [string[]] project-relative paths with / separators
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Ref
+    )
+
+    $git = Get-Command -Name git -CommandType Application -ErrorAction SilentlyContinue | Select-Object -First 1
+    if (-not $git) {
+        $exception = [System.InvalidOperationException]::new("-Since needs git, but git was not found on PATH")
+        throw [ErrorRecord]::new($exception, 'GitNotFound', [ErrorCategory]::ObjectNotFound, 'git')
+    }
+
+    # core.quotepath=false keeps non-ASCII paths unquoted
+    $output = @(& $git.Source -C $script:EffectiveScriptRoot -c core.quotepath=false diff --name-only --relative $Ref -- 2>&1)
+    if ($LASTEXITCODE -ne 0) {
+        $gitError = ($output | ForEach-Object { "$_".Trim() } | Where-Object { $_ }) -join ' '
+        $exception = [System.InvalidOperationException]::new("git diff --name-only $Ref failed: $gitError")
+        throw [ErrorRecord]::new($exception, 'GitDiffFailed', [ErrorCategory]::InvalidArgument, $Ref)
+    }
+
+    return @($output | Where-Object { $_ -isnot [ErrorRecord] } | ForEach-Object { "$_".Trim() } | Where-Object { $_ })
+}
+
+function Get-TaskSinceSkip {
+    <#
+    .SYNOPSIS
+        Returns the tasks of a run that -Since skips
+    .DESCRIPTION
+        A project task runs when one of the changed files matches its # INPUTS:
+        globs, or when a task it depends on (directly or further down) runs.
+        Every other project task in the execution order is returned, including
+        tasks without INPUTS that depend on nothing that changed. Core tasks are
+        never skipped. A matrix task depends on its instances, like in Get-TaskGraph.
+    .PARAMETER ChangedFiles
+        Project-relative paths, as returned by Get-GitChangedFile
+    .OUTPUTS
+        [string[]] task names, in execution order
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [hashtable]$AllTasks,
+
+        [string[]]$ExecutionOrder = @(),
+
+        [AllowEmptyCollection()]
+        [string[]]$ChangedFiles = @()
+    )
+
+    $changed = @{}
+    $testChanged = $null
+    $testChanged = {
+        param([string]$TaskName)
+
+        if ($changed.ContainsKey($TaskName)) {
+            return $changed[$TaskName]
+        }
+        # Cycles fail in Get-TaskExecutionOrder before this runs
+        $changed[$TaskName] = $false
+
+        $taskInfo = $AllTasks[$TaskName]
+        $result = @($ChangedFiles | Where-Object { Test-TaskInputMatch -TaskInfo $taskInfo -RelativePath $_ }).Count -gt 0
+        if (-not $result) {
+            $dependencies = if ($taskInfo.MatrixInstances) {
+                $taskInfo.MatrixInstances
+            } else {
+                foreach ($dep in $taskInfo.Dependencies) {
+                    $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $taskInfo.Namespace -Tasks $AllTasks
+                    if ($resolvedDep) {
+                        $AllTasks[$resolvedDep].Names[0]
+                    }
+                }
+            }
+            foreach ($dep in $dependencies) {
+                if (& $testChanged $dep) {
+                    $result = $true
+                    break
+                }
+            }
+        }
+
+        $changed[$TaskName] = $result
+        return $result
+    }
+
+    return @(
+        foreach ($taskName in $ExecutionOrder) {
+            if (-not $AllTasks[$taskName].IsCore -and -not (& $testChanged $taskName)) {
+                $taskName
+            }
+        }
+    )
+}
+
+function Enter-BoltLock {
+    <#
+    .SYNOPSIS
+        Takes the lock that lets only one bolt run at a time use a project
+    .DESCRIPTION
+        Opens -Path with FileShare.None, which .NET turns into an exclusive flock on
+        Linux and macOS and an exclusive sharing mode on Windows. The file holds the
+        process id of the run that has the lock (cat can read it on Linux and macOS,
+        where the lock is advisory). While another run holds it, this
+        waits (checking every 250ms) and says so once; after -Timeout it throws a
+        LockTimeout error. A -Timeout of zero fails at once.
+
+        Call Release() on the returned object when the run is done. The operating
+        system also releases the lock when the process ends, even after a crash.
+    .OUTPUTS
+        PSCustomObject with Path and a Release() method
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Path,
+
+        [TimeSpan]$Timeout = [TimeSpan]::FromSeconds(60)
+    )
+
+    New-Item -ItemType Directory -Path (Split-Path -Path $Path -Parent) -Force | Out-Null
+
+    $stopwatch = [System.Diagnostics.Stopwatch]::StartNew()
+    $announced = $false
+    while ($true) {
+        try {
+            $stream = [System.IO.File]::Open($Path, [System.IO.FileMode]::OpenOrCreate, [System.IO.FileAccess]::ReadWrite, [System.IO.FileShare]::None)
+            break
+        } catch [System.IO.IOException] {
+            if ($stopwatch.Elapsed -ge $Timeout) {
+                $exception = [System.TimeoutException]::new("Timed out after $('{0:N0}s' -f $Timeout.TotalSeconds) waiting for another bolt run to release $Path")
+                throw [ErrorRecord]::new($exception, 'LockTimeout', [ErrorCategory]::ResourceBusy, $Path)
+            }
+            if (-not $announced) {
+                Write-Host "Waiting for another bolt run to release $Path (up to $('{0:N0}s' -f $Timeout.TotalSeconds), see -LockTimeout)" -ForegroundColor Yellow
+                $announced = $true
+            }
+            Start-Sleep -Milliseconds 250
+        }
+    }
+
+    $stream.SetLength(0)
+    $bytes = [System.Text.Encoding]::ASCII.GetBytes("$PID`n")
+    $stream.Write($bytes, 0, $bytes.Length)
+    $stream.Flush()
+
+    $lock = [PSCustomObject]@{
+        Path   = $Path
+        Stream = $stream
+    }
+
+    # The lock file is kept; deleting it would let a waiting run lock a file that is already gone
+    $lock | Add-Member -MemberType ScriptMethod -Name Release -Value {
+        if ($this.Stream) {
+            $this.Stream.Dispose()
+            $this.Stream = $null
+        }
+    }
+
+    return $lock
+}
+
+function Get-BenchmarkStatistics {
+    <#
+    .SYNOPSIS
+        Summarizes the wall-clock durations of benchmark runs
+    .DESCRIPTION
+        Percentiles interpolate between the two nearest sorted durations, so the
+        median of an even number of runs is the mean of the middle two. The
+        standard deviation is the sample standard deviation, 0 for a single run.
+    .PARAMETER DurationsMs
+        Duration of each run in milliseconds
+    .OUTPUTS
+        PSCustomObject with Runs, MeanMs, MedianMs, P95Ms, P99Ms, StdDevMs, MinMs, and MaxMs
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [double[]]$DurationsMs
+    )
+
+    $sorted = @($DurationsMs | Sort-Object)
+    $count = $sorted.Count
+    $mean = ($sorted | Measure-Object -Average).Average
+
+    $percentile = {
+        param([double]$Percent)
+
+        $rank = $Percent / 100 * ($count - 1)
+        $lower = [int][math]::Floor($rank)
+        $upper = [int][math]::Ceiling($rank)
+        return $sorted[$lower] + ($sorted[$upper] - $sorted[$lower]) * ($rank - $lower)
+    }
+
+    $stdDev = 0
+    if ($count -gt 1) {
+        $squares = ($sorted | ForEach-Object { [math]::Pow($_ - $mean, 2) } | Measure-Object -Sum).Sum
+        $stdDev = [math]::Sqrt($squares / ($count - 1))
+    }
+
+    return [PSCustomObject]@{
+        Runs     = $count
+        MeanMs   = [math]::Round($mean, 1)
+        MedianMs = [math]::Round((& $percentile 50), 1)
+        P95Ms    = [math]::Round((& $percentile 95), 1)
+        P99Ms    = [math]::Round((& $percentile 99), 1)
+        StdDevMs = [math]::Round($stdDev, 1)
+        MinMs    = $sorted[0]
+        MaxMs    = $sorted[-1]
+    }
+}
+
+function Invoke-TaskBenchmark {
+    <#
+    .SYNOPSIS
+        Runs tasks -Runs times and reports how long each run took
+    .DESCRIPTION
+        Each run is a full run of the requested tasks: dependencies run in
+        dependency order, and with -Parallel independent tasks run at the same
+        time, up to -Parallelism. The cache is not used, so every run does the
+        same work. Run times are wall-clock times of the whole run.
+
+        The first failing run stops the benchmark without statistics. With
+        -OutputFormat Json, the result has a Report with the durations and
+        statistics for the caller to write to stdout as one JSON object.
+    .PARAMETER TaskNames
+        Task names requested on the command line
+    .PARAMETER ExecutionOrder
+        Task names in topological order
+    .PARAMETER SkipDependencies
+        Run like -Only
+    .PARAMETER AllTasks
+        Hashtable of all available tasks
+    .PARAMETER Arguments
+        Arguments passed to every task script
+    .PARAMETER Runs
+        Number of runs
+    .PARAMETER Parallel
+        Run each pass like -Parallel
+    .PARAMETER Parallelism
+        Maximum number of tasks running at once with -Parallel
+    .PARAMETER OutputFormat
+        Text or Json
+    .OUTPUTS
+        [PSCustomObject] with ExitCode (0 or 1) and Report ($null unless -OutputFormat Json succeeded)
+    #>
+    param(
+        [string[]]$TaskNames,
+        [string[]]$ExecutionOrder,
+        [bool]$SkipDependencies = $false,
+        [hashtable]$AllTasks,
+        [array]$Arguments,
+        [int]$Runs = 10,
+        [bool]$Parallel = $false,
+        [int]$Parallelism = [Environment]::ProcessorCount,
+        [string]$OutputFormat = 'Text'
+    )
+
+    # Matrix instances only run in the parallel scheduler, as in a normal run
+    $useScheduler = $Parallel -or ($ExecutionOrder | Where-Object { $AllTasks[$_].MatrixParent })
+    $durations = [System.Collections.Generic.List[double]]::new()
+
+    for ($run = 1; $run -le $Runs; $run++) {
+        Write-Host "Benchmark run $run of $Runs" -ForegroundColor Cyan
+        $script:TaskResults = $null
+        $runSucceeded = $true
+        $stopwatch = [System.Diagnostics.Stopwatch]::StartNew()
+
+        if ($useScheduler) {
+            $runSucceeded = (Invoke-TaskParallel -ExecutionOrder $ExecutionOrder -AllTasks $AllTasks -Arguments $Arguments -Parallelism $Parallelism).Success
+        } else {
+            $executedTasks = @{}
+            foreach ($taskName in $TaskNames) {
+                if (-not (Invoke-Task -TaskInfo $AllTasks[$taskName] -AllTasks $AllTasks -Arguments $Arguments -ExecutedTasks $executedTasks -SkipDependencies $SkipDependencies)) {
+                    $runSucceeded = $false
+                    break
+                }
+            }
+        }
+
+        $stopwatch.Stop()
+        if (-not $runSucceeded) {
+            Write-Error "Benchmark run $run of $Runs failed, so no timings were reported"
+            return [PSCustomObject]@{ ExitCode = 1; Report = $null }
+        }
+        $durations.Add($stopwatch.Elapsed.TotalMilliseconds)
+        Write-Host "Benchmark run $run of $Runs took $('{0:N3}s' -f $stopwatch.Elapsed.TotalSeconds)" -ForegroundColor Gray
+        Write-Host ""
+    }
+
+    $statistics = Get-BenchmarkStatistics -DurationsMs $durations.ToArray()
+
+    if ($OutputFormat -eq 'Json') {
+        $report = [ordered]@{
+            Tasks       = @($TaskNames)
+            Parallel    = [bool]$useScheduler
+            Parallelism = $(if ($useScheduler) { $Parallelism } else { 1 })
+            DurationsMs = @($durations | ForEach-Object { [math]::Round($_, 1) })
+        }
+        foreach ($property in $statistics.PSObject.Properties) {
+            $report[$property.Name] = $property.Value
+        }
+        return [PSCustomObject]@{ ExitCode = 0; Report = $report }
+    }
+
+    $mode = if ($useScheduler) { "parallel, up to $Parallelism at once" } else { 'sequential' }
+    Write-Host "Benchmark: $($TaskNames -join ', ') ($Runs runs, $mode, cache off)" -ForegroundColor Cyan
+    $rows = [ordered]@{
+        'Mean'    = $statistics.MeanMs
+        'Median'  = $statistics.MedianMs
+        'p95'     = $statistics.P95Ms
+        'p99'     = $statistics.P99Ms
+        'Std dev' = $statistics.StdDevMs
+        'Min'     = $statistics.MinMs
+        'Max'     = $statistics.MaxMs
+    }
+    foreach ($row in $rows.GetEnumerator()) {
+        Write-Host ("  {0,-8} {1,10}" -f $row.Key, ('{0:N3}s' -f ($row.Value / 1000)))
+    }
+    return [PSCustomObject]@{ ExitCode = 0; Report = $null }
+}
+
+function Invoke-TaskWatch {
+    <#
+    .SYNOPSIS
+        Runs tasks, then re-runs them whenever their input files change
+    .DESCRIPTION
+        Runs the execution order once, then watches the project for files that
+        match the # INPUTS: globs of those tasks. Changes are collected until no new
+        change arrives for -Debounce milliseconds. Then the tasks whose inputs
+        changed run again, followed by every task in the run that depends on them,
+        in dependency order. Changes made while tasks are running are ignored so
+        tasks that rewrite their own inputs (like formatters) do not loop.
+
+        With -Store, a Watch() subscription on the task store reports changed task
+        definitions (an edited task file, or a new TaskStoreURL list). The tasks are
+        loaded again with Get-AllTasks and the whole run starts over. Definitions that
+        cannot be loaded are reported and the current tasks are kept.
+
+        Press Ctrl+C or 'q' to stop.
+    .PARAMETER TaskNames
+        Task names requested on the command line
+    .PARAMETER ExecutionOrder
+        Task names in topological order
+    .PARAMETER SkipDependencies
+        Run the first pass like -Only
+    .PARAMETER AllTasks
+        Hashtable of all available tasks
+    .PARAMETER Arguments
+        Arguments passed to every task script
+    .PARAMETER Debounce
+        Quiet period in milliseconds before changes trigger a run
+    .PARAMETER Store
+        Task store the tasks were loaded from, watched for new task definitions
+    .OUTPUTS
+        Exit code of the last run (0 or 1)
+    #>
+    param(
+        [string[]]$TaskNames,
+        [string[]]$ExecutionOrder,
+        [bool]$SkipDependencies = $false,
+        [hashtable]$AllTasks,
+        [array]$Arguments,
+        [int]$Debounce = 200,
+        [PSCustomObject]$Store = $null
+    )
+
+    $watchedTasks = @($ExecutionOrder | Where-Object { $AllTasks[$_].Inputs.Count -gt 0 })
+    if ($watchedTasks.Count -eq 0) {
+        Write-Error "None of the tasks declare # INPUTS:, so there is nothing to watch"
+        return 1
+    }
+
+    # Runs tasks in order; re-runs skip the dependencies that did not change
+    $runTasks = {
+        param([string[]]$TasksToRun, [bool]$Rerun)
+
+        $executedTasks = @{}
+
+        # Matrix tasks run as their instances, one after another
+        $instanceNames = @($TasksToRun | ForEach-Object { if ($AllTasks[$_].MatrixInstances) { $AllTasks[$_].MatrixInstances } else { $_ } })
+        foreach ($taskName in $instanceNames) {
+            Write-Host "Executing task: $taskName" -ForegroundColor Cyan
+            $skip = if ($Rerun) { $true } else { $SkipDependencies }
+            if (-not (Invoke-Task -TaskInfo $AllTasks[$taskName] -AllTasks $AllTasks -Arguments $Arguments -ExecutedTasks $executedTasks -SkipDependencies $skip)) {
+                Write-Host "Task '$taskName' failed" -ForegroundColor Red
+                if ($ErrorActionPreference -eq 'Stop') {
+                    return 1
+                }
+            }
+        }
+        return 0
+    }
+
+    $watchExitCode = & $runTasks $TaskNames $false
+
+    $watcher = [System.IO.FileSystemWatcher]::new($script:EffectiveScriptRoot)
+    $watcher.IncludeSubdirectories = $true
+    $watcher.NotifyFilter = [System.IO.NotifyFilters]'FileName, DirectoryName, LastWrite, Size'
+    $sourceIdentifiers = @()
+    foreach ($eventName in @('Created', 'Changed', 'Deleted', 'Renamed')) {
+        $sourceIdentifier = "Bolt.Watch.$eventName.$([guid]::NewGuid().ToString('N'))"
+        Register-ObjectEvent -InputObject $watcher -EventName $eventName -SourceIdentifier $sourceIdentifier | Out-Null
+        $sourceIdentifiers += $sourceIdentifier
+    }
+
+    # Read Ctrl+C as a key press so the watch loop can stop cleanly and return the last result
+    $interactive = -not [Console]::IsInputRedirected
+    if ($interactive) {
+        $treatControlCAsInput = [Console]::TreatControlCAsInput
+        [Console]::TreatControlCAsInput = $true
+    }
+
+    # Collects changed project-relative paths from the queued watcher events
+    $receiveChanges = {
+        $paths = @()
+        foreach ($watchEvent in @(Get-Event | Where-Object { $sourceIdentifiers -contains $_.SourceIdentifier })) {
+            $changeArgs = $watchEvent.SourceEventArgs
+            foreach ($fullPath in @($changeArgs.FullPath, $changeArgs.OldFullPath)) {
+                if ($fullPath) {
+                    $relativePath = [System.IO.Path]::GetRelativePath($script:EffectiveScriptRoot, $fullPath) -replace '\\', '/'
+                    if ($relativePath -notmatch '^\.(git|bolt)(/|$)') {
+                        $paths += $relativePath
+                    }
+                }
+            }
+            Remove-Event -EventIdentifier $watchEvent.EventIdentifier
+        }
+        return $paths
+    }
+
+    $storeSubscription = $null
+    try {
+        $watcher.EnableRaisingEvents = $true
+        if ($Store) {
+            try {
+                $storeSubscription = $Store.Watch()
+            } catch {
+                Write-Verbose "Not watching the $($Store.Type) task store: $_"
+            }
+        }
+        Write-Host ""
+        Write-Host "Watching $($watchedTasks.Count) task(s) for changes: $($watchedTasks -join ', ')" -ForegroundColor Cyan
+        Write-Host "Press Ctrl+C or 'q' to stop" -ForegroundColor Gray
+
+        $changedPaths = [System.Collections.Generic.HashSet[string]]::new([StringComparer]::OrdinalIgnoreCase)
+        $quietTimer = [System.Diagnostics.Stopwatch]::new()
+
+        while ($true) {
+            # SIGTERM stopped the last run, so stop watching too
+            if ($script:TaskStopSignal -and $script:TaskStopSignal.Signal.Value -ne 0) {
+                break
+            }
+            if ($interactive -and [Console]::KeyAvailable) {
+                $key = [Console]::ReadKey($true)
+                if ($key.Key -eq 'Q' -or ($key.Key -eq 'C' -and $key.Modifiers -band [ConsoleModifiers]::Control)) {
+                    break
+                }
+            }
+
+            # New task definitions replace the tasks, and everything runs again
+            $storeTasks = if ($storeSubscription) { $storeSubscription.Receive(0) } else { $null }
+            if ($null -ne $storeTasks) {
+                # An editor can save a file in several steps, so wait until the store is quiet
+                while ($null -ne ($laterTasks = $storeSubscription.Receive($Debounce))) {
+                    $storeTasks = $laterTasks
+                }
+                try {
+                    $reloadedTasks = Get-AllTasks -TaskDirectory $TaskDirectory -ScriptRoot $script:EffectiveScriptRoot -Store (New-InMemoryTaskStore -Tasks $storeTasks)
+                    $reloadedOrder = Get-TaskExecutionOrder -TaskNames $TaskNames -AllTasks $reloadedTasks -SkipDependencies $SkipDependencies
+                } catch {
+                    Write-Host "Task definitions changed, but they could not be loaded: $($_.Exception.Message)" -ForegroundColor Red
+                    Write-Host "Keeping the current tasks" -ForegroundColor Gray
+                    continue
+                }
+                $AllTasks = $reloadedTasks
+                $ExecutionOrder = $reloadedOrder
+                $watchedTasks = @($ExecutionOrder | Where-Object { $AllTasks[$_].Inputs.Count -gt 0 })
+                $changedPaths.Clear()
+                $quietTimer.Reset()
+
+                Write-Host ""
+                Write-Host "Task definitions changed, running: $($TaskNames -join ', ')" -ForegroundColor Cyan
+                Write-Host ""
+                $watchExitCode = & $runTasks $TaskNames $false
+                & $receiveChanges | Out-Null
+
+                Write-Host ""
+                Write-Host "Watching $($watchedTasks.Count) task(s) for changes: $($watchedTasks -join ', ')" -ForegroundColor Gray
+                continue
+            }
+
+            foreach ($path in (& $receiveChanges)) {
+                if ($changedPaths.Add($path)) {
+                    $quietTimer.Restart()
+                }
+            }
+
+            if ($changedPaths.Count -eq 0 -or $quietTimer.ElapsedMilliseconds -lt $Debounce) {
+                Start-Sleep -Milliseconds 25
+                continue
+            }
+
+            # Tasks whose inputs changed, plus everything downstream of them
+            $tasksToRun = @()
+            foreach ($taskName in $ExecutionOrder) {
+                $taskInfo = $AllTasks[$taskName]
+                $affected = $false
+                foreach ($path in $changedPaths) {
+                    if ($taskInfo.Inputs.Count -gt 0 -and (Test-TaskInputMatch -TaskInfo $taskInfo -RelativePath $path)) {
+                        $affected = $true
+                        break
+                    }
+                }
+                if (-not $affected) {
+                    foreach ($dep in $taskInfo.Dependencies) {
+                        $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $taskInfo.Namespace -Tasks $AllTasks
+                        if ($resolvedDep -and $tasksToRun -contains $AllTasks[$resolvedDep].Names[0]) {
+                            $affected = $true
+                            break
+                        }
+                    }
+                }
+                if ($affected) {
+                    $tasksToRun += $taskName
+                }
+            }
+
+            $changedPaths.Clear()
+            $quietTimer.Reset()
+
+            if ($tasksToRun.Count -eq 0) {
+                continue
+            }
+
+            Write-Host ""
+            Write-Host "Change detected, running: $($tasksToRun -join ', ')" -ForegroundColor Cyan
+            Write-Host ""
+            $watchExitCode = & $runTasks $tasksToRun $true
+
+            # Drop changes made by the tasks themselves
+            & $receiveChanges | Out-Null
+
+            Write-Host ""
+            Write-Host "Watching for changes..." -ForegroundColor Gray
+        }
+    } finally {
+        if ($storeSubscription) {
+            $storeSubscription.Dispose()
+        }
+        $watcher.EnableRaisingEvents = $false
+        foreach ($sourceIdentifier in $sourceIdentifiers) {
+            Unregister-Event -SourceIdentifier $sourceIdentifier -ErrorAction SilentlyContinue
+            Get-Event -SourceIdentifier $sourceIdentifier -ErrorAction SilentlyContinue | Remove-Event -ErrorAction SilentlyContinue
+        }
+        $watcher.Dispose()
+        if ($interactive) {
+            [Console]::TreatControlCAsInput = $treatControlCAsInput
+        }
+    }
+
+    Write-Host "Stopped watching" -ForegroundColor Gray
+    return $watchExitCode
+}
+
+function Get-CompletionModel {
+    <#
+    .SYNOPSIS
+        Describes the bolt.ps1 parameters for shell completion scripts
+    .DESCRIPTION
+        Reads the parameter sets of bolt.ps1. Each set other than Help and
+        TaskExecution is a mode, started by its first mandatory parameter (like
+        -ListTasks or -NewTask). Common parameters, the positional task names, and
+        the remaining arguments are left out.
+    .PARAMETER Command
+        The CommandInfo of bolt.ps1 ($MyInvocation.MyCommand)
+    .OUTPUTS
+        PSCustomObject with Modes (set name to Switches and Flags), DefaultFlags,
+        Values (flag to allowed values), Directories, and NeedsValue
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [System.Management.Automation.CommandInfo]$Command
+    )
+
+    $skipped = @('Task', 'Arguments') + [System.Management.Automation.PSCmdlet]::CommonParameters + [System.Management.Automation.PSCmdlet]::OptionalCommonParameters
+    $modes = [ordered]@{}
+    $defaultFlags = @()
+    $values = [ordered]@{}
+    $needsValue = @()
+
+    foreach ($parameterSet in $Command.ParameterSets) {
+        $parameters = @($parameterSet.Parameters | Where-Object { $skipped -notcontains $_.Name })
+        $flags = @($parameters | ForEach-Object { "-$($_.Name)" })
+
+        foreach ($parameter in $parameters) {
+            if ($parameter.ParameterType -eq [switch]) {
+                continue
+            }
+            $needsValue += "-$($parameter.Name)"
+            $validateSet = $parameter.Attributes | Where-Object { $_ -is [ValidateSetAttribute] } | Select-Object -First 1
+            if ($validateSet) {
+                $values["-$($parameter.Name)"] = @($validateSet.ValidValues)
+            }
+        }
+
+        switch ($parameterSet.Name) {
+            'Help' { }
+            'TaskExecution' { $defaultFlags = $flags }
+            default {
+                $modeParameter = $parameters | Where-Object { $_.IsMandatory } | Select-Object -First 1
+                $switches = @("-$($modeParameter.Name)") + @($modeParameter.Aliases | ForEach-Object { "-$_" })
+                $modes[$parameterSet.Name] = [PSCustomObject]@{
+                    Switches = $switches
+                    Flags    = $flags
+                }
+            }
+        }
+    }
+
+    return [PSCustomObject]@{
+        Modes        = $modes
+        DefaultFlags = $defaultFlags
+        Values       = $values
+        Directories  = @('-TaskDirectory')
+        NeedsValue   = @($needsValue | Select-Object -Unique)
+    }
+}
+
+function Get-CompletionScript {
+    <#
+    .SYNOPSIS
+        Generates a bash, zsh, or fish completion script for bolt.ps1
+    .DESCRIPTION
+        The script completes flag names for the current mode (flags that work
+        with -ListTasks after -ListTasks, and so on), the values of flags with a
+        fixed set of values, directories for -TaskDirectory, and task names. Task
+        names come from running 'bolt.ps1 -ListTasks -NoHeader' with pwsh, using the
+        typed script path, ./bolt.ps1, or -DefaultScript in that order.
+
+        Completion is registered for the bolt, bolt.ps1, and ./bolt.ps1 commands.
+    .PARAMETER Shell
+        Bash, Zsh, or Fish
+    .PARAMETER Command
+        The CommandInfo of bolt.ps1 ($MyInvocation.MyCommand)
+    .PARAMETER DefaultScript
+        Path to bolt.ps1 to list tasks with when the command line does not name one
+    .OUTPUTS
+        [string]
+    #>
+    param(
+        [Parameter(Mandatory = $true)]
+        [ValidateSet('Bash', 'Zsh', 'Fish')]
+        [string]$Shell,
+
+        [Parameter(Mandatory = $true)]
+        [System.Management.Automation.CommandInfo]$Command,
+
+        [string]$DefaultScript = ''
+    )
+
+    $model = Get-CompletionModel -Command $Command
+    $modeSwitches = @($model.Modes.Values | ForEach-Object { $_.Switches[0] })
+    $freeValues = @($model.NeedsValue | Where-Object { -not $model.Values.Contains($_) -and $model.Directories -notcontains $_ })
+    # Single quotes in the path are the only character that needs escaping in a single-quoted shell string
+    $defaultScriptQuoted = "'" + ($DefaultScript -replace "'", "'\''") + "'"
+    $lines = [System.Collections.Generic.List[string]]::new()
+
+    switch ($Shell) {
+        'Bash' {
+            $lines.Add('# bash completion for bolt.ps1')
+            $lines.Add('# Generated by: bolt.ps1 -Completion bash')
+            $lines.Add("_bolt_ps1_default_script=$defaultScriptQuoted")
+            $lines.Add('')
+            $lines.Add('_bolt_ps1() {')
+            $lines.Add('    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"')
+            $lines.Add('    local mode="" taskdir="" script="" i')
+            $lines.Add('    for ((i = 1; i < COMP_CWORD; i++)); do')
+            $lines.Add('        case "${COMP_WORDS[i]}" in')
+            foreach ($entry in $model.Modes.GetEnumerator()) {
+                $lines.Add("            $($entry.Value.Switches -join '|')) mode=`"$($entry.Key)`" ;;")
+            }
+            $lines.Add('            -TaskDirectory) taskdir="${COMP_WORDS[i+1]}" ;;')
+            $lines.Add('        esac')
+            $lines.Add('    done')
+            $lines.Add('')
+            $lines.Add('    case "$prev" in')
+            foreach ($entry in $model.Values.GetEnumerator()) {
+                $lines.Add("        $($entry.Key)) COMPREPLY=(`$(compgen -W `"$($entry.Value -join ' ')`" -- `"`$cur`")); return ;;")
+            }
+            $lines.Add("        $($model.Directories -join '|')) COMPREPLY=(`$(compgen -d -- `"`$cur`")); return ;;")
+            if ($freeValues.Count -gt 0) {
+                $lines.Add("        $($freeValues -join '|')) return ;;")
+            }
+            $lines.Add('    esac')
+            $lines.Add('')
+            $lines.Add('    local flags')
+            $lines.Add('    case "$mode" in')
+            foreach ($entry in $model.Modes.GetEnumerator()) {
+                $lines.Add("        $($entry.Key)) flags=`"$($entry.Value.Flags -join ' ')`" ;;")
+            }
+            $lines.Add("        *) flags=`"$(($model.DefaultFlags + $modeSwitches) -join ' ')`" ;;")
+            $lines.Add('    esac')
+            $lines.Add('')
+            $lines.Add('    if [[ "$cur" == -* ]]; then')
+            $lines.Add('        COMPREPLY=($(compgen -W "$flags" -- "$cur"))')
+            $lines.Add('        return')
+            $lines.Add('    fi')
+            $lines.Add('')
+            $lines.Add('    [[ -n "$mode" ]] && return')
+            $lines.Add('    if [[ -f "${COMP_WORDS[0]}" ]]; then script="${COMP_WORDS[0]}"')
+            $lines.Add('    elif [[ -f ./bolt.ps1 ]]; then script=./bolt.ps1')
+            $lines.Add('    else script="$_bolt_ps1_default_script"; fi')
+            $lines.Add('    [[ -f "$script" ]] || return')
+            $lines.Add('    local tasks')
+            $lines.Add('    tasks=$(pwsh -NoProfile -File "$script" -ListTasks -NoHeader ${taskdir:+-TaskDirectory "$taskdir"} 2>/dev/null | cut -d" " -f1)')
+            $lines.Add('    COMPREPLY=($(compgen -W "$tasks" -- "$cur"))')
+            $lines.Add('}')
+            $lines.Add('')
+            $lines.Add('complete -F _bolt_ps1 bolt bolt.ps1 ./bolt.ps1')
+        }
+
+        'Zsh' {
+            $lines.Add('#compdef bolt bolt.ps1 ./bolt.ps1')
+            $lines.Add('# zsh completion for bolt.ps1')
+            $lines.Add('# Generated by: bolt.ps1 -Completion zsh')
+            $lines.Add("_bolt_ps1_default_script=$defaultScriptQuoted")
+            $lines.Add('')
+            $lines.Add('_bolt_ps1() {')
+            $lines.Add('    local cur="${words[CURRENT]}" prev="${words[CURRENT-1]}"')
+            $lines.Add('    local mode="" taskdir="" script="" i')
+            $lines.Add('    for ((i = 2; i < CURRENT; i++)); do')
+            $lines.Add('        case "${words[i]}" in')
+            foreach ($entry in $model.Modes.GetEnumerator()) {
+                $lines.Add("            $($entry.Value.Switches -join '|')) mode=`"$($entry.Key)`" ;;")
+            }
+            $lines.Add('            -TaskDirectory) taskdir="${words[i+1]}" ;;')
+            $lines.Add('        esac')
+            $lines.Add('    done')
+            $lines.Add('')
+            $lines.Add('    case "$prev" in')
+            foreach ($entry in $model.Values.GetEnumerator()) {
+                $lines.Add("        $($entry.Key)) compadd -- $($entry.Value -join ' '); return ;;")
+            }
+            $lines.Add("        $($model.Directories -join '|')) _files -/; return ;;")
+            if ($freeValues.Count -gt 0) {
+                $lines.Add("        $($freeValues -join '|')) return ;;")
+            }
+            $lines.Add('    esac')
+            $lines.Add('')
+            $lines.Add('    local -a flags')
+            $lines.Add('    case "$mode" in')
+            foreach ($entry in $model.Modes.GetEnumerator()) {
+                $lines.Add("        $($entry.Key)) flags=($($entry.Value.Flags -join ' ')) ;;")
+            }
+            $lines.Add("        *) flags=($(($model.DefaultFlags + $modeSwitches) -join ' ')) ;;")
+            $lines.Add('    esac')
+            $lines.Add('')
+            $lines.Add('    if [[ "$cur" == -* ]]; then')
+            $lines.Add('        compadd -- $flags')
+            $lines.Add('        return')
+            $lines.Add('    fi')
+            $lines.Add('')
+            $lines.Add('    [[ -n "$mode" ]] && return')
+            $lines.Add('    if [[ -f "${words[1]}" ]]; then script="${words[1]}"')
+            $lines.Add('    elif [[ -f ./bolt.ps1 ]]; then script=./bolt.ps1')
+            $lines.Add('    else script="$_bolt_ps1_default_script"; fi')
+            $lines.Add('    [[ -f "$script" ]] || return')
+            $lines.Add('    local -a tasks')
+            $lines.Add('    tasks=(${(f)"$(pwsh -NoProfile -File "$script" -ListTasks -NoHeader ${taskdir:+-TaskDirectory} ${taskdir:+$taskdir} 2>/dev/null | cut -d" " -f1)"})')
+            $lines.Add('    compadd -- $tasks')
+            $lines.Add('}')
+            $lines.Add('')
+            $lines.Add('compdef _bolt_ps1 bolt bolt.ps1 ./bolt.ps1')
+        }
 
-                # Fall back to non-namespaced dependency if not found in namespace
-                if (-not $resolvedDep -and $AllTasks.ContainsKey($dep)) {
-                    $resolvedDep = $dep
-                    $depTaskInfo = $AllTasks[$dep]
-                    if ($TaskInfo.Namespace) {
-                        Write-Verbose "Dependency '$dep' not found in namespace '$($TaskInfo.Namespace)', using root-level task"
-                    }
+        'Fish' {
+            $lines.Add('# fish completion for bolt.ps1')
+            $lines.Add('# Generated by: bolt.ps1 -Completion fish')
+            $lines.Add("set -g __bolt_ps1_default_script $defaultScriptQuoted")
+            $lines.Add('')
+            $lines.Add('function __bolt_ps1_mode')
+            $lines.Add('    for token in (commandline -opc)')
+            foreach ($entry in $model.Modes.GetEnumerator()) {
+                $lines.Add("        if contains -- `$token $($entry.Value.Switches -join ' ')")
+                $lines.Add("            echo $($entry.Key)")
+                $lines.Add('            return')
+                $lines.Add('        end')
+            }
+            $lines.Add('    end')
+            $lines.Add('    echo TaskExecution')
+            $lines.Add('end')
+            $lines.Add('')
+            $lines.Add('function __bolt_ps1_tasks')
+            $lines.Add('    set -l tokens (commandline -opc)')
+            $lines.Add('    set -l script $__bolt_ps1_default_script')
+            $lines.Add('    if test -f "$tokens[1]"')
+            $lines.Add('        set script $tokens[1]')
+            $lines.Add('    else if test -f ./bolt.ps1')
+            $lines.Add('        set script ./bolt.ps1')
+            $lines.Add('    end')
+            $lines.Add('    test -f "$script"; or return')
+            $lines.Add('    set -l taskdir')
+            $lines.Add('    set -l index (contains -i -- -TaskDirectory $tokens)')
+            $lines.Add('    if test -n "$index"; and test (count $tokens) -gt $index')
+            $lines.Add('        set taskdir -TaskDirectory $tokens[(math $index + 1)]')
+            $lines.Add('    end')
+            $lines.Add("    pwsh -NoProfile -File `$script -ListTasks -NoHeader `$taskdir 2>/dev/null | string replace -r '\s.*' ''")
+            $lines.Add('end')
+            $lines.Add('')
+            $lines.Add('for command in bolt bolt.ps1 ./bolt.ps1')
+            $lines.Add('    complete -c $command -f')
+            $lines.Add("    complete -c `$command -n 'test (__bolt_ps1_mode) = TaskExecution' -a '(__bolt_ps1_tasks)'")
+
+            # Each flag is offered in the modes it belongs to
+            $flagModes = [ordered]@{}
+            foreach ($flag in ($model.DefaultFlags + $modeSwitches)) {
+                if (-not $flagModes.Contains($flag)) { $flagModes[$flag] = @() }
+                $flagModes[$flag] += 'TaskExecution'
+            }
+            foreach ($entry in $model.Modes.GetEnumerator()) {
+                foreach ($flag in $entry.Value.Flags) {
+                    if (-not $flagModes.Contains($flag)) { $flagModes[$flag] = @() }
+                    $flagModes[$flag] += $entry.Key
                 }
-
-                if ($depTaskInfo) {
-                    Write-Host "`nExecuting dependency: $resolvedDep" -ForegroundColor Yellow
-                    $depResult = Invoke-Task -TaskInfo $depTaskInfo -AllTasks $AllTasks -Arguments $Arguments -ExecutedTasks $ExecutedTasks
-                    if (-not $depResult) {
-                        Write-Host "Dependency '$resolvedDep' failed" -ForegroundColor Red
-                        # Stop on dependency failure unless ErrorAction permits continuing
-                        if ($ErrorActionPreference -eq 'Stop') {
-                            return $false
-                        }
-                        Write-Host "Continuing despite dependency failure due to -ErrorAction $ErrorActionPreference..." -ForegroundColor Yellow
-                    }
+            }
+            foreach ($entry in $flagModes.GetEnumerator()) {
+                $option = $entry.Key.TrimStart('-')
+                $condition = "contains -- (__bolt_ps1_mode) $((@($entry.Value) | Select-Object -Unique) -join ' ')"
+                $argument = if ($model.Values.Contains($entry.Key)) {
+                    " -x -a '$($model.Values[$entry.Key] -join ' ')'"
+                } elseif ($model.Directories -contains $entry.Key) {
+                    " -x -a '(__fish_complete_directories)'"
+                } elseif ($model.NeedsValue -contains $entry.Key) {
+                    ' -x'
                 } else {
-                    Write-Warning "Dependency '$dep' not found, skipping"
+                    ''
                 }
+                $lines.Add("    complete -c `$command -o $option -n '$condition'$argument")
             }
-            Write-Host ""
+            $lines.Add('end')
         }
     }
 
-    # Execute the task
-    if ($TaskInfo.IsCore) {
-        # SECURITY: Log core task execution (P0 - Security Event Logging)
-        Write-SecurityLog -Event "TaskExecution" -Details "Core task: $primaryName" -Severity "Info"
+    return ($lines -join "`n")
+}
 
-        # Execute core task function
-        $result = & $TaskInfo.Function
+function Get-InitTaskTemplates {
+    <#
+    .SYNOPSIS
+        Builds the starter task files for -Init
+    .DESCRIPTION
+        Returns the task files to write for a Go, Node, or Generic project.
+        Go reads the module name and Go version from go.mod and creates tidy,
+        lint, test, build, and release tasks. Node reads the scripts from
+        package.json and picks the package manager from the lock file.
+        Generic creates build and test tasks to fill in.
+    .PARAMETER Type
+        Go, Node, or Generic
+    .PARAMETER ProjectRoot
+        The directory to inspect for go.mod or package.json
+    .OUTPUTS
+        PSCustomObject with Files (ordered file name to content) and Details
+        (lines describing what was detected)
+    #>
+    [CmdletBinding()]
+    param(
+        [Parameter(Mandatory = $true)]
+        [ValidateSet('Go', 'Node', 'Generic')]
+        [string]$Type,
 
-        # Log completion
-        $status = if ($result) { "succeeded" } else { "failed" }
-        Write-SecurityLog -Event "TaskCompletion" -Details "Core task: $primaryName ($status)" -Severity $(if ($result) { "Info" } else { "Error" })
+        [Parameter(Mandatory = $true)]
+        [string]$ProjectRoot
+    )
 
-        return $result
-    } else {
-        # SECURITY: Log task execution (P0 - Security Event Logging)
-        Write-SecurityLog -Event "TaskExecution" -Details "Task: $primaryName, Script: $($TaskInfo.ScriptPath)" -Severity "Info"
+    $files = [ordered]@{}
+    $details = @()
 
-        # Execute external script with utility functions injected
-        try {
-            # SECURITY: Validate script path before interpolation (P0 - Path Sanitization)
-            $scriptPath = $TaskInfo.ScriptPath
+    switch ($Type) {
+        'Go' {
+            $goModPath = Join-Path -Path $ProjectRoot -ChildPath 'go.mod'
+            if (-not (Test-Path -Path $goModPath -PathType Leaf)) {
+                throw "No go.mod found in $ProjectRoot. Run 'go mod init <module>' first."
+            }
 
-            # Check for dangerous characters that could enable code injection
-            if ($scriptPath -match '[`$();{}\[\]|&<>]') {
-                throw "Script path contains potentially dangerous characters: $scriptPath"
+            $goMod = Get-Content -Path $goModPath -Raw
+            $module = if ($goMod -match '(?m)^\s*module\s+"?([^\s"]+)"?') { $Matches[1] } else { $null }
+            if (-not $module) {
+                throw "Could not find a module line in $goModPath"
             }
+            $goVersion = if ($goMod -match '(?m)^\s*go\s+(\d+\.\d+(\.\d+)?)\s*$') { $Matches[1] } else { 'unknown' }
+            $details += "Go module: $module"
+            $details += "Go version: $goVersion"
+
+            # Every Go task checks for the CLI first so a missing install gives a clear message
+            $goCheck = @'
+if (-not (Get-Command go -ErrorAction SilentlyContinue)) {
+    Write-Error "Go CLI not found. Please install Go: https://go.dev/doc/install"
+    exit 1
+}
+'@
+
+            $files['Invoke-Tidy.ps1'] = @'
+# TASK: tidy
+# DESCRIPTION: Tidies go.mod and go.sum for {{MODULE}}
+# DEPENDS:
 
-            # Validate path is within project directory
-            $fullScriptPath = [System.IO.Path]::GetFullPath($scriptPath)
-            $projectRoot = [System.IO.Path]::GetFullPath($script:EffectiveScriptRoot)
+{{GOCHECK}}
 
-            if (-not $fullScriptPath.StartsWith($projectRoot + [System.IO.Path]::DirectorySeparatorChar, [StringComparison]::OrdinalIgnoreCase) -and $fullScriptPath -ne $projectRoot) {
-                throw "Script path is outside project directory: $scriptPath"
-            }
+Write-Host "Running go mod tidy..." -ForegroundColor Cyan
 
-            # Get utility functions from Bolt
-            $utilities = Get-BoltUtilities
+Push-Location $BoltConfig.ProjectRoot
+try {
+    go mod tidy
+    $exitCode = $LASTEXITCODE
+}
+finally {
+    Pop-Location
+}
+
+if ($exitCode -ne 0) {
+    Write-Host "✗ go mod tidy failed" -ForegroundColor Red
+    exit 1
+}
+
+Write-Host "✓ Module files are tidy" -ForegroundColor Green
+exit 0
+'@
+
+            $files['Invoke-Lint.ps1'] = @'
+# TASK: lint
+# DESCRIPTION: Lints {{MODULE}} with golangci-lint, or go vet when golangci-lint is not installed
+# DEPENDS: tidy
+
+{{GOCHECK}}
+
+Push-Location $BoltConfig.ProjectRoot
+try {
+    if (Get-Command golangci-lint -ErrorAction SilentlyContinue) {
+        Write-Host "Running golangci-lint..." -ForegroundColor Cyan
+        golangci-lint run ./...
+    }
+    else {
+        Write-Host "Running go vet (golangci-lint not found on PATH)..." -ForegroundColor Cyan
+        go vet ./...
+    }
+    $exitCode = $LASTEXITCODE
+}
+finally {
+    Pop-Location
+}
+
+if ($exitCode -ne 0) {
+    Write-Host "✗ Lint found problems" -ForegroundColor Red
+    exit 1
+}
+
+Write-Host "✓ Lint passed" -ForegroundColor Green
+exit 0
+'@
+
+            $files['Invoke-Test.ps1'] = @'
+# TASK: test
+# DESCRIPTION: Runs the tests for {{MODULE}}
+# DEPENDS: tidy
+# TYPE: go-test
+# PACKAGES: ./...
+# COVER: true
+
+# Bolt runs go test -json itself for TYPE: go-test and reports the test counts
+# and coverage. Set RACE: true for the race detector, or COVERPROFILE: coverage.out
+# to keep the coverage profile.
+'@
+
+            $files['Invoke-Build.ps1'] = @'
+# TASK: build
+# DESCRIPTION: Builds {{MODULE}} (Go {{GOVERSION}}) into bin/
+# DEPENDS: lint, test
+# INPUTS: **/*.go, go.mod, go.sum
+# OUTPUTS: bin/*
+
+{{GOCHECK}}
+
+Write-Host "Running go build..." -ForegroundColor Cyan
+
+Push-Location $BoltConfig.ProjectRoot
+try {
+    go build -o bin/ ./...
+    $exitCode = $LASTEXITCODE
+}
+finally {
+    Pop-Location
+}
+
+if ($exitCode -ne 0) {
+    Write-Host "✗ Build failed" -ForegroundColor Red
+    exit 1
+}
+
+Write-Host "✓ Build output written to bin/" -ForegroundColor Green
+exit 0
+'@
+
+            $files['Invoke-Release.ps1'] = @'
+# TASK: release
+# DESCRIPTION: Cross-compiles {{MODULE}} for Linux, macOS, and Windows into dist/
+# DEPENDS: build
+
+{{GOCHECK}}
+
+Write-Host "Building release binaries..." -ForegroundColor Cyan
+
+$targets = @('linux/amd64', 'linux/arm64', 'darwin/amd64', 'darwin/arm64', 'windows/amd64', 'windows/arm64')
+$failedTargets = @()
+$originalGoos = $env:GOOS
+$originalGoarch = $env:GOARCH
+
+Push-Location $BoltConfig.ProjectRoot
+try {
+    foreach ($target in $targets) {
+        $os, $arch = $target -split '/'
+        $env:GOOS = $os
+        $env:GOARCH = $arch
+        Write-Host "  $target" -ForegroundColor Gray
+        go build -trimpath -ldflags '-s -w' -o "dist/$os-$arch/" ./...
+        if ($LASTEXITCODE -ne 0) {
+            $failedTargets += $target
+        }
+    }
+}
+finally {
+    $env:GOOS = $originalGoos
+    $env:GOARCH = $originalGoarch
+    Pop-Location
+}
+
+if ($failedTargets.Count -gt 0) {
+    Write-Host "✗ Release build failed for: $($failedTargets -join ', ')" -ForegroundColor Red
+    exit 1
+}
+
+Write-Host "✓ Release binaries written to dist/" -ForegroundColor Green
+exit 0
+'@
 
-            # Build function definitions for injection
-            $utilityDefinitions = @()
-            foreach ($util in $utilities.GetEnumerator()) {
-                $funcDef = $util.Value.ToString()
-                $utilityDefinitions += "function $($util.Key) { $funcDef }"
+            foreach ($fileName in @($files.Keys)) {
+                $files[$fileName] = $files[$fileName].Replace('{{GOCHECK}}', $goCheck).Replace('{{MODULE}}', $module).Replace('{{GOVERSION}}', $goVersion)
             }
+        }
 
-            # Get Bolt configuration with task context
-            $taskScriptRoot = [System.IO.Path]::GetDirectoryName($TaskInfo.ScriptPath)
-            $boltConfig = Get-BoltConfig -ScriptRoot $script:EffectiveScriptRoot -TaskDirectory $TaskDirectory -TaskScriptRoot $taskScriptRoot -TaskName $primaryName
+        'Node' {
+            $packageJsonPath = Join-Path -Path $ProjectRoot -ChildPath 'package.json'
+            if (-not (Test-Path -Path $packageJsonPath -PathType Leaf)) {
+                throw "No package.json found in $ProjectRoot. Run 'npm init' first."
+            }
 
-            # Serialize config to JSON for injection
-            $configJson = $boltConfig | ConvertTo-Json -Depth 10 -Compress
+            try {
+                $package = Get-Content -Path $packageJsonPath -Raw | ConvertFrom-Json
+            }
+            catch {
+                throw "Could not read ${packageJsonPath}: $($_.Exception.Message)"
+            }
 
-            # Escape single quotes in JSON for PowerShell string literal
-            $configJsonEscaped = $configJson -replace "'", "''"
+            # The lock file decides the package manager, npm is the default
+            $packageManager, $installCommand = if (Test-Path -Path (Join-Path $ProjectRoot 'pnpm-lock.yaml')) {
+                'pnpm', 'pnpm install --frozen-lockfile'
+            }
+            elseif (Test-Path -Path (Join-Path $ProjectRoot 'yarn.lock')) {
+                'yarn', 'yarn install --frozen-lockfile'
+            }
+            elseif (Test-Path -Path (Join-Path $ProjectRoot 'package-lock.json')) {
+                'npm', 'npm ci'
+            }
+            else {
+                'npm', 'npm install'
+            }
 
-            $stopwatchConfig = [System.Diagnostics.Stopwatch]::StartNew()
-            Write-Verbose "Configuration prepared for task '$primaryName' in $($stopwatchConfig.ElapsedMilliseconds)ms"
+            $scripts = @()
+            if ($package.scripts) {
+                $scripts = @($package.scripts.PSObject.Properties.Name)
+            }
+            $details += "Package: $(if ($package.name) { $package.name } else { '(unnamed)' })"
+            $details += "Package manager: $packageManager"
 
-            # Create the complete script that:
-            # 1. Injects BoltConfig object
-            # 2. Defines utility functions
-            # 3. Sets up task context variables
-            # 4. Executes the original task script
-            $scriptContent = @"
-# Injected Bolt configuration
-`$BoltConfig = '$configJsonEscaped' | ConvertFrom-Json
+            $template = @'
+# TASK: {{TASK}}
+# DESCRIPTION: {{DESCRIPTION}}
+# DEPENDS: {{DEPENDS}}
 
-# Injected Bolt utility functions
-$($utilityDefinitions -join "`n")
+if (-not (Get-Command {{PM}} -ErrorAction SilentlyContinue)) {
+    Write-Error "{{PM}} not found. Please install Node.js: https://nodejs.org/"
+    exit 1
+}
 
-# Set task context variables
-`$TaskScriptRoot = '$taskScriptRoot'
+Write-Host "Running {{COMMAND}}..." -ForegroundColor Cyan
 
-# Execute the original task script in the context of its directory
-Push-Location `$TaskScriptRoot
+Push-Location $BoltConfig.ProjectRoot
 try {
-    . '$($TaskInfo.ScriptPath)' @Arguments
-} finally {
+    {{COMMAND}}
+    $exitCode = $LASTEXITCODE
+}
+finally {
     Pop-Location
 }
-"@
 
-            $scriptBlock = [ScriptBlock]::Create($scriptContent)
+if ($exitCode -ne 0) {
+    Write-Host "✗ {{COMMAND}} failed" -ForegroundColor Red
+    exit 1
+}
+
+Write-Host "✓ {{TASK}} completed successfully" -ForegroundColor Green
+exit 0
+'@
+
+            $nodeTasks = @(
+                @{ Name = 'install'; Description = "Installs dependencies with $packageManager"; Depends = ''; Command = $installCommand }
+            )
+            $buildDepends = @('install')
+            foreach ($scriptName in @('lint', 'test')) {
+                if ($scripts -contains $scriptName) {
+                    $nodeTasks += @{ Name = $scriptName; Description = "Runs the '$scriptName' script from package.json"; Depends = 'install'; Command = "$packageManager run $scriptName" }
+                    $buildDepends += $scriptName
+                }
+            }
+            if ($scripts -contains 'build') {
+                $nodeTasks += @{ Name = 'build'; Description = "Runs the 'build' script from package.json"; Depends = ($buildDepends -join ', '); Command = "$packageManager run build" }
+            }
+            $details += "Scripts found: $(if ($scripts.Count -gt 0) { $scripts -join ', ' } else { '(none)' })"
 
-            # Execute with the injected functions and context
-            & $scriptBlock
+            foreach ($nodeTask in $nodeTasks) {
+                $taskNameCapitalized = (Get-Culture).TextInfo.ToTitleCase($nodeTask.Name)
+                $files["Invoke-$taskNameCapitalized.ps1"] = $template.Replace('{{TASK}}', $nodeTask.Name).Replace('{{DESCRIPTION}}', $nodeTask.Description).Replace('{{DEPENDS}}', $nodeTask.Depends).Replace('{{COMMAND}}', $nodeTask.Command).Replace('{{PM}}', $packageManager)
+            }
+        }
 
-        } catch {
-            Write-Error "Error executing task '$primaryName': $_"
-            Write-SecurityLog -Event "TaskCompletion" -Details "Task: $primaryName (failed with error: $_)" -Severity "Error"
-            return $false
+        'Generic' {
+            $files['Invoke-Build.ps1'] = @'
+# TASK: build
+# DESCRIPTION: TODO: Describe how this project is built
+# DEPENDS: test
+
+Write-Host "Running build task..." -ForegroundColor Cyan
+
+# TODO: Replace this with the command that builds the project
+$exitCode = 0
+
+if ($exitCode -ne 0) {
+    Write-Host "✗ Build failed" -ForegroundColor Red
+    exit 1
+}
+
+Write-Host "✓ Build completed successfully" -ForegroundColor Green
+exit 0
+'@
+
+            $files['Invoke-Test.ps1'] = @'
+# TASK: test
+# DESCRIPTION: TODO: Describe how this project is tested
+# DEPENDS:
+
+Write-Host "Running test task..." -ForegroundColor Cyan
+
+# TODO: Replace this with the command that runs the tests
+$exitCode = 0
+
+if ($exitCode -ne 0) {
+    Write-Host "✗ Tests failed" -ForegroundColor Red
+    exit 1
+}
+
+Write-Host "✓ Tests passed" -ForegroundColor Green
+exit 0
+'@
+        }
+    }
+
+    return [PSCustomObject]@{
+        Files   = $files
+        Details = $details
+    }
+}
+
+
+# Handle parameter sets
+switch ($PSCmdlet.ParameterSetName) {
+    'Help' {
+        # With no parameters in a terminal, pick the tasks from the -Interactive menu
+        if (Test-InteractiveTerminal) {
+            $Interactive = $true
+            break
+        }
+
+        # Default behavior when no parameters - show available tasks
+        Write-Host "Bolt! Build orchestration for PowerShell" -ForegroundColor Cyan
+        Write-Host ""
+        Write-Host "Usage:" -ForegroundColor Yellow
+        Write-Host "  .\bolt.ps1 <task> [task2 task3...] [arguments]" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task>,<task2>,<task3> [arguments]  (comma-separated)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -Only [arguments]  (skip dependencies)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -Parallel [-Parallelism <n>]  (run independent tasks at the same time)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 -Tag <tag>  (run every task with a # TAGS: label)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 -Interactive  (pick the tasks to run from a menu)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -NoFailFast  (keep going after a failure and list every failed task)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -NoCache  (ignore cached results)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -UpdateSnapshot  (rewrite # SNAPSHOT: files)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -DryRun  (show the plan without running anything)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -Watch  (re-run when input files change)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -Benchmark [-Runs <n>]  (time repeated runs)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -OutputFormat Json  (machine-readable results)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -CleanEnv  (only declared variables, PATH, HOME, and temp)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -RefreshRemotes  (download RemoteIncludes again)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -AuditLog <path>  (append a JSON record per task)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -CpuProfile <path> -MemProfile <path>  (profile bolt itself)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 <task> -OtelEndpoint <url> [-OtelServiceName <name>]  (OpenTelemetry trace)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 -ListTasks  (or -Help)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 -NewTask <name>" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 -Init -Type Go|Node|Generic [-Force]  (scaffold starter tasks)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 -Graph [<task>] [-Format Dot|Mermaid]  (dependency graph)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 -Completion bash|zsh|fish  (shell completion script)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 -Secret Set|Get|Delete -Key <name>  (secrets in the OS keychain)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 -Audit Tail -AuditLog <path> [-Last <n>]  (recent audit records)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 -ListLogs  (task log files of the last run, with LogDir)" -ForegroundColor Gray
+        Write-Host "  .\bolt.ps1 -Upgrade Check|Apply  (look for or install a newer bolt release)" -ForegroundColor Gray
+        Write-Host ""
+        Write-Host "For module installation, use New-BoltModule.ps1:" -ForegroundColor Yellow
+        Write-Host "  .\New-BoltModule.ps1 -Install" -ForegroundColor Gray
+        Write-Host "  .\New-BoltModule.ps1 -Uninstall" -ForegroundColor Gray
+        Write-Host ""
+
+        # Show available tasks
+        $ListTasks = $true
+    }
+}
+
+# Determine the effective script root
+# When running as a module, use BOLT_PROJECT_ROOT environment variable
+# When running as a script, use $PSScriptRoot
+$script:EffectiveScriptRoot = if ($env:BOLT_PROJECT_ROOT) {
+    Write-Verbose "Running in module mode, using project root: $env:BOLT_PROJECT_ROOT"
+    $env:BOLT_PROJECT_ROOT
+} else {
+    Write-Verbose "Running in script mode, using PSScriptRoot: $PSScriptRoot"
+    $PSScriptRoot
+}
+
+# -BaseDir moves the root relative # WORKDIR: paths are resolved from
+$script:TaskBaseDir = $null
+if ($BaseDir) {
+    $script:TaskBaseDir = [System.IO.Path]::GetFullPath($BaseDir, (Get-Location).ProviderPath)
+    if (-not [System.IO.Directory]::Exists($script:TaskBaseDir)) {
+        Write-Error "-BaseDir '$BaseDir' does not exist: $script:TaskBaseDir"
+        exit 1
+    }
+}
+
+# JSON output keeps stdout for the run summary, so hide human-readable output
+# (script-scoped copies, so the caller's session is not changed in module mode)
+if ($OutputFormat -eq 'Json') {
+    $PSDefaultParameterValues = $PSDefaultParameterValues.Clone()
+    $PSDefaultParameterValues['Write-Host:InformationAction'] = 'Ignore'
+    $WarningPreference = 'SilentlyContinue'
+}
+
+# Handle Completion parameter set (before task discovery, so stdout only has the script)
+if ($PSCmdlet.ParameterSetName -eq 'Completion') {
+    # In module mode this bolt.ps1 is not in the project, so there is no default script to list tasks with
+    $defaultScript = if ($env:BOLT_PROJECT_ROOT) { '' } else { $PSCommandPath }
+    Write-Output (Get-CompletionScript -Shell $Completion -Command $MyInvocation.MyCommand -DefaultScript $defaultScript)
+    exit 0
+}
+
+# Handle Secret parameter set (before task discovery, so -Secret Get writes only the value)
+if ($PSCmdlet.ParameterSetName -eq 'Secret') {
+    try {
+        $secretStore = Get-SecretStore
+        switch ($Secret) {
+            'Set' {
+                # Read the value from a pipe, like: cat token.txt | pwsh -File bolt.ps1 -Secret Set -Key TOKEN
+                $pipedLines = @($input)
+                if ($pipedLines.Count -gt 0) {
+                    $secretValue = $pipedLines -join "`n"
+                } elseif ([Console]::IsInputRedirected) {
+                    $secretValue = [Console]::In.ReadToEnd() -replace '\r?\n$', ''
+                } else {
+                    $secureValue = Read-Host -Prompt "Value for $Key" -AsSecureString
+                    $secretValue = [System.Net.NetworkCredential]::new('', $secureValue).Password
+                }
+                if (-not $secretValue) {
+                    Write-Error "The value for secret '$Key' cannot be empty"
+                    exit 1
+                }
+                $secretStore.Set($Key, $secretValue)
+                Write-Host "✓ Secret '$Key' stored in the $($secretStore.Type) keychain" -ForegroundColor Green
+            }
+            'Get' {
+                $secretValue = $secretStore.Get($Key)
+                if ($null -eq $secretValue) {
+                    Write-Error "Secret '$Key' is not set"
+                    exit 1
+                }
+                Write-Output $secretValue
+            }
+            'Delete' {
+                if (-not $secretStore.Delete($Key)) {
+                    Write-Error "Secret '$Key' is not set"
+                    exit 1
+                }
+                Write-Host "✓ Secret '$Key' deleted" -ForegroundColor Green
+            }
+        }
+        exit 0
+    }
+    catch {
+        Write-Error $(if ($_.FullyQualifiedErrorId -eq 'SecretStoreUnavailable') { $_.Exception.Message } else { "Secret $($Secret.ToLower()) failed: $($_.Exception.Message)" })
+        exit 1
+    }
+}
+
+# Handle ListLogs parameter set (before task discovery, it only reads last-run.txt)
+if ($PSCmdlet.ParameterSetName -eq 'ListLogs') {
+    $logDirSetting = (Get-BoltConfigFile -ScriptRoot $script:EffectiveScriptRoot -TaskDirectory $TaskDirectory)['LogDir']
+    if (-not $logDirSetting) {
+        Write-Error "LogDir is not set in bolt.config.json"
+        exit 1
+    }
+    $logIndexPath = Join-Path -Path ([System.IO.Path]::GetFullPath([string]$logDirSetting, $script:EffectiveScriptRoot)) -ChildPath 'last-run.txt'
+    if (Test-Path -LiteralPath $logIndexPath -PathType Leaf) {
+        Get-Content -LiteralPath $logIndexPath | Where-Object { $_ -and (Test-Path -LiteralPath $_ -PathType Leaf) } | Write-Output
+    }
+    exit 0
+}
+
+# Handle Audit parameter set (before task discovery, the log is all it needs)
+if ($PSCmdlet.ParameterSetName -eq 'Audit') {
+    try {
+        $auditPath = [System.IO.Path]::GetFullPath($AuditLog, (Get-Location).ProviderPath)
+        Write-AuditRecords -Records @(Get-AuditRecords -Path $auditPath -Last $Last)
+        exit 0
+    }
+    catch {
+        Write-Error $_.Exception.Message
+        exit 1
+    }
+}
+
+# Handle Upgrade parameter set (before task discovery, it only works on this script)
+if ($PSCmdlet.ParameterSetName -eq 'Upgrade') {
+    try {
+        $upgradeTarget = if ($Upgrade -eq 'Apply') { Get-BoltUpgradeTarget -ScriptPath $PSCommandPath -Version $script:BoltVersion }
+        $latestRelease = Get-BoltLatestRelease
+        if (-not (Test-NewerBoltVersion -Current $script:BoltVersion -Candidate $latestRelease.Version)) {
+            Write-Host "✓ Bolt $script:BoltVersion is up to date (latest release: $($latestRelease.Version))" -ForegroundColor Green
+            exit 0
         }
 
-        # Check exit code
-        if ($LASTEXITCODE -ne 0 -and $LASTEXITCODE -ne $null) {
-            Write-SecurityLog -Event "TaskCompletion" -Details "Task: $primaryName (failed with exit code: $LASTEXITCODE)" -Severity "Error"
-            return $false
+        Write-Host "Bolt $($latestRelease.Version) is available (this is $script:BoltVersion)" -ForegroundColor Yellow
+        if ($Upgrade -eq 'Check') {
+            Write-Host "Run .\bolt.ps1 -Upgrade Apply to install it" -ForegroundColor Gray
+            exit 0
         }
 
-        Write-SecurityLog -Event "TaskCompletion" -Details "Task: $primaryName (succeeded)" -Severity "Info"
-        return $true
+        $upgradedPath = Install-BoltRelease -Release $latestRelease -TargetPath $upgradeTarget.Path -Module:$upgradeTarget.Module
+        Write-SecurityLog -Event "BoltUpgrade" -Details "Replaced $upgradedPath with bolt $($latestRelease.Version) (was $script:BoltVersion)" -Severity "Warning"
+        Write-Host "✓ Replaced $upgradedPath with bolt $($latestRelease.Version)" -ForegroundColor Green
+        Write-Host "Run bolt again to use the new version" -ForegroundColor Gray
+        exit 0
     }
-}
-
-# Handle parameter sets
-switch ($PSCmdlet.ParameterSetName) {
-    'Help' {
-        # Default behavior when no parameters - show available tasks
-        Write-Host "Bolt! Build orchestration for PowerShell" -ForegroundColor Cyan
-        Write-Host ""
-        Write-Host "Usage:" -ForegroundColor Yellow
-        Write-Host "  .\bolt.ps1 <task> [task2 task3...] [arguments]" -ForegroundColor Gray
-        Write-Host "  .\bolt.ps1 <task>,<task2>,<task3> [arguments]  (comma-separated)" -ForegroundColor Gray
-        Write-Host "  .\bolt.ps1 <task> -Only [arguments]  (skip dependencies)" -ForegroundColor Gray
-        Write-Host "  .\bolt.ps1 -ListTasks  (or -Help)" -ForegroundColor Gray
-        Write-Host "  .\bolt.ps1 -NewTask <name>" -ForegroundColor Gray
-        Write-Host ""
-        Write-Host "For module installation, use New-BoltModule.ps1:" -ForegroundColor Yellow
-        Write-Host "  .\New-BoltModule.ps1 -Install" -ForegroundColor Gray
-        Write-Host "  .\New-BoltModule.ps1 -Uninstall" -ForegroundColor Gray
-        Write-Host ""
-
-        # Show available tasks
-        $ListTasks = $true
+    catch {
+        Write-Error $_.Exception.Message
+        exit 1
     }
 }
 
-# Determine the effective script root
-# When running as a module, use BOLT_PROJECT_ROOT environment variable
-# When running as a script, use $PSScriptRoot
-$script:EffectiveScriptRoot = if ($env:BOLT_PROJECT_ROOT) {
-    Write-Verbose "Running in module mode, using project root: $env:BOLT_PROJECT_ROOT"
-    $env:BOLT_PROJECT_ROOT
-} else {
-    Write-Verbose "Running in script mode, using PSScriptRoot: $PSScriptRoot"
-    $PSScriptRoot
+# -CpuProfile and -MemProfile time bolt's own work, starting with task discovery
+# (paths are resolved now, because tasks with # WORKDIR: change the location)
+$script:BoltProfiler = $null
+if ($CpuProfile -or $MemProfile) {
+    $cpuProfilePath = if ($CpuProfile) { [System.IO.Path]::GetFullPath($CpuProfile, (Get-Location).ProviderPath) }
+    $memProfilePath = if ($MemProfile) { [System.IO.Path]::GetFullPath($MemProfile, (Get-Location).ProviderPath) }
+    $script:BoltProfiler = New-BoltProfiler
 }
 
 # Discover all available tasks
-$availableTasks = Get-AllTasks -TaskDirectory $TaskDirectory -ScriptRoot $EffectiveScriptRoot
+Start-BoltProfilePhase -Name 'discover'
+try {
+    $taskStore = Get-TaskStore -ScriptRoot $EffectiveScriptRoot -TaskDirectory $TaskDirectory -RefreshRemotes:$RefreshRemotes
+    $availableTasks = Get-AllTasks -TaskDirectory $TaskDirectory -ScriptRoot $EffectiveScriptRoot -RefreshRemotes:$RefreshRemotes -Store $taskStore
+}
+catch {
+    Write-Error $_.Exception.Message
+    exit 1
+}
+Stop-BoltProfilePhase -Name 'discover'
 
 # SECURITY: Log TaskDirectory usage if non-default (P0 - Security Event Logging)
 if ($TaskDirectory -ne ".build") {
@@ -2267,6 +11581,69 @@ exit 0
     exit 0
 }
 
+# Handle Init parameter set
+if ($PSCmdlet.ParameterSetName -eq 'Init') {
+    Write-Host "Creating starter tasks for a $Type project" -ForegroundColor Cyan
+
+    try {
+        $scaffold = Get-InitTaskTemplates -Type $Type -ProjectRoot $EffectiveScriptRoot
+    }
+    catch {
+        Write-Error $_.Exception.Message
+        exit 1
+    }
+
+    foreach ($detail in $scaffold.Details) {
+        Write-Host "  $detail" -ForegroundColor Gray
+    }
+
+    $buildPath = Join-Path -Path $EffectiveScriptRoot -ChildPath $TaskDirectory
+
+    # Check every file first so a refused run writes nothing
+    $existingFiles = @($scaffold.Files.Keys | Where-Object { Test-Path -Path (Join-Path -Path $buildPath -ChildPath $_) })
+    if ($existingFiles.Count -gt 0 -and -not $Force) {
+        Write-Error "Task files already exist in ${TaskDirectory}: $($existingFiles -join ', '). Use -Force to overwrite them."
+        exit 1
+    }
+
+    if (-not (Test-Path -Path $buildPath)) {
+        New-Item -Path $buildPath -ItemType Directory -Force | Out-Null
+        Write-Host "  Created $TaskDirectory directory" -ForegroundColor Gray
+    }
+
+    Write-Host ""
+    foreach ($fileName in $scaffold.Files.Keys) {
+        $filePath = Join-Path -Path $buildPath -ChildPath $fileName
+        $overwrite = $existingFiles -contains $fileName
+        try {
+            $scaffold.Files[$fileName] | Out-File -FilePath $filePath -Encoding UTF8 -Force:$overwrite -NoClobber:(-not $overwrite) -ErrorAction Stop
+
+            # SECURITY: Log file creation event (P0 - Security Event Logging)
+            $action = if ($overwrite) { 'Overwrote' } else { 'Created' }
+            Write-SecurityLog -Event "FileCreation" -Details "$action task file: $fileName in $TaskDirectory" -Severity "Info"
+        }
+        catch [System.IO.IOException] {
+            Write-Error "Task file already exists: $fileName"
+            exit 1
+        }
+        catch {
+            Write-Error "Failed to create task file: $_"
+            exit 1
+        }
+
+        $status = if ($overwrite) { "✓ $fileName (overwritten)" } else { "✓ $fileName" }
+        Write-Host $status -ForegroundColor Green
+    }
+
+    Write-Host ""
+    Write-Host "Next steps:" -ForegroundColor Yellow
+    Write-Host "  1. Review the generated tasks in $TaskDirectory" -ForegroundColor Gray
+    Write-Host "  2. Run '.\bolt.ps1 -ListTasks' to see them" -ForegroundColor Gray
+    Write-Host "  3. Run '.\bolt.ps1 -ValidateTasks' after editing them" -ForegroundColor Gray
+
+    exit 0
+}
+
 # Handle ListVariables parameter set
 if ($PSCmdlet.ParameterSetName -eq 'ListVariables') {
     Show-BoltVariables -ScriptRoot $EffectiveScriptRoot -TaskDirectory $TaskDirectory
@@ -2305,16 +11682,35 @@ if ($PSCmdlet.ParameterSetName -eq 'RemoveVariable') {
     }
 }
 
+# Handle Graph parameter set
+if ($PSCmdlet.ParameterSetName -eq 'Graph') {
+    $graphTasks = @($Task | ForEach-Object { $_ -split ',' } | ForEach-Object { $_.Trim() } | Where-Object { $_ })
+    $unknownTasks = @($graphTasks | Where-Object { -not $availableTasks.ContainsKey($_) })
+    if ($unknownTasks.Count -gt 0) {
+        Write-Error "Task(s) not found: $($unknownTasks -join ', ')"
+        exit 1
+    }
+
+    $taskGraph = Get-TaskGraph -AllTasks $availableTasks -TaskNames $graphTasks
+    Write-Output (Format-TaskGraph -Graph $taskGraph -Format $Format)
+    exit 0
+}
+
 # Handle ValidateTasks parameter set
 if ($PSCmdlet.ParameterSetName -eq 'ValidateTasks') {
-    $exitCode = Show-ValidationReport -AllTasks $availableTasks -TaskDirectory $TaskDirectory
+    $exitCode = Show-ValidationReport -AllTasks $availableTasks -TaskDirectory $TaskDirectory -Strict:$Strict
     exit $exitCode
 }
 
+# -Tag accepts comma-separated lists like -Task
+$tagNames = @($Tag | ForEach-Object { $_ -split ',' } | ForEach-Object { $_.Trim() } | Where-Object { $_ } | Select-Object -Unique)
+
 # Handle ListTasks parameter set
 if ($PSCmdlet.ParameterSetName -eq 'ListTasks' -or $ListTasks) {
-    Write-Host "Available tasks:" -ForegroundColor Cyan
-    Write-Host ""
+    if (-not $NoHeader) {
+        Write-Host "Available tasks:" -ForegroundColor Cyan
+        Write-Host ""
+    }
 
     $uniqueTasks = @{}
     foreach ($taskKey in $availableTasks.Keys) {
@@ -2337,47 +11733,137 @@ if ($PSCmdlet.ParameterSetName -eq 'ListTasks' -or $ListTasks) {
             continue
         }
         $primaryName = $names[0]
+
+        # Matrix instances are shown with their matrix task
+        if ($taskInfo['MatrixParent']) {
+            continue
+        }
+        # -Filter matches the primary name or any alias
+        if ($Filter -and -not ($names | Where-Object { $_ -like $Filter })) {
+            continue
+        }
+        # -Tag keeps the tasks with any of the given tags
+        if ($tagNames.Count -gt 0 -and -not ($taskInfo['Tags'] | Where-Object { $_ -cin $tagNames })) {
+            continue
+        }
         if (-not $uniqueTasks.ContainsKey($primaryName)) {
             $uniqueTasks[$primaryName] = $taskInfo
         }
     }
 
-    foreach ($taskName in ($uniqueTasks.Keys | Sort-Object)) {
-        $taskInfo = $uniqueTasks[$taskName]
-        $aliases = $taskInfo['Names'] | Where-Object { $_ -ne $taskName }
-        
-        # Determine source label
-        if ($taskInfo['IsCore']) {
-            $source = "core"
-        }
-        elseif ($taskInfo['Namespace']) {
-            $source = "project:$($taskInfo['Namespace'])"
-        }
-        else {
-            $source = "project"
+    if ($OutputFormat -eq 'Json') {
+        $taskList = foreach ($taskName in ($uniqueTasks.Keys | Sort-Object)) {
+            $taskInfo = $uniqueTasks[$taskName]
+            [ordered]@{
+                Name         = $taskName
+                Aliases      = @($taskInfo['Names'] | Where-Object { $_ -ne $taskName })
+                Description  = $taskInfo['Description']
+                Dependencies = @($taskInfo['Dependencies'])
+                Type         = [string]$taskInfo['Type']
+                Timeout      = $taskInfo['Timeout']
+                Source       = if ($taskInfo['IsCore']) { 'core' } else { 'project' }
+                Namespace    = $taskInfo['Namespace']
+                Matrix       = @($taskInfo['MatrixInstances'] | Where-Object { $_ })
+                Tags         = @($taskInfo['Tags'] | Where-Object { $_ })
+                ScriptPath   = $taskInfo['ScriptPath']
+            }
         }
+        Write-Output (ConvertTo-Json -InputObject @($taskList) -Depth 5 -AsArray)
+        exit 0
+    }
 
-        Write-Host "  $taskName" -ForegroundColor Green -NoNewline
-        if ($aliases.Count -gt 0) {
-            Write-Host " (aliases: $($aliases -join ', '))" -ForegroundColor Gray -NoNewline
+    if ($uniqueTasks.Count -eq 0 -and ($Filter -or $tagNames.Count -gt 0)) {
+        $selection = @(if ($Filter) { "'$Filter'" }) + @(if ($tagNames.Count -gt 0) { "tag $($tagNames -join ', ')" })
+        Write-Host "No tasks match $($selection -join ' with ')" -ForegroundColor Yellow
+        exit 0
+    }
+
+    # -NoHeader prints one aligned line per task for scripts
+    if ($NoHeader) {
+        $nameWidth = ($uniqueTasks.Keys | Measure-Object -Property Length -Maximum).Maximum
+        foreach ($taskName in ($uniqueTasks.Keys | Sort-Object)) {
+            Write-Output ("{0}  {1}" -f $taskName.PadRight($nameWidth), $uniqueTasks[$taskName]['Description']).TrimEnd()
         }
-        Write-Host " [$source]" -ForegroundColor DarkGray
+        exit 0
+    }
+
+    # Group tasks by source: core, root project tasks, then one group per namespace
+    $groups = [ordered]@{}
+    $groupOrder = @('core', 'project') + @($uniqueTasks.Values | Where-Object { $_['Namespace'] } | ForEach-Object { "project:$($_['Namespace'])" } | Sort-Object -Unique)
+    foreach ($group in $groupOrder) {
+        $groups[$group] = @()
+    }
+    foreach ($taskName in ($uniqueTasks.Keys | Sort-Object)) {
+        $taskInfo = $uniqueTasks[$taskName]
+        $source = if ($taskInfo['IsCore']) { 'core' } elseif ($taskInfo['Namespace']) { "project:$($taskInfo['Namespace'])" } else { 'project' }
+        $groups[$source] += $taskName
+    }
 
-        if ($taskInfo['Description']) {
-            Write-Host "    $($taskInfo['Description'])" -ForegroundColor Gray
+    foreach ($source in $groups.Keys) {
+        if ($groups[$source].Count -eq 0) {
+            continue
         }
 
-        if ($taskInfo['Dependencies'].Count -gt 0) {
-            Write-Host "    Dependencies: $($taskInfo['Dependencies'] -join ', ')" -ForegroundColor DarkGray
+        $groupTitle = switch ($source) {
+            'core' { 'Core tasks' }
+            'project' { 'Project tasks' }
+            default { "Namespace '$($source.Substring('project:'.Length))'" }
         }
+        Write-Host "$($groupTitle):" -ForegroundColor Yellow
         Write-Host ""
+
+        foreach ($taskName in $groups[$source]) {
+            $taskInfo = $uniqueTasks[$taskName]
+            $aliases = $taskInfo['Names'] | Where-Object { $_ -ne $taskName }
+
+            Write-Host "  $taskName" -ForegroundColor Green -NoNewline
+            if ($aliases.Count -gt 0) {
+                Write-Host " (aliases: $($aliases -join ', '))" -ForegroundColor Gray -NoNewline
+            }
+            Write-Host " [$source]" -ForegroundColor DarkGray
+
+            if ($taskInfo['Description']) {
+                Write-Host "    $($taskInfo['Description'])" -ForegroundColor Gray
+            }
+
+            if ($taskInfo['Type'] -eq 'group') {
+                # Members are listed under their group with their own descriptions
+                $memberNames = @(
+                    foreach ($dep in $taskInfo['Dependencies']) {
+                        $resolvedDep = Resolve-TaskDependency -DependencyName $dep -CurrentNamespace $taskInfo['Namespace'] -Tasks $availableTasks
+                        if ($resolvedDep) { $availableTasks[$resolvedDep].Names[0] } else { $dep }
+                    }
+                )
+                $memberWidth = ($memberNames | Measure-Object -Property Length -Maximum).Maximum
+                Write-Host "    Group of:" -ForegroundColor DarkGray
+                foreach ($memberName in $memberNames) {
+                    $memberDescription = if ($availableTasks.ContainsKey($memberName)) { $availableTasks[$memberName].Description } else { '(not found)' }
+                    Write-Host ("      {0}  {1}" -f $memberName.PadRight($memberWidth), $memberDescription).TrimEnd() -ForegroundColor Gray
+                }
+            } elseif ($taskInfo['Dependencies'].Count -gt 0) {
+                Write-Host "    Dependencies: $($taskInfo['Dependencies'] -join ', ')" -ForegroundColor DarkGray
+            }
+
+            if ($taskInfo['Timeout']) {
+                Write-Host "    Timeout: $($taskInfo['Timeout'])" -ForegroundColor DarkGray
+            }
+
+            if ($taskInfo['MatrixInstances']) {
+                Write-Host "    Matrix: $($taskInfo['MatrixInstances'] -join ', ')" -ForegroundColor DarkGray
+            }
+
+            if ($taskInfo['Tags']) {
+                Write-Host "    Tags: $($taskInfo['Tags'] -join ', ')" -ForegroundColor DarkGray
+            }
+            Write-Host ""
+        }
     }
 
     exit 0
 }
 
-# Handle TaskExecution parameter set
-if ($PSCmdlet.ParameterSetName -eq 'TaskExecution') {
+# Handle TaskExecution parameter set (and the menu a bare run in a terminal shows)
+if ($PSCmdlet.ParameterSetName -eq 'TaskExecution' -or $Interactive) {
     # Parse task list - support comma-separated or space-separated tasks
     $taskList = @()
     $remainingArgs = @()
@@ -2413,10 +11899,35 @@ foreach ($arg in $Arguments) {
     }
 }
 
+# -Tag adds every tagged task, as if its name had been given
+if ($tagNames.Count -gt 0) {
+    $taggedTasks = Get-TaggedTask -Tag $tagNames -AllTasks $availableTasks
+    if ($taggedTasks.Count -eq 0) {
+        Write-Error "No tasks are tagged $($tagNames -join ', ')"
+        exit 1
+    }
+    $taskList = @($taskList) + @($taggedTasks | Where-Object { $_ -notin $taskList })
+}
+if ($taskList.Count -eq 0 -and $Interactive) {
+    if (-not (Test-InteractiveTerminal)) {
+        Write-Error "-Interactive needs a terminal; pass the task names instead"
+        exit 1
+    }
+    $taskList = @(Select-TaskInteractive -AllTasks $availableTasks)
+    if ($taskList.Count -eq 0) {
+        Write-Host "No tasks selected" -ForegroundColor Yellow
+        exit 0
+    }
+}
+if ($taskList.Count -eq 0) {
+    Write-Error "Specify the tasks to run, or -Tag to run the tasks with a tag"
+    exit 1
+}
+
 # Handle -Outline flag (before validation so we can show missing tasks)
 if ($Outline) {
-    Show-TaskOutline -TaskNames $taskList -AllTasks $availableTasks -SkipDependencies $Only
-    exit 0
+    $exitCode = Show-TaskOutline -TaskNames $taskList -AllTasks $availableTasks -SkipDependencies $Only
+    exit $exitCode
 }
 
 # Validate all tasks exist
@@ -2427,7 +11938,196 @@ foreach ($taskName in $taskList) {
     }
 }
 
-function Write-Separator {
+if ($FailFast -and $NoFailFast) {
+    Write-Error "-FailFast and -NoFailFast cannot be used together"
+    exit 1
+}
+
+# Time a child task gets to exit after SIGINT or SIGTERM before it is killed
+try {
+    $script:GracePeriodMs = [long](ConvertFrom-Duration -Duration $GracePeriod).TotalMilliseconds
+}
+catch {
+    Write-Error "Invalid -GracePeriod: $($_.Exception.Message)"
+    exit 1
+}
+try {
+    $lockTimeoutSpan = ConvertFrom-Duration -Duration $LockTimeout
+}
+catch {
+    Write-Error "Invalid -LockTimeout: $($_.Exception.Message)"
+    exit 1
+}
+
+# Structured log on stderr, separate from the console output
+if ($LogLevel) {
+    $script:BoltLogger = New-BoltLogger -Level $LogLevel -Format $LogFormat
+}
+
+# Task audit records, appended by Add-TaskResult
+if ($AuditLog) {
+    $script:TaskAuditLog = New-AuditLog -Path ([System.IO.Path]::GetFullPath($AuditLog, (Get-Location).ProviderPath)) -GitSha (Get-GitHeadSha)
+}
+
+# OpenTelemetry trace of the run, with a span per task from Add-TaskResult
+if ($OtelEndpoint) {
+    $otelService = if ($OtelServiceName) { $OtelServiceName } elseif ($env:OTEL_SERVICE_NAME) { $env:OTEL_SERVICE_NAME } else { 'bolt' }
+    try {
+        $script:TaskTracer = New-TaskTracer -Endpoint $OtelEndpoint -ServiceName $otelService -TaskNames $taskList
+    }
+    catch {
+        if ($_.FullyQualifiedErrorId -ne 'InvalidOtelEndpoint') {
+            throw
+        }
+        Write-Error $_.Exception.Message
+        exit 1
+    }
+}
+
+# LogDir in bolt.config.json keeps a copy of each task's output, relative to the project root
+$script:TaskLogDir = $null
+$logDirSetting = (Get-BoltConfigFile -ScriptRoot $script:EffectiveScriptRoot -TaskDirectory $TaskDirectory)['LogDir']
+if ($logDirSetting) {
+    $script:TaskLogDir = [System.IO.Path]::GetFullPath([string]$logDirSetting, $script:EffectiveScriptRoot)
+}
+
+# Build the dependency graph up front so cycles fail before any task runs
+Start-BoltProfilePhase -Name 'plan'
+try {
+    $executionOrder = Get-TaskExecutionOrder -TaskNames $taskList -AllTasks $availableTasks -SkipDependencies $Only
+}
+catch {
+    if ($_.FullyQualifiedErrorId -notin @('CyclicDependency', 'MatrixDependency', 'ArtifactProducer')) {
+        throw
+    }
+    Write-Error $_.Exception.Message
+    exit 1
+}
+Write-Verbose "Execution order: $($executionOrder -join ', ')"
+
+# Invalid # WHEN: conditions, # PLATFORM: entries, # SNAPSHOT: paths, resource limits, and # WORKDIR: paths fail before any task runs
+foreach ($taskName in $executionOrder) {
+    $taskInfo = $availableTasks[$taskName]
+    try {
+        foreach ($entry in $taskInfo.Platform) {
+            ConvertFrom-TaskPlatform -Entry $entry | Out-Null
+        }
+        if ($taskInfo.When) {
+            ConvertFrom-TaskCondition -Expression $taskInfo.When | Out-Null
+        }
+        if ($taskInfo.Snapshot) {
+            Get-TaskSnapshotPath -TaskInfo $taskInfo | Out-Null
+        }
+        Get-TaskResourceLimits -TaskInfo $taskInfo | Out-Null
+        if ($taskInfo.WorkDir) {
+            Get-TaskWorkingDirectory -TaskInfo $taskInfo | Out-Null
+        }
+    }
+    catch {
+        if ($_.FullyQualifiedErrorId -notin @('InvalidCondition', 'InvalidPlatform', 'InvalidSnapshotPath', 'InvalidResourceLimit', 'InvalidWorkingDirectory')) {
+            throw
+        }
+        Write-Error "Task '$taskName': $($_.Exception.Message)"
+        exit 1
+    }
+}
+
+# -Since skips the tasks whose inputs did not change since a git ref
+$script:SinceSkipped = @{}
+if ($Since) {
+    if ($Watch) {
+        Write-Error "-Since cannot be used with -Watch"
+        exit 1
+    }
+    try {
+        $changedFiles = Get-GitChangedFile -Ref $Since
+        foreach ($skippedTask in (Get-TaskSinceSkip -AllTasks $availableTasks -ExecutionOrder $executionOrder -ChangedFiles $changedFiles)) {
+            $script:SinceSkipped[$skippedTask] = $true
+        }
+        $script:SinceRef = $Since
+        Write-Host "Changed since ${Since}: $($changedFiles.Count) file(s), skipping $($script:SinceSkipped.Count) task(s)" -ForegroundColor Cyan
+        Write-Verbose "Changed files: $($changedFiles -join ', ')"
+    }
+    catch {
+        if ($_.FullyQualifiedErrorId -eq 'GitNotFound') {
+            Write-Warning "$($_.Exception.Message). -Since is ignored and every task runs."
+        } elseif ($_.FullyQualifiedErrorId -eq 'GitDiffFailed') {
+            Write-Error $_.Exception.Message
+            exit 1
+        } else {
+            throw
+        }
+    }
+}
+
+Stop-BoltProfilePhase -Name 'plan'
+
+# Dry run prints the plan and stops before any task or hook runs
+if ($DryRun) {
+    $plan = Get-TaskPlan -ExecutionOrder $executionOrder -AllTasks $availableTasks -Arguments $remainingArgs
+    if ($OutputFormat -eq 'Json') {
+        Write-Output ($plan | ConvertTo-Json -Depth 6)
+    } else {
+        Show-TaskPlan -TaskNames $taskList -Plan $plan
+        foreach ($planError in $plan.Errors) {
+            Write-Host $planError -ForegroundColor Red
+        }
+    }
+    exit $(if ($plan.Errors.Count -gt 0) { 1 } else { 0 })
+}
+
+# Only one run at a time per project, so two runs cannot write the same output files
+Start-BoltProfilePhase -Name 'lock'
+try {
+    $runLock = Enter-BoltLock -Path (Join-Path -Path $script:EffectiveScriptRoot -ChildPath '.bolt/bolt.lock') -Timeout $lockTimeoutSpan
+}
+catch {
+    if ($_.FullyQualifiedErrorId -ne 'LockTimeout') {
+        throw
+    }
+    Write-Error $_.Exception.Message
+    exit 1
+}
+
+Stop-BoltProfilePhase -Name 'lock'
+
+# -ListLogs prints the log files of this run from now on
+if ($script:TaskLogDir) {
+    Clear-TaskLogIndex -LogDir $script:TaskLogDir
+}
+
+# exit and Ctrl+C still run the finally block, so the lock is released in module mode too
+try {
+    Start-BoltProfilePhase -Name 'run'
+
+    # Benchmarks time full runs, so every run does the same work
+    if ($Benchmark) {
+        if ($Watch) {
+            Write-Error "-Benchmark cannot be used with -Watch"
+            exit 1
+        }
+        $NoCache = $true
+        $benchmark = Invoke-TaskBenchmark -TaskNames $taskList -ExecutionOrder $executionOrder -SkipDependencies $Only -AllTasks $availableTasks -Arguments $remainingArgs -Runs $Runs -Parallel $Parallel -Parallelism $Parallelism -OutputFormat $OutputFormat
+        if ($script:TaskStopSignal -and $script:TaskStopSignal.Signal.Value -ne 0) {
+            exit (128 + $script:TaskStopSignal.Signal.Value)
+        }
+        if ($benchmark.Report) {
+            Write-Output ($benchmark.Report | ConvertTo-Json -Depth 3)
+        }
+        exit $benchmark.ExitCode
+    }
+
+    # Watch mode runs until stopped and exits with the result of the last run
+    if ($Watch) {
+        if ($OutputFormat -eq 'Json') {
+            Write-Error "-OutputFormat Json cannot be used with -Watch"
+            exit 1
+        }
+        $watchExitCode = Invoke-TaskWatch -TaskNames $taskList -ExecutionOrder $executionOrder -SkipDependencies $Only -AllTasks $availableTasks -Arguments $remainingArgs -Debounce $Debounce -Store $taskStore
+        exit $watchExitCode
+    }
+
+    function Write-Separator {
         <#
         .SYNOPSIS
             Writes a horizontal separator line
@@ -2450,53 +12150,104 @@ function Write-Separator {
         Write-Host ($Character * $Length) -ForegroundColor $Color
     }
 
-# Execute all tasks in sequence
-$executedTasks = @{}
-$allSucceeded = $true
-$failedTasks = @()
+    # Execute all tasks (in sequence unless -Parallel is used or matrix instances are in the run)
+    $runStopwatch = [System.Diagnostics.Stopwatch]::StartNew()
+    $executedTasks = @{}
+    $allSucceeded = $true
+    $failedTasks = @()
 
-foreach ($taskName in $taskList) {
-    $taskInfo = $availableTasks[$taskName]
+    if ($Parallel -or ($executionOrder | Where-Object { $availableTasks[$_].MatrixParent })) {
+        $parallelResult = Invoke-TaskParallel -ExecutionOrder $executionOrder -AllTasks $availableTasks -Arguments $remainingArgs -Parallelism $Parallelism
+        $allSucceeded = $parallelResult.Success
+        $failedTasks = $parallelResult.FailedTasks
+    } else {
+        foreach ($taskName in $taskList) {
+            $taskInfo = $availableTasks[$taskName]
 
-    Write-Host "Executing task: $taskName" -ForegroundColor Cyan
-    if ($taskInfo.Description) {
-        Write-Host "Description: $($taskInfo.Description)" -ForegroundColor Gray
-    }
-    Write-Host ""
+            Write-Host "Executing task: $taskName" -ForegroundColor Cyan
+            if ($taskInfo.Description) {
+                Write-Host "Description: $($taskInfo.Description)" -ForegroundColor Gray
+            }
+            Write-Host ""
 
-    # Execute the task with dependency resolution
-    $result = Invoke-Task -TaskInfo $taskInfo -AllTasks $availableTasks -Arguments $remainingArgs -ExecutedTasks $executedTasks -SkipDependencies $Only
+            # Execute the task with dependency resolution
+            $result = Invoke-Task -TaskInfo $taskInfo -AllTasks $availableTasks -Arguments $remainingArgs -ExecutedTasks $executedTasks -SkipDependencies $Only
 
-    if (-not $result) {
-        Write-Host "`nTask '$taskName' failed" -ForegroundColor Red
-        $allSucceeded = $false
-        $failedTasks += $taskName
+            if (-not $result) {
+                Write-Host "`nTask '$taskName' failed" -ForegroundColor Red
+                $allSucceeded = $false
+                $failedTasks += $taskName
 
-        # Check if we should stop on error (default behavior)
-        if ($ErrorActionPreference -eq 'Stop') {
-            break
+                # Check if we should stop on error (default behavior) or were asked to stop
+                if ($script:TaskStopSignal -and $script:TaskStopSignal.Signal.Value -ne 0) {
+                    break
+                }
+                if ($NoFailFast) {
+                    Write-Host "Continuing to next task due to -NoFailFast..." -ForegroundColor Yellow
+                } elseif ($FailFast -or $ErrorActionPreference -eq 'Stop') {
+                    break
+                } else {
+                    # Otherwise continue to next task (when ErrorAction is Continue, SilentlyContinue, or Ignore)
+                    Write-Host "Continuing to next task due to -ErrorAction $ErrorActionPreference..." -ForegroundColor Yellow
+                }
+            } else {
+                Write-Host "`nTask '$taskName' completed successfully" -ForegroundColor Green
+            }
+
+            if ($taskList.Count -gt 1 -and $taskName -ne $taskList[-1]) {
+                Write-Host ""
+                Write-Separator -Character "=" -Length 60 -Color DarkGray
+                Write-Host ""
+            }
         }
-        # Otherwise continue to next task (when ErrorAction is Continue, SilentlyContinue, or Ignore)
-        Write-Host "Continuing to next task due to -ErrorAction $ErrorActionPreference..." -ForegroundColor Yellow
-    } else {
-        Write-Host "`nTask '$taskName' completed successfully" -ForegroundColor Green
     }
 
-    if ($taskList.Count -gt 1 -and $taskName -ne $taskList[-1]) {
-        Write-Host ""
-        Write-Separator -Character "=" -Length 60 -Color DarkGray
+    if ($OutputFormat -eq 'Json') {
+        Write-RunSummary -ExecutionOrder $executionOrder -DurationMs $runStopwatch.ElapsedMilliseconds -AllTasks $availableTasks
+    }
+
+    # Summary if there were failures
+    if (-not $allSucceeded) {
         Write-Host ""
+        Write-Separator -Character "=" -Length 60 -Color Red
+        Write-Host "Build completed with failures" -ForegroundColor Red
+        # Every task that failed in the run, not only the ones on the command line
+        $failedResults = @(@(if ($script:TaskResults) { $script:TaskResults }) | Where-Object { $_.Status -in @('failure', 'timeout') } | ForEach-Object { $_.Name } | Select-Object -Unique)
+        Write-Host "Failed tasks: $($(if ($failedResults.Count -gt 0) { $failedResults } else { $failedTasks }) -join ', ')" -ForegroundColor Red
+        $dependencySkips = @(@(if ($script:TaskResults) { $script:TaskResults }) | Where-Object { $_['SkipReason'] -eq 'dependency' } | ForEach-Object { $_.Name })
+        if ($dependencySkips.Count -gt 0) {
+            Write-Host "Skipped because a dependency failed: $($dependencySkips -join ', ')" -ForegroundColor Yellow
+        }
+        Write-Separator -Character "=" -Length 60 -Color Red
+
+        # Stopped by SIGINT or SIGTERM: exit like a shell would (130 or 143)
+        if ($script:TaskStopSignal -and $script:TaskStopSignal.Signal.Value -ne 0) {
+            exit (128 + $script:TaskStopSignal.Signal.Value)
+        }
+        exit 1
     }
 }
+finally {
+    $runLock.Release()
 
-# Summary if there were failures
-if (-not $allSucceeded) {
-    Write-Host ""
-    Write-Separator -Character "=" -Length 60 -Color Red
-    Write-Host "Build completed with failures" -ForegroundColor Red
-    Write-Host "Failed tasks: $($failedTasks -join ', ')" -ForegroundColor Red
-    Write-Separator -Character "=" -Length 60 -Color Red
-    exit 1
+    # Send the trace on every exit, including failures and Ctrl+C
+    if ($script:TaskTracer) {
+        try {
+            $script:TaskTracer.Shutdown()
+        }
+        catch {
+            Write-Warning "Could not send the trace to '$($script:TaskTracer.Url)': $($_.Exception.GetBaseException().Message)"
+        }
+    }
+
+    if ($script:BoltProfiler) {
+        try {
+            Save-BoltProfile -Profiler $script:BoltProfiler -CpuPath $cpuProfilePath -MemPath $memProfilePath
+        }
+        catch {
+            Write-Warning "Could not write the profile: $($_.Exception.Message)"
+        }
+    }
 }
 
 } # End TaskExecution parameter set
@@ -0,0 +1,48 @@
+<#
+.SYNOPSIS
+    Example Bolt plugin that prints a message from the task metadata
+.DESCRIPTION
+    Shows the plugin pattern: build an object with a Name and an Execute script
+    method, then register it with Register-BoltPlugin.
+
+    Add the script to bolt.config.json:
+
+        { "Plugins": ["examples/plugins/PrintPlugin.ps1"] }
+
+    and use it from a task file that has no script body:
+
+        # TASK: hello
+        # DESCRIPTION: Says hello
+        # TYPE: plugin/print
+        # MESSAGE: Hello from ${USER}
+
+    Execute gets the task (Name, Description, ScriptPath, Arguments, Metadata, Info)
+    and the task environment as a hashtable. Throw to fail the task, or set
+    $global:LASTEXITCODE to a non-zero exit code.
+#>
+
+$printPlugin = [PSCustomObject]@{
+    Name = 'print'
+}
+
+$printPlugin | Add-Member -MemberType ScriptMethod -Name Execute -Value {
+    param($Task, [hashtable]$Environment)
+
+    $message = $Task.Metadata['MESSAGE']
+    if (-not $message) {
+        throw "Task '$($Task.Name)' has no # MESSAGE: for the print plugin"
+    }
+
+    # Replace ${NAME} with the value from the task environment
+    $message = [regex]::Replace($message, '\$\{([A-Za-z_][A-Za-z0-9_]*)\}', {
+        param($match)
+        [string]$Environment[$match.Groups[1].Value]
+    })
+
+    Write-Host $message -ForegroundColor Cyan
+    foreach ($argument in $Task.Arguments) {
+        Write-Host "  argument: $argument" -ForegroundColor Gray
+    }
+}
+
+Register-BoltPlugin -Plugin $printPlugin
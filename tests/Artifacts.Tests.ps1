@@ -0,0 +1,160 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Artifact'
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Artifacts" -Tag "Core", "Artifacts" {
+
+    BeforeAll {
+        # Producer body that writes the artifact file
+        $script:PackageBody = '$dist = Join-Path $BoltConfig.ProjectRoot "dist"; New-Item -ItemType Directory -Path $dist -Force | Out-Null; Set-Content -Path (Join-Path $dist "app.txt") -Value "app" -NoNewline; exit 0'
+    }
+
+    BeforeEach {
+        foreach ($path in @('.build', 'dist')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+    }
+
+    Context "Implicit Dependencies" {
+        It "Should run the producer before the consumer" {
+            New-TestTask -Name 'package' -Body $script:PackageBody -ExtraMetadata '# PRODUCES: dist/app.txt'
+            New-TestTask -Name 'deploy' -Body 'Get-Content (Join-Path $BoltConfig.ProjectRoot "dist/app.txt"); exit 0' -ExtraMetadata '# CONSUMES: dist/app.txt'
+
+            $result = Invoke-Bolt -Arguments @('deploy')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output.IndexOf('Ran package') | Should -BeLessThan $result.Output.IndexOf('Ran deploy')
+        }
+
+        It "Should treat './dist/app.txt' and 'dist/app.txt' as the same artifact" {
+            New-TestTask -Name 'package' -Body $script:PackageBody -ExtraMetadata '# PRODUCES: ./dist/app.txt'
+            New-TestTask -Name 'deploy' -ExtraMetadata '# CONSUMES: dist/app.txt'
+
+            $plan = (Invoke-Bolt -Arguments @('deploy', '-DryRun', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            ($plan.Tasks.Name -join ',') | Should -Be 'package,deploy'
+        }
+
+        It "Should not add a second edge when the consumer already depends on the producer" {
+            New-TestTask -Name 'package' -Body $script:PackageBody -ExtraMetadata '# PRODUCES: dist/app.txt'
+            New-TestTask -Name 'deploy' -Depends @('package') -ExtraMetadata '# CONSUMES: dist/app.txt'
+
+            $tasks = (Invoke-Bolt -Arguments @('-ListTasks', '-Filter', 'deploy', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            @($tasks[0].Dependencies).Count | Should -Be 1
+        }
+
+        It "Should wait for the producer with -Parallel" {
+            New-TestTask -Name 'package' -Body "Start-Sleep -Milliseconds 300; $($script:PackageBody)" -ExtraMetadata '# PRODUCES: dist/app.txt'
+            New-TestTask -Name 'deploy' -Body 'if (-not (Test-Path (Join-Path $BoltConfig.ProjectRoot "dist/app.txt"))) { exit 1 }; exit 0' -ExtraMetadata '# CONSUMES: dist/app.txt'
+
+            $result = Invoke-Bolt -Arguments @('deploy', 'package', '-Parallel')
+
+            $result.ExitCode | Should -Be 0
+        }
+
+        It "Should connect a namespaced producer to a root consumer" {
+            New-TestTask -Name 'package' -Namespace 'golang' -Body $script:PackageBody -ExtraMetadata '# PRODUCES: dist/app.txt'
+            New-TestTask -Name 'deploy' -ExtraMetadata '# CONSUMES: dist/app.txt'
+
+            $plan = (Invoke-Bolt -Arguments @('deploy', '-DryRun', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            ($plan.Tasks.Name -join ',') | Should -Be 'golang-package,deploy'
+        }
+    }
+
+    Context "Producer Checks" {
+        It "Should fail when no task produces a consumed artifact" {
+            New-TestTask -Name 'deploy' -ExtraMetadata '# CONSUMES: dist/app.txt'
+
+            $result = Invoke-Bolt -Arguments @('deploy')
+
+            $result.ExitCode | Should -Be 1
+            $result.Error | Should -Match "Task 'deploy' consumes 'dist/app.txt', but no task produces it"
+            $result.Output | Should -Not -Match 'Ran deploy'
+        }
+
+        It "Should fail when more than one task produces an artifact" {
+            New-TestTask -Name 'package' -ExtraMetadata '# PRODUCES: dist/app.txt'
+            New-TestTask -Name 'repackage' -ExtraMetadata '# PRODUCES: dist/app.txt'
+            New-TestTask -Name 'deploy' -ExtraMetadata '# CONSUMES: dist/app.txt'
+
+            $result = Invoke-Bolt -Arguments @('deploy')
+
+            $result.ExitCode | Should -Be 1
+            $result.Error | Should -Match 'produced by more than one task: package, repackage'
+        }
+
+        It "Should report artifact problems with -ValidateTasks" {
+            New-TestTask -Name 'package' -ExtraMetadata '# PRODUCES: ../outside.txt'
+            New-TestTask -Name 'deploy' -ExtraMetadata '# CONSUMES: dist/missing.txt'
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match "No task produces 'dist/missing.txt'"
+            $result.Output | Should -Match "Artifact '\.\./outside\.txt' must be a path inside the project root"
+        }
+    }
+
+    Context "Producer Name Conflicts" {
+        BeforeEach {
+            # golang-deploy would depend on 'package', which resolves to golang-package instead of the producer
+            New-TestTask -Name 'package' -ExtraMetadata '# PRODUCES: dist/app.txt'
+            New-TestTask -Name 'package' -Namespace 'golang'
+            New-TestTask -Name 'deploy' -Namespace 'golang' -ExtraMetadata '# CONSUMES: dist/app.txt'
+        }
+
+        It "Should still list tasks" {
+            $result = Invoke-Bolt -Arguments @('-ListTasks')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match 'golang-deploy'
+        }
+
+        It "Should report the conflict with -ValidateTasks" {
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match "'dist/app\.txt' is produced by 'package', but 'golang-package' in namespace 'golang' has the same name"
+        }
+
+        It "Should fail a run of the consumer before any task runs" {
+            $result = Invoke-Bolt -Arguments @('golang-deploy')
+
+            $result.ExitCode | Should -Be 1
+            $result.Error | Should -Match "Task 'golang-deploy' consumes 'dist/app\.txt'"
+            $result.Output | Should -Not -Match 'Ran '
+        }
+    }
+
+    Context "Run Summary" {
+        It "Should list artifacts with their producer and SHA-256 digest" {
+            New-TestTask -Name 'package' -Body $script:PackageBody -ExtraMetadata '# PRODUCES: dist/app.txt'
+            New-TestTask -Name 'deploy' -ExtraMetadata '# CONSUMES: dist/app.txt'
+
+            $summary = (Invoke-Bolt -Arguments @('deploy', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            $expected = (Get-FileHash -Path (Join-Path $script:TempTestRoot 'dist/app.txt') -Algorithm SHA256).Hash.ToLowerInvariant()
+            @($summary.Artifacts).Count | Should -Be 1
+            $summary.Artifacts[0].Path | Should -Be 'dist/app.txt'
+            $summary.Artifacts[0].Producer | Should -Be 'package'
+            $summary.Artifacts[0].Sha256 | Should -Be $expected
+        }
+
+        It "Should report a null digest when the producer did not write the file" {
+            New-TestTask -Name 'package' -ExtraMetadata '# PRODUCES: dist/app.txt'
+
+            $summary = (Invoke-Bolt -Arguments @('package', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            $summary.Artifacts[0].Sha256 | Should -BeNullOrEmpty
+        }
+    }
+}
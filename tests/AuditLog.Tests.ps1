@@ -0,0 +1,163 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'AuditLog'
+    $script:AuditPath = Join-Path -Path $script:TempTestRoot -ChildPath 'audit.ndjson'
+
+    # Helper function to read every record in the audit log
+    function Get-AuditLines {
+        return @(Get-Content -Path $script:AuditPath | ForEach-Object { $_ | ConvertFrom-Json })
+    }
+
+    # Load the audit log functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $script:AuditFunctionAsts = @($ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('New-AuditLog', 'Get-AuditRecords') })
+    foreach ($functionAst in $script:AuditFunctionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Audit Log Records" -Tag "Core", "AuditLog" {
+
+    BeforeEach {
+        Remove-Item -Path $script:AuditPath -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should append one JSON record per line with every field" {
+        $auditLog = New-AuditLog -Path $script:AuditPath -GitSha '4f02f09a'
+
+        $auditLog.Write('build', 'success', 1204, 0)
+        $auditLog.Write('test', 'failure', 87, 3)
+
+        $lines = @(Get-Content -Path $script:AuditPath)
+        $lines.Count | Should -Be 2
+        $record = $lines[1] | ConvertFrom-Json -AsHashtable
+        @($record.Keys) | Should -Be @('timestamp', 'task', 'user', 'hostname', 'git_sha', 'status', 'duration_ms', 'exit_code')
+        $record.task | Should -Be 'test'
+        $record.user | Should -Be ([Environment]::UserName)
+        $record.hostname | Should -Be ([Environment]::MachineName)
+        $record.git_sha | Should -Be '4f02f09a'
+        $record.status | Should -Be 'failure'
+        $record.duration_ms | Should -Be 87
+        $record.exit_code | Should -Be 3
+        $lines[1] | Should -Match '"timestamp":"\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}Z"'
+    }
+
+    It "Should keep every record when several processes write at once" {
+        $functions = ($script:AuditFunctionAsts | ForEach-Object { $_.Extent.Text }) -join "`n"
+        $jobs = foreach ($writer in 1..4) {
+            Start-Job -ScriptBlock {
+                param($Functions, $Path, $Writer)
+
+                . ([ScriptBlock]::Create($Functions))
+                $auditLog = New-AuditLog -Path $Path
+                foreach ($run in 1..50) {
+                    $auditLog.Write("task-$Writer", 'success', $run, 0)
+                }
+            } -ArgumentList $functions, $script:AuditPath, $writer
+        }
+        $jobs | Wait-Job -Timeout 120 | Receive-Job
+        $jobs | Remove-Job -Force
+
+        $records = Get-AuditLines
+        $records.Count | Should -Be 200
+        foreach ($writer in 1..4) {
+            @($records | Where-Object task -eq "task-$writer").Count | Should -Be 50
+        }
+    }
+
+    It "Should read the last records and skip lines that are not records" {
+        $auditLog = New-AuditLog -Path $script:AuditPath
+        foreach ($task in @('one', 'two', 'three')) {
+            $auditLog.Write($task, 'success', 1, 0)
+        }
+        Add-Content -Path $script:AuditPath -Value 'not json'
+        $auditLog.Write('four', 'success', 1, 0)
+
+        $records = @(Get-AuditRecords -Path $script:AuditPath -Last 3 -WarningAction SilentlyContinue)
+
+        $records.task | Should -Be @('three', 'four')
+    }
+
+    It "Should throw AuditLogNotFound for a missing file" {
+        $thrown = $null
+        try {
+            Get-AuditRecords -Path (Join-Path $script:TempTestRoot 'missing.ndjson')
+        } catch {
+            $thrown = $_
+        }
+
+        $thrown.FullyQualifiedErrorId | Should -Be 'AuditLogNotFound'
+    }
+}
+
+Describe "Audit Log Runs" -Tag "Core", "AuditLog" {
+
+    BeforeEach {
+        foreach ($path in @('.build', '.git', 'audit.ndjson')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+    }
+
+    It "Should record every task of a run" {
+        New-TestTask -Name 'compile'
+        New-TestTask -Name 'package' -Depends @('compile') -Body 'exit 4'
+
+        $result = Invoke-Bolt -Arguments @('package', '-AuditLog', 'audit.ndjson', '-ErrorAction', 'Continue')
+
+        $result.ExitCode | Should -Be 1
+        $records = Get-AuditLines
+        $records.task | Should -Be @('compile', 'package')
+        $records[0].status | Should -Be 'success'
+        $records[1].status | Should -Be 'failure'
+        $records[1].exit_code | Should -Be 4
+        $records[1].duration_ms | Should -BeGreaterOrEqual 0
+        $records[0].git_sha | Should -BeNullOrEmpty
+    }
+
+    It "Should append to the records of earlier runs" {
+        New-TestTask -Name 'lint'
+
+        (Invoke-Bolt -Arguments @('lint', '-AuditLog', 'audit.ndjson')).ExitCode | Should -Be 0
+        (Invoke-Bolt -Arguments @('lint', '-Parallel', '-AuditLog', 'audit.ndjson')).ExitCode | Should -Be 0
+
+        (Get-AuditLines).Count | Should -Be 2
+    }
+
+    It "Should record the commit of a git repository" -Skip:(-not (Get-Command -Name git -CommandType Application -ErrorAction SilentlyContinue)) {
+        New-TestTask -Name 'lint'
+        & git -C $script:TempTestRoot init --quiet
+        & git -C $script:TempTestRoot -c user.name=bolt -c user.email=bolt@example.com commit --allow-empty --quiet -m 'initial'
+        $head = (& git -C $script:TempTestRoot rev-parse HEAD).Trim()
+
+        (Invoke-Bolt -Arguments @('lint', '-AuditLog', 'audit.ndjson')).ExitCode | Should -Be 0
+
+        (Get-AuditLines)[0].git_sha | Should -Be $head
+    }
+
+    It "Should print the last records with -Audit Tail" {
+        New-TestTask -Name 'compile'
+        New-TestTask -Name 'package' -Depends @('compile')
+        (Invoke-Bolt -Arguments @('package', '-AuditLog', 'audit.ndjson')).ExitCode | Should -Be 0
+
+        $result = Invoke-Bolt -Arguments @('-Audit', 'Tail', '-AuditLog', 'audit.ndjson', '-Last', '1')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'TIME \(UTC\)\s+TASK\s+STATUS'
+        $result.Output | Should -Match "package\s+success\s+\d+\.\d{2}s\s+0\s+$([regex]::Escape([Environment]::UserName))@"
+        $result.Output | Should -Not -Match 'compile'
+    }
+
+    It "Should fail -Audit Tail when the log does not exist" {
+        $result = Invoke-Bolt -Arguments @('-Audit', 'Tail', '-AuditLog', 'missing.ndjson')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match "Audit log '.*missing.ndjson' does not exist"
+    }
+}
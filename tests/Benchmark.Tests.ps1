@@ -0,0 +1,136 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Benchmark'
+    $script:RunsPath = Join-Path -Path $script:TempTestRoot -ChildPath 'runs.txt'
+
+    # Load the statistics function for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('Get-BenchmarkStatistics') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Benchmark Statistics" -Tag "Core", "Benchmark" {
+
+    It "Should compute mean, median, and standard deviation" {
+        $statistics = Get-BenchmarkStatistics -DurationsMs @(400, 100, 300, 200)
+
+        $statistics.Runs | Should -Be 4
+        $statistics.MeanMs | Should -Be 250
+        $statistics.MedianMs | Should -Be 250
+        $statistics.StdDevMs | Should -Be 129.1
+        $statistics.MinMs | Should -Be 100
+        $statistics.MaxMs | Should -Be 400
+    }
+
+    It "Should interpolate p95 and p99 between the nearest runs" {
+        $statistics = Get-BenchmarkStatistics -DurationsMs @(1..10 | ForEach-Object { $_ * 100 })
+
+        $statistics.MedianMs | Should -Be 550
+        $statistics.P95Ms | Should -Be 955
+        $statistics.P99Ms | Should -Be 991
+    }
+
+    It "Should report a single run with no deviation" {
+        $statistics = Get-BenchmarkStatistics -DurationsMs @(42)
+
+        $statistics.MeanMs | Should -Be 42
+        $statistics.MedianMs | Should -Be 42
+        $statistics.P99Ms | Should -Be 42
+        $statistics.StdDevMs | Should -Be 0
+    }
+}
+
+Describe "Benchmark Runs" -Tag "Core", "Benchmark" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.bolt') -Recurse -Force -ErrorAction SilentlyContinue
+        Remove-Item -Path $script:RunsPath -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should run the task and its dependencies -Runs times" {
+        New-TestTask -Name 'compile' -Body "Add-Content -Path '$($script:RunsPath)' -Value 'compile'"
+        New-TestTask -Name 'build' -Depends @('compile') -Body "Add-Content -Path '$($script:RunsPath)' -Value 'build'"
+
+        $result = Invoke-Bolt -Arguments @('build', '-Benchmark', '-Runs', '3')
+
+        $result.ExitCode | Should -Be 0
+        @(Get-Content -Path $script:RunsPath) | Should -Be @('compile', 'build', 'compile', 'build', 'compile', 'build')
+        $result.Output | Should -Match 'Benchmark run 3 of 3 took'
+        $result.Output | Should -Match 'Benchmark: build \(3 runs, sequential, cache off\)'
+        foreach ($row in @('Mean', 'Median', 'p95', 'p99', 'Std dev')) {
+            $result.Output | Should -Match "  $row\s+[\d.,]+s"
+        }
+    }
+
+    It "Should run cached tasks every time" {
+        Set-Content -Path (Join-Path $script:TempTestRoot 'input.txt') -Value 'unchanged'
+        New-TestTask -Name 'build' -ExtraMetadata '# INPUTS: input.txt' -Body "Add-Content -Path '$($script:RunsPath)' -Value 'build'"
+        $null = Invoke-Bolt -Arguments @('build')
+        Remove-Item -Path $script:RunsPath -Force
+
+        $result = Invoke-Bolt -Arguments @('build', '-Benchmark', '-Runs', '2')
+
+        $result.ExitCode | Should -Be 0
+        @(Get-Content -Path $script:RunsPath).Count | Should -Be 2
+    }
+
+    It "Should write durations and statistics as JSON" {
+        New-TestTask -Name 'build'
+
+        $result = Invoke-Bolt -Arguments @('build', '-Benchmark', '-Runs', '2', '-OutputFormat', 'Json')
+
+        $result.ExitCode | Should -Be 0
+        $report = $result.Output | ConvertFrom-Json
+        $report.Tasks | Should -Be @('build')
+        $report.Runs | Should -Be 2
+        $report.Parallel | Should -BeFalse
+        $report.DurationsMs.Count | Should -Be 2
+        foreach ($property in @('MeanMs', 'MedianMs', 'P95Ms', 'P99Ms', 'StdDevMs', 'MinMs', 'MaxMs')) {
+            $report.$property | Should -BeGreaterThan -1
+        }
+    }
+
+    It "Should honor -Parallel and -Parallelism" {
+        New-TestTask -Name 'lint'
+        New-TestTask -Name 'test'
+        New-TestTask -Name 'build' -Depends @('lint', 'test')
+
+        $result = Invoke-Bolt -Arguments @('build', '-Benchmark', '-Runs', '2', '-Parallel', '-Parallelism', '2', '-OutputFormat', 'Json')
+
+        $result.ExitCode | Should -Be 0
+        $report = $result.Output | ConvertFrom-Json
+        $report.Parallel | Should -BeTrue
+        $report.Parallelism | Should -Be 2
+        $report.DurationsMs.Count | Should -Be 2
+    }
+
+    It "Should stop at the first failing run" {
+        New-TestTask -Name 'broken' -Body "Add-Content -Path '$($script:RunsPath)' -Value 'broken'; exit 1"
+
+        $result = Invoke-Bolt -Arguments @('broken', '-Benchmark', '-Runs', '5')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'Benchmark run 1 of 5 failed'
+        @(Get-Content -Path $script:RunsPath).Count | Should -Be 1
+        $result.Output | Should -Not -Match 'Median'
+    }
+
+    It "Should reject -Benchmark with -Watch" {
+        New-TestTask -Name 'build' -ExtraMetadata '# INPUTS: input.txt'
+
+        $result = Invoke-Bolt -Arguments @('build', '-Benchmark', '-Watch')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match '-Benchmark cannot be used with -Watch'
+    }
+}
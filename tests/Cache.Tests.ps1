@@ -0,0 +1,115 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Cache'
+
+    # Helper function to reset the project: sources, cache, and a cacheable build task
+    function Initialize-CacheProject {
+        foreach ($path in @('.build', '.bolt', 'src', 'out')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        $srcPath = Join-Path -Path $script:TempTestRoot -ChildPath 'src/lib'
+        New-Item -ItemType Directory -Path $buildPath, $srcPath -Force | Out-Null
+        Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'src/main.txt') -Value 'main'
+        Set-Content -Path (Join-Path -Path $srcPath -ChildPath 'util.txt') -Value 'util'
+        Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'src/notes.md') -Value 'not an input'
+
+        $content = @'
+# TASK: build
+# DESCRIPTION: Concatenates sources
+# DEPENDS:
+# INPUTS: src/**/*.txt
+# OUTPUTS: out/*.txt
+
+$projectRoot = $BoltConfig.ProjectRoot
+New-Item -ItemType Directory -Path (Join-Path $projectRoot 'out') -Force | Out-Null
+Get-ChildItem -Path (Join-Path $projectRoot 'src') -Filter '*.txt' -Recurse |
+    Get-Content | Set-Content -Path (Join-Path $projectRoot 'out/result.txt')
+Write-Host "Built result"
+exit 0
+'@
+        Set-Content -Path (Join-Path -Path $buildPath -ChildPath 'Invoke-Build.ps1') -Value $content
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Output Caching" -Tag "Core", "Cache" {
+
+    BeforeEach {
+        Initialize-CacheProject
+    }
+
+    It "Should run the task on the first run and write a manifest" {
+        $result = Invoke-Bolt -Arguments @('build')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Built result'
+        $result.Output | Should -Not -Match 'CACHED'
+
+        $manifestPath = Join-Path -Path $script:TempTestRoot -ChildPath '.bolt/cache/build.json'
+        $manifestPath | Should -Exist
+        $manifest = Get-Content -Path $manifestPath -Raw | ConvertFrom-Json
+        $manifest.Key | Should -Match '^[0-9A-F]{64}$'
+        ($manifest.Inputs.PSObject.Properties.Name -join ',') | Should -Be 'src/lib/util.txt,src/main.txt'
+        ($manifest.Outputs -join ',') | Should -Be 'out/result.txt'
+    }
+
+    It "Should <Expected> after <Scenario>" -ForEach @(
+        @{ Scenario = 'no changes'; Expected = 'hit'; Change = {} }
+        @{ Scenario = 'one input file changed'; Expected = 'miss'; Change = { Set-Content -Path (Join-Path $script:TempTestRoot 'src/lib/util.txt') -Value 'changed' } }
+        @{ Scenario = 'an input file was added'; Expected = 'miss'; Change = { Set-Content -Path (Join-Path $script:TempTestRoot 'src/lib/extra.txt') -Value 'extra' } }
+        @{ Scenario = 'an input file was removed'; Expected = 'miss'; Change = { Remove-Item -Path (Join-Path $script:TempTestRoot 'src/main.txt') } }
+        @{ Scenario = 'a file outside the inputs changed'; Expected = 'hit'; Change = { Set-Content -Path (Join-Path $script:TempTestRoot 'src/notes.md') -Value 'edited' } }
+        @{ Scenario = 'an output was deleted'; Expected = 'miss'; Change = { Remove-Item -Path (Join-Path $script:TempTestRoot 'out/result.txt') } }
+        @{ Scenario = 'the task script changed'; Expected = 'miss'; Change = { Add-Content -Path (Join-Path $script:TempTestRoot '.build/Invoke-Build.ps1') -Value '# edited' } }
+    ) {
+        (Invoke-Bolt -Arguments @('build')).ExitCode | Should -Be 0
+        & $Change
+
+        $result = Invoke-Bolt -Arguments @('build')
+
+        $result.ExitCode | Should -Be 0
+        if ($Expected -eq 'hit') {
+            $result.Output | Should -Match 'CACHED'
+            $result.Output | Should -Not -Match 'Built result'
+        } else {
+            $result.Output | Should -Not -Match 'CACHED'
+            $result.Output | Should -Match 'Built result'
+        }
+    }
+
+    It "Should run the task with -NoCache even when nothing changed" {
+        (Invoke-Bolt -Arguments @('build')).ExitCode | Should -Be 0
+
+        $result = Invoke-Bolt -Arguments @('build', '-NoCache')
+
+        $result.Output | Should -Match 'Built result'
+        $result.Output | Should -Not -Match 'CACHED'
+    }
+
+    It "Should not cache tasks without INPUTS" {
+        $taskPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build/Invoke-Build.ps1'
+        (Get-Content -Path $taskPath) | Where-Object { $_ -notmatch '^# (INPUTS|OUTPUTS):' } | Set-Content -Path $taskPath
+
+        (Invoke-Bolt -Arguments @('build')).ExitCode | Should -Be 0
+        $result = Invoke-Bolt -Arguments @('build')
+
+        $result.Output | Should -Match 'Built result'
+        Join-Path -Path $script:TempTestRoot -ChildPath '.bolt/cache/build.json' | Should -Not -Exist
+    }
+
+    It "Should not cache a failed run" {
+        $taskPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build/Invoke-Build.ps1'
+        (Get-Content -Path $taskPath -Raw) -replace 'exit 0', 'exit 1' | Set-Content -Path $taskPath
+
+        (Invoke-Bolt -Arguments @('build')).ExitCode | Should -Be 1
+
+        Join-Path -Path $script:TempTestRoot -ChildPath '.bolt/cache/build.json' | Should -Not -Exist
+    }
+}
@@ -0,0 +1,109 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Completion'
+
+    # One task for the completion scripts to list
+    $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+    New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+    Set-Content -Path (Join-Path $buildPath 'Invoke-Deploy.ps1') -Value "# TASK: deploy`n# DESCRIPTION: Deploys`n# DEPENDS:`n`nexit 0"
+
+    # Writes the completion script for a shell to a file and returns its path
+    function Save-CompletionScript {
+        param([string]$Shell)
+
+        $result = Invoke-Bolt -Arguments @('-Completion', $Shell)
+        $path = Join-Path -Path $script:TempTestRoot -ChildPath "completion.$Shell"
+        Set-Content -Path $path -Value $result.Output -NoNewline
+        return $path
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Shell Completion Scripts" -Tag "Core", "Completion" {
+
+    Context "Generated Content" {
+        It "Should write a completion script for <Shell> to stdout" -ForEach @(
+            @{ Shell = 'bash'; Register = 'complete -F _bolt_ps1 bolt bolt.ps1' }
+            @{ Shell = 'zsh'; Register = 'compdef _bolt_ps1 bolt bolt.ps1' }
+            @{ Shell = 'fish'; Register = 'complete -c $command' }
+        ) {
+            $result = Invoke-Bolt -Arguments @('-Completion', $Shell)
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match ([regex]::Escape($Register))
+            $result.Output | Should -Match '-ListTasks -NoHeader'
+            $result.Output | Should -Not -Match 'Available tasks'
+        }
+
+        It "Should list the flags of each mode" {
+            $script = (Invoke-Bolt -Arguments @('-Completion', 'bash')).Output
+
+            $script | Should -Match 'ListTasks\) flags="[^"]*-Filter'
+            $script | Should -Match 'Init\) flags="[^"]*-Force'
+            $script | Should -Match '\*\) flags="[^"]*-Parallel[^"]*-ListTasks'
+            $script | Should -Not -Match 'flags="[^"]*-ErrorAction'
+        }
+
+        It "Should complete flag values and directories" {
+            $script = (Invoke-Bolt -Arguments @('-Completion', 'bash')).Output
+
+            $script | Should -Match '-OutputFormat\) COMPREPLY=\(\$\(compgen -W "Text Json"'
+            $script | Should -Match '-TaskDirectory\) COMPREPLY=\(\$\(compgen -d'
+        }
+
+        It "Should reject unknown shells" {
+            $result = Invoke-Bolt -Arguments @('-Completion', 'tcsh')
+
+            $result.ExitCode | Should -Not -Be 0
+        }
+    }
+
+    Context "Bash" {
+        It "Should be valid bash" -Skip:(-not (Get-Command bash -ErrorAction SilentlyContinue)) {
+            $path = Save-CompletionScript -Shell 'bash'
+
+            bash -n $path
+            $LASTEXITCODE | Should -Be 0
+        }
+
+        It "Should complete task names and flags" -Skip:(-not (Get-Command bash -ErrorAction SilentlyContinue)) {
+            $path = Save-CompletionScript -Shell 'bash'
+            $probe = @'
+source "$1"
+cd "$2"
+COMP_WORDS=(./bolt.ps1 de); COMP_CWORD=1; _bolt_ps1; echo "tasks: ${COMPREPLY[*]}"
+COMP_WORDS=(./bolt.ps1 -ListTasks -Fi); COMP_CWORD=2; _bolt_ps1; echo "flags: ${COMPREPLY[*]}"
+COMP_WORDS=(./bolt.ps1 build -OutputFormat J); COMP_CWORD=3; _bolt_ps1; echo "values: ${COMPREPLY[*]}"
+'@
+            $probePath = Join-Path $script:TempTestRoot 'probe.sh'
+            Set-Content -Path $probePath -Value $probe
+
+            $output = bash $probePath $path $script:TempTestRoot
+
+            $output | Should -Contain 'tasks: deploy'
+            $output | Should -Contain 'flags: -Filter'
+            $output | Should -Contain 'values: Json'
+        }
+    }
+
+    Context "Zsh and Fish" {
+        It "Should be valid zsh" -Skip:(-not (Get-Command zsh -ErrorAction SilentlyContinue)) {
+            $path = Save-CompletionScript -Shell 'zsh'
+
+            zsh -n $path
+            $LASTEXITCODE | Should -Be 0
+        }
+
+        It "Should be valid fish" -Skip:(-not (Get-Command fish -ErrorAction SilentlyContinue)) {
+            $path = Save-CompletionScript -Shell 'fish'
+
+            fish -n $path
+            $LASTEXITCODE | Should -Be 0
+        }
+    }
+}
@@ -0,0 +1,244 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Container'
+
+    # A fake docker that prints its arguments, one per line, so the docker run command can be checked
+    $script:FakeDockerPath = Join-Path -Path $script:TempTestRoot -ChildPath 'fake-docker'
+    New-Item -ItemType Directory -Path $script:FakeDockerPath -Force | Out-Null
+    if ($IsWindows) {
+        Set-Content -Path (Join-Path $script:FakeDockerPath 'docker.cmd') -Value "@echo off`r`nfor %%a in (%*) do echo docker-arg: %%~a"
+    } else {
+        $fakeDocker = Join-Path $script:FakeDockerPath 'docker'
+        Set-Content -Path $fakeDocker -Value "#!/bin/sh`nprintf 'docker-arg: %s\n' `"`$@`""
+        chmod +x $fakeDocker
+    }
+
+    # Load container helper functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('ConvertTo-ContainerPath', 'ConvertFrom-ContainerVolume') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Container Helpers" -Tag "Core", "Container" {
+
+    BeforeAll {
+        $script:EffectiveScriptRoot = [System.IO.Path]::GetFullPath($script:TempTestRoot)
+    }
+
+    It "Should map paths inside the project to the container root" {
+        ConvertTo-ContainerPath -Path $script:EffectiveScriptRoot -ContainerRoot '/workspace' | Should -Be '/workspace'
+        ConvertTo-ContainerPath -Path (Join-Path $script:EffectiveScriptRoot '.build') -ContainerRoot '/workspace' | Should -Be '/workspace/.build'
+    }
+
+    It "Should leave paths outside the project unchanged" {
+        $outside = [System.IO.Path]::GetTempPath()
+        ConvertTo-ContainerPath -Path $outside -ContainerRoot '/workspace' | Should -Be $outside
+    }
+
+    It "Should resolve relative volume sources against the project root" {
+        $expected = "$([System.IO.Path]::GetFullPath((Join-Path $script:EffectiveScriptRoot './cache'))):/root/.cache:ro"
+        ConvertFrom-ContainerVolume -Volume './cache:/root/.cache:ro' | Should -Be $expected
+    }
+
+    It "Should keep named volumes and absolute sources" {
+        ConvertFrom-ContainerVolume -Volume 'go-cache:/go/pkg' | Should -Be 'go-cache:/go/pkg'
+        ConvertFrom-ContainerVolume -Volume '/var/run/docker.sock:/var/run/docker.sock' | Should -Be '/var/run/docker.sock:/var/run/docker.sock'
+    }
+
+    It "Should reject volumes without a container path" {
+        { ConvertFrom-ContainerVolume -Volume 'cache' } | Should -Throw "*source:/container/path*"
+        { ConvertFrom-ContainerVolume -Volume 'my cache:/cache' } | Should -Throw "*Docker volume name*"
+    }
+}
+
+Describe "Container Tasks" -Tag "Core", "Container" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        $script:OriginalPath = $env:PATH
+    }
+
+    AfterEach {
+        $env:PATH = $script:OriginalPath
+        Remove-Item -Path Env:\BOLT_TEST_TOKEN -ErrorAction SilentlyContinue
+    }
+
+    Context "Docker Command" {
+        BeforeEach {
+            $env:PATH = "$($script:FakeDockerPath)$([System.IO.Path]::PathSeparator)$($env:PATH)"
+        }
+
+        It "Should run the task with docker run and the expanded image" {
+            New-TestTask -Name 'compile' -ExtraMetadata "# CONTAINER: golang:`${GO_VERSION}`n# ENV: GO_VERSION=1.22"
+
+            $result = Invoke-Bolt -Arguments @('compile')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match 'docker-arg: run'
+            $result.Output | Should -Match 'docker-arg: --rm'
+            $result.Output | Should -Match 'docker-arg: golang:1\.22'
+            $result.Output | Should -Match ([regex]::Escape("docker-arg: $([System.IO.Path]::GetFullPath($script:TempTestRoot)):/workspace"))
+            $result.Output | Should -Match 'docker-arg: /workspace/\.build'
+            $result.Output | Should -Not -Match 'Ran compile'
+        }
+
+        It "Should name the container after the task and run it with --init" {
+            New-TestTask -Name 'compile' -ExtraMetadata '# CONTAINER: alpine'
+
+            $result = Invoke-Bolt -Arguments @('compile')
+
+            $result.Output | Should -Match "docker-arg: --name`r?`ndocker-arg: bolt-compile-[0-9a-f]{32}"
+            $result.Output | Should -Match 'docker-arg: --init'
+        }
+
+        It "Should pass declared and passthrough variables by name only" {
+            $env:BOLT_TEST_TOKEN = 'secret-value'
+            New-TestTask -Name 'compile' -ExtraMetadata "# CONTAINER: alpine`n# ENV: MODE=release`n# ENV_PASSTHROUGH: BOLT_TEST_TOKEN"
+
+            $result = Invoke-Bolt -Arguments @('compile')
+
+            $result.Output | Should -Match 'docker-arg: MODE'
+            $result.Output | Should -Match 'docker-arg: BOLT_TEST_TOKEN'
+            $result.Output | Should -Not -Match 'secret-value'
+        }
+
+        It "Should pass CPU and memory limits to docker run" {
+            New-TestTask -Name 'compile' -ExtraMetadata "# CONTAINER: alpine`n# CPU_QUOTA: 0.5`n# MEMORY_LIMIT_MB: 256"
+
+            $result = Invoke-Bolt -Arguments @('compile')
+
+            $result.Output | Should -Match "docker-arg: --cpus`r?`ndocker-arg: 0\.5"
+            $result.Output | Should -Match "docker-arg: --memory`r?`ndocker-arg: 256m"
+        }
+
+        It "Should add the task volumes" {
+            New-TestTask -Name 'compile' -ExtraMetadata "# CONTAINER: alpine`n# VOLUMES: go-cache:/go/pkg, ./out:/out"
+
+            $result = Invoke-Bolt -Arguments @('compile')
+
+            $result.Output | Should -Match 'docker-arg: go-cache:/go/pkg'
+            $result.Output | Should -Match ([regex]::Escape("$([System.IO.Path]::GetFullPath((Join-Path $script:TempTestRoot 'out'))):/out"))
+        }
+
+        It "Should show the image in a dry run" {
+            New-TestTask -Name 'compile' -ExtraMetadata "# CONTAINER: golang:`${GO_VERSION}`n# ENV: GO_VERSION=1.22"
+
+            $result = Invoke-Bolt -Arguments @('compile', '-DryRun')
+
+            $result.Output | Should -Match 'Container: golang:1\.22'
+            $result.Output | Should -Not -Match 'docker-arg'
+        }
+    }
+
+    Context "Stopping Containers" -Skip:$IsWindows {
+        BeforeAll {
+            # A fake docker whose run never finishes and that logs the stop and kill calls
+            $script:SlowDockerPath = Join-Path -Path $script:TempTestRoot -ChildPath 'slow-docker'
+            $script:DockerLogPath = Join-Path -Path $script:TempTestRoot -ChildPath 'docker-calls.txt'
+            New-Item -ItemType Directory -Path $script:SlowDockerPath -Force | Out-Null
+            $slowDocker = Join-Path $script:SlowDockerPath 'docker'
+            Set-Content -Path $slowDocker -Value "#!/bin/sh`nif [ `"`$1`" = run ]; then sleep 30; exit 0; fi`necho `"`$@`" >> '$($script:DockerLogPath)'"
+            chmod +x $slowDocker
+        }
+
+        BeforeEach {
+            Remove-Item -Path $script:DockerLogPath -Force -ErrorAction SilentlyContinue
+            $env:PATH = "$($script:SlowDockerPath)$([System.IO.Path]::PathSeparator)$($env:PATH)"
+        }
+
+        It "Should kill the container when the task times out" {
+            New-TestTask -Name 'compile' -ExtraMetadata "# CONTAINER: alpine`n# TIMEOUT: 1s"
+
+            $result = Invoke-Bolt -Arguments @('compile')
+
+            $result.ExitCode | Should -Be 1
+            Get-Content -Path $script:DockerLogPath -Raw | Should -Match '^kill bolt-compile-[0-9a-f]{32}'
+        }
+    }
+
+    Context "Docker Not Installed" {
+        It "Should fail with a clear message when docker is not on PATH" {
+            New-TestTask -Name 'compile' -ExtraMetadata '# CONTAINER: alpine'
+            $pwshPath = (Get-Process -Id $PID).Path
+            $env:PATH = Split-Path -Path $pwshPath -Parent
+
+            $result = Invoke-Bolt -Arguments @('compile', '-OutputFormat', 'Json')
+            $summary = $result.Output | ConvertFrom-Json
+
+            $result.ExitCode | Should -Be 1
+            $summary.Tasks[0].Status | Should -Be 'failure'
+            $summary.Tasks[0].Stderr | Should -Match 'docker was not found on PATH'
+        }
+    }
+
+    Context "Validation" {
+        It "Should report invalid volumes and passthrough names" {
+            New-TestTask -Name 'compile' -ExtraMetadata "# CONTAINER: alpine`n# VOLUMES: cache`n# ENV_PASSTHROUGH: NOT-VALID"
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match 'VOLUMES'
+            $result.Output | Should -Match 'ENV_PASSTHROUGH'
+        }
+
+        It "Should report container settings without CONTAINER" {
+            New-TestTask -Name 'compile' -ExtraMetadata '# VOLUMES: go-cache:/go/pkg'
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match 'no effect without CONTAINER'
+        }
+    }
+}
+
+# Runs a real container, include with: Invoke-Pester -Tag Integration
+Describe "Container Tasks with Docker" -Tag "Integration", "Container" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'from-container.txt') -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should run the task inside the image with the project mounted" -Skip:(-not (Get-Command docker -ErrorAction SilentlyContinue)) {
+        New-TestTask -Name 'inside' -ExtraMetadata "# CONTAINER: mcr.microsoft.com/powershell:latest`n# ENV: GREETING=hello" -Body @'
+Set-Content -Path (Join-Path $BoltConfig.ProjectRoot 'from-container.txt') -Value "$env:GREETING from $($BoltConfig.ProjectRoot)"
+exit 0
+'@
+
+        $result = Invoke-Bolt -Arguments @('inside')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran inside'
+        (Get-Content -Path (Join-Path $script:TempTestRoot 'from-container.txt') -Raw).Trim() | Should -Be 'hello from /workspace'
+    }
+
+    It "Should return the exit code from the container" -Skip:(-not (Get-Command docker -ErrorAction SilentlyContinue)) {
+        New-TestTask -Name 'inside' -ExtraMetadata '# CONTAINER: mcr.microsoft.com/powershell:latest' -Body 'exit 3'
+
+        $result = Invoke-Bolt -Arguments @('inside', '-OutputFormat', 'Json')
+
+        $result.ExitCode | Should -Be 1
+        ($result.Output | ConvertFrom-Json).Tasks[0].ExitCode | Should -Be 3
+    }
+
+    It "Should not leave the container running after a timeout" -Skip:(-not (Get-Command docker -ErrorAction SilentlyContinue)) {
+        New-TestTask -Name 'sleeper' -ExtraMetadata "# CONTAINER: mcr.microsoft.com/powershell:latest`n# TIMEOUT: 5s" -Body 'Start-Sleep -Seconds 60'
+
+        $result = Invoke-Bolt -Arguments @('sleeper')
+
+        $result.ExitCode | Should -Be 1
+        Start-Sleep -Seconds 2
+        docker ps -a -q --filter 'name=bolt-sleeper-' | Should -BeNullOrEmpty
+    }
+}
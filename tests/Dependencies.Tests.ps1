@@ -0,0 +1,118 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Dependency'
+
+    # Helper function to read the numbered execution order from -Outline output
+    function Get-ExecutionOrderFromOutput {
+        param([string]$Output)
+
+        $executionSection = $Output -split 'Execution order:' | Select-Object -Last 1
+        return @([regex]::Matches($executionSection, '(?m)^\s+\d+\.\s+(\S+)') | ForEach-Object { $_.Groups[1].Value })
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Dependency Graph" -Tag "Core", "Dependencies" {
+
+    BeforeEach {
+        # Start every test with an empty .build directory
+        Get-ChildItem -Path $script:TempTestRoot -Directory -Filter '.build*' -Force -ErrorAction SilentlyContinue |
+            Remove-Item -Recurse -Force -ErrorAction SilentlyContinue
+    }
+
+    Context "Diamond Dependencies" {
+        BeforeEach {
+            # top -> left, right -> base
+            New-TestTask -Name 'base'
+            New-TestTask -Name 'left' -Depends @('base')
+            New-TestTask -Name 'right' -Depends @('base')
+            New-TestTask -Name 'top' -Depends @('left', 'right')
+        }
+
+        It "Should order the shared dependency before both branches" {
+            $result = Invoke-Bolt -Arguments @('top', '-Outline')
+            $order = Get-ExecutionOrderFromOutput -Output $result.Output
+
+            ($order -join ',') | Should -Be 'base,left,right,top'
+            $result.ExitCode | Should -Be 0
+        }
+
+        It "Should run the shared dependency only once" {
+            $result = Invoke-Bolt -Arguments @('top')
+
+            ([regex]::Matches($result.Output, 'Ran base')).Count | Should -Be 1
+            $result.Output | Should -Match 'Ran top'
+            $result.ExitCode | Should -Be 0
+        }
+    }
+
+    Context "Fan-Out Dependencies" {
+        BeforeEach {
+            # one, two, three all depend on setup
+            New-TestTask -Name 'setup'
+            New-TestTask -Name 'one' -Depends @('setup')
+            New-TestTask -Name 'two' -Depends @('setup')
+            New-TestTask -Name 'three' -Depends @('setup')
+        }
+
+        It "Should run the common dependency first and once" {
+            $result = Invoke-Bolt -Arguments @('one,two,three', '-Outline')
+            $order = Get-ExecutionOrderFromOutput -Output $result.Output
+
+            ($order -join ',') | Should -Be 'setup,one,two,three'
+        }
+
+        It "Should execute every dependent task" {
+            $result = Invoke-Bolt -Arguments @('one,two,three')
+
+            ([regex]::Matches($result.Output, 'Ran setup')).Count | Should -Be 1
+            $result.Output | Should -Match 'Ran one'
+            $result.Output | Should -Match 'Ran two'
+            $result.Output | Should -Match 'Ran three'
+            $result.ExitCode | Should -Be 0
+        }
+    }
+
+    Context "Circular Dependencies" {
+        BeforeEach {
+            # alpha -> beta -> gamma -> alpha
+            New-TestTask -Name 'alpha' -Depends @('beta')
+            New-TestTask -Name 'beta' -Depends @('gamma')
+            New-TestTask -Name 'gamma' -Depends @('alpha')
+        }
+
+        It "Should fail before running any task" {
+            $result = Invoke-Bolt -Arguments @('alpha')
+
+            $result.ExitCode | Should -Not -Be 0
+            $result.Output | Should -Not -Match 'Ran (alpha|beta|gamma)'
+        }
+
+        It "Should report the cycle path" {
+            $result = Invoke-Bolt -Arguments @('alpha')
+            $combinedOutput = $result.Error + $result.Output
+
+            $combinedOutput | Should -Match 'Circular dependency detected: alpha -> beta -> gamma -> alpha'
+        }
+
+        It "Should report the cycle in -Outline without hanging" {
+            $result = Invoke-Bolt -Arguments @('alpha', '-Outline')
+
+            $result.Output | Should -Match '\(CIRCULAR\)'
+            $result.Output | Should -Match 'Circular dependency detected'
+            $result.ExitCode | Should -Be 1
+        }
+
+        It "Should still run tasks outside the cycle with -Only" {
+            $result = Invoke-Bolt -Arguments @('alpha', '-Only')
+
+            $result.Output | Should -Match 'Ran alpha'
+            $result.ExitCode | Should -Be 0
+        }
+    }
+}
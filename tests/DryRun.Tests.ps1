@@ -0,0 +1,130 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'DryRun'
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Dry Run" -Tag "Core", "DryRun" {
+
+    BeforeEach {
+        foreach ($path in @('.build', '.bolt', 'src', 'ran.txt', 'bolt.config.json')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+    }
+
+    Context "Plan Output" {
+        BeforeEach {
+            # Every task body leaves a marker file, so a dry run must not create it
+            $marker = 'Add-Content -Path (Join-Path $BoltConfig.ProjectRoot "ran.txt") -Value "ran"; exit 0'
+            New-TestTask -Name 'format' -Body $marker
+            New-TestTask -Name 'lint' -Body $marker -ExtraMetadata "# BEFORE: Write-Host 'Linting for `${DEPLOY_ENV}'`n# ENV: DEPLOY_ENV=staging"
+            New-TestTask -Name 'build' -Depends @('format', 'lint') -Body $marker -ExtraMetadata '# TIMEOUT: 5m'
+        }
+
+        It "Should not run any task or hook" {
+            $result = Invoke-Bolt -Arguments @('build', '-DryRun')
+
+            $result.ExitCode | Should -Be 0
+            Test-Path -Path (Join-Path $script:TempTestRoot 'ran.txt') | Should -BeFalse
+            $result.Output | Should -Not -Match 'Ran build'
+            $result.Output | Should -Not -Match 'Running before hook'
+        }
+
+        It "Should print tasks grouped by what can run in parallel" {
+            $result = Invoke-Bolt -Arguments @('build', '-DryRun')
+
+            $result.Output | Should -Match '(?s)Group 1:.*1\. format.*2\. lint.*Group 2:.*3\. build'
+            $result.Output | Should -Match 'Depends on: format, lint'
+            $result.Output | Should -Match 'Tasks: 3, groups: 2, cache hits: 0'
+        }
+
+        It "Should show commands, environment, and hooks with variables filled in" {
+            $result = Invoke-Bolt -Arguments @('build', '-DryRun')
+
+            $result.Output | Should -Match 'Command: \.build/Invoke-Lint\.ps1'
+            $result.Output | Should -Match 'Env: DEPLOY_ENV=staging'
+            $result.Output | Should -Match "Before: Write-Host 'Linting for staging'"
+            $result.Output | Should -Match 'Timeout: 5m'
+        }
+
+        It "Should write the plan as JSON" {
+            $result = Invoke-Bolt -Arguments @('build', '-DryRun', '-OutputFormat', 'Json')
+            $plan = $result.Output | ConvertFrom-Json
+
+            $result.ExitCode | Should -Be 0
+            ($plan.Tasks.Name -join ',') | Should -Be 'format,lint,build'
+            $plan.Groups.Count | Should -Be 2
+            ($plan.Groups[0] -join ',') | Should -Be 'format,lint'
+            $plan.Tasks[1].Env.DEPLOY_ENV | Should -Be 'staging'
+            $plan.Tasks[1].Before[0].Type | Should -Be 'inline'
+            $plan.Tasks[1].Before[0].Command | Should -Be "Write-Host 'Linting for staging'"
+            $plan.Tasks[2].Group | Should -Be 2
+            $plan.Tasks[2].Cached | Should -BeFalse
+            $plan.Errors.Count | Should -Be 0
+        }
+
+        It "Should only plan the named task with -Only" {
+            $plan = (Invoke-Bolt -Arguments @('build', '-Only', '-DryRun', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            @($plan.Tasks).Count | Should -Be 1
+            @($plan.Tasks[0].Dependencies).Count | Should -Be 0
+            $plan.Tasks[0].Group | Should -Be 1
+        }
+
+        It "Should pass task arguments through to the command" {
+            $result = Invoke-Bolt -Arguments @('format', '-DryRun', 'release')
+
+            $result.Output | Should -Match 'Command: \.build/Invoke-Format\.ps1 release'
+        }
+    }
+
+    Context "Cache Hits" {
+        It "Should mark tasks the cache would skip" {
+            New-Item -ItemType Directory -Path (Join-Path $script:TempTestRoot 'src') -Force | Out-Null
+            Set-Content -Path (Join-Path $script:TempTestRoot 'src/input.txt') -Value 'data'
+            New-TestTask -Name 'compile' -ExtraMetadata '# INPUTS: src/*.txt'
+
+            (Invoke-Bolt -Arguments @('compile')).ExitCode | Should -Be 0
+            $result = Invoke-Bolt -Arguments @('compile', '-DryRun')
+
+            $result.Output | Should -Match 'compile \(CACHED\)'
+            $result.Output | Should -Match 'cache hits: 1'
+        }
+
+        It "Should not report cache hits with -NoCache" {
+            New-Item -ItemType Directory -Path (Join-Path $script:TempTestRoot 'src') -Force | Out-Null
+            Set-Content -Path (Join-Path $script:TempTestRoot 'src/input.txt') -Value 'data'
+            New-TestTask -Name 'compile' -ExtraMetadata '# INPUTS: src/*.txt'
+
+            (Invoke-Bolt -Arguments @('compile')).ExitCode | Should -Be 0
+            $plan = (Invoke-Bolt -Arguments @('compile', '-DryRun', '-NoCache', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            $plan.Tasks[0].Cached | Should -BeFalse
+        }
+    }
+
+    Context "Variable Errors" {
+        It "Should report hooks with undefined variables and exit with 1" {
+            New-TestTask -Name 'deploy' -ExtraMetadata '# BEFORE: Write-Host ${BOLT_DRYRUN_MISSING}'
+
+            $result = Invoke-Bolt -Arguments @('deploy', '-DryRun')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match 'ERROR: .*BOLT_DRYRUN_MISSING'
+        }
+
+        It "Should leave undefined variables in place with -UndefinedVars Passthrough" {
+            New-TestTask -Name 'deploy' -ExtraMetadata '# BEFORE: Write-Host ${BOLT_DRYRUN_MISSING}'
+
+            $result = Invoke-Bolt -Arguments @('deploy', '-DryRun', '-UndefinedVars', 'Passthrough')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match 'Before: Write-Host \$\{BOLT_DRYRUN_MISSING\}'
+        }
+    }
+}
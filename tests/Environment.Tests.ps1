@@ -0,0 +1,198 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Environment'
+
+    # Load Merge-TaskEnvironment from bolt.ps1 without running the script
+    $boltAst = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $mergeFunction = $boltAst.Find({
+        param($node)
+        $node -is [System.Management.Automation.Language.FunctionDefinitionAst] -and $node.Name -eq 'Merge-TaskEnvironment'
+    }, $true)
+    . ([ScriptBlock]::Create($mergeFunction.Extent.Text))
+
+    # Inherited by every bolt.ps1 process started below
+    $env:BOLT_ENV_TEST_PARENT = 'from-parent'
+
+    # Helper function to create a task that prints the test environment variables
+    function New-TestTask {
+        param(
+            [string]$Name,
+            [string[]]$Env = @()
+        )
+
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+
+        $envLines = ($Env | ForEach-Object { "# ENV: $_" }) -join "`n"
+        $taskNameCapitalized = (Get-Culture).TextInfo.ToTitleCase($Name)
+        $content = @"
+# TASK: $Name
+# DESCRIPTION: Test task $Name
+# DEPENDS:
+$envLines
+
+Write-Host "GLOBAL=[`$env:BOLT_ENV_TEST_GLOBAL]"
+Write-Host "TASK=[`$env:BOLT_ENV_TEST_TASK]"
+Write-Host "PARENT=[`$env:BOLT_ENV_TEST_PARENT]"
+exit 0
+"@
+        Set-Content -Path (Join-Path -Path $buildPath -ChildPath "Invoke-$taskNameCapitalized.ps1") -Value $content
+    }
+
+    # Helper function to write the Env section of bolt.config.json
+    function Set-TestConfigEnv {
+        param([hashtable]$Env)
+
+        @{ Env = $Env } | ConvertTo-Json | Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.config.json')
+    }
+}
+
+AfterAll {
+    Remove-Item -Path Env:\BOLT_ENV_TEST_PARENT -ErrorAction SilentlyContinue
+    Remove-TestProject
+}
+
+Describe "Merge-TaskEnvironment" -Tag "Core", "Environment" {
+
+    It "Should let task values override global values" {
+        $result = Merge-TaskEnvironment -Parent @{ A = 'p' } -Global @{ A = 'g'; B = 'g' } -Task @{ B = 't' }
+
+        $result['A'] | Should -Be 'g'
+        $result['B'] | Should -Be 't'
+    }
+
+    It "Should keep parent values unless -Clean is used" {
+        (Merge-TaskEnvironment -Parent @{ A = 'p' } -Global @{} -Task @{})['A'] | Should -Be 'p'
+        (Merge-TaskEnvironment -Parent @{ A = 'p' } -Global @{} -Task @{} -Clean).ContainsKey('A') | Should -BeFalse
+    }
+
+    It "Should expand a self reference from the parent value instead of recursing" {
+        $result = Merge-TaskEnvironment -Parent @{ FOO = 'base' } -Global @{ FOO = '${FOO}:extra' } -Task @{}
+
+        $result['FOO'] | Should -Be 'base:extra'
+    }
+
+    It "Should expand task references from the global layer" {
+        $result = Merge-TaskEnvironment -Parent @{ FOO = 'base' } -Global @{ FOO = '${FOO}:g' } -Task @{ FOO = '${FOO}:t'; BAR = '${FOO}' }
+
+        $result['FOO'] | Should -Be 'base:g:t'
+        $result['BAR'] | Should -Be 'base:g'
+    }
+
+    It "Should keep -Keep names with -Clean unless they are declared" {
+        $result = Merge-TaskEnvironment -Parent @{ PATH = '/usr/bin'; HOME = '/home/me'; DROP = 'dropped' } -Global @{} -Task @{ HOME = '/tmp/home' } -Clean -Keep @('PATH', 'HOME', 'TEMP')
+
+        $result['PATH'] | Should -Be '/usr/bin'
+        $result['HOME'] | Should -Be '/tmp/home'
+        $result.ContainsKey('TEMP') | Should -BeFalse
+        $result.ContainsKey('DROP') | Should -BeFalse
+    }
+
+    It "Should read parent values for references with -Clean" {
+        $result = Merge-TaskEnvironment -Parent @{ KEEP = 'kept'; DROP = 'dropped' } -Global @{ KEEP = '${KEEP}' } -Task @{} -Clean
+
+        $result['KEEP'] | Should -Be 'kept'
+        $result.ContainsKey('DROP') | Should -BeFalse
+    }
+
+    It "Should expand unknown names to an empty string" {
+        (Merge-TaskEnvironment -Parent @{} -Global @{ A = 'x${MISSING}y' } -Task @{})['A'] | Should -Be 'xy'
+    }
+}
+
+Describe "Task Environment Variables" -Tag "Core", "Environment" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.config.json') -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should apply config and task variables on top of the process environment" {
+        Set-TestConfigEnv -Env @{ BOLT_ENV_TEST_GLOBAL = 'global'; BOLT_ENV_TEST_TASK = 'global' }
+        New-TestTask -Name 'show' -Env @('BOLT_ENV_TEST_TASK=task')
+
+        $result = Invoke-Bolt -Arguments @('show')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'GLOBAL=\[global\]'
+        $result.Output | Should -Match 'TASK=\[task\]'
+        $result.Output | Should -Match 'PARENT=\[from-parent\]'
+    }
+
+    It "Should apply the same variables in a child process" {
+        Set-TestConfigEnv -Env @{ BOLT_ENV_TEST_GLOBAL = 'global' }
+        New-TestTask -Name 'show' -Env @('BOLT_ENV_TEST_TASK=task')
+
+        $result = Invoke-Bolt -Arguments @('show', '-Parallel')
+
+        $result.Output | Should -Match 'GLOBAL=\[global\]'
+        $result.Output | Should -Match 'TASK=\[task\]'
+        $result.Output | Should -Match 'PARENT=\[from-parent\]'
+    }
+
+    It "Should remove inherited variables with -CleanEnv" {
+        Set-TestConfigEnv -Env @{ BOLT_ENV_TEST_GLOBAL = 'global' }
+        New-TestTask -Name 'show'
+
+        $result = Invoke-Bolt -Arguments @('show', '-CleanEnv')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'GLOBAL=\[global\]'
+        $result.Output | Should -Match 'PARENT=\[\]'
+    }
+
+    It "Should keep PATH and HOME with -CleanEnv so tools are still found" {
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+        Set-Content -Path (Join-Path -Path $buildPath -ChildPath 'Invoke-Tools.ps1') -Value @'
+# TASK: tools
+# DESCRIPTION: Runs a tool from PATH
+# DEPENDS:
+
+Write-Host "PATH=[$(if ($env:PATH) { 'set' })] HOME=[$(if ($env:HOME -or $env:USERPROFILE) { 'set' })]"
+& pwsh -NoProfile -Command 'exit 0'
+exit $LASTEXITCODE
+'@
+
+        $result = Invoke-Bolt -Arguments @('tools', '-CleanEnv')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'PATH=\[set\] HOME=\[set\]'
+    }
+
+    It "Should pass selected variables through -CleanEnv with a reference" {
+        New-TestTask -Name 'show' -Env @('BOLT_ENV_TEST_PARENT=${BOLT_ENV_TEST_PARENT}')
+
+        $result = Invoke-Bolt -Arguments @('show', '-CleanEnv')
+
+        $result.Output | Should -Match 'PARENT=\[from-parent\]'
+    }
+
+    It "Should expand a self reference to the parent value" {
+        New-TestTask -Name 'show' -Env @('BOLT_ENV_TEST_PARENT=${BOLT_ENV_TEST_PARENT}:extra')
+
+        $result = Invoke-Bolt -Arguments @('show')
+
+        $result.Output | Should -Match 'PARENT=\[from-parent:extra\]'
+    }
+
+    It "Should not leak task variables into the next task" {
+        New-TestTask -Name 'first' -Env @('BOLT_ENV_TEST_TASK=first')
+        New-TestTask -Name 'second'
+
+        $result = Invoke-Bolt -Arguments @('first', 'second')
+
+        $result.Output | Should -Match 'TASK=\[first\]'
+        $result.Output | Should -Match 'TASK=\[\]'
+    }
+
+    It "Should warn about an invalid ENV entry" {
+        New-TestTask -Name 'show' -Env @('not valid')
+
+        $result = Invoke-Bolt -Arguments @('show')
+
+        ($result.Output + $result.Error) | Should -Match "Invalid ENV entry 'not valid'"
+    }
+}
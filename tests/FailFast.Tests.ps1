@@ -0,0 +1,90 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'FailFast'
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Fail-Fast Control" -Tag "Core", "FailFast" {
+
+    BeforeEach {
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        if (Test-Path -Path $buildPath) {
+            Remove-Item -Path $buildPath -Recurse -Force
+        }
+
+        New-TestTask -Name 'broken' -Body 'exit 1'
+        New-TestTask -Name 'after' -Depends @('broken')
+        New-TestTask -Name 'other'
+        New-TestTask -Name 'alsobroken' -Body 'exit 1'
+    }
+
+    It "Should stop at the first failing task by default" {
+        $result = Invoke-Bolt -Arguments @('broken', 'other')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Not -Match 'Ran other'
+    }
+
+    It "Should stop at the first failing task with -FailFast and -ErrorAction Continue" {
+        $result = Invoke-Bolt -Arguments @('broken', 'other', '-FailFast', '-ErrorAction', 'Continue')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Not -Match 'Ran other'
+    }
+
+    It "Should run the remaining tasks and list every failure with -NoFailFast" {
+        $result = Invoke-Bolt -Arguments @('broken', 'after', 'other', 'alsobroken', '-NoFailFast')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match 'Ran other'
+        $result.Output | Should -Not -Match 'Ran after'
+        $result.Output | Should -Match "Task 'after' skipped because broken failed \(SKIPPED\(dependency\)\)"
+        $result.Output | Should -Match 'Failed tasks: broken, alsobroken'
+        $result.Output | Should -Match 'Skipped because a dependency failed: after'
+    }
+
+    It "Should keep starting independent tasks in parallel with -NoFailFast" {
+        $result = Invoke-Bolt -Arguments @('broken', 'after', 'other', '-Parallel', '-Parallelism', '1', '-NoFailFast', '-NoProgress')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match '\[other\]\s+Ran other'
+        $result.Output | Should -Match '\[after\]\s+skipped because broken failed'
+        $result.Output | Should -Not -Match 'Ran after'
+    }
+
+    It "Should report FailedTasks in the JSON summary in both modes" -ForEach @(
+        @{ Mode = '-FailFast'; Expected = @('broken') }
+        @{ Mode = '-NoFailFast'; Expected = @('broken', 'alsobroken') }
+    ) {
+        $result = Invoke-Bolt -Arguments @('broken', 'other', 'alsobroken', $Mode, '-OutputFormat', 'Json')
+        $summary = $result.Output | ConvertFrom-Json
+
+        $result.ExitCode | Should -Be 1
+        $summary.Success | Should -BeFalse
+        @($summary.FailedTasks) | Should -Be $Expected
+    }
+
+    It "Should record tasks skipped for a failed dependency in the JSON summary" {
+        $result = Invoke-Bolt -Arguments @('after', 'other', '-NoFailFast', '-OutputFormat', 'Json')
+        $summary = $result.Output | ConvertFrom-Json
+
+        $after = $summary.Tasks | Where-Object Name -eq 'after'
+        $after.Status | Should -Be 'skipped'
+        $after.SkipReason | Should -Be 'dependency'
+        $after.Attempts | Should -Be 0
+        ($summary.Tasks | Where-Object Name -eq 'other').Status | Should -Be 'success'
+    }
+
+    It "Should reject -FailFast together with -NoFailFast" {
+        $result = Invoke-Bolt -Arguments @('other', '-FailFast', '-NoFailFast')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match '-FailFast and -NoFailFast cannot be used together'
+        $result.Output | Should -Not -Match 'Ran other'
+    }
+}
@@ -0,0 +1,289 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'GoTest'
+
+    # Helper function to create a go-test task in the temp .build directory
+    function New-GoTestTask {
+        param(
+            [string]$Name = 'test',
+            [string]$ExtraMetadata = ''
+        )
+
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+
+        $taskNameCapitalized = (Get-Culture).TextInfo.ToTitleCase($Name)
+        $content = @"
+# TASK: $Name
+# DESCRIPTION: Test task $Name
+# DEPENDS:
+# TYPE: go-test
+$ExtraMetadata
+"@
+        Set-Content -Path (Join-Path -Path $buildPath -ChildPath "Invoke-$taskNameCapitalized.ps1") -Value $content
+    }
+
+    # Helper function to write the events the fake go prints
+    function Set-GoTestEvents {
+        param(
+            [object[]]$Events
+        )
+
+        $lines = foreach ($testEvent in $Events) {
+            if ($testEvent -is [string]) { $testEvent } else { $testEvent | ConvertTo-Json -Compress }
+        }
+        Set-Content -Path $script:GoEventsPath -Value $lines
+    }
+
+    # A fake go that records its arguments and working directory, then prints canned test events
+    $script:FakeGoPath = Join-Path -Path $script:TempTestRoot -ChildPath 'fake-go'
+    $script:GoArgsPath = Join-Path -Path $script:TempTestRoot -ChildPath 'go-args.txt'
+    $script:GoEventsPath = Join-Path -Path $script:TempTestRoot -ChildPath 'go-events.txt'
+    New-Item -ItemType Directory -Path $script:FakeGoPath -Force | Out-Null
+    if (-not $IsWindows) {
+        $fakeGo = Join-Path $script:FakeGoPath 'go'
+        Set-Content -Path $fakeGo -Value @"
+#!/bin/sh
+pwd > '$($script:GoArgsPath)'
+printf '%s\n' "`$@" >> '$($script:GoArgsPath)'
+cat '$($script:GoEventsPath)'
+exit `${BOLT_FAKE_GO_EXIT:-0}
+"@
+        chmod +x $fakeGo
+    }
+
+    # Load go test helper functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('New-GoTestSummary', 'Read-GoTestEvent', 'Complete-GoTestSummary') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Go Test Events" -Tag "Core", "GoTest" {
+
+    BeforeEach {
+        $script:Summary = New-GoTestSummary
+    }
+
+    It "Should count passed, failed, and skipped tests" {
+        '{"Action":"pass","Package":"example/a","Test":"TestOne"}',
+        '{"Action":"pass","Package":"example/a","Test":"TestOne/sub"}',
+        '{"Action":"skip","Package":"example/a","Test":"TestTwo"}',
+        '{"Action":"fail","Package":"example/b","Test":"TestThree"}' | ForEach-Object {
+            Read-GoTestEvent -Summary $script:Summary -Line $_ | Should -BeNullOrEmpty
+        }
+
+        $script:Summary.Passed | Should -Be 2
+        $script:Summary.Skipped | Should -Be 1
+        $script:Summary.Failed | Should -Be 1
+        $script:Summary.FailedTests | Should -Be @('example/b.TestThree')
+    }
+
+    It "Should set the package status, elapsed time, and coverage" {
+        $output = Read-GoTestEvent -Summary $script:Summary -Line '{"Action":"output","Package":"example/a","Output":"coverage: 75.5% of statements\n"}'
+        $null = Read-GoTestEvent -Summary $script:Summary -Line '{"Action":"pass","Package":"example/a","Elapsed":0.42}'
+
+        $output | Should -Be 'coverage: 75.5% of statements'
+        $script:Summary.Packages.Count | Should -Be 1
+        $script:Summary.Packages[0].Name | Should -Be 'example/a'
+        $script:Summary.Packages[0].Status | Should -Be 'pass'
+        $script:Summary.Packages[0].Coverage | Should -Be 75.5
+        $script:Summary.Packages[0].Elapsed | Should -Be 0.42
+        $script:Summary.Passed | Should -Be 0
+    }
+
+    It "Should return lines that are not test events unchanged" {
+        Read-GoTestEvent -Summary $script:Summary -Line '# example/a' | Should -Be '# example/a'
+        Read-GoTestEvent -Summary $script:Summary -Line '{not json' | Should -Be '{not json'
+        $script:Summary.Packages.Count | Should -Be 0
+    }
+
+    It "Should average the package coverage without a coverage profile" {
+        $null = Read-GoTestEvent -Summary $script:Summary -Line '{"Action":"output","Package":"example/a","Output":"coverage: 80.0% of statements\n"}'
+        $null = Read-GoTestEvent -Summary $script:Summary -Line '{"Action":"output","Package":"example/b","Output":"coverage: 50.0% of statements\n"}'
+        $null = Read-GoTestEvent -Summary $script:Summary -Line '{"Action":"pass","Package":"example/c"}'
+
+        Complete-GoTestSummary -Summary $script:Summary
+
+        $script:Summary.Coverage | Should -Be 65
+    }
+
+    It "Should compute coverage from the statements in the coverage profile" {
+        $profilePath = Join-Path -Path $script:TempTestRoot -ChildPath 'unit-coverage.out'
+        Set-Content -Path $profilePath -Value @(
+            'mode: set'
+            'example/a/a.go:3.20,5.2 2 1'
+            'example/a/a.go:7.20,9.2 1 0'
+            'example/a/a.go:7.20,9.2 1 1'
+            'example/a/a.go:11.20,13.2 3 0'
+        )
+
+        Complete-GoTestSummary -Summary $script:Summary -CoverProfile $profilePath
+
+        # 3 of 6 statements ran; the block listed twice is counted once
+        $script:Summary.Coverage | Should -Be 50
+    }
+
+    It "Should leave coverage empty when go test reported none" {
+        Complete-GoTestSummary -Summary $script:Summary
+
+        $script:Summary.Coverage | Should -BeNullOrEmpty
+    }
+}
+
+Describe "Go Test Tasks" -Tag "Core", "GoTest" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        Remove-Item -Path $script:GoArgsPath -Force -ErrorAction SilentlyContinue
+        $script:OriginalPath = $env:PATH
+    }
+
+    AfterEach {
+        $env:PATH = $script:OriginalPath
+        Remove-Item -Path Env:\BOLT_FAKE_GO_EXIT -ErrorAction SilentlyContinue
+    }
+
+    Context "Running go test" -Skip:$IsWindows {
+        BeforeEach {
+            $env:PATH = "$($script:FakeGoPath)$([System.IO.Path]::PathSeparator)$($env:PATH)"
+            Set-GoTestEvents -Events @(
+                @{ Action = 'run'; Package = 'example/a'; Test = 'TestOne' }
+                @{ Action = 'output'; Package = 'example/a'; Test = 'TestOne'; Output = "=== RUN   TestOne`n" }
+                @{ Action = 'pass'; Package = 'example/a'; Test = 'TestOne'; Elapsed = 0.01 }
+                @{ Action = 'output'; Package = 'example/a'; Output = "coverage: 80.0% of statements`n" }
+                @{ Action = 'pass'; Package = 'example/a'; Elapsed = 0.2 }
+            )
+        }
+
+        It "Should run go test -json for every package by default" {
+            New-GoTestTask
+
+            $result = Invoke-Bolt -Arguments @('test')
+            $goArgs = @(Get-Content -Path $script:GoArgsPath)
+
+            $result.ExitCode | Should -Be 0
+            $goArgs[0] | Should -Be ([System.IO.Path]::GetFullPath($script:TempTestRoot).TrimEnd([System.IO.Path]::DirectorySeparatorChar))
+            $goArgs[1..($goArgs.Count - 1)] | Should -Be @('test', '-json', './...')
+            $result.Output | Should -Match '=== RUN   TestOne'
+            $result.Output | Should -Match 'go test: 1 passed, 0 failed, 0 skipped, 80% coverage'
+            $result.Output | Should -Not -Match '"Action"'
+        }
+
+        It "Should pass the flags and packages from the metadata" {
+            New-GoTestTask -ExtraMetadata "# PACKAGES: ./cmd/..., ./internal/...`n# RACE: true`n# COVER: true`n# COVERPROFILE: coverage.out"
+
+            $result = Invoke-Bolt -Arguments @('test', '-run', 'TestOne')
+            $goArgs = @(Get-Content -Path $script:GoArgsPath)
+
+            $result.ExitCode | Should -Be 0
+            $expectedProfile = [System.IO.Path]::GetFullPath((Join-Path $script:TempTestRoot 'coverage.out'))
+            $goArgs[1..($goArgs.Count - 1)] | Should -Be @('test', '-json', '-race', '-cover', "-coverprofile=$expectedProfile", './cmd/...', './internal/...', '-run', 'TestOne')
+        }
+
+        It "Should run go test in the GoPath directory from bolt.config.json" {
+            New-Item -ItemType Directory -Path (Join-Path $script:TempTestRoot 'src') -Force | Out-Null
+            Set-Content -Path (Join-Path $script:TempTestRoot 'bolt.config.json') -Value '{ "GoPath": "src" }'
+            New-GoTestTask
+
+            try {
+                $null = Invoke-Bolt -Arguments @('test')
+                (Get-Content -Path $script:GoArgsPath -TotalCount 1) | Should -Be ([System.IO.Path]::GetFullPath((Join-Path $script:TempTestRoot 'src')))
+            } finally {
+                Remove-Item -Path (Join-Path $script:TempTestRoot 'bolt.config.json') -Force -ErrorAction SilentlyContinue
+            }
+        }
+
+        It "Should add the GoTestSummary to the JSON run summary" {
+            Set-GoTestEvents -Events @(
+                @{ Action = 'pass'; Package = 'example/a'; Test = 'TestOne' }
+                @{ Action = 'skip'; Package = 'example/a'; Test = 'TestTwo' }
+                @{ Action = 'fail'; Package = 'example/b'; Test = 'TestThree' }
+                @{ Action = 'fail'; Package = 'example/b'; Elapsed = 0.3 }
+            )
+            $env:BOLT_FAKE_GO_EXIT = '1'
+            New-GoTestTask
+
+            $result = Invoke-Bolt -Arguments @('test', '-OutputFormat', 'Json')
+            $summary = $result.Output | ConvertFrom-Json
+
+            $result.ExitCode | Should -Be 1
+            $summary.Tasks[0].Status | Should -Be 'failure'
+            $summary.Tasks[0].GoTestSummary.Passed | Should -Be 1
+            $summary.Tasks[0].GoTestSummary.Skipped | Should -Be 1
+            $summary.Tasks[0].GoTestSummary.Failed | Should -Be 1
+            $summary.Tasks[0].GoTestSummary.FailedTests | Should -Be @('example/b.TestThree')
+            ($summary.Tasks[0].GoTestSummary.Packages | Where-Object Name -eq 'example/b').Status | Should -Be 'fail'
+        }
+
+        It "Should show the go test command in a dry run" {
+            New-GoTestTask -ExtraMetadata '# RACE: true'
+
+            $result = Invoke-Bolt -Arguments @('test', '-DryRun')
+
+            $result.Output | Should -Match 'go test -json -race \./\.\.\.'
+            Test-Path -Path $script:GoArgsPath | Should -BeFalse
+        }
+    }
+
+    Context "Go Not Installed" {
+        It "Should fail with a clear message when go is not on PATH" {
+            New-GoTestTask
+            $pwshPath = (Get-Process -Id $PID).Path
+            $env:PATH = Split-Path -Path $pwshPath -Parent
+
+            $result = Invoke-Bolt -Arguments @('test', '-OutputFormat', 'Json')
+            $summary = $result.Output | ConvertFrom-Json
+
+            $result.ExitCode | Should -Be 1
+            $summary.Tasks[0].Status | Should -Be 'failure'
+            $summary.Tasks[0].Stderr | Should -Match 'go was not found on PATH'
+        }
+    }
+
+    Context "Validation" {
+        It "Should accept a go-test task without commands" {
+            New-GoTestTask -ExtraMetadata '# COVER: true'
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 0
+        }
+
+        It "Should report unknown task types" {
+            New-GoTestTask
+            Set-Content -Path (Join-Path $script:TempTestRoot '.build/Invoke-Other.ps1') -Value "# TASK: other`n# TYPE: cargo-test`nexit 0"
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match "Unknown task type 'cargo-test'"
+        }
+
+        It "Should report RACE and COVER values other than true or false" {
+            New-GoTestTask -ExtraMetadata '# RACE: yes'
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match 'RACE'
+        }
+
+        It "Should report go test settings without TYPE" {
+            Set-Content -Path (Join-Path (New-Item -ItemType Directory -Path (Join-Path $script:TempTestRoot '.build') -Force) 'Invoke-Test.ps1') -Value "# TASK: test`n# PACKAGES: ./...`nexit 0"
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match 'no effect without TYPE: go-test'
+        }
+    }
+}
@@ -0,0 +1,76 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Graph'
+
+    New-TestTask -Name 'format'
+    New-TestTask -Name 'lint' -Depends @('format')
+    New-TestTask -Name 'build' -Depends @('lint')
+    New-TestTask -Name 'docs'
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Dependency Graph Output" -Tag "Core", "Graph" {
+
+    Context "Graphviz DOT" {
+        It "Should write a digraph with labelled nodes" {
+            $result = Invoke-Bolt -Arguments @('-Graph')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match 'digraph bolt \{'
+            $result.Output | Should -Match ([regex]::Escape('"build" [label="build\nTest task build"];'))
+        }
+
+        It "Should point edges from the dependency to the dependent" {
+            $output = (Invoke-Bolt -Arguments @('-Graph')).Output
+
+            $output | Should -Match ([regex]::Escape('"format" -> "lint";'))
+            $output | Should -Match ([regex]::Escape('"lint" -> "build";'))
+            $output | Should -Not -Match ([regex]::Escape('"build" -> "lint";'))
+        }
+
+        It "Should include disconnected tasks as isolated nodes" {
+            $output = (Invoke-Bolt -Arguments @('-Graph')).Output
+
+            $output | Should -Match ([regex]::Escape('"docs" [label='))
+            $output | Should -Not -Match '"docs" ->'
+            $output | Should -Match ([regex]::Escape('"check-index" [label='))
+        }
+    }
+
+    Context "Mermaid" {
+        It "Should write a graph TD block" {
+            $result = Invoke-Bolt -Arguments @('-Graph', '-Format', 'Mermaid')
+            $lines = $result.Output -split '\r?\n'
+
+            $result.ExitCode | Should -Be 0
+            $lines[0] | Should -Be 'graph TD'
+            $buildId = ($lines | Where-Object { $_ -match '^\s+(n\d+)\["build<br/>Test task build"\]$' } | ForEach-Object { $Matches[1] })
+            $lintId = ($lines | Where-Object { $_ -match '^\s+(n\d+)\["lint<br/>Test task lint"\]$' } | ForEach-Object { $Matches[1] })
+            $buildId | Should -Not -BeNullOrEmpty
+            $lines | Should -Contain "    $lintId --> $buildId"
+        }
+    }
+
+    Context "Sub-graph for a Task" {
+        It "Should only include the task and its dependencies" {
+            $output = (Invoke-Bolt -Arguments @('-Graph', 'lint')).Output
+
+            $output | Should -Match '"lint" \[label='
+            $output | Should -Match '"format" \[label='
+            $output | Should -Not -Match '"build"'
+            $output | Should -Not -Match '"docs"'
+        }
+
+        It "Should fail for an unknown task" {
+            $result = Invoke-Bolt -Arguments @('-Graph', 'missing')
+
+            $result.ExitCode | Should -Be 1
+            $result.Error | Should -Match 'Task\(s\) not found: missing'
+        }
+    }
+}
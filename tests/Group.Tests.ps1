@@ -0,0 +1,148 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Group'
+
+    # Load the group status functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('Get-TaskGroupStatus', 'Resolve-TaskDependency') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Group Status" -Tag "Core", "Group" {
+
+    BeforeAll {
+        $script:AllTasks = @{
+            lint  = @{ Names = @('lint'); Dependencies = @(); Namespace = $null }
+            test  = @{ Names = @('test'); Dependencies = @(); Namespace = $null }
+            ci    = @{ Names = @('ci'); Dependencies = @('lint', 'test'); Namespace = $null; Type = 'group' }
+            outer = @{ Names = @('outer'); Dependencies = @('ci'); Namespace = $null; Type = 'group' }
+        }
+    }
+
+    It "Should be <Expected> when lint is <Lint> and test is <Test>" -ForEach @(
+        @{ Lint = 'success'; Test = 'success'; Expected = 'success' }
+        @{ Lint = 'success'; Test = 'failure'; Expected = 'failure' }
+        @{ Lint = 'timeout'; Test = 'success'; Expected = 'failure' }
+        @{ Lint = 'skipped'; Test = 'success'; Expected = 'success' }
+        @{ Lint = 'success'; Test = $null; Expected = 'skipped' }
+        @{ Lint = 'failure'; Test = $null; Expected = 'failure' }
+    ) {
+        $results = @(
+            if ($Lint) { [ordered]@{ Name = 'lint'; Status = $Lint; DurationMs = 100; Attempts = $(if ($Lint -eq 'skipped') { 0 } else { 1 }) } }
+            if ($Test) { [ordered]@{ Name = 'test'; Status = $Test; DurationMs = 250; Attempts = 1 } }
+        )
+
+        $group = Get-TaskGroupStatus -TaskInfo $script:AllTasks['ci'] -AllTasks $script:AllTasks -Results $results
+
+        $group.Status | Should -Be $Expected
+        $group.Members | Should -Be @('lint', 'test')
+    }
+
+    It "Should add up member durations and name the failed members" {
+        $results = @(
+            [ordered]@{ Name = 'lint'; Status = 'failure'; DurationMs = 100; Attempts = 1 }
+            [ordered]@{ Name = 'test'; Status = 'success'; DurationMs = 250; Attempts = 1 }
+        )
+
+        $group = Get-TaskGroupStatus -TaskInfo $script:AllTasks['ci'] -AllTasks $script:AllTasks -Results $results
+
+        $group.DurationMs | Should -Be 350
+        $group.FailedMembers | Should -Be @('lint')
+    }
+
+    It "Should treat cancelled members and skipped groups as not finished" {
+        $cancelled = @(
+            [ordered]@{ Name = 'lint'; Status = 'success'; DurationMs = 100; Attempts = 1 }
+            [ordered]@{ Name = 'test'; Status = 'skipped'; DurationMs = 40; Attempts = 1 }
+        )
+        (Get-TaskGroupStatus -TaskInfo $script:AllTasks['ci'] -AllTasks $script:AllTasks -Results $cancelled).PendingMembers | Should -Be @('test')
+
+        $skippedGroup = @([ordered]@{ Name = 'ci'; Status = 'skipped'; DurationMs = 0; Attempts = 0; Members = @('lint', 'test') })
+        (Get-TaskGroupStatus -TaskInfo $script:AllTasks['outer'] -AllTasks $script:AllTasks -Results $skippedGroup).Status | Should -Be 'skipped'
+    }
+}
+
+Describe "Task Group Runs" -Tag "Core", "Group" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        New-TestTask -Name 'lint'
+        New-TestTask -Name 'test' -Depends @('lint')
+        New-TestTask -Name 'build'
+    }
+
+    It "Should run the members in dependency order without a command of its own" {
+        New-TestTask -Name 'ci' -Depends @('test', 'lint', 'build') -ExtraMetadata '# TYPE: group'
+
+        $result = Invoke-Bolt -Arguments @('ci')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '(?s)Ran lint.*Ran test.*Ran build'
+        $result.Output | Should -Not -Match 'Ran ci'
+        $result.Output | Should -Match "Group 'ci' succeeded \(test, lint, build\)"
+    }
+
+    It "Should record a failed group with its members in JSON results" {
+        New-TestTask -Name 'test' -Depends @('lint') -Body 'exit 2'
+        New-TestTask -Name 'ci' -Depends @('lint', 'test', 'build') -ExtraMetadata '# TYPE: group'
+
+        $result = Invoke-Bolt -Arguments @('ci', '-OutputFormat', 'Json')
+
+        $result.ExitCode | Should -Be 1
+        $summary = $result.Output | ConvertFrom-Json
+        $group = $summary.Tasks | Where-Object Name -eq 'ci'
+        $group.Status | Should -Be 'failure'
+        $group.Members | Should -Be @('lint', 'test', 'build')
+        $group.Stderr | Should -Be 'Failed members: test'
+        $summary.Success | Should -BeFalse
+    }
+
+    It "Should fail the group after its members ran with -ErrorAction Continue" {
+        New-TestTask -Name 'lint' -Body 'exit 1'
+        New-TestTask -Name 'ci' -Depends @('lint', 'build') -ExtraMetadata '# TYPE: group'
+
+        $result = Invoke-Bolt -Arguments @('ci', '-ErrorAction', 'Continue')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match 'Ran build'
+        $result.Output | Should -Match "Group 'ci' failed \(failed members: lint\)"
+    }
+
+    It "Should complete groups in -Parallel runs" {
+        New-TestTask -Name 'ci' -Depends @('test', 'build') -ExtraMetadata '# TYPE: group'
+
+        $result = Invoke-Bolt -Arguments @('ci', '-Parallel')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '\[ci\]\s+group succeeded \(test, build\)'
+    }
+
+    It "Should list the members under their group" {
+        New-TestTask -Name 'ci' -Depends @('lint', 'build') -ExtraMetadata '# TYPE: group'
+
+        $result = Invoke-Bolt -Arguments @('-ListTasks')
+
+        $result.Output | Should -Match '(?m)^    Group of:\r?\n      lint   Test task lint\r?\n      build  Test task build'
+    }
+
+    It "Should report groups with a script body or without members in -ValidateTasks" {
+        New-TestTask -Name 'ci' -Depends @('lint') -ExtraMetadata '# TYPE: group'
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        Set-Content -Path (Join-Path $buildPath 'Invoke-Empty.ps1') -Value "# TASK: empty`n# DESCRIPTION: No members`n# DEPENDS:`n# TYPE: group"
+
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match 'Group tasks do not run their script'
+        $result.Output | Should -Match 'Group tasks need their members in DEPENDS'
+    }
+}
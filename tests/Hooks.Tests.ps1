@@ -0,0 +1,118 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Hook'
+
+    # Helper function to create a task file with optional hook lines
+    function New-TestTask {
+        param(
+            [string]$Name,
+            [string[]]$Before = @(),
+            [string[]]$After = @(),
+            [int]$ExitCode = 0
+        )
+
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+
+        $hookLines = @($Before | ForEach-Object { "# BEFORE: $_" }) + @($After | ForEach-Object { "# AFTER: $_" })
+        $taskNameCapitalized = (Get-Culture).TextInfo.ToTitleCase($Name)
+        $content = @"
+# TASK: $Name
+# DESCRIPTION: Test task $Name
+# DEPENDS:
+$($hookLines -join "`n")
+
+Write-Host "Main $Name"
+exit $ExitCode
+"@
+        Set-Content -Path (Join-Path -Path $buildPath -ChildPath "Invoke-$taskNameCapitalized.ps1") -Value $content
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Hooks" -Tag "Core", "Hooks" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+    }
+
+    Context "Successful Hooks" {
+        It "Should run before hooks, the task, then after hooks in order" {
+            New-TestTask -Name 'deploy' -Before @("Write-Host 'Before one'", "Write-Host 'Before two'") -After @("Write-Host 'After one'")
+
+            $result = Invoke-Bolt -Arguments @('deploy')
+
+            $result.ExitCode | Should -Be 0
+            $output = $result.Output
+            $output.IndexOf('Before one') | Should -BeLessThan $output.IndexOf('Before two')
+            $output.IndexOf('Before two') | Should -BeLessThan $output.IndexOf('Main deploy')
+            $output.IndexOf('Main deploy') | Should -BeLessThan $output.IndexOf('After one')
+        }
+
+        It "Should run a hook that names another task" {
+            New-TestTask -Name 'clean'
+            New-TestTask -Name 'deploy' -Before @('clean')
+
+            $result = Invoke-Bolt -Arguments @('deploy')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output.IndexOf('Main clean') | Should -BeLessThan $result.Output.IndexOf('Main deploy')
+        }
+
+        It "Should run inline hooks from the task directory" {
+            New-TestTask -Name 'deploy' -Before @("Write-Host ""Hook dir: `$((Get-Location).Path)""")
+
+            $result = Invoke-Bolt -Arguments @('deploy')
+
+            $result.Output | Should -Match ([regex]::Escape("Hook dir: $(Join-Path $script:TempTestRoot '.build')"))
+        }
+    }
+
+    Context "Failing Hooks" {
+        It "Should skip the task when a before hook fails and still run after hooks" {
+            New-TestTask -Name 'deploy' -Before @("throw 'not ready'", "Write-Host 'Second before'") -After @("Write-Host 'Cleanup ran'")
+
+            $result = Invoke-Bolt -Arguments @('deploy')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Not -Match 'Main deploy'
+            $result.Output | Should -Not -Match 'Second before'
+            $result.Output | Should -Match 'Cleanup ran'
+        }
+
+        It "Should run after hooks when the task fails" {
+            New-TestTask -Name 'deploy' -After @("Write-Host 'Cleanup ran'") -ExitCode 1
+
+            $result = Invoke-Bolt -Arguments @('deploy')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match 'Cleanup ran'
+        }
+
+        It "Should fail the task when an after hook fails" {
+            New-TestTask -Name 'deploy' -After @('pwsh -NoProfile -Command "exit 4"')
+
+            $result = Invoke-Bolt -Arguments @('deploy')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match 'Main deploy'
+            $result.Output | Should -Match 'exited with code 4'
+        }
+
+        It "Should report hook errors separately in the JSON summary" {
+            New-TestTask -Name 'deploy' -Before @("throw 'not ready'") -After @("throw 'cleanup broke'")
+
+            $summary = (Invoke-Bolt -Arguments @('deploy', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            $summary.Tasks[0].Status | Should -Be 'failure'
+            $summary.Tasks[0].HookErrors.Count | Should -Be 2
+            $summary.Tasks[0].HookErrors[0] | Should -Match "before hook .* failed: not ready"
+            $summary.Tasks[0].HookErrors[1] | Should -Match "after hook .* failed: cleanup broke"
+        }
+    }
+}
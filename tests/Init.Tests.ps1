@@ -0,0 +1,121 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Init'
+
+    $script:BuildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Project Scaffolding" -Tag "Core", "Init" {
+
+    BeforeEach {
+        foreach ($path in @('.build', '.bolt', 'go.mod', 'package.json', 'pnpm-lock.yaml')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+    }
+
+    Context "Go Projects" {
+        BeforeEach {
+            Set-Content -Path (Join-Path $script:TempTestRoot 'go.mod') -Value "module example.com/widgets`n`ngo 1.22.3`n"
+        }
+
+        It "Should create the Go starter tasks" {
+            $result = Invoke-Bolt -Arguments @('-Init', '-Type', 'Go')
+
+            $result.ExitCode | Should -Be 0
+            foreach ($fileName in @('Invoke-Tidy.ps1', 'Invoke-Lint.ps1', 'Invoke-Test.ps1', 'Invoke-Build.ps1', 'Invoke-Release.ps1')) {
+                Join-Path $script:BuildPath $fileName | Should -Exist
+            }
+            $result.Output | Should -Match 'Go module: example.com/widgets'
+            $result.Output | Should -Match 'Go version: 1.22.3'
+        }
+
+        It "Should use the module name, Go version, and linter fallback in the tasks" {
+            Invoke-Bolt -Arguments @('-Init', '-Type', 'Go') | Out-Null
+
+            $build = Get-Content -Path (Join-Path $script:BuildPath 'Invoke-Build.ps1') -Raw
+            $build | Should -Match 'Builds example.com/widgets \(Go 1.22.3\)'
+            $build | Should -Match '# DEPENDS: lint, test'
+            $lint = Get-Content -Path (Join-Path $script:BuildPath 'Invoke-Lint.ps1') -Raw
+            $lint | Should -Match 'golangci-lint run'
+            $lint | Should -Match 'go vet'
+            Get-Content -Path (Join-Path $script:BuildPath 'Invoke-Test.ps1') -Raw | Should -Match '# TYPE: go-test'
+            Get-Content -Path (Join-Path $script:BuildPath 'Invoke-Release.ps1') -Raw | Should -Match 'GOOS'
+        }
+
+        It "Should generate tasks that pass validation" {
+            Invoke-Bolt -Arguments @('-Init', '-Type', 'Go') | Out-Null
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 0
+            $listed = Invoke-Bolt -Arguments @('-ListTasks', '-OutputFormat', 'Json')
+            ($listed.Output | ConvertFrom-Json).Name | Should -Contain 'release'
+        }
+
+        It "Should fail when go.mod is missing" {
+            Remove-Item -Path (Join-Path $script:TempTestRoot 'go.mod') -Force
+
+            $result = Invoke-Bolt -Arguments @('-Init', '-Type', 'Go')
+
+            $result.ExitCode | Should -Be 1
+            $result.Error | Should -Match 'No go.mod found'
+            $script:BuildPath | Should -Not -Exist
+        }
+    }
+
+    Context "Existing Files" {
+        BeforeEach {
+            New-Item -ItemType Directory -Path $script:BuildPath -Force | Out-Null
+            Set-Content -Path (Join-Path $script:BuildPath 'Invoke-Build.ps1') -Value '# my build'
+        }
+
+        It "Should refuse to overwrite existing task files" {
+            $result = Invoke-Bolt -Arguments @('-Init')
+
+            $result.ExitCode | Should -Be 1
+            $result.Error | Should -Match 'Invoke-Build.ps1'
+            $result.Error | Should -Match '-Force'
+            (Get-Content -Path (Join-Path $script:BuildPath 'Invoke-Build.ps1') -Raw).Trim() | Should -Be '# my build'
+            Join-Path $script:BuildPath 'Invoke-Test.ps1' | Should -Not -Exist
+        }
+
+        It "Should overwrite existing task files with -Force" {
+            $result = Invoke-Bolt -Arguments @('-Init', '-Force')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match 'overwritten'
+            Get-Content -Path (Join-Path $script:BuildPath 'Invoke-Build.ps1') -Raw | Should -Match '# TASK: build'
+        }
+    }
+
+    Context "Node and Generic Projects" {
+        It "Should create tasks for the scripts in package.json" {
+            Set-Content -Path (Join-Path $script:TempTestRoot 'package.json') -Value '{ "name": "web", "scripts": { "test": "vitest", "build": "vite build" } }'
+            Set-Content -Path (Join-Path $script:TempTestRoot 'pnpm-lock.yaml') -Value ''
+
+            $result = Invoke-Bolt -Arguments @('-Init', '-Type', 'Node')
+
+            $result.ExitCode | Should -Be 0
+            Join-Path $script:BuildPath 'Invoke-Install.ps1' | Should -Exist
+            Join-Path $script:BuildPath 'Invoke-Lint.ps1' | Should -Not -Exist
+            $build = Get-Content -Path (Join-Path $script:BuildPath 'Invoke-Build.ps1') -Raw
+            $build | Should -Match 'pnpm run build'
+            $build | Should -Match '# DEPENDS: install, test'
+        }
+
+        It "Should create build and test placeholders for generic projects" {
+            $result = Invoke-Bolt -Arguments @('-Init')
+
+            $result.ExitCode | Should -Be 0
+            Join-Path $script:BuildPath 'Invoke-Build.ps1' | Should -Exist
+            Join-Path $script:BuildPath 'Invoke-Test.ps1' | Should -Exist
+            (Invoke-Bolt -Arguments @('build')).ExitCode | Should -Be 0
+        }
+    }
+}
@@ -0,0 +1,214 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Interactive'
+
+    # Load the task menu for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('New-TaskMenu', 'Test-InteractiveTerminal') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+
+    $script:Escape = [char]27
+
+    function New-Key {
+        param(
+            [ConsoleKey]$Key,
+            [char]$Char = [char]0,
+            [switch]$Control
+        )
+
+        return [ConsoleKeyInfo]::new($Char, $Key, $false, $false, $Control.IsPresent)
+    }
+
+    function Get-MenuLines {
+        # Strip the cursor and screen control codes so only the drawn text is left
+        return @(($script:Writer.ToString() -replace "$($script:Escape)\[[0-9;?]*[A-Za-z]", '') -split '\r?\n' | Where-Object { $_ })
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Menu" -Tag "Core", "Interactive" {
+
+    BeforeEach {
+        $script:Writer = [System.IO.StringWriter]::new()
+        $script:Columns = 60
+        $script:Rows = 24
+        $script:Items = @(
+            [PSCustomObject]@{ Name = 'build'; Description = 'Compile the project' }
+            [PSCustomObject]@{ Name = 'lint'; Description = 'Run the linters' }
+            [PSCustomObject]@{ Name = 'test'; Description = 'Run the tests' }
+        )
+        $script:Menu = New-TaskMenu -Items $script:Items -Writer $script:Writer -Width { $script:Columns } -Height { $script:Rows }
+    }
+
+    It "Should list every task with its description" {
+        $script:Menu.Render()
+
+        $lines = Get-MenuLines
+        $lines.Count | Should -Be 4
+        $lines[0] | Should -Match '^Select tasks:'
+        $lines[1] | Should -Be '> [ ] build  Compile the project'
+        $lines[2] | Should -Be '  [ ] lint   Run the linters'
+        $lines[3] | Should -Be '  [ ] test   Run the tests'
+    }
+
+    It "Should move the cursor and toggle the selection" {
+        $script:Menu.HandleKey((New-Key -Key DownArrow)) | Should -Be 'continue'
+        $script:Menu.HandleKey((New-Key -Key Spacebar -Char ' ')) | Should -Be 'continue'
+        $script:Menu.HandleKey((New-Key -Key J -Char 'j')) | Should -Be 'continue'
+        $script:Menu.HandleKey((New-Key -Key Spacebar -Char ' ')) | Should -Be 'continue'
+        $script:Menu.Render()
+
+        $lines = Get-MenuLines
+        $lines[2] | Should -Match '^  \[x\] lint'
+        $lines[3] | Should -Match '^> \[x\] test'
+        $script:Menu.Selected() | Should -Be @('lint', 'test')
+    }
+
+    It "Should unselect a task when Space is pressed again" {
+        $script:Menu.HandleKey((New-Key -Key Spacebar -Char ' ')) | Out-Null
+        $script:Menu.HandleKey((New-Key -Key Spacebar -Char ' ')) | Out-Null
+
+        $script:Menu.Selected() | Should -BeNullOrEmpty
+    }
+
+    It "Should keep the cursor on the list" {
+        $script:Menu.HandleKey((New-Key -Key UpArrow)) | Out-Null
+        $script:Menu.Cursor | Should -Be 0
+
+        1..5 | ForEach-Object { $script:Menu.HandleKey((New-Key -Key DownArrow)) | Out-Null }
+        $script:Menu.Cursor | Should -Be 2
+    }
+
+    It "Should return selected tasks in menu order on Enter" {
+        $script:Menu.HandleKey((New-Key -Key DownArrow)) | Out-Null
+        $script:Menu.HandleKey((New-Key -Key DownArrow)) | Out-Null
+        $script:Menu.HandleKey((New-Key -Key Spacebar -Char ' ')) | Out-Null
+        $script:Menu.HandleKey((New-Key -Key K -Char 'k')) | Out-Null
+        $script:Menu.HandleKey((New-Key -Key K -Char 'k')) | Out-Null
+        $script:Menu.HandleKey((New-Key -Key Spacebar -Char ' ')) | Out-Null
+
+        $script:Menu.HandleKey((New-Key -Key Enter -Char "`r")) | Should -Be 'run'
+        $script:Menu.Selected() | Should -Be @('build', 'test')
+    }
+
+    It "Should run the task under the cursor when nothing is selected" {
+        $script:Menu.HandleKey((New-Key -Key DownArrow)) | Out-Null
+
+        $script:Menu.HandleKey((New-Key -Key Enter -Char "`r")) | Should -Be 'run'
+        $script:Menu.Selected() | Should -Be @('lint')
+    }
+
+    It "Should cancel on Escape, q, and Ctrl+C" {
+        $script:Menu.HandleKey((New-Key -Key Escape)) | Should -Be 'cancel'
+        $script:Menu.HandleKey((New-Key -Key Q -Char 'q')) | Should -Be 'cancel'
+        $script:Menu.HandleKey((New-Key -Key C -Char ([char]3) -Control)) | Should -Be 'cancel'
+    }
+
+    It "Should show only the names when the terminal is too narrow for descriptions" {
+        $script:Columns = 20
+        $script:Menu.Render()
+
+        $lines = Get-MenuLines
+        $lines[1] | Should -Be '> [ ] build'
+        $lines[2] | Should -Be '  [ ] lint'
+        $lines | ForEach-Object { $_.Length | Should -BeLessThan 20 }
+    }
+
+    It "Should cut long descriptions to the terminal width" {
+        $script:Items[0].Description = 'x' * 100
+        $script:Menu.Render()
+
+        $line = (Get-MenuLines)[1]
+        $line.Length | Should -Be 59
+        $line | Should -Match '\.\.\.$'
+    }
+
+    It "Should scroll with the cursor when there are more tasks than rows" {
+        $items = 1..10 | ForEach-Object { [PSCustomObject]@{ Name = "task$_"; Description = '' } }
+        $script:Rows = 5
+        $menu = New-TaskMenu -Items $items -Writer $script:Writer -Width { $script:Columns } -Height { $script:Rows }
+
+        1..6 | ForEach-Object { $menu.HandleKey((New-Key -Key DownArrow)) | Out-Null }
+        $menu.Render()
+
+        $lines = Get-MenuLines
+        $lines.Count | Should -Be 5
+        $lines[1] | Should -Be '  [ ] task5'
+        $lines[3] | Should -Be '> [ ] task7'
+        $lines[4] | Should -Be '(7 of 10)'
+    }
+
+    It "Should erase the drawn lines and show the cursor on Clear" {
+        $script:Menu.Render()
+        $script:Writer.GetStringBuilder().Clear() | Out-Null
+
+        $script:Menu.Clear()
+
+        $script:Writer.ToString() | Should -Be "$($script:Escape)[4A`r$($script:Escape)[J$($script:Escape)[?25h"
+    }
+
+    It "Should replace the previous menu on Render" {
+        $script:Menu.Render()
+        $script:Writer.GetStringBuilder().Clear() | Out-Null
+
+        $script:Menu.Render()
+
+        $script:Writer.ToString() | Should -Match "^$([regex]::Escape("$($script:Escape)[4A"))"
+        $script:Writer.ToString() | Should -Match ([regex]::Escape("$($script:Escape)[?25l"))
+    }
+}
+
+Describe "Interactive Terminal Detection" -Tag "Core", "Interactive" {
+
+    BeforeEach {
+        $script:SavedNoInteractive = $env:BOLT_NO_INTERACTIVE
+    }
+
+    AfterEach {
+        $env:BOLT_NO_INTERACTIVE = $script:SavedNoInteractive
+    }
+
+    It "Should not treat the terminal as interactive when BOLT_NO_INTERACTIVE is set" {
+        $env:BOLT_NO_INTERACTIVE = '1'
+
+        Test-InteractiveTerminal | Should -BeFalse
+    }
+}
+
+Describe "Interactive Runs" -Tag "Core", "Interactive" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        New-TestTask -Name 'build'
+    }
+
+    It "Should fail -Interactive when output is redirected" {
+        $result = Invoke-Bolt -Arguments @('-Interactive')
+
+        $result.ExitCode | Should -Be 1
+        "$($result.Output)$($result.Error)" | Should -Match '-Interactive needs a terminal'
+        $result.Output | Should -Not -Match 'Ran build'
+    }
+
+    It "Should ignore -Interactive when tasks are named" {
+        $result = Invoke-Bolt -Arguments @('build', '-Interactive')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran build'
+    }
+
+    It "Should still show usage for a bare run when output is redirected" {
+        $result = Invoke-Bolt -Arguments @()
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Bolt! Build orchestration'
+    }
+}
@@ -0,0 +1,109 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'ListTasks'
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Listing" -Tag "Core", "ListTasks" {
+
+    BeforeAll {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        New-TestTask -Name 'test-unit' -ExtraMetadata '# TIMEOUT: 5m'
+        New-TestTask -Name 'test-integration' -Depends @('test-unit')
+        New-TestTask -Name 'publish' -Depends @('test-unit', 'test-integration')
+    }
+
+    Context "Text Output" {
+        It "Should show the timeout of a task" {
+            $result = Invoke-Bolt -Arguments @('-ListTasks')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match '(?s)test-unit.*Timeout: 5m'
+        }
+
+        It "Should only list tasks matching -Filter" {
+            $result = Invoke-Bolt -Arguments @('-ListTasks', '-Filter', 'test-*')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match 'test-unit'
+            $result.Output | Should -Match 'test-integration'
+            $result.Output | Should -Not -Match 'publish'
+            $result.Output | Should -Not -Match 'check-index'
+        }
+
+        It "Should say so when no task matches -Filter" {
+            $result = Invoke-Bolt -Arguments @('-ListTasks', '-Filter', 'nothing*')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match "No tasks match 'nothing\*'"
+        }
+
+        It "Should print one aligned line per task with -NoHeader" {
+            $result = Invoke-Bolt -Arguments @('-ListTasks', '-Filter', 'test-*', '-NoHeader')
+
+            $result.ExitCode | Should -Be 0
+            $lines = @($result.Output -split "`r?`n" | Where-Object { $_ })
+            $lines.Count | Should -Be 2
+            $result.Output | Should -Not -Match 'Available tasks'
+            $lines[0] | Should -Be 'test-integration  Test task test-integration'
+            $lines[1] | Should -Be 'test-unit         Test task test-unit'
+        }
+    }
+
+    Context "JSON Output" {
+        It "Should write a JSON array with task metadata" {
+            $result = Invoke-Bolt -Arguments @('-ListTasks', '-OutputFormat', 'Json')
+
+            $result.ExitCode | Should -Be 0
+            $tasks = $result.Output | ConvertFrom-Json
+            $publish = $tasks | Where-Object { $_.Name -eq 'publish' }
+            $publish.Description | Should -Be 'Test task publish'
+            ($publish.Dependencies -join ',') | Should -Be 'test-unit,test-integration'
+            $publish.Source | Should -Be 'project'
+            ($tasks | Where-Object { $_.Name -eq 'test-unit' }).Timeout | Should -Be '5m'
+            ($tasks | Where-Object { $_.Name -eq 'check-index' }).Source | Should -Be 'core'
+        }
+
+        It "Should not print the human-readable list in JSON mode" {
+            $result = Invoke-Bolt -Arguments @('-ListTasks', '-OutputFormat', 'Json')
+
+            $result.Output | Should -Not -Match 'Available tasks'
+        }
+
+        It "Should apply -Filter to the JSON array" {
+            $tasks = (Invoke-Bolt -Arguments @('-ListTasks', '-Filter', 'pub*', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            @($tasks).Count | Should -Be 1
+            $tasks[0].Name | Should -Be 'publish'
+        }
+
+        It "Should write an empty array when nothing matches" {
+            $result = Invoke-Bolt -Arguments @('-ListTasks', '-Filter', 'nothing*', '-OutputFormat', 'Json')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output.Trim() | Should -Be '[]'
+        }
+    }
+
+    Context "Custom Task Directory" {
+        It "Should list tasks from -TaskDirectory" {
+            $customPath = Join-Path -Path $script:TempTestRoot -ChildPath 'custom-tasks'
+            New-Item -ItemType Directory -Path $customPath -Force | Out-Null
+            Set-Content -Path (Join-Path -Path $customPath -ChildPath 'Invoke-Deploy.ps1') -Value @'
+# TASK: deploy
+# DESCRIPTION: Custom deploy
+exit 0
+'@
+
+            $tasks = (Invoke-Bolt -Arguments @('-ListTasks', '-TaskDirectory', 'custom-tasks', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            $tasks.Name | Should -Contain 'deploy'
+            $tasks.Name | Should -Not -Contain 'publish'
+        }
+    }
+}
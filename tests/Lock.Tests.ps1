@@ -0,0 +1,144 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Lock'
+    $script:LockPath = Join-Path -Path $script:TempTestRoot -ChildPath '.bolt/bolt.lock'
+
+    # Helper function to start bolt.ps1 without waiting for it
+    function Start-Bolt {
+        param(
+            [string[]]$Arguments
+        )
+
+        $boltScriptPath = Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.ps1'
+
+        $params = @{
+            FilePath = 'pwsh'
+            ArgumentList = @('-NoProfile', '-File', $boltScriptPath) + $Arguments
+            WorkingDirectory = $script:TempTestRoot
+            NoNewWindow = $true
+            PassThru = $true
+            RedirectStandardOutput = (Join-Path -Path $script:TempTestRoot -ChildPath 'first-stdout.txt')
+            RedirectStandardError = (Join-Path -Path $script:TempTestRoot -ChildPath 'first-stderr.txt')
+        }
+
+        $process = Start-Process @params
+        # Keep the handle open so ExitCode is available after the process exits
+        $null = $process.Handle
+        return $process
+    }
+
+    New-TestTask -Name 'quick'
+
+    # Load the lock function for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('Enter-BoltLock') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Run Lock" -Tag "Core", "Lock" {
+
+    It "Should write the process id to the lock file" {
+        $lock = Enter-BoltLock -Path $script:LockPath
+        try {
+            $lock.Stream.Position = 0
+            $reader = [System.IO.StreamReader]::new($lock.Stream, [System.Text.Encoding]::ASCII, $false, 1024, $true)
+            $reader.ReadToEnd().Trim() | Should -Be "$PID"
+            $reader.Dispose()
+        } finally {
+            $lock.Release()
+        }
+    }
+
+    It "Should fail with LockTimeout while the lock is held" {
+        $lock = Enter-BoltLock -Path $script:LockPath
+        try {
+            $thrown = $null
+            try {
+                Enter-BoltLock -Path $script:LockPath -Timeout ([TimeSpan]::Zero) 6>$null
+            } catch {
+                $thrown = $_
+            }
+
+            $thrown.FullyQualifiedErrorId | Should -Be 'LockTimeout'
+        } finally {
+            $lock.Release()
+        }
+
+        $again = Enter-BoltLock -Path $script:LockPath
+        $again.Release()
+    }
+
+    It "Should allow Release to be called twice" {
+        $lock = Enter-BoltLock -Path $script:LockPath
+        $lock.Release()
+
+        { $lock.Release() } | Should -Not -Throw
+    }
+}
+
+Describe "Concurrent Runs" -Tag "Core", "Lock" {
+
+    It "Should fail after -LockTimeout when another run holds the lock" {
+        $lock = Enter-BoltLock -Path $script:LockPath
+        try {
+            $result = Invoke-Bolt -Arguments @('quick', '-LockTimeout', '1s')
+        } finally {
+            $lock.Release()
+        }
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match 'Waiting for another bolt run to release .*bolt\.lock \(up to 1s, see -LockTimeout\)'
+        $result.Output | Should -Not -Match 'Ran quick'
+        $result.Error | Should -Match 'Timed out after 1s waiting for another bolt run to release'
+    }
+
+    It "Should wait for the other run to finish" {
+        New-TestTask -Name 'slow' -Body 'Start-Sleep -Seconds 3'
+        $first = Start-Bolt -Arguments @('slow')
+        try {
+            # Wait until the first run has the lock
+            $deadline = [DateTime]::UtcNow.AddSeconds(20)
+            while (-not (Select-String -Path (Join-Path $script:TempTestRoot 'first-stdout.txt') -Pattern 'Ran slow' -Quiet -ErrorAction SilentlyContinue) -and [DateTime]::UtcNow -lt $deadline) {
+                Start-Sleep -Milliseconds 100
+            }
+
+            $result = Invoke-Bolt -Arguments @('quick', '-LockTimeout', '30s') -OutputName 'second-stdout.txt' -ErrorName 'second-stderr.txt'
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match 'Waiting for another bolt run'
+            $result.Output | Should -Match 'Ran quick'
+            $first.HasExited | Should -BeTrue
+        } finally {
+            $first.WaitForExit()
+        }
+    }
+
+    It "Should not take the lock for -DryRun" {
+        $lock = Enter-BoltLock -Path $script:LockPath
+        try {
+            $result = Invoke-Bolt -Arguments @('quick', '-DryRun', '-LockTimeout', '0')
+        } finally {
+            $lock.Release()
+        }
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Not -Match 'Waiting for another bolt run'
+    }
+
+    It "Should reject an invalid -LockTimeout" {
+        $result = Invoke-Bolt -Arguments @('quick', '-LockTimeout', 'soon')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'Invalid -LockTimeout'
+        $result.Output | Should -Not -Match 'Ran quick'
+    }
+}
@@ -0,0 +1,170 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Logging'
+
+    # Load the logging functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('New-BoltLogger', 'Write-BoltLog', 'Test-BoltLogLevel') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Structured Logger" -Tag "Core", "Logging" {
+
+    BeforeEach {
+        $script:Writer = [System.IO.StringWriter]::new()
+    }
+
+    AfterEach {
+        $script:BoltLogger = $null
+    }
+
+    It "Should write text entries as key=value pairs" {
+        $logger = New-BoltLogger -Level Info -Writer $script:Writer
+
+        $logger.Log('Info', 'task start', [ordered]@{ task = 'build'; source = 'project' })
+
+        $script:Writer.ToString() | Should -Match '^time=\S+ level=INFO msg="task start" task=build source=project\r?\n$'
+    }
+
+    It "Should quote text values with spaces, quotes, or '='" {
+        $logger = New-BoltLogger -Level Info -Writer $script:Writer
+
+        $logger.Log('Info', 'exec', [ordered]@{ dir = 'C:\My Project'; arg = 'a=b'; quote = 'say "hi"'; empty = '' })
+
+        $line = $script:Writer.ToString()
+        $line | Should -Match 'dir="C:\\\\My Project"'
+        $line | Should -Match 'arg="a=b"'
+        $line | Should -Match 'quote="say \\"hi\\""'
+        $line | Should -Match 'empty=""'
+    }
+
+    It "Should write Json entries with array fields as arrays" {
+        $logger = New-BoltLogger -Level Debug -Format Json -Writer $script:Writer
+
+        $logger.Log('Debug', 'process start', [ordered]@{ task = 'build'; argv = @('pwsh', '-File', 'x.ps1'); pid = 42 })
+
+        $entry = $script:Writer.ToString() | ConvertFrom-Json
+        $entry.level | Should -Be 'DEBUG'
+        $entry.msg | Should -Be 'process start'
+        $entry.argv | Should -Be @('pwsh', '-File', 'x.ps1')
+        $entry.pid | Should -Be 42
+    }
+
+    It "Should drop entries below the configured level" {
+        $logger = New-BoltLogger -Level Warn -Writer $script:Writer
+
+        $logger.Log('Debug', 'debug entry', $null)
+        $logger.Log('Info', 'info entry', $null)
+        $logger.Log('Warn', 'warn entry', $null)
+        $logger.Log('Error', 'error entry', $null)
+
+        $lines = @($script:Writer.ToString() -split '\r?\n' | Where-Object { $_ })
+        $lines.Count | Should -Be 2
+        $lines[0] | Should -Match 'level=WARN msg="warn entry"'
+        $lines[1] | Should -Match 'level=ERROR msg="error entry"'
+    }
+
+    It "Should write through Write-BoltLog only when a logger is set" {
+        $script:BoltLogger = $null
+        { Write-BoltLog -Level Info -Message 'ignored' } | Should -Not -Throw
+        Test-BoltLogLevel -Level Error | Should -BeFalse
+
+        $script:BoltLogger = New-BoltLogger -Level Info -Writer $script:Writer
+        Write-BoltLog -Level Info -Message 'kept' -Fields ([ordered]@{ task = 'lint' })
+
+        $script:Writer.ToString() | Should -Match 'msg=kept task=lint'
+        Test-BoltLogLevel -Level Debug | Should -BeFalse
+        Test-BoltLogLevel -Level Info | Should -BeTrue
+    }
+}
+
+Describe "Structured Logging Runs" -Tag "Core", "Logging" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should not log anything without -LogLevel" {
+        New-TestTask -Name 'quiet'
+
+        $result = Invoke-Bolt -Arguments @('quiet')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran quiet'
+        $result.Error | Should -Not -Match 'level='
+    }
+
+    It "Should log task start and finish at Info" {
+        New-TestTask -Name 'compile'
+        New-TestTask -Name 'package' -Depends @('compile')
+
+        $result = Invoke-Bolt -Arguments @('package', '-LogLevel', 'Info')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran package'
+        $result.Error | Should -Match 'level=INFO msg="task start" task=compile'
+        $result.Error | Should -Match 'level=INFO msg="task finish" task=package status=success exit_code=0'
+        $result.Error | Should -Not -Match 'level=DEBUG'
+    }
+
+    It "Should log failed tasks at Error" {
+        New-TestTask -Name 'broken' -Body 'exit 3'
+
+        $result = Invoke-Bolt -Arguments @('broken', '-LogLevel', 'Info', '-ErrorAction', 'Continue')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'level=ERROR msg="task finish" task=broken status=failure exit_code=3'
+    }
+
+    It "Should log child processes with argv, environment keys, and times at Debug" {
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+        Set-Content -Path (Join-Path $buildPath 'Invoke-Child.ps1') -Value "# TASK: child`n# DESCRIPTION: Child task`n# ENV: DEPLOY_TOKEN=hunter2`nexit 0"
+
+        $result = Invoke-Bolt -Arguments @('child', '-Parallel', '-LogLevel', 'Debug', '-LogFormat', 'Json')
+
+        $result.ExitCode | Should -Be 0
+        $entries = @($result.Error -split '\r?\n' | Where-Object { $_.StartsWith('{') } | ForEach-Object { $_ | ConvertFrom-Json })
+
+        $start = $entries | Where-Object msg -eq 'process start'
+        $start.task | Should -Be 'child'
+        $start.argv | Should -Contain '-File'
+        $start.env_keys | Should -Contain 'DEPLOY_TOKEN'
+        $result.Error | Should -Not -Match 'hunter2'
+        $start.dir | Should -Not -BeNullOrEmpty
+        $start.started | Should -Not -BeNullOrEmpty
+
+        $exit = $entries | Where-Object msg -eq 'process exit'
+        $exit.pid | Should -Be $start.pid
+        $exit.exit_code | Should -Be 0
+        $exit.stopped | Should -Not -BeNullOrEmpty
+    }
+
+    It "Should keep -OutputFormat Json results on stdout" {
+        New-TestTask -Name 'summary'
+
+        $result = Invoke-Bolt -Arguments @('summary', '-OutputFormat', 'Json', '-LogLevel', 'Info')
+
+        $result.ExitCode | Should -Be 0
+        ($result.Output | ConvertFrom-Json).Tasks[0].Name | Should -Be 'summary'
+        $result.Error | Should -Match 'msg="task finish" task=summary'
+    }
+
+    It "Should reject an unknown -LogLevel" {
+        New-TestTask -Name 'quick'
+
+        $result = Invoke-Bolt -Arguments @('quick', '-LogLevel', 'Trace')
+
+        $result.ExitCode | Should -Not -Be 0
+        $result.Output | Should -Not -Match 'Ran quick'
+    }
+}
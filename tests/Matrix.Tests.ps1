@@ -0,0 +1,125 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Matrix'
+
+    # Helper function to create a task file with optional MATRIX lines
+    function New-TestTask {
+        param(
+            [string]$Name,
+            [string[]]$Depends = @(),
+            [string[]]$Matrix = @(),
+            [string]$Body = 'exit 0'
+        )
+
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+
+        $matrixLines = ($Matrix | ForEach-Object { "# MATRIX: $_" }) -join "`n"
+        $taskNameCapitalized = (Get-Culture).TextInfo.ToTitleCase($Name)
+        $content = @"
+# TASK: $Name
+# DESCRIPTION: Test task $Name
+# DEPENDS: $($Depends -join ', ')
+$matrixLines
+
+$Body
+"@
+        Set-Content -Path (Join-Path -Path $buildPath -ChildPath "Invoke-$taskNameCapitalized.ps1") -Value $content
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Matrix" -Tag "Core", "Matrix" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should run one instance per combination with the values as environment variables" {
+        New-TestTask -Name 'test' -Matrix @('GO_VERSION=1.21, 1.22', 'TARGET_OS=linux, windows') -Body 'Write-Host "go=$env:GO_VERSION os=$env:TARGET_OS"; exit 0'
+
+        $result = Invoke-Bolt -Arguments @('test')
+
+        $result.ExitCode | Should -Be 0
+        foreach ($expected in @('go=1.21 os=linux', 'go=1.21 os=windows', 'go=1.22 os=linux', 'go=1.22 os=windows')) {
+            $result.Output | Should -Match ([regex]::Escape($expected))
+        }
+    }
+
+    It "Should name instances and report MatrixValues in the RunSummary" {
+        New-TestTask -Name 'prepare'
+        New-TestTask -Name 'test' -Depends @('prepare') -Matrix @('GO_VERSION=1.21, 1.22')
+
+        $summary = (Invoke-Bolt -Arguments @('test', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+        ($summary.Tasks.Name -join ',') | Should -Be 'prepare,test[GO_VERSION=1.21],test[GO_VERSION=1.22]'
+        $summary.Tasks[0].MatrixValues.PSObject.Properties.Name | Should -BeNullOrEmpty
+        $summary.Tasks[1].MatrixValues.GO_VERSION | Should -Be '1.21'
+        $summary.Tasks[2].MatrixValues.GO_VERSION | Should -Be '1.22'
+        $summary.Success | Should -BeTrue
+    }
+
+    It "Should run instances in parallel" {
+        New-TestTask -Name 'slow' -Matrix @('SLOT=1, 2, 3') -Body 'Start-Sleep -Seconds 2; exit 0'
+
+        $stopwatch = [System.Diagnostics.Stopwatch]::StartNew()
+        $result = Invoke-Bolt -Arguments @('slow', '-Parallelism', '3')
+        $stopwatch.Stop()
+
+        $result.ExitCode | Should -Be 0
+        $stopwatch.Elapsed.TotalSeconds | Should -BeLessThan 5.5
+    }
+
+    It "Should respect -Parallelism for instances" {
+        $logPath = Join-Path -Path $script:TempTestRoot -ChildPath 'slots.log'
+        Remove-Item -Path $logPath -Force -ErrorAction SilentlyContinue
+        $body = @"
+Add-Content -Path '$logPath' -Value "start `$env:SLOT"
+Start-Sleep -Milliseconds 500
+Add-Content -Path '$logPath' -Value "end `$env:SLOT"
+exit 0
+"@
+        New-TestTask -Name 'slow' -Matrix @('SLOT=1, 2, 3') -Body $body
+
+        (Invoke-Bolt -Arguments @('slow', '-Parallelism', '1')).ExitCode | Should -Be 0
+
+        # With one slot, every instance ends before the next one starts
+        $events = @(Get-Content -Path $logPath | ForEach-Object { ($_ -split ' ')[0] })
+        ($events -join ',') | Should -Be 'start,end,start,end,start,end'
+    }
+
+    It "Should let a task depend on one instance" {
+        New-TestTask -Name 'test' -Matrix @('GO_VERSION=1.21, 1.22') -Body 'Write-Host "ran test $env:GO_VERSION"; exit 0'
+        New-TestTask -Name 'release' -Depends @('test[GO_VERSION=1.22]')
+
+        $summary = (Invoke-Bolt -Arguments @('release', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+        ($summary.Tasks.Name -join ',') | Should -Be 'test[GO_VERSION=1.22],release'
+    }
+
+    It "Should reject a dependency on the matrix task itself" {
+        New-TestTask -Name 'test' -Matrix @('GO_VERSION=1.21, 1.22')
+        New-TestTask -Name 'release' -Depends @('test') -Body 'Write-Host "ran release"; exit 0'
+
+        $result = Invoke-Bolt -Arguments @('release')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match "depends on matrix task 'test'"
+        $result.Error | Should -Match ([regex]::Escape('test[GO_VERSION=1.21]'))
+        $result.Output | Should -Not -Match 'ran release'
+    }
+
+    It "Should list the matrix task with its instances" {
+        New-TestTask -Name 'test' -Matrix @('GO_VERSION=1.21, 1.22')
+
+        $result = Invoke-Bolt -Arguments @('-ListTasks')
+
+        $result.Output | Should -Match ([regex]::Escape('Matrix: test[GO_VERSION=1.21], test[GO_VERSION=1.22]'))
+        $result.Output | Should -Not -Match '(?m)^\s+test\[GO_VERSION'
+    }
+}
@@ -1,56 +1,12 @@
 #Requires -Version 7.0
 
 BeforeAll {
-    $script:ProjectRoot = Split-Path -Path $PSScriptRoot -Parent
-    $script:BoltScriptSource = Join-Path -Path $ProjectRoot -ChildPath 'bolt.ps1'
-    $script:TempTestRoot = Join-Path -Path ([System.IO.Path]::GetTempPath()) -ChildPath "BoltNamespaceTests_$(Get-Random)"
-    
-    # Helper function to invoke bolt.ps1 with arguments
-    function Invoke-Bolt {
-        param(
-            [string[]]$Arguments,
-            [hashtable]$Parameters = @{}
-        )
-        
-        $boltScriptPath = Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.ps1'
-        
-        $params = @{
-            FilePath = 'pwsh'
-            ArgumentList = @('-NoProfile', '-File', $boltScriptPath) + $Arguments
-            WorkingDirectory = $script:TempTestRoot
-            Wait = $true
-            NoNewWindow = $true
-            PassThru = $true
-            RedirectStandardOutput = (Join-Path -Path $script:TempTestRoot -ChildPath 'stdout.txt')
-            RedirectStandardError = (Join-Path -Path $script:TempTestRoot -ChildPath 'stderr.txt')
-        }
-        
-        foreach ($key in $Parameters.Keys) {
-            $params[$key] = $Parameters[$key]
-        }
-        
-        $process = Start-Process @params
-        
-        $stdout = Get-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'stdout.txt') -Raw -ErrorAction SilentlyContinue
-        $stderr = Get-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'stderr.txt') -Raw -ErrorAction SilentlyContinue
-        
-        return [PSCustomObject]@{
-            ExitCode = $process.ExitCode
-            Output = $stdout
-            Error = $stderr
-        }
-    }
-    
-    # Create temp test directory and copy bolt.ps1
-    New-Item -ItemType Directory -Path $script:TempTestRoot -Force | Out-Null
-    Copy-Item -Path $script:BoltScriptSource -Destination (Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.ps1') -Force
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Namespace'
 }
 
 AfterAll {
-    # Clean up temp test directory
-    if (Test-Path -Path $script:TempTestRoot) {
-        Remove-Item -Path $script:TempTestRoot -Recurse -Force -ErrorAction SilentlyContinue
-    }
+    Remove-TestProject
 }
 
 Describe "Multi-Namespace Task Discovery" -Tag "Core", "Namespaces" {
@@ -414,4 +370,128 @@ exit 0
             $result.ExitCode | Should -Be 0
         }
     }
+
+    Context "Cross-Namespace Dependencies" {
+        It "Should resolve namespace:task references in DEPENDS" {
+            $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+            $frontendPath = Join-Path -Path $buildPath -ChildPath 'frontend'
+            $backendPath = Join-Path -Path $buildPath -ChildPath 'backend'
+            New-Item -ItemType Directory -Path $frontendPath, $backendPath -Force | Out-Null
+
+            Set-Content -Path (Join-Path -Path $backendPath -ChildPath 'Invoke-Build.ps1') -Value @'
+# TASK: build
+# DESCRIPTION: Backend build
+# DEPENDS:
+Write-Host "Backend build" -ForegroundColor Cyan
+exit 0
+'@
+            Set-Content -Path (Join-Path -Path $frontendPath -ChildPath 'Invoke-Build.ps1') -Value @'
+# TASK: build
+# DESCRIPTION: Frontend build
+# DEPENDS: backend:build
+Write-Host "Frontend build" -ForegroundColor Cyan
+exit 0
+'@
+
+            $result = Invoke-Bolt -Arguments @('frontend-build')
+
+            $result.Output | Should -Match '(?s)Backend build.*Frontend build'
+            $result.ExitCode | Should -Be 0
+        }
+
+        It "Should not fall back to a root task for a namespace:task reference" {
+            $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+            $frontendPath = Join-Path -Path $buildPath -ChildPath 'frontend'
+            New-Item -ItemType Directory -Path $frontendPath -Force | Out-Null
+
+            Set-Content -Path (Join-Path -Path $buildPath -ChildPath 'Invoke-Build.ps1') -Value @'
+# TASK: build
+# DESCRIPTION: Root build
+# DEPENDS:
+Write-Host "Root build" -ForegroundColor Cyan
+exit 0
+'@
+            Set-Content -Path (Join-Path -Path $frontendPath -ChildPath 'Invoke-Deploy.ps1') -Value @'
+# TASK: deploy
+# DESCRIPTION: Frontend deploy
+# DEPENDS: backend:build
+Write-Host "Frontend deploy" -ForegroundColor Cyan
+exit 0
+'@
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.Output | Should -Match "Dependency 'backend:build' does not exist"
+            $result.ExitCode | Should -Be 1
+        }
+    }
+
+    Context "Duplicate Task Names" {
+        It "Should reject two files that declare the same task name" {
+            $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+            New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+
+            foreach ($fileName in @('Invoke-Build.ps1', 'Invoke-Compile.ps1')) {
+                Set-Content -Path (Join-Path -Path $buildPath -ChildPath $fileName) -Value @'
+# TASK: build
+# DESCRIPTION: Build
+exit 0
+'@
+            }
+
+            $result = Invoke-Bolt -Arguments @('-ListTasks')
+
+            $result.ExitCode | Should -Be 1
+            $result.Error | Should -Match "Duplicate task name 'build'"
+            $result.Error | Should -Match 'Invoke-Build\.ps1'
+            $result.Error | Should -Match 'Invoke-Compile\.ps1'
+        }
+
+        It "Should reject a root task that matches a namespaced task name" {
+            $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+            $golangPath = Join-Path -Path $buildPath -ChildPath 'golang'
+            New-Item -ItemType Directory -Path $golangPath -Force | Out-Null
+
+            Set-Content -Path (Join-Path -Path $buildPath -ChildPath 'Invoke-Golang-Build.ps1') -Value @'
+# TASK: golang-build
+# DESCRIPTION: Root task with a namespaced name
+exit 0
+'@
+            Set-Content -Path (Join-Path -Path $golangPath -ChildPath 'Invoke-Build.ps1') -Value @'
+# TASK: build
+# DESCRIPTION: Golang build
+exit 0
+'@
+
+            $result = Invoke-Bolt -Arguments @('golang-build')
+
+            $result.ExitCode | Should -Be 1
+            $result.Error | Should -Match "Duplicate task name 'golang-build'"
+        }
+    }
+
+    Context "Grouped Task List" {
+        It "Should group -ListTasks output by namespace" {
+            $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+            foreach ($namespace in @('golang', 'bicep')) {
+                $namespacePath = Join-Path -Path $buildPath -ChildPath $namespace
+                New-Item -ItemType Directory -Path $namespacePath -Force | Out-Null
+                Set-Content -Path (Join-Path -Path $namespacePath -ChildPath 'Invoke-Build.ps1') -Value @"
+# TASK: build
+# DESCRIPTION: $namespace build
+exit 0
+"@
+            }
+            Set-Content -Path (Join-Path -Path $buildPath -ChildPath 'Invoke-Release.ps1') -Value @'
+# TASK: release
+# DESCRIPTION: Root release
+exit 0
+'@
+
+            $result = Invoke-Bolt -Arguments @('-ListTasks')
+
+            $result.Output | Should -Match "(?s)Core tasks:.*check-index.*Project tasks:.*release.*Namespace 'bicep':.*bicep-build.*Namespace 'golang':.*golang-build"
+            $result.ExitCode | Should -Be 0
+        }
+    }
 }
@@ -0,0 +1,104 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'OutputFormat'
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "JSON Output Format" -Tag "Core", "OutputFormat" {
+
+    BeforeEach {
+        foreach ($path in @('.build', '.bolt', 'src')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+    }
+
+    Context "Successful Run" {
+        BeforeEach {
+            New-TestTask -Name 'prepare'
+            New-TestTask -Name 'compile' -Depends @('prepare') -Body 'Start-Sleep -Milliseconds 100; exit 0'
+        }
+
+        It "Should write a single JSON document to stdout" {
+            $result = Invoke-Bolt -Arguments @('compile', '-OutputFormat', 'Json')
+
+            $result.ExitCode | Should -Be 0
+            { $result.Output | ConvertFrom-Json } | Should -Not -Throw
+            $result.Output | Should -Not -Match 'Executing task'
+            $result.Output | Should -Not -Match 'Ran compile'
+        }
+
+        It "Should report every task in execution order" {
+            $summary = (Invoke-Bolt -Arguments @('compile', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            $summary.Success | Should -BeTrue
+            ($summary.Tasks.Name -join ',') | Should -Be 'prepare,compile'
+            ($summary.Tasks.Status -join ',') | Should -Be 'success,success'
+            $summary.Tasks[1].ExitCode | Should -Be 0
+            $summary.Tasks[1].DurationMs | Should -BeGreaterOrEqual 100
+        }
+
+        It "Should include all RunSummary task fields" {
+            $summary = (Invoke-Bolt -Arguments @('prepare', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            $fields = $summary.Tasks[0].PSObject.Properties.Name
+            foreach ($field in @('Name', 'Status', 'DurationMs', 'ExitCode', 'Stderr', 'MatrixValues', 'Attempts', 'RollbackStatus')) {
+                $fields | Should -Contain $field
+            }
+        }
+    }
+
+    Context "Failed Run" {
+        BeforeEach {
+            New-TestTask -Name 'broken' -Body '[Console]::Error.WriteLine("compiler exploded"); exit 3'
+            New-TestTask -Name 'publish' -Depends @('broken')
+        }
+
+        It "Should record the failure with exit code and stderr" {
+            $result = Invoke-Bolt -Arguments @('publish', '-OutputFormat', 'Json')
+            $summary = $result.Output | ConvertFrom-Json
+
+            $result.ExitCode | Should -Be 1
+            $summary.Success | Should -BeFalse
+            $summary.Tasks[0].Name | Should -Be 'broken'
+            $summary.Tasks[0].Status | Should -Be 'failure'
+            $summary.Tasks[0].ExitCode | Should -Be 3
+            $summary.Tasks[0].Stderr | Should -Match 'compiler exploded'
+        }
+
+        It "Should mark tasks that never ran as skipped" {
+            $summary = (Invoke-Bolt -Arguments @('publish', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            $summary.Tasks[1].Name | Should -Be 'publish'
+            $summary.Tasks[1].Status | Should -Be 'skipped'
+        }
+    }
+
+    Context "Cached and Parallel Runs" {
+        It "Should report cached tasks as skipped" {
+            New-Item -ItemType Directory -Path (Join-Path $script:TempTestRoot 'src') -Force | Out-Null
+            Set-Content -Path (Join-Path $script:TempTestRoot 'src/input.txt') -Value 'data'
+            New-TestTask -Name 'cached' -ExtraMetadata '# INPUTS: src/*.txt'
+
+            (Invoke-Bolt -Arguments @('cached')).ExitCode | Should -Be 0
+            $summary = (Invoke-Bolt -Arguments @('cached', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            $summary.Tasks[0].Status | Should -Be 'skipped'
+        }
+
+        It "Should produce the same summary with -Parallel" {
+            New-TestTask -Name 'left'
+            New-TestTask -Name 'right'
+
+            $result = Invoke-Bolt -Arguments @('left', 'right', '-Parallel', '-OutputFormat', 'Json')
+            $summary = $result.Output | ConvertFrom-Json
+
+            $result.ExitCode | Should -Be 0
+            ($summary.Tasks.Status -join ',') | Should -Be 'success,success'
+        }
+    }
+}
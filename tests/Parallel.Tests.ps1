@@ -0,0 +1,118 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Parallel'
+
+    # Helper function to create a task that sleeps, then writes a line and exits
+    function New-TestTask {
+        param(
+            [string]$Name,
+            [string[]]$Depends = @(),
+            [int]$SleepMilliseconds = 0,
+            [int]$ExitCode = 0
+        )
+
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+
+        $taskNameCapitalized = (Get-Culture).TextInfo.ToTitleCase($Name)
+        $content = @"
+# TASK: $Name
+# DESCRIPTION: Test task $Name
+# DEPENDS: $($Depends -join ', ')
+
+Write-Host "Start $Name"
+Start-Sleep -Milliseconds $SleepMilliseconds
+Write-Host "Done $Name"
+exit $ExitCode
+"@
+        Set-Content -Path (Join-Path -Path $buildPath -ChildPath "Invoke-$taskNameCapitalized.ps1") -Value $content
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Parallel Task Execution" -Tag "Core", "Parallel" {
+
+    BeforeEach {
+        # Start every test with an empty .build directory
+        Get-ChildItem -Path $script:TempTestRoot -Directory -Filter '.build*' -Force -ErrorAction SilentlyContinue |
+            Remove-Item -Recurse -Force -ErrorAction SilentlyContinue
+    }
+
+    Context "Independent Tasks" {
+        BeforeEach {
+            New-TestTask -Name 'alpha' -SleepMilliseconds 2000
+            New-TestTask -Name 'beta' -SleepMilliseconds 2000
+        }
+
+        It "Should run independent tasks at the same time" {
+            $result = Invoke-Bolt -Arguments @('alpha', 'beta', '-Parallel', '-Parallelism', '2')
+
+            $result.ExitCode | Should -Be 0
+            # Both tasks start before either finishes
+            $result.Output.IndexOf('Start beta') | Should -BeLessThan $result.Output.IndexOf('Done alpha')
+            $result.Output.IndexOf('Start alpha') | Should -BeLessThan $result.Output.IndexOf('Done beta')
+        }
+
+        It "Should prefix each output line with the task name" {
+            $result = Invoke-Bolt -Arguments @('alpha', 'beta', '-Parallel')
+
+            $result.Output | Should -Match '(?m)^\[alpha\]\s+Done alpha'
+            $result.Output | Should -Match '(?m)^\[beta\]\s+Done beta'
+        }
+
+        It "Should run one task at a time with -Parallelism 1" {
+            $result = Invoke-Bolt -Arguments @('alpha', 'beta', '-Parallel', '-Parallelism', '1')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output.IndexOf('Done alpha') | Should -BeLessThan $result.Output.IndexOf('Start beta')
+        }
+    }
+
+    Context "Dependencies" {
+        BeforeEach {
+            # left and right both depend on base
+            New-TestTask -Name 'base' -SleepMilliseconds 500
+            New-TestTask -Name 'left' -Depends @('base')
+            New-TestTask -Name 'right' -Depends @('base')
+        }
+
+        It "Should start a task only after its dependencies complete" {
+            $result = Invoke-Bolt -Arguments @('left', 'right', '-Parallel')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output.IndexOf('Done base') | Should -BeLessThan $result.Output.IndexOf('Start left')
+            $result.Output.IndexOf('Done base') | Should -BeLessThan $result.Output.IndexOf('Start right')
+            ([regex]::Matches($result.Output, 'Done base')).Count | Should -Be 1
+        }
+    }
+
+    Context "Failure Handling" {
+        BeforeEach {
+            New-TestTask -Name 'broken' -SleepMilliseconds 200 -ExitCode 1
+            New-TestTask -Name 'slow' -SleepMilliseconds 10000
+            New-TestTask -Name 'after' -Depends @('broken')
+        }
+
+        It "Should cancel in-flight tasks on the first failure" {
+            $result = Invoke-Bolt -Arguments @('broken', 'slow', '-Parallel', '-Parallelism', '2')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match '\[slow\]\s+cancelled'
+            $result.Output | Should -Not -Match 'Done slow'
+            $result.Output | Should -Match 'Failed tasks: broken'
+            $result.Elapsed.TotalSeconds | Should -BeLessThan 10
+        }
+
+        It "Should not start tasks that depend on a failed task" {
+            $result = Invoke-Bolt -Arguments @('after', '-Parallel')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Not -Match 'Start after'
+        }
+    }
+}
@@ -0,0 +1,160 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Platform'
+
+    # Load the platform functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('Get-BoltPlatform', 'ConvertFrom-TaskPlatform', 'Test-TaskPlatform', 'Get-TaskPlatformWarnings', 'Resolve-TaskDependency') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+
+    # A platform entry for this machine and one for a machine it is not
+    $script:HostPlatform = Get-BoltPlatform
+    $script:HostEntry = "$($script:HostPlatform.Os)/$($script:HostPlatform.Arch)"
+    $script:OtherEntry = if ($script:HostPlatform.Os -eq 'windows') { 'linux' } else { 'windows' }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Platform Matching" -Tag "Core", "Platform" {
+
+    It "Should report the host as GOOS and GOARCH names" {
+        $script:HostPlatform.Os | Should -BeIn @('windows', 'darwin', 'linux', 'freebsd')
+        $script:HostPlatform.Arch | Should -Match '^[a-z0-9]+$'
+    }
+
+    It "Should be <Expected> for <Entries> on linux/amd64" -ForEach @(
+        @{ Entries = @('linux/amd64'); Expected = $true }
+        @{ Entries = @('linux'); Expected = $true }
+        @{ Entries = @('darwin/arm64', 'linux/amd64'); Expected = $true }
+        @{ Entries = @('linux/arm64'); Expected = $false }
+        @{ Entries = @('darwin', 'windows'); Expected = $false }
+        @{ Entries = @(); Expected = $true }
+    ) {
+        $current = [PSCustomObject]@{ Os = 'linux'; Arch = 'amd64' }
+
+        Test-TaskPlatform -Platform $Entries -Current $current | Should -Be $Expected
+    }
+
+    It "Should parse GOOS and GOOS/GOARCH entries" {
+        $parsed = ConvertFrom-TaskPlatform -Entry 'darwin/arm64'
+        $parsed.Os | Should -Be 'darwin'
+        $parsed.Arch | Should -Be 'arm64'
+
+        (ConvertFrom-TaskPlatform -Entry 'windows').Arch | Should -BeNullOrEmpty
+    }
+
+    It "Should reject '<Entry>'" -ForEach @(
+        @{ Entry = 'macos' }
+        @{ Entry = 'linux/x64' }
+        @{ Entry = 'Linux/amd64' }
+        @{ Entry = 'linux/amd64/v3' }
+        @{ Entry = '' }
+    ) {
+        { ConvertFrom-TaskPlatform -Entry $Entry } | Should -Throw -ErrorId 'InvalidPlatform'
+    }
+
+    It "Should warn about a dependency that is always skipped while its dependent runs" {
+        $current = [PSCustomObject]@{ Os = 'linux'; Arch = 'amd64' }
+        $allTasks = @{
+            native  = @{ Names = @('native'); Dependencies = @(); Namespace = $null; ScriptPath = 'native.ps1'; Platform = @('darwin') }
+            release = @{ Names = @('release'); Dependencies = @('native'); Namespace = $null; ScriptPath = 'release.ps1'; Platform = @() }
+            mac     = @{ Names = @('mac'); Dependencies = @('native'); Namespace = $null; ScriptPath = 'mac.ps1'; Platform = @('darwin') }
+        }
+
+        $warnings = Get-TaskPlatformWarnings -AllTasks $allTasks -Current $current
+
+        $warnings.Count | Should -Be 1
+        $warnings[0].Task | Should -Be 'release'
+        $warnings[0].Issue | Should -Match "Dependency 'native' only runs on darwin and is always skipped on linux/amd64"
+    }
+}
+
+Describe "Platform-Specific Tasks" -Tag "Core", "Platform" {
+
+    BeforeEach {
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        if (Test-Path -Path $buildPath) {
+            Remove-Item -Path $buildPath -Recurse -Force
+        }
+    }
+
+    It "Should run a task whose platform list includes this machine" {
+        New-TestTask -Name 'native' -ExtraMetadata "# PLATFORM: $script:OtherEntry, $script:HostEntry"
+
+        $result = Invoke-Bolt -Arguments @('native')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran native'
+    }
+
+    It "Should skip a task for another platform and still run its dependents" {
+        New-TestTask -Name 'native' -ExtraMetadata "# PLATFORM: $script:OtherEntry"
+        New-TestTask -Name 'release' -Depends @('native')
+
+        $result = Invoke-Bolt -Arguments @('release')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Not -Match 'Ran native'
+        $result.Output | Should -Match "Task 'native' does not run on .+ \(SKIPPED\(platform\)\)"
+        $result.Output | Should -Match 'Ran release'
+    }
+
+    It "Should skip a task for another platform with -Parallel" {
+        New-TestTask -Name 'native' -ExtraMetadata "# PLATFORM: $script:OtherEntry"
+        New-TestTask -Name 'other'
+
+        $result = Invoke-Bolt -Arguments @('native', 'other', '-Parallel', '-NoProgress')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '\[native\]\s+does not run on this platform \(SKIPPED\(platform\)\)'
+        $result.Output | Should -Match 'Ran other'
+    }
+
+    It "Should record SkipReason platform in the JSON summary" {
+        New-TestTask -Name 'native' -ExtraMetadata "# PLATFORM: $script:OtherEntry"
+
+        $result = Invoke-Bolt -Arguments @('native', '-OutputFormat', 'Json')
+        $summary = $result.Output | ConvertFrom-Json
+
+        $summary.Tasks[0].Status | Should -Be 'skipped'
+        $summary.Tasks[0].SkipReason | Should -Be 'platform'
+    }
+
+    It "Should stop before any task runs when a platform is unknown" {
+        New-TestTask -Name 'first'
+        New-TestTask -Name 'native' -Depends @('first') -ExtraMetadata '# PLATFORM: macos/arm64'
+
+        $result = Invoke-Bolt -Arguments @('native')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match "unknown GOOS 'macos'"
+        $result.Output | Should -Not -Match 'Ran first'
+    }
+
+    It "Should warn about an impossible dependency with -ValidateTasks without failing" {
+        New-TestTask -Name 'native' -ExtraMetadata "# PLATFORM: $script:OtherEntry"
+        New-TestTask -Name 'release' -Depends @('native')
+
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Warnings:'
+        $result.Output | Should -Match "release\s+\| DEPENDS \| Dependency 'native' only runs on $script:OtherEntry"
+    }
+
+    It "Should report an unknown platform with -ValidateTasks" {
+        New-TestTask -Name 'native' -ExtraMetadata '# PLATFORM: linux/x64'
+
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match "native\s+\| PLATFORM \| .*unknown GOARCH 'x64'"
+    }
+}
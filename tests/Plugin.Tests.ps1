@@ -0,0 +1,194 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Plugin'
+    $script:PrintPluginSource = Join-Path -Path $ProjectRoot -ChildPath 'examples/plugins/PrintPlugin.ps1'
+
+    # Helper function to create a task file in the temp .build directory
+    function New-TestTask {
+        param(
+            [string]$Name,
+            [string[]]$Depends = @(),
+            [string]$ExtraMetadata = '',
+            [string]$Body = ''
+        )
+
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+
+        $taskNameCapitalized = (Get-Culture).TextInfo.ToTitleCase($Name)
+        $content = @"
+# TASK: $Name
+# DESCRIPTION: Test task $Name
+# DEPENDS: $($Depends -join ', ')
+$ExtraMetadata
+$Body
+"@
+        Set-Content -Path (Join-Path -Path $buildPath -ChildPath "Invoke-$taskNameCapitalized.ps1") -Value $content
+    }
+
+    # Helper function to write bolt.config.json with the given plugin scripts
+    function Set-PluginConfig {
+        param(
+            [string[]]$Plugins
+        )
+
+        @{ Plugins = $Plugins } | ConvertTo-Json | Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.config.json')
+    }
+
+    # Copy the example plugin
+    New-Item -ItemType Directory -Path (Join-Path $script:TempTestRoot 'plugins') -Force | Out-Null
+    Copy-Item -Path $script:PrintPluginSource -Destination (Join-Path -Path $script:TempTestRoot -ChildPath 'plugins/PrintPlugin.ps1') -Force
+
+    # Load the plugin registry functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('New-ShellExecutor', 'Get-BoltPluginRegistry', 'Register-BoltPlugin') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Plugin Registry" -Tag "Core", "Plugin" {
+
+    BeforeEach {
+        $script:BoltPlugins = $null
+    }
+
+    BeforeAll {
+        # Helper function to build a plugin object
+        function New-TestPlugin {
+            param(
+                [string]$Name,
+                [switch]$NoExecute
+            )
+
+            $plugin = [PSCustomObject]@{ Name = $Name }
+            if (-not $NoExecute) {
+                $plugin | Add-Member -MemberType ScriptMethod -Name Execute -Value { param($Task, $Environment) }
+            }
+            return $plugin
+        }
+    }
+
+    It "Should start with the built-in shell executor" {
+        $registry = Get-BoltPluginRegistry
+
+        $registry.Keys | Should -Be @('shell')
+        $registry['shell'].PSObject.Methods['Execute'] | Should -Not -BeNullOrEmpty
+    }
+
+    It "Should register a plugin by name" {
+        Register-BoltPlugin -Plugin (New-TestPlugin -Name 'print')
+
+        (Get-BoltPluginRegistry)['print'].Name | Should -Be 'print'
+    }
+
+    It "Should reject a name that is already registered" {
+        Register-BoltPlugin -Plugin (New-TestPlugin -Name 'print')
+
+        { Register-BoltPlugin -Plugin (New-TestPlugin -Name 'print') } | Should -Throw -ErrorId 'PluginAlreadyRegistered'
+        { Register-BoltPlugin -Plugin (New-TestPlugin -Name 'shell') } | Should -Throw -ErrorId 'PluginAlreadyRegistered'
+    }
+
+    It "Should reject invalid names and plugins without Execute" {
+        { Register-BoltPlugin -Plugin (New-TestPlugin -Name 'Print Plugin') } | Should -Throw -ErrorId 'InvalidPluginName'
+        { Register-BoltPlugin -Plugin (New-TestPlugin -Name 'print' -NoExecute) } | Should -Throw -ErrorId 'InvalidPlugin'
+        (Get-BoltPluginRegistry).ContainsKey('print') | Should -BeFalse
+    }
+}
+
+Describe "Plugin Tasks" -Tag "Core", "Plugin" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        Set-PluginConfig -Plugins @('plugins/PrintPlugin.ps1')
+    }
+
+    It "Should run a task with the example print plugin" {
+        New-TestTask -Name 'hello' -ExtraMetadata "# TYPE: plugin/print`n# MESSAGE: Hello `${BOLT_TEST_NAME}`n# ENV: BOLT_TEST_NAME=world"
+
+        $result = Invoke-Bolt -Arguments @('hello', 'first', 'second')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Hello world'
+        $result.Output | Should -Match 'argument: first'
+        $result.Output | Should -Match 'argument: second'
+    }
+
+    It "Should fail the task when the plugin throws" {
+        New-TestTask -Name 'hello' -ExtraMetadata '# TYPE: plugin/print'
+
+        $result = Invoke-Bolt -Arguments @('hello', '-OutputFormat', 'Json')
+        $summary = $result.Output | ConvertFrom-Json
+
+        $result.ExitCode | Should -Be 1
+        $summary.Tasks[0].Status | Should -Be 'failure'
+        $summary.Tasks[0].Stderr | Should -Match "has no # MESSAGE: for the print plugin"
+        $summary.Tasks[0].Stderr | Should -Not -Match 'Exception calling'
+    }
+
+    It "Should run plugin tasks after their dependencies with -Parallel" {
+        New-TestTask -Name 'prepare' -Body "Write-Host 'Ran prepare'`nexit 0"
+        New-TestTask -Name 'hello' -Depends @('prepare') -ExtraMetadata "# TYPE: plugin/print`n# MESSAGE: Hello after prepare"
+
+        $result = Invoke-Bolt -Arguments @('hello', '-Parallel')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '(?s)Ran prepare.*Hello after prepare'
+        ([regex]::Matches($result.Output, 'Ran prepare')).Count | Should -Be 1
+    }
+
+    It "Should show the plugin in a dry run without loading it" {
+        Set-PluginConfig -Plugins @('plugins/Missing.ps1')
+        New-TestTask -Name 'hello' -ExtraMetadata "# TYPE: plugin/print`n# MESSAGE: Hi"
+
+        $result = Invoke-Bolt -Arguments @('hello', '-DryRun')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Command: plugin/print \.build/Invoke-Hello\.ps1'
+    }
+
+    Context "Validation" {
+        It "Should accept plugin tasks without commands" {
+            New-TestTask -Name 'hello' -ExtraMetadata "# TYPE: plugin/print`n# MESSAGE: Hi"
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 0
+        }
+
+        It "Should report plugins that are not registered" {
+            New-TestTask -Name 'hello' -ExtraMetadata '# TYPE: plugin/deploy'
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match "Plugin 'deploy' is not registered"
+        }
+
+        It "Should report plugin scripts that do not exist" {
+            Set-PluginConfig -Plugins @('plugins/Missing.ps1')
+            New-TestTask -Name 'hello' -ExtraMetadata '# TYPE: plugin/print'
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match "Plugin script 'plugins/Missing.ps1' does not exist"
+        }
+
+        It "Should report TIMEOUT on plugin tasks" {
+            New-TestTask -Name 'hello' -ExtraMetadata "# TYPE: plugin/print`n# MESSAGE: Hi`n# TIMEOUT: 5s"
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match 'cannot use TIMEOUT or CONTAINER'
+        }
+    }
+}
@@ -0,0 +1,123 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Profile'
+
+    # Load the profiler functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('New-BoltProfiler', 'Save-BoltProfile') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Bolt Profiler" -Tag "Core", "Profile" {
+
+    It "Should count and time each phase" {
+        $profiler = New-BoltProfiler
+
+        foreach ($run in 1..3) {
+            $profiler.Start('cache-key')
+            Start-Sleep -Milliseconds 20
+            $profiler.Stop('cache-key')
+        }
+        $profiler.Start('run')
+        $profiler.Stop('run')
+
+        @($profiler.Phases.Keys) | Should -Be @('cache-key', 'run')
+        $profiler.Phases['cache-key'].Count | Should -Be 3
+        [TimeSpan]::FromTicks($profiler.Phases['cache-key'].WallTicks).TotalMilliseconds | Should -BeGreaterOrEqual 55
+        $profiler.Phases['run'].Count | Should -Be 1
+    }
+
+    It "Should ignore Stop for a phase that did not start" {
+        $profiler = New-BoltProfiler
+
+        { $profiler.Stop('plan') } | Should -Not -Throw
+        $profiler.Phases.Count | Should -Be 0
+    }
+
+    It "Should stop running phases when the CPU profile is saved" {
+        $profiler = New-BoltProfiler
+        $profiler.Start('run')
+        $cpuPath = Join-Path $script:TempTestRoot 'unit-cpu.json'
+
+        Save-BoltProfile -Profiler $profiler -CpuPath $cpuPath
+
+        $cpuProfile = Get-Content -Path $cpuPath -Raw | ConvertFrom-Json
+        $cpuProfile.Type | Should -Be 'cpu'
+        $cpuProfile.Phases[0].Name | Should -Be 'run'
+        $cpuProfile.Phases[0].Count | Should -Be 1
+    }
+}
+
+Describe "Profiling Runs" -Tag "Core", "Profile" {
+
+    BeforeEach {
+        foreach ($path in @('.build', 'cpu.json', 'mem.json', 'args.txt')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+    }
+
+    It "Should write a CPU profile with every phase of the run" {
+        New-TestTask -Name 'compile'
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        Set-Content -Path (Join-Path $buildPath 'Invoke-Check.ps1') -Value "# TASK: check`n# DESCRIPTION: Cached check`n# DEPENDS: compile`n# INPUTS: .build/*.ps1`nexit 0"
+
+        $result = Invoke-Bolt -Arguments @('check', '-CpuProfile', 'cpu.json')
+
+        $result.ExitCode | Should -Be 0
+        $cpuProfile = Get-Content -Path (Join-Path $script:TempTestRoot 'cpu.json') -Raw | ConvertFrom-Json
+        $cpuProfile.Type | Should -Be 'cpu'
+        $cpuProfile.WallMs | Should -BeGreaterThan 0
+        $cpuProfile.CpuMs | Should -BeGreaterOrEqual 0
+        @($cpuProfile.Phases.Name | Where-Object { $_ -ne 'cache-key' }) | Should -Be @('discover', 'plan', 'lock', 'run')
+        $cpuProfile.Phases.Name | Should -Contain 'cache-key'
+        ($cpuProfile.Phases | Where-Object Name -eq 'cache-key').Count | Should -BeGreaterOrEqual 1
+        ($cpuProfile.Phases | Where-Object Name -eq 'run').Count | Should -Be 1
+    }
+
+    It "Should write a memory profile" {
+        New-TestTask -Name 'compile'
+
+        $result = Invoke-Bolt -Arguments @('compile', '-MemProfile', 'mem.json')
+
+        $result.ExitCode | Should -Be 0
+        $memProfile = Get-Content -Path (Join-Path $script:TempTestRoot 'mem.json') -Raw | ConvertFrom-Json
+        $memProfile.Type | Should -Be 'memory'
+        $memProfile.HeapBytes | Should -BeGreaterThan 0
+        $memProfile.TotalAllocatedBytes | Should -BeGreaterThan 0
+        $memProfile.PeakWorkingSetBytes | Should -BeGreaterOrEqual $memProfile.WorkingSetBytes
+        $memProfile.GcCollections.Gen0 | Should -BeGreaterOrEqual 0
+    }
+
+    It "Should write both profiles when a task fails" {
+        New-TestTask -Name 'broken' -Body 'exit 2'
+
+        $result = Invoke-Bolt -Arguments @('broken', '-CpuProfile', 'cpu.json', '-MemProfile', 'mem.json')
+
+        $result.ExitCode | Should -Be 1
+        Join-Path $script:TempTestRoot 'cpu.json' | Should -Exist
+        Join-Path $script:TempTestRoot 'mem.json' | Should -Exist
+    }
+
+    It "Should not pass the profile parameters to tasks in <Mode> runs" -ForEach @(
+        @{ Mode = 'sequential'; Extra = @() }
+        @{ Mode = 'parallel'; Extra = @('-Parallel') }
+    ) {
+        New-TestTask -Name 'echo' -Body "Set-Content -Path (Join-Path `$PSScriptRoot '../args.txt') -Value (`$args -join ' ')`nexit 0"
+
+        $result = Invoke-Bolt -Arguments (@('echo', 'release', '-CpuProfile', 'cpu.json', '-MemProfile', 'mem.json') + $Extra)
+
+        $result.ExitCode | Should -Be 0
+        $taskArgs = Get-Content -Path (Join-Path $script:TempTestRoot 'args.txt') -Raw
+        $taskArgs | Should -Match 'release'
+        $taskArgs | Should -Not -Match 'Profile|cpu\.json|mem\.json'
+    }
+}
@@ -0,0 +1,135 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Progress'
+
+    # Load the progress display for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('New-TaskProgressDisplay') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+
+    $script:Escape = [char]27
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Progress Display" -Tag "Core", "Progress" {
+
+    BeforeEach {
+        $script:Writer = [System.IO.StringWriter]::new()
+        $script:Columns = 40
+        $script:Display = New-TaskProgressDisplay -Writer $script:Writer -Width { $script:Columns } -IntervalMs 0
+    }
+
+    It "Should draw a spinner, the name, the elapsed time, and the last line" {
+        $script:Display.Render(@(
+            [PSCustomObject]@{ Name = '[lint]'; Elapsed = [TimeSpan]::FromMilliseconds(1240); Text = 'checking files' }
+            [PSCustomObject]@{ Name = '[test]'; Elapsed = [TimeSpan]::FromSeconds(12); Text = '' }
+        ))
+
+        $lines = @($script:Writer.ToString() -split '\r?\n' | Where-Object { $_ })
+        $lines.Count | Should -Be 2
+        $lines[0] | Should -Match '^[-\\|/] \[lint\] 1[.,]2s  checking files$'
+        $lines[1] | Should -Match '^[-\\|/] \[test\] 12[.,]0s$'
+    }
+
+    It "Should keep lines one column shorter than the terminal" {
+        $script:Display.Render(@(
+            [PSCustomObject]@{ Name = '[build]'; Elapsed = [TimeSpan]::Zero; Text = 'x' * 100 }
+        ))
+
+        $line = ($script:Writer.ToString() -split '\r?\n')[0]
+        $line.Length | Should -Be 39
+        $line | Should -Match '\.\.\.$'
+    }
+
+    It "Should drop color codes and control characters from the last line" {
+        $script:Display.Render(@(
+            [PSCustomObject]@{ Name = '[build]'; Elapsed = [TimeSpan]::Zero; Text = "$($script:Escape)[32mok$($script:Escape)[0m`tdone" }
+        ))
+
+        $script:Writer.ToString() | Should -Match 'ok done'
+        $script:Writer.ToString() | Should -Not -Match '\[32m'
+    }
+
+    It "Should erase the drawn lines on Clear" {
+        $entries = @(
+            [PSCustomObject]@{ Name = '[lint]'; Elapsed = [TimeSpan]::Zero; Text = 'a' }
+            [PSCustomObject]@{ Name = '[test]'; Elapsed = [TimeSpan]::Zero; Text = 'b' }
+        )
+        $script:Display.Render($entries)
+        $script:Writer.GetStringBuilder().Clear() | Out-Null
+
+        $script:Display.Clear()
+
+        $script:Writer.ToString() | Should -Be "$($script:Escape)[2A`r$($script:Escape)[J"
+    }
+
+    It "Should count wrapped rows after the terminal gets narrower" {
+        $script:Display.Render(@(
+            [PSCustomObject]@{ Name = '[build]'; Elapsed = [TimeSpan]::Zero; Text = 'x' * 100 }
+            [PSCustomObject]@{ Name = '[test]'; Elapsed = [TimeSpan]::Zero; Text = '' }
+        ))
+        $script:Writer.GetStringBuilder().Clear() | Out-Null
+
+        # 39 columns wrap into 3 rows at width 15, and the short line fits in one
+        $script:Columns = 15
+        $script:Display.Clear()
+
+        $script:Writer.ToString() | Should -Be "$($script:Escape)[4A`r$($script:Escape)[J"
+    }
+
+    It "Should do nothing on Clear when nothing is drawn" {
+        $script:Display.Clear()
+
+        $script:Writer.ToString() | Should -BeNullOrEmpty
+    }
+
+    It "Should not redraw lines that are still on screen within the interval" {
+        $display = New-TaskProgressDisplay -Writer $script:Writer -Width { 80 } -IntervalMs 60000
+        $entries = @([PSCustomObject]@{ Name = '[build]'; Elapsed = [TimeSpan]::Zero; Text = 'first' })
+
+        $display.Render($entries)
+        $display.Render(@([PSCustomObject]@{ Name = '[build]'; Elapsed = [TimeSpan]::Zero; Text = 'second' }))
+        $script:Writer.ToString() | Should -Not -Match 'second'
+
+        # Output erased the display, so it is drawn again right away
+        $display.Clear()
+        $display.Render(@([PSCustomObject]@{ Name = '[build]'; Elapsed = [TimeSpan]::Zero; Text = 'third' }))
+        $script:Writer.ToString() | Should -Match 'third'
+    }
+}
+
+Describe "Task Progress Runs" -Tag "Core", "Progress" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should print plain lines when standard error is not a terminal" {
+        New-TestTask -Name 'lint' -Body "Start-Sleep -Milliseconds 300; exit 0"
+        New-TestTask -Name 'test' -Body "Start-Sleep -Milliseconds 300; exit 0"
+        New-TestTask -Name 'all' -Depends @('lint', 'test')
+
+        $result = Invoke-Bolt -Arguments @('all', '-Parallel')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '\[lint\]\s+Ran lint'
+        "$($result.Output)$($result.Error)" | Should -Not -Match ([regex]::Escape("$($script:Escape)[J"))
+    }
+
+    It "Should accept -NoProgress" {
+        New-TestTask -Name 'lint'
+
+        $result = Invoke-Bolt -Arguments @('lint', '-Parallel', '-NoProgress')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '\[lint\]\s+Ran lint'
+    }
+}
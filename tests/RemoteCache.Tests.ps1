@@ -0,0 +1,308 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'RemoteCache'
+    $script:RequestLog = Join-Path -Path $script:TempTestRoot -ChildPath 'requests.log'
+
+    # Helper function to reset the project with a cacheable task and bolt.config.json
+    function Initialize-RemoteCacheProject {
+        param(
+            [hashtable]$Config
+        )
+
+        foreach ($path in @('.build', '.bolt', 'src', 'bolt.config.json')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath, (Join-Path $script:TempTestRoot 'src') -Force | Out-Null
+        Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'src/main.txt') -Value 'main'
+
+        $content = @'
+# TASK: check
+# DESCRIPTION: Checks sources
+# DEPENDS:
+# INPUTS: src/*.txt
+
+Write-Host "Checked sources"
+exit 0
+'@
+        Set-Content -Path (Join-Path -Path $buildPath -ChildPath 'Invoke-Check.ps1') -Value $content
+        $Config | ConvertTo-Json | Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.config.json')
+    }
+
+    # Start a small cache server that keeps entries in memory and logs each request.
+    # It also plays the GitHub Actions token endpoint (/oidc/token) and an OIDC
+    # exchange (/auth) that accepts the identity tokens the tests set up.
+    $serverScript = Join-Path -Path $script:TempTestRoot -ChildPath 'cache-server.ps1'
+    Set-Content -Path $serverScript -Value @'
+param([string]$Prefix, [string]$LogPath)
+
+$store = @{}
+$listener = [System.Net.HttpListener]::new()
+$listener.Prefixes.Add($Prefix)
+$listener.Start()
+
+while ($listener.IsListening) {
+    $context = $listener.GetContext()
+    $request = $context.Request
+    $response = $context.Response
+    $key = $request.Url.AbsolutePath
+
+    Add-Content -Path $LogPath -Value "$($request.HttpMethod) $key $($request.Headers['Authorization'])"
+
+    if ($key -eq '/oidc/token') {
+        $bytes = [System.Text.Encoding]::UTF8.GetBytes((@{ value = "github-id-token:$($request.QueryString['audience'])" } | ConvertTo-Json -Compress))
+        $response.ContentType = 'application/json'
+        $response.OutputStream.Write($bytes, 0, $bytes.Length)
+    } elseif ($key -eq '/auth') {
+        $reader = [System.IO.StreamReader]::new($request.InputStream)
+        $exchange = $reader.ReadToEnd() | ConvertFrom-Json
+        $reader.Dispose()
+        Add-Content -Path $LogPath -Value "EXCHANGE $($exchange.provider) $($exchange.token)"
+        if ($exchange.token -like 'github-id-token:*' -or $exchange.token -eq 'gitlab-jwt') {
+            $bytes = [System.Text.Encoding]::UTF8.GetBytes('{"access_token":"short-lived-token","expires_in":300}')
+            $response.ContentType = 'application/json'
+            $response.OutputStream.Write($bytes, 0, $bytes.Length)
+        } else {
+            $response.StatusCode = 401
+        }
+    } elseif ($request.HttpMethod -eq 'PUT') {
+        $reader = [System.IO.StreamReader]::new($request.InputStream)
+        $store[$key] = $reader.ReadToEnd()
+        $reader.Dispose()
+        $response.StatusCode = 200
+    } elseif ($store.ContainsKey($key)) {
+        $bytes = [System.Text.Encoding]::UTF8.GetBytes($store[$key])
+        $response.ContentType = 'application/json'
+        $response.OutputStream.Write($bytes, 0, $bytes.Length)
+    } else {
+        $response.StatusCode = 404
+    }
+
+    $response.Close()
+}
+'@
+    Start-TestServer -ScriptPath $serverScript -Arguments @($script:RequestLog) -PingPath '/cache/ping'
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Remote Task Cache" -Tag "Core", "Cache", "RemoteCache" {
+
+    BeforeEach {
+        Remove-Item -Path $script:RequestLog -Force -ErrorAction SilentlyContinue
+    }
+
+    Context "Server Reachable" {
+        BeforeEach {
+            # A fresh source file per test keeps cache keys from leaking between tests
+            Initialize-RemoteCacheProject -Config @{
+                RemoteCacheURL   = $script:ServerUrl
+                RemoteCacheToken = '${BOLT_TEST_CACHE_TOKEN}'
+            }
+            Set-Content -Path (Join-Path $script:TempTestRoot 'src/main.txt') -Value "main $(Get-Random)"
+            $env:BOLT_TEST_CACHE_TOKEN = 'secret-token'
+        }
+
+        AfterEach {
+            Remove-Item -Path Env:\BOLT_TEST_CACHE_TOKEN -ErrorAction SilentlyContinue
+        }
+
+        It "Should upload the manifest with a bearer token after a successful run" {
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.ExitCode | Should -Be 0
+            $log = Get-Content -Path $script:RequestLog
+            ($log | Where-Object { $_ -match '^GET /cache/[0-9a-f]+ Bearer secret-token$' }).Count | Should -Be 1
+            ($log | Where-Object { $_ -match '^PUT /cache/[0-9a-f]+ Bearer secret-token$' }).Count | Should -Be 1
+        }
+
+        It "Should skip the task when only the remote cache has the entry" {
+            (Invoke-Bolt -Arguments @('check')).ExitCode | Should -Be 0
+            Remove-Item -Path (Join-Path $script:TempTestRoot '.bolt') -Recurse -Force
+
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match "Task 'check' is up to date \(CACHED\)"
+            $result.Output | Should -Not -Match 'Checked sources'
+        }
+
+        It "Should keep a local copy of the manifest" {
+            (Invoke-Bolt -Arguments @('check')).ExitCode | Should -Be 0
+
+            Test-Path -Path (Join-Path $script:TempTestRoot '.bolt/cache/check.json') | Should -BeTrue
+        }
+
+        It "Should run the task again when the input changes" {
+            (Invoke-Bolt -Arguments @('check')).ExitCode | Should -Be 0
+            Set-Content -Path (Join-Path $script:TempTestRoot 'src/main.txt') -Value 'changed'
+
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.Output | Should -Match 'Checked sources'
+        }
+    }
+
+    Context "Remote Cache TTL" {
+        It "Should treat remote entries older than RemoteCacheTTL as a miss" {
+            Initialize-RemoteCacheProject -Config @{
+                RemoteCacheURL = $script:ServerUrl
+                RemoteCacheTTL = '1s'
+            }
+            Set-Content -Path (Join-Path $script:TempTestRoot 'src/main.txt') -Value "main $(Get-Random)"
+
+            (Invoke-Bolt -Arguments @('check')).ExitCode | Should -Be 0
+            Start-Sleep -Seconds 2
+
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match 'Checked sources'
+        }
+    }
+
+    Context "Server Unreachable" {
+        BeforeEach {
+            Initialize-RemoteCacheProject -Config @{
+                RemoteCacheURL = "http://localhost:$(Get-FreePort)"
+            }
+        }
+
+        It "Should warn and still run the task" {
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match 'Checked sources'
+            ($result.Output + $result.Error) | Should -Match 'Remote cache at .* is not reachable, using the local cache'
+        }
+
+        It "Should fall back to the local cache on the next run" {
+            (Invoke-Bolt -Arguments @('check')).ExitCode | Should -Be 0
+
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match "Task 'check' is up to date \(CACHED\)"
+        }
+    }
+
+    Context "OIDC Authentication" {
+        BeforeEach {
+            # Tests may run in a CI job that has identity tokens of its own
+            $script:SavedIdentity = @{}
+            foreach ($name in @('ACTIONS_ID_TOKEN_REQUEST_URL', 'ACTIONS_ID_TOKEN_REQUEST_TOKEN', 'CI_JOB_JWT_V2')) {
+                $script:SavedIdentity[$name] = [Environment]::GetEnvironmentVariable($name)
+                [Environment]::SetEnvironmentVariable($name, $null)
+            }
+
+            Initialize-RemoteCacheProject -Config @{
+                RemoteCacheURL      = $script:ServerUrl
+                RemoteCacheAuth     = 'oidc'
+                RemoteCacheAuthURL  = "$($script:ServerUrl)/auth"
+                RemoteCacheAudience = 'bolt-cache'
+                RemoteCacheToken    = '${BOLT_TEST_CACHE_TOKEN}'
+            }
+            Set-Content -Path (Join-Path $script:TempTestRoot 'src/main.txt') -Value "main $(Get-Random)"
+        }
+
+        AfterEach {
+            foreach ($name in $script:SavedIdentity.Keys) {
+                [Environment]::SetEnvironmentVariable($name, $script:SavedIdentity[$name])
+            }
+            Remove-Item -Path Env:\BOLT_TEST_CACHE_TOKEN -ErrorAction SilentlyContinue
+        }
+
+        It "Should exchange a GitHub Actions identity token for a short-lived token" {
+            $env:ACTIONS_ID_TOKEN_REQUEST_URL = "$($script:ServerUrl)/oidc/token?api-version=2.0"
+            $env:ACTIONS_ID_TOKEN_REQUEST_TOKEN = 'request-token'
+
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.ExitCode | Should -Be 0
+            $log = Get-Content -Path $script:RequestLog
+            $log | Should -Contain 'GET /oidc/token Bearer request-token'
+            @($log | Where-Object { $_ -eq 'EXCHANGE github github-id-token:bolt-cache' }).Count | Should -Be 1
+            ($log | Where-Object { $_ -match '^GET /cache/[0-9a-f]+ Bearer short-lived-token$' }).Count | Should -Be 1
+            ($log | Where-Object { $_ -match '^PUT /cache/[0-9a-f]+ Bearer short-lived-token$' }).Count | Should -Be 1
+        }
+
+        It "Should exchange the GitLab CI_JOB_JWT_V2 token" {
+            $env:CI_JOB_JWT_V2 = 'gitlab-jwt'
+
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.ExitCode | Should -Be 0
+            $log = Get-Content -Path $script:RequestLog
+            $log | Should -Contain 'EXCHANGE gitlab gitlab-jwt'
+            ($log | Where-Object { $_ -match '^PUT /cache/[0-9a-f]+ Bearer short-lived-token$' }).Count | Should -Be 1
+        }
+
+        It "Should fall back to RemoteCacheToken outside CI" {
+            $env:BOLT_TEST_CACHE_TOKEN = 'secret-token'
+
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.ExitCode | Should -Be 0
+            $log = Get-Content -Path $script:RequestLog
+            $log | Should -Not -Match '^EXCHANGE'
+            ($log | Where-Object { $_ -match '^PUT /cache/[0-9a-f]+ Bearer secret-token$' }).Count | Should -Be 1
+        }
+
+        It "Should use no authentication outside CI without RemoteCacheToken" {
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.ExitCode | Should -Be 0
+            ($result.Output + $result.Error) | Should -Not -Match 'Ignoring remote cache settings'
+            (Get-Content -Path $script:RequestLog | Where-Object { $_ -match '^PUT /cache/[0-9a-f]+ $' }).Count | Should -Be 1
+        }
+
+        It "Should warn and use the local cache when the exchange is rejected" {
+            $env:CI_JOB_JWT_V2 = 'forged-token'
+
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match 'Checked sources'
+            ($result.Output + $result.Error) | Should -Match "OIDC token exchange at '.*/auth' failed"
+            ($result.Output + $result.Error) | Should -Not -Match 'forged-token'
+            Get-Content -Path $script:RequestLog | Should -Not -Match '^(GET|PUT) /cache/'
+            Test-Path -Path (Join-Path $script:TempTestRoot '.bolt/cache/check.json') | Should -BeTrue
+        }
+    }
+
+    Context "Invalid Settings" {
+        It "Should warn and use the local cache when RemoteCacheURL is not http" {
+            Initialize-RemoteCacheProject -Config @{
+                RemoteCacheURL = 'ftp://cache.example.com'
+            }
+
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.ExitCode | Should -Be 0
+            ($result.Output + $result.Error) | Should -Match 'Ignoring remote cache settings'
+        }
+
+        It "Should warn and use the local cache when <Case>" -ForEach @(
+            @{ Case = 'RemoteCacheAuth is unknown'; Settings = @{ RemoteCacheAuth = 'kerberos' }; Message = 'RemoteCacheAuth must be token or oidc' }
+            @{ Case = 'RemoteCacheAuthURL is missing'; Settings = @{ RemoteCacheAuth = 'oidc' }; Message = 'RemoteCacheAuthURL must start with http' }
+            @{ Case = 'RemoteCacheAuthURL is not https'; Settings = @{ RemoteCacheAuth = 'oidc'; RemoteCacheAuthURL = 'http://auth.example.com/exchange' }; Message = 'RemoteCacheAuthURL must use https' }
+            @{ Case = 'RemoteCacheURL is not https with oidc'; Settings = @{ RemoteCacheURL = 'http://cache.example.com'; RemoteCacheAuth = 'oidc'; RemoteCacheAuthURL = 'https://auth.example.com/exchange' }; Message = 'RemoteCacheURL must use https when RemoteCacheAuth is oidc' }
+        ) {
+            $config = @{ RemoteCacheURL = $script:ServerUrl }
+            foreach ($key in $Settings.Keys) {
+                $config[$key] = $Settings[$key]
+            }
+            Initialize-RemoteCacheProject -Config $config
+
+            $result = Invoke-Bolt -Arguments @('check')
+
+            $result.ExitCode | Should -Be 0
+            ($result.Output + $result.Error) | Should -Match "Ignoring remote cache settings in bolt.config.json: $Message"
+        }
+    }
+}
@@ -0,0 +1,223 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'RemoteInclude'
+    $script:ServedRoot = Join-Path -Path ([System.IO.Path]::GetTempPath()) -ChildPath "BoltRemoteIncludeServed_$(Get-Random)"
+    $script:RequestLog = Join-Path -Path $script:TempTestRoot -ChildPath 'requests.log'
+
+    # Helper function to publish a task file on the test server and return its URL and digest
+    function Publish-RemoteTask {
+        param(
+            [string]$Path,
+            [string]$Content
+        )
+
+        $fullPath = Join-Path -Path $script:ServedRoot -ChildPath $Path
+        New-Item -ItemType Directory -Path (Split-Path -Path $fullPath -Parent) -Force | Out-Null
+        Set-Content -Path $fullPath -Value $Content
+
+        return @{
+            Url    = "$($script:ServerUrl)/$Path"
+            Sha256 = (Get-FileHash -Path $fullPath -Algorithm SHA256).Hash.ToLowerInvariant()
+        }
+    }
+
+    # Helper function to write bolt.config.json with the given RemoteIncludes
+    function Set-RemoteIncludes {
+        param(
+            [hashtable[]]$Includes
+        )
+
+        @{ RemoteIncludes = $Includes } | ConvertTo-Json -Depth 5 | Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.config.json')
+    }
+
+    # Helper function to count the downloads of a served file
+    function Get-DownloadCount {
+        param(
+            [string]$Path
+        )
+
+        return @(Get-Content -Path $script:RequestLog -ErrorAction SilentlyContinue | Where-Object { $_ -eq "GET /$Path" }).Count
+    }
+
+    New-Item -ItemType Directory -Path $script:ServedRoot -Force | Out-Null
+
+    # Load the remote include functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('Get-RemoteIncludeNamespace', 'Get-RemoteIncludeFile') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+
+    # Start a small file server for the served directory that logs each request
+    $serverScript = Join-Path -Path $script:TempTestRoot -ChildPath 'file-server.ps1'
+    Set-Content -Path $serverScript -Value @'
+param([string]$Prefix, [string]$Root, [string]$LogPath)
+
+$listener = [System.Net.HttpListener]::new()
+$listener.Prefixes.Add($Prefix)
+$listener.Start()
+
+while ($listener.IsListening) {
+    $context = $listener.GetContext()
+    $request = $context.Request
+    $response = $context.Response
+
+    Add-Content -Path $LogPath -Value "$($request.HttpMethod) $($request.Url.AbsolutePath)"
+
+    $path = Join-Path -Path $Root -ChildPath $request.Url.AbsolutePath.TrimStart('/')
+    if (Test-Path -LiteralPath $path -PathType Leaf) {
+        $bytes = [System.IO.File]::ReadAllBytes($path)
+        $response.OutputStream.Write($bytes, 0, $bytes.Length)
+    } else {
+        $response.StatusCode = 404
+    }
+
+    $response.Close()
+}
+'@
+    Start-TestServer -ScriptPath $serverScript -Arguments @($script:ServedRoot, $script:RequestLog)
+}
+
+AfterAll {
+    Remove-TestProject -AdditionalPath $script:ServedRoot
+}
+
+Describe "Remote Include Namespaces" -Tag "Core", "RemoteInclude" {
+
+    It "Should use namespace '<Namespace>' for <Url>" -ForEach @(
+        @{ Url = 'https://tasks.example.com/ci/Invoke-Lint.ps1'; Namespace = 'tasks-example-com-ci' }
+        @{ Url = 'https://example.com/Invoke-Lint.ps1'; Namespace = 'example-com' }
+        @{ Url = 'http://localhost:8080/Team_A/Shared%20Tasks/Invoke-Lint.ps1'; Namespace = 'localhost-team-a-shared-tasks' }
+        @{ Url = 'https://RAW.Example.com/org/repo/v1.2/Invoke-Lint.ps1'; Namespace = 'raw-example-com-org-repo-v1-2' }
+    ) {
+        Get-RemoteIncludeNamespace -Url $Url | Should -Be $Namespace
+    }
+
+    It "Should reject <Case>" -ForEach @(
+        @{ Case = 'a non-http URL'; Url = 'ftp://example.com/Invoke-Lint.ps1'; Message = 'must be an http:// or https:// URL' }
+        @{ Case = 'plain http to another host'; Url = 'http://example.com/Invoke-Lint.ps1'; Message = 'must use https' }
+        @{ Case = 'a file that is not a task'; Url = 'https://example.com/lint.ps1'; Message = 'must name an Invoke-\*\.ps1 task file' }
+        @{ Case = 'a short digest'; Url = 'https://example.com/Invoke-Lint.ps1'; Sha256 = 'abc123'; Message = 'needs a Sha256 of 64 hex digits' }
+    ) {
+        $digest = if ($Sha256) { $Sha256 } else { '0' * 64 }
+        $thrown = $null
+        try {
+            Get-RemoteIncludeFile -Url $Url -Sha256 $digest -CacheRoot (Join-Path $script:TempTestRoot 'unit-cache')
+        } catch {
+            $thrown = $_
+        }
+
+        $thrown | Should -Not -BeNullOrEmpty
+        $thrown.FullyQualifiedErrorId | Should -Be 'InvalidRemoteInclude'
+        $thrown.Exception.Message | Should -Match $Message
+    }
+}
+
+Describe "Remote Includes" -Tag "Core", "RemoteInclude" {
+
+    BeforeEach {
+        foreach ($path in @('.build', '.bolt', 'bolt.config.json', 'requests.log')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+        Get-ChildItem -Path $script:ServedRoot -Force | Remove-Item -Recurse -Force
+
+        $script:Lint = Publish-RemoteTask -Path 'tasks/Invoke-Lint.ps1' -Content @'
+# TASK: lint
+# DESCRIPTION: Shared lint task
+# DEPENDS: format
+
+Write-Host "Ran remote lint"
+exit 0
+'@
+        $script:Format = Publish-RemoteTask -Path 'tasks/Invoke-Format.ps1' -Content @'
+# TASK: format
+# DESCRIPTION: Shared format task
+
+Write-Host "Ran remote format"
+exit 0
+'@
+    }
+
+    It "Should download, verify, and run tasks in the URL's namespace" {
+        Set-RemoteIncludes -Includes @($script:Lint, $script:Format)
+
+        $result = Invoke-Bolt -Arguments @('localhost-tasks-lint')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran remote format'
+        $result.Output | Should -Match 'Ran remote lint'
+        Join-Path $script:TempTestRoot '.bolt/remote/localhost-tasks/Invoke-Lint.ps1' | Should -Exist
+    }
+
+    It "Should use the cached copy on later runs" {
+        Set-RemoteIncludes -Includes @($script:Format)
+
+        (Invoke-Bolt -Arguments @('localhost-tasks-format')).ExitCode | Should -Be 0
+        $result = Invoke-Bolt -Arguments @('localhost-tasks-format')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran remote format'
+        Get-DownloadCount -Path 'tasks/Invoke-Format.ps1' | Should -Be 1
+    }
+
+    It "Should download again with -RefreshRemotes" {
+        Set-RemoteIncludes -Includes @($script:Format)
+
+        (Invoke-Bolt -Arguments @('localhost-tasks-format')).ExitCode | Should -Be 0
+        $result = Invoke-Bolt -Arguments @('localhost-tasks-format', '-RefreshRemotes')
+
+        $result.ExitCode | Should -Be 0
+        Get-DownloadCount -Path 'tasks/Invoke-Format.ps1' | Should -Be 2
+    }
+
+    It "Should download again when the Sha256 no longer matches the cached copy" {
+        Set-RemoteIncludes -Includes @($script:Format)
+        (Invoke-Bolt -Arguments @('localhost-tasks-format')).ExitCode | Should -Be 0
+
+        $updated = Publish-RemoteTask -Path 'tasks/Invoke-Format.ps1' -Content "# TASK: format`n# DESCRIPTION: Shared format task`n`nWrite-Host 'Ran remote format v2'`nexit 0"
+        Set-RemoteIncludes -Includes @($updated)
+        $result = Invoke-Bolt -Arguments @('localhost-tasks-format')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran remote format v2'
+        Get-DownloadCount -Path 'tasks/Invoke-Format.ps1' | Should -Be 2
+    }
+
+    It "Should fail with both digests when the download does not match" {
+        $expected = 'a' * 64
+        Set-RemoteIncludes -Includes @(@{ Url = $script:Format.Url; Sha256 = $expected })
+
+        $result = Invoke-Bolt -Arguments @('localhost-tasks-format')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match "failed SHA-256 verification: expected $expected, got $($script:Format.Sha256)"
+        $result.Output | Should -Not -Match 'Ran remote format'
+        Join-Path $script:TempTestRoot '.bolt/remote/localhost-tasks/Invoke-Format.ps1' | Should -Not -Exist
+    }
+
+    It "Should fail when a remote include cannot be downloaded" {
+        Set-RemoteIncludes -Includes @(@{ Url = "$($script:ServerUrl)/tasks/Invoke-Missing.ps1"; Sha256 = 'a' * 64 })
+
+        $result = Invoke-Bolt -Arguments @('-ListTasks')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match "Failed to download remote include '.*Invoke-Missing.ps1'"
+    }
+
+    It "Should list remote tasks with project tasks" {
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+        Set-Content -Path (Join-Path $buildPath 'Invoke-Build.ps1') -Value "# TASK: build`n# DESCRIPTION: Local build`nexit 0"
+        Set-RemoteIncludes -Includes @($script:Lint, $script:Format)
+
+        $result = Invoke-Bolt -Arguments @('-ListTasks', '-NoHeader')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'build'
+        $result.Output | Should -Match 'localhost-tasks-lint'
+        $result.Output | Should -Match 'localhost-tasks-format'
+    }
+}
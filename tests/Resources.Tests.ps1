@@ -0,0 +1,171 @@
+#Requires -Version 7.0
+
+BeforeDiscovery {
+    # Real cgroup tests need cgroup v2 and write access to /sys/fs/cgroup (usually root)
+    $canUseCgroups = $false
+    if ($IsLinux -and (Test-Path -Path '/sys/fs/cgroup/cgroup.controllers')) {
+        try {
+            New-Item -ItemType Directory -Path '/sys/fs/cgroup/bolt' -Force -ErrorAction Stop | Out-Null
+            $canUseCgroups = $true
+        } catch {
+            $canUseCgroups = $false
+        }
+    }
+}
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Resources'
+
+    # Load the resource limit functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('Get-TaskResourceLimits') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Resource Limit Metadata" -Tag "Core", "Resources" {
+
+    It "Should read CPU_QUOTA '<CpuQuota>' and MEMORY_LIMIT_MB '<MemoryLimitMb>'" -ForEach @(
+        @{ CpuQuota = '0.5'; MemoryLimitMb = '512'; ExpectedCpu = 0.5; ExpectedMemory = 512 }
+        @{ CpuQuota = '1'; MemoryLimitMb = ''; ExpectedCpu = 1.0; ExpectedMemory = $null }
+        @{ CpuQuota = ''; MemoryLimitMb = '64'; ExpectedCpu = $null; ExpectedMemory = 64 }
+    ) {
+        $limits = Get-TaskResourceLimits -TaskInfo @{ Names = @('build'); Resources = @{ CpuQuota = $CpuQuota; MemoryLimitMb = $MemoryLimitMb } }
+
+        $limits.CpuQuota | Should -Be $ExpectedCpu
+        $limits.MemoryLimitMb | Should -Be $ExpectedMemory
+    }
+
+    It "Should reject CPU_QUOTA '<CpuQuota>' and MEMORY_LIMIT_MB '<MemoryLimitMb>'" -ForEach @(
+        @{ CpuQuota = '0'; MemoryLimitMb = ''; Message = 'CPU_QUOTA must be a number greater than 0 and at most 1.0: 0' }
+        @{ CpuQuota = '1.5'; MemoryLimitMb = ''; Message = 'CPU_QUOTA must be a number greater than 0 and at most 1.0: 1.5' }
+        @{ CpuQuota = 'half'; MemoryLimitMb = ''; Message = 'CPU_QUOTA must be a number' }
+        @{ CpuQuota = ''; MemoryLimitMb = '0'; Message = 'MEMORY_LIMIT_MB must be a whole number of megabytes greater than 0: 0' }
+        @{ CpuQuota = ''; MemoryLimitMb = '1.5'; Message = 'MEMORY_LIMIT_MB must be a whole number' }
+        @{ CpuQuota = ''; MemoryLimitMb = '512MB'; Message = 'MEMORY_LIMIT_MB must be a whole number' }
+    ) {
+        $thrown = $null
+        try {
+            Get-TaskResourceLimits -TaskInfo @{ Names = @('build'); Resources = @{ CpuQuota = $CpuQuota; MemoryLimitMb = $MemoryLimitMb } }
+        } catch {
+            $thrown = $_
+        }
+
+        $thrown | Should -Not -BeNullOrEmpty
+        $thrown.FullyQualifiedErrorId | Should -Be 'InvalidResourceLimit'
+        $thrown.Exception.Message | Should -Match ([regex]::Escape($Message))
+    }
+}
+
+Describe "Resource Limited Tasks" -Tag "Core", "Resources" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        $script:CgroupRoot = Join-Path -Path $script:TempTestRoot -ChildPath "cgroup-$(Get-Random)"
+        $env:BOLT_CGROUP_ROOT = $script:CgroupRoot
+    }
+
+    AfterEach {
+        Remove-Item -Path Env:\BOLT_CGROUP_ROOT -ErrorAction SilentlyContinue
+    }
+
+    It "Should stop before any task runs when a limit is invalid" {
+        New-TestTask -Name 'compile'
+        New-TestTask -Name 'package' -Depends @('compile') -ExtraMetadata '# CPU_QUOTA: 2'
+
+        $result = Invoke-Bolt -Arguments @('package')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match "Task 'package': CPU_QUOTA must be a number greater than 0 and at most 1.0: 2"
+        $result.Output | Should -Not -Match 'Ran compile'
+    }
+
+    It "Should report invalid limits in -ValidateTasks" {
+        New-TestTask -Name 'package' -ExtraMetadata '# MEMORY_LIMIT_MB: lots'
+
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match 'RESOURCES'
+        $result.Output | Should -Match 'MEMORY_LIMIT_MB must be a whole number'
+    }
+
+    Context "Linux" -Skip:(-not $IsLinux) {
+
+        It "Should write cpu.max and memory.max and start the task in the cgroup" {
+            New-TestTask -Name 'limited' -ExtraMetadata "# CPU_QUOTA: 0.5`n# MEMORY_LIMIT_MB: 256" -Body 'Set-Content -Path (Join-Path $PSScriptRoot "../pid.txt") -Value $PID'
+
+            $result = Invoke-Bolt -Arguments @('limited')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match 'Ran limited'
+            $cgroup = Join-Path -Path $script:CgroupRoot -ChildPath 'limited'
+            (Get-Content -Path (Join-Path $cgroup 'cpu.max') -Raw).Trim() | Should -Be '50000 100000'
+            (Get-Content -Path (Join-Path $cgroup 'memory.max') -Raw).Trim() | Should -Be '268435456'
+            (Get-Content -Path (Join-Path $cgroup 'cgroup.procs') -Raw).Trim() | Should -Be (Get-Content -Path (Join-Path $script:TempTestRoot 'pid.txt') -Raw).Trim()
+        }
+
+        It "Should only write the limits that are set" {
+            New-TestTask -Name 'limited' -ExtraMetadata '# MEMORY_LIMIT_MB: 64'
+
+            $result = Invoke-Bolt -Arguments @('limited', '-Parallel')
+
+            $result.ExitCode | Should -Be 0
+            $cgroup = Join-Path -Path $script:CgroupRoot -ChildPath 'limited'
+            (Get-Content -Path (Join-Path $cgroup 'memory.max') -Raw).Trim() | Should -Be '67108864'
+            Test-Path -Path (Join-Path $cgroup 'cpu.max') | Should -BeFalse
+        }
+
+        It "Should fail the task with a clear message when the cgroup cannot be created" {
+            $env:BOLT_CGROUP_ROOT = '/proc/bolt-not-writable'
+            New-TestTask -Name 'limited' -ExtraMetadata '# CPU_QUOTA: 0.25'
+
+            $result = Invoke-Bolt -Arguments @('limited', '-OutputFormat', 'Json')
+
+            $result.ExitCode | Should -Be 1
+            $summary = $result.Output | ConvertFrom-Json
+            $summary.Tasks[0].Status | Should -Be 'failure'
+            $summary.Tasks[0].Stderr | Should -Match "Cannot set the CPU and memory limits of task 'limited'"
+            $summary.Tasks[0].Stderr | Should -Match 'BOLT_CGROUP_ROOT'
+        }
+
+        It "Should run the task in /sys/fs/cgroup/bolt/<task> by default" -Skip:(-not $canUseCgroups) {
+            Remove-Item -Path Env:\BOLT_CGROUP_ROOT -ErrorAction SilentlyContinue
+            New-TestTask -Name 'limited' -ExtraMetadata '# CPU_QUOTA: 0.5' -Body 'Get-Content -Path /proc/self/cgroup | Set-Content -Path (Join-Path $PSScriptRoot "../cgroup.txt")'
+
+            $result = Invoke-Bolt -Arguments @('limited')
+
+            $result.ExitCode | Should -Be 0
+            Get-Content -Path (Join-Path $script:TempTestRoot 'cgroup.txt') -Raw | Should -Match '/bolt/limited'
+            Test-Path -Path '/sys/fs/cgroup/bolt/limited' | Should -BeFalse
+        }
+    }
+
+    Context "Windows and macOS" -Skip:$IsLinux {
+
+        It "Should run the task without limits" {
+            New-TestTask -Name 'limited' -ExtraMetadata '# CPU_QUOTA: 0.5'
+
+            $result = Invoke-Bolt -Arguments @('limited')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match 'Ran limited'
+        }
+
+        It "Should fail the task with -StrictResources" {
+            New-TestTask -Name 'limited' -ExtraMetadata '# CPU_QUOTA: 0.5'
+
+            $result = Invoke-Bolt -Arguments @('limited', '-StrictResources', '-OutputFormat', 'Json')
+
+            $result.ExitCode | Should -Be 1
+            ($result.Output | ConvertFrom-Json).Tasks[0].Stderr | Should -Match 'only work on Linux or in a # CONTAINER: task'
+        }
+    }
+}
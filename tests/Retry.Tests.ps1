@@ -0,0 +1,225 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Retry'
+
+    # Load the retry helpers from bolt.ps1 without running the script
+    $boltAst = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $retryFunctions = $boltAst.FindAll({
+        param($node)
+        $node -is [System.Management.Automation.Language.FunctionDefinitionAst]
+    }, $true) | Where-Object { $_.Name -in @('ConvertFrom-Duration', 'Get-TaskRetryPolicy', 'Get-TaskRetryDelay') }
+    foreach ($functionAst in $retryFunctions) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+
+    # Task body that fails until it has run -SucceedOn times, counting runs in a file
+    function Get-FlakyBody {
+        param(
+            [int]$SucceedOn
+        )
+
+        return @"
+`$counterPath = Join-Path `$BoltConfig.ProjectRoot 'attempts.txt'
+`$count = if (Test-Path `$counterPath) { [int](Get-Content `$counterPath) } else { 0 }
+`$count++
+Set-Content -Path `$counterPath -Value `$count
+if (`$count -lt $SucceedOn) { exit 7 }
+exit 0
+"@
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Retry Settings" -Tag "Core", "Retry" {
+
+    It "Should default to a single attempt without RETRY" {
+        $policy = Get-TaskRetryPolicy -TaskInfo @{ Retry = '' }
+
+        $policy.Attempts | Should -Be 1
+    }
+
+    It "Should parse '<Retry>'" -ForEach @(
+        @{ Retry = '3'; Attempts = 3; DelayMs = 1000; Backoff = 2.0 }
+        @{ Retry = 'attempts=5'; Attempts = 5; DelayMs = 1000; Backoff = 2.0 }
+        @{ Retry = 'attempts=4, delay=250ms, backoff=1.5'; Attempts = 4; DelayMs = 250; Backoff = 1.5 }
+        @{ Retry = '2, delay=2s'; Attempts = 2; DelayMs = 2000; Backoff = 2.0 }
+        @{ Retry = 'Attempts = 10, Backoff = 1'; Attempts = 10; DelayMs = 1000; Backoff = 1.0 }
+    ) {
+        $policy = Get-TaskRetryPolicy -TaskInfo @{ Retry = $Retry }
+
+        $policy.Attempts | Should -Be $Attempts
+        $policy.Delay.TotalMilliseconds | Should -Be $DelayMs
+        $policy.BackoffFactor | Should -Be $Backoff
+    }
+
+    It "Should reject '<Retry>'" -ForEach @(
+        @{ Retry = '0' }
+        @{ Retry = '11' }
+        @{ Retry = 'attempts=many' }
+        @{ Retry = 'delay=soon' }
+        @{ Retry = 'backoff=0.5' }
+        @{ Retry = 'backoff=fast' }
+        @{ Retry = 'jitter=1s' }
+        @{ Retry = 'three times' }
+    ) {
+        { Get-TaskRetryPolicy -TaskInfo @{ Retry = $Retry } } | Should -Throw
+    }
+
+    It "Should multiply the delay by the backoff factor after each attempt" {
+        $policy = Get-TaskRetryPolicy -TaskInfo @{ Retry = 'attempts=4, delay=100ms, backoff=3' }
+
+        (Get-TaskRetryDelay -Policy $policy -Attempt 1).TotalMilliseconds | Should -Be 100
+        (Get-TaskRetryDelay -Policy $policy -Attempt 2).TotalMilliseconds | Should -Be 300
+        (Get-TaskRetryDelay -Policy $policy -Attempt 3).TotalMilliseconds | Should -Be 900
+    }
+}
+
+Describe "Task Retry" -Tag "Core", "Retry" {
+
+    BeforeEach {
+        foreach ($path in @('.build', '.bolt', 'src', 'attempts.txt')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+    }
+
+    Context "Sequential Runs" {
+        It "Should run a flaky task again until it succeeds" {
+            New-TestTask -Name 'flaky' -Body (Get-FlakyBody -SucceedOn 3) -ExtraMetadata '# RETRY: attempts=3, delay=10ms'
+
+            $result = Invoke-Bolt -Arguments @('flaky')
+
+            $result.ExitCode | Should -Be 0
+            Get-Content -Path (Join-Path $script:TempTestRoot 'attempts.txt') | Should -Be 3
+            $result.Output | Should -Match "attempt 1 of 3 failed with exit code 7 \(\d+\.\ds\), retrying in 0\.0s"
+            $result.Output | Should -Match "attempt 2 of 3 failed with exit code 7"
+            $result.Output | Should -Match "attempt 3 of 3 succeeded \(\d+\.\ds\)"
+        }
+
+        It "Should fail with the last exit code when every attempt fails" {
+            New-TestTask -Name 'broken' -Body 'exit 5' -ExtraMetadata '# RETRY: attempts=2, delay=10ms'
+
+            $summary = (Invoke-Bolt -Arguments @('broken', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            $summary.Success | Should -BeFalse
+            $summary.Tasks[0].Status | Should -Be 'failure'
+            $summary.Tasks[0].ExitCode | Should -Be 5
+            $summary.Tasks[0].Attempts | Should -Be 2
+        }
+
+        It "Should record the number of attempts in the JSON summary" {
+            New-TestTask -Name 'flaky' -Body (Get-FlakyBody -SucceedOn 2) -ExtraMetadata '# RETRY: attempts=5, delay=10ms'
+
+            $summary = (Invoke-Bolt -Arguments @('flaky', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            $summary.Tasks[0].Status | Should -Be 'success'
+            $summary.Tasks[0].Attempts | Should -Be 2
+        }
+
+        It "Should wait longer after each failed attempt" {
+            New-TestTask -Name 'broken' -Body 'exit 1' -ExtraMetadata '# RETRY: attempts=3, delay=300ms, backoff=2'
+
+            $stopwatch = [System.Diagnostics.Stopwatch]::StartNew()
+            $result = Invoke-Bolt -Arguments @('broken')
+            $stopwatch.Stop()
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match 'retrying in 0\.3s'
+            $result.Output | Should -Match 'retrying in 0\.6s'
+            $stopwatch.ElapsedMilliseconds | Should -BeGreaterOrEqual 900
+        }
+
+        It "Should run hooks once around all attempts" {
+            New-TestTask -Name 'flaky' -Body (Get-FlakyBody -SucceedOn 3) -ExtraMetadata "# RETRY: attempts=3, delay=10ms`n# BEFORE: Write-Host 'Before hook ran'`n# AFTER: Write-Host 'After hook ran'"
+
+            $result = Invoke-Bolt -Arguments @('flaky')
+
+            $result.ExitCode | Should -Be 0
+            ([regex]::Matches($result.Output, 'Before hook ran')).Count | Should -Be 1
+            ([regex]::Matches($result.Output, 'After hook ran')).Count | Should -Be 1
+        }
+
+        It "Should not run a dependent task when every attempt fails" {
+            New-TestTask -Name 'broken' -Body 'exit 1' -ExtraMetadata '# RETRY: attempts=2, delay=10ms'
+            New-TestTask -Name 'deploy' -Depends @('broken')
+
+            $result = Invoke-Bolt -Arguments @('deploy')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Not -Match 'Ran deploy'
+        }
+
+        It "Should fail a task with an invalid RETRY without running it" {
+            New-TestTask -Name 'bad' -ExtraMetadata '# RETRY: attempts=20'
+
+            $result = Invoke-Bolt -Arguments @('bad')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match "invalid RETRY: RETRY attempts must be a number from 1 to 10"
+            $result.Output | Should -Not -Match 'Ran bad'
+        }
+    }
+
+    Context "Caching" {
+        It "Should only write the cache after a successful attempt" {
+            New-Item -ItemType Directory -Path (Join-Path $script:TempTestRoot 'src') -Force | Out-Null
+            Set-Content -Path (Join-Path $script:TempTestRoot 'src/input.txt') -Value 'data'
+            New-TestTask -Name 'broken' -Body 'exit 1' -ExtraMetadata "# RETRY: attempts=2, delay=10ms`n# INPUTS: src/*.txt"
+
+            (Invoke-Bolt -Arguments @('broken')).ExitCode | Should -Be 1
+
+            Test-Path -Path (Join-Path $script:TempTestRoot '.bolt/cache/broken.json') | Should -BeFalse
+        }
+
+        It "Should cache a task that succeeded after a retry" {
+            New-Item -ItemType Directory -Path (Join-Path $script:TempTestRoot 'src') -Force | Out-Null
+            Set-Content -Path (Join-Path $script:TempTestRoot 'src/input.txt') -Value 'data'
+            New-TestTask -Name 'flaky' -Body (Get-FlakyBody -SucceedOn 2) -ExtraMetadata "# RETRY: attempts=3, delay=10ms`n# INPUTS: src/*.txt"
+
+            (Invoke-Bolt -Arguments @('flaky')).ExitCode | Should -Be 0
+            $result = Invoke-Bolt -Arguments @('flaky')
+
+            $result.Output | Should -Match "Task 'flaky' is up to date \(CACHED\)"
+        }
+    }
+
+    Context "Parallel Runs" {
+        It "Should retry a flaky task with -Parallel" {
+            New-TestTask -Name 'flaky' -Body (Get-FlakyBody -SucceedOn 3) -ExtraMetadata '# RETRY: attempts=3, delay=10ms'
+            New-TestTask -Name 'steady'
+
+            $result = Invoke-Bolt -Arguments @('flaky', 'steady', '-Parallel')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match '\[flaky\]\s+attempt 1 of 3 failed with exit code 7'
+            $result.Output | Should -Match '\[flaky\]\s+started attempt 3 of 3'
+            $result.Output | Should -Match '\[flaky\]\s+completed \(\d+\.\ds, attempt 3 of 3\)'
+        }
+
+        It "Should report the attempts of a parallel task in the JSON summary" {
+            New-TestTask -Name 'broken' -Body 'exit 4' -ExtraMetadata '# RETRY: attempts=2, delay=10ms'
+
+            $summary = (Invoke-Bolt -Arguments @('broken', '-Parallel', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+            $summary.Tasks[0].Status | Should -Be 'failure'
+            $summary.Tasks[0].ExitCode | Should -Be 4
+            $summary.Tasks[0].Attempts | Should -Be 2
+        }
+    }
+
+    Context "Validation" {
+        It "Should report an invalid RETRY with -ValidateTasks" {
+            New-TestTask -Name 'bad' -ExtraMetadata '# RETRY: backoff=0'
+
+            $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+            $result.ExitCode | Should -Be 1
+            $result.Output | Should -Match 'RETRY'
+            $result.Output | Should -Match 'backoff must be a number of at least 1'
+        }
+    }
+}
@@ -0,0 +1,125 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Rollback'
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Rollback" -Tag "Core", "Rollback" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should run rollback commands in reverse order when the task fails" {
+        New-TestTask -Name 'deploy' -Body 'exit 2' -ExtraMetadata "# ROLLBACK: Write-Host 'undo upload'`n# ROLLBACK: Write-Host 'undo release'"
+
+        $result = Invoke-Bolt -Arguments @('deploy', '-ErrorAction', 'Continue')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match '(?s)Ran deploy.*Running rollback: Write-Host ''undo release''.*undo release.*undo upload'
+    }
+
+    It "Should not run rollback commands when the task succeeds" {
+        New-TestTask -Name 'deploy' -ExtraMetadata "# ROLLBACK: Write-Host 'undo upload'"
+
+        $result = Invoke-Bolt -Arguments @('deploy', '-OutputFormat', 'Json')
+
+        $result.ExitCode | Should -Be 0
+        $summary = $result.Output | ConvertFrom-Json
+        $summary.Tasks[0].RollbackStatus | Should -Be ''
+        (Invoke-Bolt -Arguments @('deploy')).Output | Should -Not -Match 'undo upload'
+    }
+
+    It "Should not run rollback commands when the task is skipped" {
+        New-TestTask -Name 'deploy' -Body 'exit 1' -ExtraMetadata "# WHEN: exists('missing.txt')`n# ROLLBACK: Write-Host 'undo upload'"
+
+        $result = Invoke-Bolt -Arguments @('deploy')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'SKIPPED\(condition\)'
+        $result.Output | Should -Not -Match 'undo upload'
+    }
+
+    It "Should keep rolling back after a rollback command fails" {
+        New-TestTask -Name 'deploy' -Body 'exit 1' -ExtraMetadata "# ROLLBACK: Write-Host 'undo upload'`n# ROLLBACK: throw 'cannot reach server'`n# ROLLBACK: exit 4"
+
+        $result = Invoke-Bolt -Arguments @('deploy', '-ErrorAction', 'Continue')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match "rollback 'exit 4' exited with code 4"
+        $result.Output | Should -Match "rollback 'throw 'cannot reach server'' exited with code 1"
+        $result.Output | Should -Match 'undo upload'
+    }
+
+    It "Should report RollbackStatus in JSON results" {
+        New-TestTask -Name 'clean' -Body 'exit 1' -ExtraMetadata "# ROLLBACK: Write-Host 'restored'"
+        New-TestTask -Name 'broken' -Body 'exit 1' -ExtraMetadata "# ROLLBACK: exit 3"
+        New-TestTask -Name 'plain' -Body 'exit 1'
+
+        $clean = (Invoke-Bolt -Arguments @('clean', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+        $broken = (Invoke-Bolt -Arguments @('broken', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+        $plain = (Invoke-Bolt -Arguments @('plain', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+        $clean.Tasks[0].Status | Should -Be 'failure'
+        $clean.Tasks[0].RollbackStatus | Should -Be 'success'
+        $broken.Tasks[0].RollbackStatus | Should -Be 'failure'
+        $plain.Tasks[0].RollbackStatus | Should -Be ''
+    }
+
+    It "Should run rollback commands with the task's environment in a new process" {
+        $body = "`$env:BOLT_ROLLBACK_LEAK = 'set by task'`nexit 1"
+        New-TestTask -Name 'deploy' -Body $body -ExtraMetadata "# ENV: DEPLOY_TARGET=staging`n# ROLLBACK: Write-Host `"target=`$env:DEPLOY_TARGET leak=[`$env:BOLT_ROLLBACK_LEAK]`""
+
+        $result = Invoke-Bolt -Arguments @('deploy', '-ErrorAction', 'Continue')
+
+        $result.Output | Should -Match 'target=staging leak=\[\]'
+    }
+
+    It "Should replace `${NAME} placeholders in rollback commands" {
+        New-TestTask -Name 'deploy' -Body 'exit 1' -ExtraMetadata "# ENV: RELEASE=v1.2.3`n# ROLLBACK: Write-Host 'deleting `${RELEASE}'"
+
+        $result = Invoke-Bolt -Arguments @('deploy', '-ErrorAction', 'Continue')
+
+        $result.Output | Should -Match 'deleting v1\.2\.3'
+    }
+
+    It "Should roll back failed tasks but not cancelled tasks in -Parallel runs" {
+        New-TestTask -Name 'fast' -Body 'exit 1' -ExtraMetadata "# ROLLBACK: Write-Host 'undo fast'"
+        New-TestTask -Name 'slow' -Body 'Start-Sleep -Seconds 10' -ExtraMetadata "# ROLLBACK: Write-Host 'undo slow'"
+        New-TestTask -Name 'all' -Depends @('fast', 'slow')
+
+        $result = Invoke-Bolt -Arguments @('all', '-Parallel')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match '\[fast\]\s+undo fast'
+        $result.Output | Should -Match '\[slow\]\s+cancelled'
+        $result.Output | Should -Not -Match 'undo slow'
+    }
+
+    It "Should keep timing out running tasks while a rollback runs in -Parallel runs" {
+        New-TestTask -Name 'fast' -Body 'exit 1' -ExtraMetadata "# ROLLBACK: Start-Sleep -Seconds 5"
+        New-TestTask -Name 'slow' -Body 'Start-Sleep -Seconds 30' -ExtraMetadata '# TIMEOUT: 1s'
+        New-TestTask -Name 'all' -Depends @('fast', 'slow')
+
+        $result = Invoke-Bolt -Arguments @('all', '-Parallel', '-NoFailFast', '-OutputFormat', 'Json')
+        $slow = ($result.Output | ConvertFrom-Json).Tasks | Where-Object { $_.Name -eq 'slow' }
+
+        $slow.Status | Should -Be 'timeout'
+        $slow.DurationMs | Should -BeLessThan 4000
+    }
+
+    It "Should list rollback commands in run order in the dry run plan" {
+        New-TestTask -Name 'deploy' -ExtraMetadata "# ROLLBACK: Write-Host 'undo upload'`n# ROLLBACK: Write-Host 'undo release'"
+
+        $result = Invoke-Bolt -Arguments @('deploy', '-DryRun')
+        $result.Output | Should -Match "(?s)Rollback: Write-Host 'undo release'.*Rollback: Write-Host 'undo upload'"
+
+        $plan = (Invoke-Bolt -Arguments @('deploy', '-DryRun', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+        $plan.Tasks[0].Rollback | Should -Be @("Write-Host 'undo release'", "Write-Host 'undo upload'")
+    }
+}
@@ -0,0 +1,236 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Secret'
+
+    # Helper function to write a value to a file for -Secret Set to read on stdin
+    function New-SecretInput {
+        param([string]$Value)
+
+        $path = Join-Path -Path $script:TempTestRoot -ChildPath 'secret-input.txt'
+        [System.IO.File]::WriteAllText($path, $Value)
+        return $path
+    }
+
+    # A fake secret-tool that keeps secrets as files, one per service and account
+    $script:FakeBinPath = Join-Path -Path $script:TempTestRoot -ChildPath 'fake-bin'
+    $script:KeyringPath = Join-Path -Path $script:TempTestRoot -ChildPath 'keyring'
+    New-Item -ItemType Directory -Path $script:FakeBinPath, $script:KeyringPath -Force | Out-Null
+    $fakeSecretTool = Join-Path -Path $script:FakeBinPath -ChildPath 'secret-tool'
+    Set-Content -Path $fakeSecretTool -Value @"
+#!/bin/sh
+command="`$1"; shift
+[ "`$command" = "store" ] && shift
+file="$($script:KeyringPath)/`$2.`$4"
+case "`$command" in
+    store) cat > "`$file" ;;
+    lookup) [ -f "`$file" ] || exit 1; cat "`$file" ;;
+    clear) rm -f "`$file" ;;
+esac
+"@
+    & chmod +x $fakeSecretTool
+    $script:OriginalPath = $env:PATH
+
+    # Load the masking function for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('Protect-SecretText') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    $env:PATH = $script:OriginalPath
+    Remove-TestProject
+}
+
+Describe "Secret Masking" -Tag "Core", "Secret" {
+
+    It "Should replace every secret value with ***" {
+        Protect-SecretText -Text 'user=admin token=s3cr3t again s3cr3t' -Secret @('s3cr3t', 'admin') |
+            Should -Be 'user=*** token=*** again ***'
+    }
+
+    It "Should mask the longest secret first" {
+        Protect-SecretText -Text 'key=abc123' -Secret @('abc', 'abc123') | Should -Be 'key=***'
+    }
+
+    It "Should ignore empty secrets" {
+        Protect-SecretText -Text 'nothing to hide' -Secret @('') | Should -Be 'nothing to hide'
+    }
+}
+
+Describe "Secret Commands" -Tag "Core", "Secret" -Skip:(-not $IsLinux) {
+
+    BeforeEach {
+        $env:PATH = "$($script:FakeBinPath)$([System.IO.Path]::PathSeparator)$($script:OriginalPath)"
+        Remove-Item -Path (Join-Path -Path $script:KeyringPath -ChildPath '*') -Force -ErrorAction SilentlyContinue
+    }
+
+    AfterEach {
+        $env:PATH = $script:OriginalPath
+    }
+
+    It "Should set, get, and delete a secret" {
+        $set = Invoke-Bolt -Arguments @('-Secret', 'Set', '-Key', 'DEPLOY_TOKEN') -InputFile (New-SecretInput -Value 'hunter2')
+        $set.ExitCode | Should -Be 0
+        $set.Output | Should -Match "Secret 'DEPLOY_TOKEN' stored in the secret-tool keychain"
+        Get-Content -Path (Join-Path -Path $script:KeyringPath -ChildPath 'bolt.DEPLOY_TOKEN') -Raw | Should -Be 'hunter2'
+
+        $get = Invoke-Bolt -Arguments @('-Secret', 'Get', '-Key', 'DEPLOY_TOKEN')
+        $get.ExitCode | Should -Be 0
+        $get.Output.Trim() | Should -Be 'hunter2'
+
+        $delete = Invoke-Bolt -Arguments @('-Secret', 'Delete', '-Key', 'DEPLOY_TOKEN')
+        $delete.ExitCode | Should -Be 0
+        $delete.Output | Should -Match "Secret 'DEPLOY_TOKEN' deleted"
+
+        $missing = Invoke-Bolt -Arguments @('-Secret', 'Get', '-Key', 'DEPLOY_TOKEN')
+        $missing.ExitCode | Should -Be 1
+        $missing.Error | Should -Match "Secret 'DEPLOY_TOKEN' is not set"
+    }
+
+    It "Should work outside a project" {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+
+        $result = Invoke-Bolt -Arguments @('-Secret', 'Delete', '-Key', 'NEVER_SET')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match "Secret 'NEVER_SET' is not set"
+    }
+
+    It "Should reject an empty value" {
+        $result = Invoke-Bolt -Arguments @('-Secret', 'Set', '-Key', 'EMPTY') -InputFile (New-SecretInput -Value '')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match "cannot be empty"
+    }
+
+    It "Should reject a key that is not an environment variable name" {
+        $result = Invoke-Bolt -Arguments @('-Secret', 'Get', '-Key', 'not-a-name')
+
+        $result.ExitCode | Should -Not -Be 0
+    }
+
+    It "Should explain how to install secret-tool when it is missing" {
+        $env:PATH = $script:OriginalPath -split [System.IO.Path]::PathSeparator |
+            Where-Object { -not (Test-Path -Path (Join-Path -Path $_ -ChildPath 'secret-tool')) } |
+            Join-String -Separator ([System.IO.Path]::PathSeparator)
+
+        $result = Invoke-Bolt -Arguments @('-Secret', 'Get', '-Key', 'DEPLOY_TOKEN')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'Secrets need secret-tool, but it was not found on PATH'
+    }
+}
+
+Describe "Secret Environment" -Tag "Core", "Secret" -Skip:(-not $IsLinux) {
+
+    BeforeAll {
+        $env:PATH = "$($script:FakeBinPath)$([System.IO.Path]::PathSeparator)$($script:OriginalPath)"
+        Invoke-Bolt -Arguments @('-Secret', 'Set', '-Key', 'DEPLOY_TOKEN') -InputFile (New-SecretInput -Value 'hunter2') | Out-Null
+    }
+
+    AfterAll {
+        $env:PATH = $script:OriginalPath
+    }
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'token.txt') -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should set # SECRET_ENV: secrets in the task environment" {
+        New-TestTask -Name 'deploy' -ExtraMetadata '# SECRET_ENV: DEPLOY_TOKEN' -Body 'Set-Content -Path (Join-Path $PSScriptRoot "../token.txt") -Value $env:DEPLOY_TOKEN'
+
+        $result = Invoke-Bolt -Arguments @('deploy')
+
+        $result.ExitCode | Should -Be 0
+        (Get-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'token.txt') -Raw).Trim() | Should -Be 'hunter2'
+    }
+
+    It "Should not leave secrets in the Bolt process environment" {
+        New-TestTask -Name 'deploy' -ExtraMetadata '# SECRET_ENV: DEPLOY_TOKEN'
+        New-TestTask -Name 'report' -Depends @('deploy') -Body 'Set-Content -Path (Join-Path $PSScriptRoot "../token.txt") -Value "[$env:DEPLOY_TOKEN]"'
+
+        $result = Invoke-Bolt -Arguments @('report')
+
+        $result.ExitCode | Should -Be 0
+        (Get-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'token.txt') -Raw).Trim() | Should -Be '[]'
+    }
+
+    It "Should mask secret values in the output of a plain serial run" {
+        New-TestTask -Name 'deploy' -ExtraMetadata '# SECRET_ENV: DEPLOY_TOKEN' -Body 'Write-Host "token is $env:DEPLOY_TOKEN"; Write-Output "again $env:DEPLOY_TOKEN"'
+
+        $result = Invoke-Bolt -Arguments @('deploy')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'token is \*\*\*'
+        $result.Output | Should -Match 'again \*\*\*'
+        $result.Output | Should -Not -Match 'hunter2'
+    }
+
+    It "Should mask secret values in task output and keep secret names out of the JSON summary" {
+        New-TestTask -Name 'deploy' -ExtraMetadata '# SECRET_ENV: DEPLOY_TOKEN' -Body '[Console]::Error.WriteLine("token is $env:DEPLOY_TOKEN"); exit 1'
+
+        $result = Invoke-Bolt -Arguments @('deploy', '-OutputFormat', 'Json')
+
+        $result.ExitCode | Should -Be 1
+        $summary = $result.Output | ConvertFrom-Json
+        $summary.Tasks[0].Stderr | Should -Match 'token is \*\*\*'
+        $result.Output | Should -Not -Match 'hunter2'
+        $result.Output | Should -Not -Match 'DEPLOY_TOKEN'
+    }
+
+    It "Should keep secret names and values out of debug logs" {
+        New-TestTask -Name 'deploy' -ExtraMetadata '# SECRET_ENV: DEPLOY_TOKEN' -Body 'Write-Host "token is $env:DEPLOY_TOKEN"'
+
+        $result = Invoke-Bolt -Arguments @('deploy', '-Parallel', '-LogLevel', 'Debug', '-LogFormat', 'Json')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'token is \*\*\*'
+        $result.Output | Should -Not -Match 'hunter2'
+        $result.Error | Should -Match 'process start'
+        $result.Error | Should -Not -Match 'DEPLOY_TOKEN'
+        $result.Error | Should -Not -Match 'hunter2'
+    }
+
+    It "Should not cache a task whose output variable holds a secret" {
+        Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'src.txt') -Value 'source'
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.bolt') -Recurse -Force -ErrorAction SilentlyContinue
+        New-TestTask -Name 'deploy' -ExtraMetadata "# SECRET_ENV: DEPLOY_TOKEN`n# OUTPUT_VARS: SESSION`n# INPUTS: src.txt" -Body 'Write-Output "::set-output name=SESSION::session-$env:DEPLOY_TOKEN"'
+
+        $first = Invoke-Bolt -Arguments @('deploy')
+        $second = Invoke-Bolt -Arguments @('deploy')
+
+        $first.ExitCode | Should -Be 0
+        $second.Output | Should -Not -Match 'CACHED'
+        $cachePath = Join-Path -Path $script:TempTestRoot -ChildPath '.bolt/cache'
+        if (Test-Path -Path $cachePath) {
+            Get-ChildItem -Path $cachePath -File -Recurse | Get-Content -Raw | Should -Not -Match 'hunter2'
+        }
+    }
+
+    It "Should fail a task whose secret is not set" {
+        New-TestTask -Name 'deploy' -ExtraMetadata '# SECRET_ENV: MISSING_TOKEN'
+
+        $result = Invoke-Bolt -Arguments @('deploy', '-OutputFormat', 'Json')
+
+        $result.ExitCode | Should -Be 1
+        $summary = $result.Output | ConvertFrom-Json
+        $summary.Tasks[0].Status | Should -Be 'failure'
+        $summary.Tasks[0].Stderr | Should -Match 'needs a # SECRET_ENV: secret that is not set'
+        $result.Output | Should -Not -Match 'MISSING_TOKEN'
+    }
+
+    It "Should report invalid # SECRET_ENV: names in -ValidateTasks" {
+        New-TestTask -Name 'deploy' -ExtraMetadata '# SECRET_ENV: DEPLOY-TOKEN'
+
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match 'SECRET_ENV'
+    }
+}
@@ -0,0 +1,146 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'SharedVars'
+
+    # Load the shared variable functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('New-SharedVariableStore', 'ConvertFrom-SetOutputLine', 'Get-TaskInputVarProducers', 'Resolve-TaskDependency') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Shared Variable Store" -Tag "Core", "SharedVars" {
+
+    It "Should return the value and producer of a set variable" {
+        $store = New-SharedVariableStore
+        $store.Set('image', 'IMAGE_TAG', 'v1.2.3')
+
+        $store.Get('IMAGE_TAG') | Should -Be 'v1.2.3'
+        $store.Producers['IMAGE_TAG'] | Should -Be 'image'
+        $store.Get('OTHER') | Should -BeNullOrEmpty
+    }
+
+    It "Should parse '<Line>'" -ForEach @(
+        @{ Line = '::set-output name=IMAGE_TAG::v1.2.3'; Name = 'IMAGE_TAG'; Value = 'v1.2.3' }
+        @{ Line = '::set-output name=URL::https://example.com/a::b'; Name = 'URL'; Value = 'https://example.com/a::b' }
+        @{ Line = '::set-output name=EMPTY::'; Name = 'EMPTY'; Value = '' }
+    ) {
+        $parsed = ConvertFrom-SetOutputLine -Line $Line
+
+        $parsed.Name | Should -Be $Name
+        $parsed.Value | Should -Be $Value
+    }
+
+    It "Should ignore '<Line>'" -ForEach @(
+        @{ Line = 'echo ::set-output name=IMAGE_TAG::v1' }
+        @{ Line = '::set-output name=1BAD::v1' }
+        @{ Line = '::warning::something' }
+        @{ Line = '' }
+    ) {
+        ConvertFrom-SetOutputLine -Line $Line | Should -BeNullOrEmpty
+    }
+
+    It "Should find the producers in the dependency chain only" {
+        $allTasks = @{
+            image   = @{ Names = @('image'); Dependencies = @(); Namespace = $null; OutputVars = @('IMAGE_TAG') }
+            build   = @{ Names = @('build'); Dependencies = @('image'); Namespace = $null; OutputVars = @() }
+            other   = @{ Names = @('other'); Dependencies = @(); Namespace = $null; OutputVars = @('IMAGE_TAG') }
+            deploy  = @{ Names = @('deploy'); Dependencies = @('build'); Namespace = $null; InputVars = @('IMAGE_TAG', 'REGION') }
+        }
+
+        $producers = Get-TaskInputVarProducers -TaskInfo $allTasks['deploy'] -AllTasks $allTasks
+
+        $producers['IMAGE_TAG'] | Should -Be @('image')
+        @($producers['REGION']).Count | Should -Be 0
+    }
+}
+
+Describe "Passing Values Between Tasks" -Tag "Core", "SharedVars" {
+
+    BeforeEach {
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        if (Test-Path -Path $buildPath) {
+            Remove-Item -Path $buildPath -Recurse -Force
+        }
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.bolt') -Recurse -Force -ErrorAction SilentlyContinue
+
+        New-TestTask -Name 'image' -ExtraMetadata '# OUTPUT_VARS: IMAGE_TAG' -Body @'
+Write-Output "::set-output name=IMAGE_TAG::v1.2.3"
+exit 0
+'@
+        New-TestTask -Name 'deploy' -Depends @('image') -ExtraMetadata '# INPUT_VARS: IMAGE_TAG' -Body @'
+Write-Host "Deploying $env:IMAGE_TAG"
+exit 0
+'@
+    }
+
+    It "Should pass a value to a dependent task and hide the annotation" {
+        $result = Invoke-Bolt -Arguments @('deploy')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Deploying v1\.2\.3'
+        $result.Output | Should -Not -Match '::set-output'
+    }
+
+    It "Should pass a value with -Parallel" {
+        $result = Invoke-Bolt -Arguments @('deploy', '-Parallel', '-NoProgress')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '\[deploy\]\s+Deploying v1\.2\.3'
+    }
+
+    It "Should warn about undeclared and unset variables" {
+        New-TestTask -Name 'image' -ExtraMetadata '# OUTPUT_VARS: IMAGE_TAG' -Body @'
+Write-Output "::set-output name=OTHER::x"
+exit 0
+'@
+
+        $result = Invoke-Bolt -Arguments @('deploy')
+
+        $result.ExitCode | Should -Be 0
+        "$($result.Output)$($result.Error)" | Should -Match "set 'OTHER', which is not in its OUTPUT_VARS"
+        "$($result.Output)$($result.Error)" | Should -Match "declares 'IMAGE_TAG' in OUTPUT_VARS but did not write"
+        $result.Output | Should -Match '(?m)Deploying *$'
+    }
+
+    It "Should restore the values of a cached producer" {
+        Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'src.txt') -Value 'source'
+        New-TestTask -Name 'image' -ExtraMetadata "# OUTPUT_VARS: IMAGE_TAG`n# INPUTS: src.txt" -Body @'
+Write-Output "::set-output name=IMAGE_TAG::v1.2.3"
+exit 0
+'@
+
+        $first = Invoke-Bolt -Arguments @('deploy')
+        $second = Invoke-Bolt -Arguments @('deploy')
+
+        $first.Output | Should -Match 'Ran image'
+        $second.Output | Should -Not -Match 'Ran image'
+        $second.Output | Should -Match 'Deploying v1\.2\.3'
+    }
+
+    It "Should report input variables without one producer with -ValidateTasks" {
+        New-TestTask -Name 'tag' -ExtraMetadata '# OUTPUT_VARS: IMAGE_TAG'
+        New-TestTask -Name 'deploy' -Depends @('image', 'tag') -ExtraMetadata '# INPUT_VARS: IMAGE_TAG, REGION'
+
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match "deploy\s+\| INPUT_VARS \| 'IMAGE_TAG' is declared in OUTPUT_VARS by more than one task in the dependency chain: image, tag"
+        $result.Output | Should -Match "deploy\s+\| INPUT_VARS \| No task in the dependency chain declares 'REGION' in OUTPUT_VARS"
+    }
+
+    It "Should pass -ValidateTasks when every input variable has one producer" {
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Not -Match 'INPUT_VARS'
+    }
+}
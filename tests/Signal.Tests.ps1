@@ -0,0 +1,180 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Signal'
+    $script:StdoutPath = Join-Path -Path $script:TempTestRoot -ChildPath 'stdout.txt'
+
+    # Helper function to start bolt.ps1 without waiting for it
+    function Start-Bolt {
+        param(
+            [string[]]$Arguments
+        )
+
+        $boltScriptPath = Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.ps1'
+
+        $params = @{
+            FilePath = 'pwsh'
+            ArgumentList = @('-NoProfile', '-File', $boltScriptPath) + $Arguments
+            WorkingDirectory = $script:TempTestRoot
+            NoNewWindow = $true
+            PassThru = $true
+            RedirectStandardOutput = $script:StdoutPath
+            RedirectStandardError = (Join-Path -Path $script:TempTestRoot -ChildPath 'stderr.txt')
+        }
+
+        $process = Start-Process @params
+        # Keep the handle open so ExitCode is available after the process exits
+        $null = $process.Handle
+        return $process
+    }
+
+    # Helper function to send a signal with the kill executable (kill is an alias of Stop-Process)
+    function Send-Signal {
+        param(
+            [int]$ProcessId,
+            [string]$Signal = 'TERM'
+        )
+
+        $kill = Get-Command -Name kill -CommandType Application | Select-Object -First 1
+        & $kill.Source -s $Signal $ProcessId
+    }
+
+    # Helper function to wait until every file exists
+    function Wait-ForFile {
+        param(
+            [string[]]$Path,
+            [int]$TimeoutSeconds = 20
+        )
+
+        $deadline = [DateTime]::UtcNow.AddSeconds($TimeoutSeconds)
+        while ([DateTime]::UtcNow -lt $deadline) {
+            if (@($Path | Where-Object { -not (Test-Path -Path $_) }).Count -eq 0) {
+                # Give the writer a moment to finish the file
+                Start-Sleep -Milliseconds 200
+                return $true
+            }
+            Start-Sleep -Milliseconds 50
+        }
+        return $false
+    }
+
+    # Helper function for a task that writes its process id and then runs for a while
+    function New-SlowTask {
+        param(
+            [string]$Name,
+            [string[]]$Depends = @()
+        )
+
+        $pidFile = Join-Path -Path $script:TempTestRoot -ChildPath "$Name.pid"
+        New-TestTask -Name $Name -Depends $Depends -Body "Set-Content -Path '$pidFile' -Value `$PID`nStart-Sleep -Seconds 60`nexit 0"
+        return $pidFile
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Stop Signals" -Tag "Core", "Signal" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '*.pid') -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should stop the running task on SIGTERM and record exit code 143" -Skip:$IsWindows {
+        $pidFile = New-SlowTask -Name 'slow'
+
+        $bolt = Start-Bolt -Arguments @('slow', '-OutputFormat', 'Json')
+        try {
+            Wait-ForFile -Path $pidFile | Should -BeTrue
+            Send-Signal -ProcessId $bolt.Id
+
+            $bolt.WaitForExit(20000) | Should -BeTrue
+            $bolt.ExitCode | Should -Be 143
+
+            $summary = Get-Content -Path $script:StdoutPath -Raw | ConvertFrom-Json
+            $summary.Success | Should -BeFalse
+            $summary.Tasks[0].Status | Should -Be 'failure'
+            $summary.Tasks[0].ExitCode | Should -Be 143
+
+            Get-Process -Id ([int](Get-Content -Path $pidFile)) -ErrorAction SilentlyContinue | Should -BeNullOrEmpty
+        } finally {
+            if (-not $bolt.HasExited) { $bolt.Kill($true) }
+        }
+    }
+
+    It "Should not run tasks that depend on a stopped task" -Skip:$IsWindows {
+        $pidFile = New-SlowTask -Name 'slow'
+        New-TestTask -Name 'after' -Depends @('slow')
+
+        $bolt = Start-Bolt -Arguments @('after', '-OutputFormat', 'Json', '-ErrorAction', 'Continue')
+        try {
+            Wait-ForFile -Path $pidFile | Should -BeTrue
+            Send-Signal -ProcessId $bolt.Id
+
+            $bolt.WaitForExit(20000) | Should -BeTrue
+            $bolt.ExitCode | Should -Be 143
+
+            $summary = Get-Content -Path $script:StdoutPath -Raw | ConvertFrom-Json
+            ($summary.Tasks.Status -join ',') | Should -Be 'failure,skipped'
+        } finally {
+            if (-not $bolt.HasExited) { $bolt.Kill($true) }
+        }
+    }
+
+    It "Should kill processes that ignore SIGTERM" -Skip:($IsWindows -or -not (Get-Command -Name bash -CommandType Application -ErrorAction SilentlyContinue)) {
+        $pidFile = Join-Path -Path $script:TempTestRoot -ChildPath 'stubborn.pid'
+        New-TestTask -Name 'stubborn' -Body "bash -c 'trap : TERM; echo `$`$ > $pidFile; while true; do sleep 0.1; done'`nexit 0"
+
+        $bolt = Start-Bolt -Arguments @('stubborn', '-OutputFormat', 'Json', '-GracePeriod', '1s')
+        try {
+            Wait-ForFile -Path $pidFile | Should -BeTrue
+            Send-Signal -ProcessId $bolt.Id
+
+            $bolt.WaitForExit(20000) | Should -BeTrue
+            $bolt.ExitCode | Should -Be 143
+
+            Start-Sleep -Milliseconds 500
+            Get-Process -Id ([int](Get-Content -Path $pidFile)) -ErrorAction SilentlyContinue | Should -BeNullOrEmpty
+        } finally {
+            if (-not $bolt.HasExited) { $bolt.Kill($true) }
+        }
+    }
+
+    It "Should stop every running task in -Parallel runs" -Skip:$IsWindows {
+        $firstPidFile = New-SlowTask -Name 'first'
+        $secondPidFile = New-SlowTask -Name 'second'
+
+        $bolt = Start-Bolt -Arguments @('first', 'second', '-Parallel')
+        try {
+            Wait-ForFile -Path $firstPidFile, $secondPidFile | Should -BeTrue
+            Send-Signal -ProcessId $bolt.Id
+
+            $bolt.WaitForExit(20000) | Should -BeTrue
+            $bolt.ExitCode | Should -Be 143
+
+            $output = Get-Content -Path $script:StdoutPath -Raw
+            $output | Should -Match 'Received SIGTERM, stopping 2 running task\(s\)'
+            $output | Should -Match '\[first\]\s+stopped by SIGTERM'
+            $output | Should -Match '\[second\]\s+stopped by SIGTERM'
+
+            foreach ($pidFile in @($firstPidFile, $secondPidFile)) {
+                Get-Process -Id ([int](Get-Content -Path $pidFile)) -ErrorAction SilentlyContinue | Should -BeNullOrEmpty
+            }
+        } finally {
+            if (-not $bolt.HasExited) { $bolt.Kill($true) }
+        }
+    }
+
+    It "Should reject an invalid -GracePeriod" {
+        New-TestTask -Name 'quick'
+
+        $result = Invoke-Bolt -Arguments @('quick', '-GracePeriod', 'soon')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'Invalid -GracePeriod'
+        $result.Output | Should -Not -Match 'Ran quick'
+    }
+}
@@ -0,0 +1,195 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Since'
+
+    # Helper function to run git in the temp project
+    function Invoke-Git {
+        param(
+            [string[]]$Arguments
+        )
+
+        & git -C $script:TempTestRoot -c user.name=bolt -c user.email=bolt@example.com @Arguments | Out-Null
+    }
+
+    # Helper function to reset the project: a git repo with sources, docs, and three tasks
+    function Initialize-SinceProject {
+        param(
+            [switch]$NoGit
+        )
+
+        foreach ($path in @('.git', '.build', '.bolt', 'src', 'docs')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath, (Join-Path $script:TempTestRoot 'src'), (Join-Path $script:TempTestRoot 'docs') -Force | Out-Null
+        Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'src/main.txt') -Value 'main'
+        Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'docs/guide.md') -Value 'guide'
+
+        Set-Content -Path (Join-Path $buildPath 'Invoke-Compile.ps1') -Value "# TASK: compile`n# DESCRIPTION: Compiles`n# INPUTS: src/*.txt`nWrite-Host 'Ran compile'`nexit 0"
+        Set-Content -Path (Join-Path $buildPath 'Invoke-Package.ps1') -Value "# TASK: package`n# DESCRIPTION: Packages`n# DEPENDS: compile`nWrite-Host 'Ran package'`nexit 0"
+        Set-Content -Path (Join-Path $buildPath 'Invoke-Docs.ps1') -Value "# TASK: docs`n# DESCRIPTION: Builds the docs`n# INPUTS: docs/**/*.md`nWrite-Host 'Ran docs'`nexit 0"
+
+        if ($NoGit) {
+            return
+        }
+        Invoke-Git -Arguments @('init', '--quiet')
+        Invoke-Git -Arguments @('add', 'bolt.ps1', '.build', 'src', 'docs')
+        Invoke-Git -Arguments @('commit', '--quiet', '-m', 'initial')
+    }
+
+    # Load the -Since helper functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('Get-TaskSinceSkip', 'Test-TaskInputMatch', 'ConvertTo-GlobRegex', 'Resolve-TaskDependency') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Since Task Selection" -Tag "Core", "Since" {
+
+    BeforeAll {
+        # Helper function to build a task entry like Get-AllTasks does
+        function New-TaskEntry {
+            param(
+                [string]$Name,
+                [string[]]$Dependencies = @(),
+                [string[]]$Inputs = @(),
+                [switch]$IsCore
+            )
+
+            @{ Names = @($Name); Dependencies = $Dependencies; Inputs = $Inputs; Namespace = ''; IsCore = [bool]$IsCore }
+        }
+
+        $script:Tasks = @{
+            'compile' = New-TaskEntry -Name 'compile' -Inputs @('src/**/*.go')
+            'docs'    = New-TaskEntry -Name 'docs' -Inputs @('docs')
+            'package' = New-TaskEntry -Name 'package' -Dependencies @('compile')
+            'release' = New-TaskEntry -Name 'release' -Dependencies @('package', 'docs')
+            'check'   = New-TaskEntry -Name 'check' -IsCore
+        }
+        $script:Order = @('compile', 'package', 'docs', 'release', 'check')
+    }
+
+    It "Should run tasks whose inputs changed and everything that depends on them" {
+        $skipped = Get-TaskSinceSkip -AllTasks $script:Tasks -ExecutionOrder $script:Order -ChangedFiles @('src/cmd/main.go')
+
+        $skipped | Should -Be @('docs')
+    }
+
+    It "Should match literal INPUTS directories" {
+        $skipped = Get-TaskSinceSkip -AllTasks $script:Tasks -ExecutionOrder $script:Order -ChangedFiles @('docs/guide.md')
+
+        $skipped | Should -Be @('compile', 'package')
+    }
+
+    It "Should skip every project task when nothing changed" {
+        $skipped = Get-TaskSinceSkip -AllTasks $script:Tasks -ExecutionOrder $script:Order -ChangedFiles @()
+
+        $skipped | Should -Be @('compile', 'package', 'docs', 'release')
+    }
+
+    It "Should ignore changed files that match no INPUTS" {
+        $skipped = Get-TaskSinceSkip -AllTasks $script:Tasks -ExecutionOrder $script:Order -ChangedFiles @('README.md', 'src/notes.txt')
+
+        $skipped | Should -Be @('compile', 'package', 'docs', 'release')
+    }
+
+    It "Should run a task when a dependency outside the execution order changed" {
+        $skipped = Get-TaskSinceSkip -AllTasks $script:Tasks -ExecutionOrder @('package') -ChangedFiles @('src/main.go')
+
+        $skipped | Should -BeNullOrEmpty
+    }
+}
+
+Describe "Since Runs" -Tag "Core", "Since" {
+
+    BeforeEach {
+        $script:OriginalPath = $env:PATH
+    }
+
+    AfterEach {
+        $env:PATH = $script:OriginalPath
+    }
+
+    Context "With git" -Skip:(-not (Get-Command -Name git -CommandType Application -ErrorAction SilentlyContinue)) {
+        BeforeEach {
+            Initialize-SinceProject
+        }
+
+        It "Should run only the changed tasks and their dependents" {
+            Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'src/main.txt') -Value 'changed'
+
+            $result = Invoke-Bolt -Arguments @('package', 'docs', '-Since', 'HEAD', '-OutputFormat', 'Json')
+            $summary = $result.Output | ConvertFrom-Json
+
+            $result.ExitCode | Should -Be 0
+            ($summary.Tasks | Where-Object Name -eq 'compile').Status | Should -Be 'success'
+            ($summary.Tasks | Where-Object Name -eq 'package').Status | Should -Be 'success'
+            $docs = $summary.Tasks | Where-Object Name -eq 'docs'
+            $docs.Status | Should -Be 'skipped'
+            $docs.SkipReason | Should -Be 'git'
+        }
+
+        It "Should report skipped tasks as SKIPPED(git)" {
+            Invoke-Git -Arguments @('tag', 'base')
+            Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'docs/guide.md') -Value 'changed'
+            Invoke-Git -Arguments @('commit', '--quiet', '-am', 'docs')
+
+            $result = Invoke-Bolt -Arguments @('package', 'docs', '-Since', 'base')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match "Task 'compile' has no changes since base \(SKIPPED\(git\)\)"
+            $result.Output | Should -Match "Task 'package' has no changes since base \(SKIPPED\(git\)\)"
+            $result.Output | Should -Match 'Ran docs'
+            $result.Output | Should -Not -Match 'Ran compile'
+        }
+
+        It "Should skip tasks with -Parallel" {
+            Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'docs/guide.md') -Value 'changed'
+
+            $result = Invoke-Bolt -Arguments @('package', 'docs', '-Since', 'HEAD', '-Parallel')
+
+            $result.ExitCode | Should -Be 0
+            $result.Output | Should -Match '\[compile\]\s+no changes since HEAD \(SKIPPED\(git\)\)'
+            $result.Output | Should -Match 'Ran docs'
+        }
+
+        It "Should show skipped tasks in a dry run" {
+            $result = Invoke-Bolt -Arguments @('docs', '-Since', 'HEAD', '-DryRun', '-OutputFormat', 'Json')
+            $plan = $result.Output | ConvertFrom-Json
+
+            $result.ExitCode | Should -Be 0
+            $plan.Tasks[0].SkipReason | Should -Be 'git'
+        }
+
+        It "Should fail for a ref that does not exist" {
+            $result = Invoke-Bolt -Arguments @('docs', '-Since', 'no-such-ref')
+
+            $result.ExitCode | Should -Be 1
+            $result.Error | Should -Match 'git diff --name-only no-such-ref failed'
+            $result.Output | Should -Not -Match 'Ran docs'
+        }
+    }
+
+    Context "Without git" {
+        It "Should warn and run every task when git is not on PATH" {
+            Initialize-SinceProject -NoGit
+            $pwshPath = (Get-Process -Id $PID).Path
+            $env:PATH = Split-Path -Path $pwshPath -Parent
+
+            $result = Invoke-Bolt -Arguments @('docs', '-Since', 'HEAD')
+
+            $result.ExitCode | Should -Be 0
+            "$($result.Output)$($result.Error)" | Should -Match 'git was not found on PATH'
+            $result.Output | Should -Match 'Ran docs'
+        }
+    }
+}
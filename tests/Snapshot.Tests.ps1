@@ -0,0 +1,209 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Snapshot'
+
+    # Helper function to create a task that prints the lines of input.txt
+    function New-GeneratorTask {
+        param(
+            [string]$Name = 'generate',
+            [string]$Snapshot = 'testdata/generate.golden'
+        )
+
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+
+        $taskNameCapitalized = (Get-Culture).TextInfo.ToTitleCase($Name)
+        $content = @"
+# TASK: $Name
+# DESCRIPTION: Test task $Name
+# SNAPSHOT: $Snapshot
+
+Get-Content -Path (Join-Path -Path `$PSScriptRoot -ChildPath '../input.txt')
+[Console]::Error.WriteLine('progress on stderr is not compared')
+exit 0
+"@
+        Set-Content -Path (Join-Path -Path $buildPath -ChildPath "Invoke-$taskNameCapitalized.ps1") -Value $content
+    }
+
+    # Helper function to set the lines the generator task prints
+    function Set-GeneratorInput {
+        param([string[]]$Lines)
+
+        Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'input.txt') -Value $Lines
+    }
+
+    # Load the diff function for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('Get-UnifiedDiff') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Unified Diff" -Tag "Core", "Snapshot" {
+
+    It "Should return nothing for the same lines" {
+        @(Get-UnifiedDiff -Expected @('a', 'b') -Actual @('a', 'b')).Count | Should -Be 0
+    }
+
+    It "Should show a changed line with its context" {
+        $diff = Get-UnifiedDiff -Expected @('a', 'b', 'c') -Actual @('a', 'x', 'c') -ExpectedLabel 'golden' -ActualLabel 'output'
+
+        $diff | Should -Be @('--- golden', '+++ output', '@@ -1,3 +1,3 @@', ' a', '-b', '+x', ' c')
+    }
+
+    It "Should number a hunk that only adds lines from the line before it" {
+        $diff = Get-UnifiedDiff -Expected @() -Actual @('new')
+
+        $diff[2] | Should -Be '@@ -0,0 +1,1 @@'
+        $diff[3] | Should -Be '+new'
+    }
+
+    It "Should split changes far apart into separate hunks" {
+        $expected = 1..20 | ForEach-Object { "line $_" }
+        $actual = @($expected)
+        $actual[1] = 'changed 2'
+        $actual[18] = 'changed 19'
+
+        $diff = Get-UnifiedDiff -Expected $expected -Actual $actual
+
+        @($diff | Where-Object { $_.StartsWith('@@') }) | Should -Be @('@@ -1,5 +1,5 @@', '@@ -16,5 +16,5 @@')
+    }
+
+    It "Should only report the line counts when there are too many changed lines to compare" {
+        $expected = 1..10 | ForEach-Object { "old $_" }
+        $actual = @('same') + (1..12 | ForEach-Object { "new $_" })
+
+        $diff = Get-UnifiedDiff -Expected (@('same') + $expected) -Actual $actual -MaxComparisons 100
+
+        $diff | Should -Be @('--- expected', '+++ actual', 'files differ (11 vs 13 lines, 10 vs 12 changed lines are too many to compare)')
+    }
+
+    It "Should compare lines case-sensitively" {
+        $diff = Get-UnifiedDiff -Expected @('Hello') -Actual @('hello')
+
+        $diff | Should -Contain '-Hello'
+        $diff | Should -Contain '+hello'
+    }
+}
+
+Describe "Task Snapshots" -Tag "Core", "Snapshot" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'testdata') -Recurse -Force -ErrorAction SilentlyContinue
+        $script:SnapshotPath = Join-Path -Path $script:TempTestRoot -ChildPath 'testdata/generate.golden'
+    }
+
+    It "Should write the snapshot on the first run" {
+        New-GeneratorTask
+        Set-GeneratorInput -Lines @('alpha', 'beta')
+
+        $result = Invoke-Bolt -Arguments @('generate')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match "Task 'generate' snapshot created: testdata/generate.golden"
+        Get-Content -Path $script:SnapshotPath | Should -Be @('alpha', 'beta')
+    }
+
+    It "Should pass when the output matches the snapshot" {
+        New-GeneratorTask
+        Set-GeneratorInput -Lines @('alpha', 'beta')
+        Invoke-Bolt -Arguments @('generate') | Out-Null
+
+        $result = Invoke-Bolt -Arguments @('generate')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Not -Match 'snapshot'
+    }
+
+    It "Should fail and print a unified diff when the output changes" {
+        New-GeneratorTask
+        Set-GeneratorInput -Lines @('alpha', 'beta', 'gamma')
+        Invoke-Bolt -Arguments @('generate') | Out-Null
+        Set-GeneratorInput -Lines @('alpha', 'BETA', 'gamma')
+
+        $result = Invoke-Bolt -Arguments @('generate')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match "Task 'generate' output does not match snapshot testdata/generate.golden"
+        $result.Output | Should -Match '--- testdata/generate.golden'
+        $result.Output | Should -Match '\+\+\+ generate output'
+        $result.Output | Should -Match '(?m)^-beta$'
+        $result.Output | Should -Match '(?m)^\+BETA$'
+        Get-Content -Path $script:SnapshotPath | Should -Be @('alpha', 'beta', 'gamma')
+    }
+
+    It "Should rewrite the snapshot with -UpdateSnapshot" {
+        New-GeneratorTask
+        Set-GeneratorInput -Lines @('old')
+        Invoke-Bolt -Arguments @('generate') | Out-Null
+        Set-GeneratorInput -Lines @('new')
+
+        $result = Invoke-Bolt -Arguments @('generate', '-UpdateSnapshot')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match "Task 'generate' snapshot updated"
+        Get-Content -Path $script:SnapshotPath | Should -Be @('new')
+        (Invoke-Bolt -Arguments @('generate')).ExitCode | Should -Be 0
+    }
+
+    It "Should add the diff to JSON results" {
+        New-GeneratorTask
+        Set-GeneratorInput -Lines @('one')
+        Invoke-Bolt -Arguments @('generate') | Out-Null
+        Set-GeneratorInput -Lines @('two')
+
+        $result = Invoke-Bolt -Arguments @('generate', '-OutputFormat', 'Json')
+
+        $result.ExitCode | Should -Be 1
+        $summary = $result.Output | ConvertFrom-Json
+        $summary.Tasks[0].Status | Should -Be 'failure'
+        $summary.Tasks[0].ExitCode | Should -Be 0
+        $summary.Tasks[0].SnapshotDiff | Should -Match '(?m)^-one$'
+        $summary.Tasks[0].SnapshotDiff | Should -Match '(?m)^\+two$'
+    }
+
+    It "Should compare snapshots in -Parallel runs" {
+        New-GeneratorTask
+        Set-GeneratorInput -Lines @('one')
+        Invoke-Bolt -Arguments @('generate', '-Parallel') | Out-Null
+        Get-Content -Path $script:SnapshotPath | Should -Be @('one')
+        Set-GeneratorInput -Lines @('two')
+
+        $result = Invoke-Bolt -Arguments @('generate', '-Parallel')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match '\[generate\]\s+output does not match snapshot testdata/generate.golden'
+        $result.Output | Should -Match '\[generate\]\s+\+two'
+    }
+
+    It "Should show the snapshot in the dry run plan" {
+        New-GeneratorTask
+
+        $result = Invoke-Bolt -Arguments @('generate', '-DryRun')
+
+        $result.Output | Should -Match 'Snapshot: testdata/generate.golden'
+        Test-Path -Path $script:SnapshotPath | Should -BeFalse
+    }
+
+    It "Should stop before any task runs when the snapshot path leaves the project" {
+        New-GeneratorTask -Snapshot '../outside.golden'
+        Set-GeneratorInput -Lines @('one')
+
+        $result = Invoke-Bolt -Arguments @('generate')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match "Task 'generate': Snapshot '../outside.golden' must be a path inside the project root"
+
+        $validation = Invoke-Bolt -Arguments @('-ValidateTasks')
+        $validation.Output | Should -Match 'SNAPSHOT'
+    }
+}
@@ -0,0 +1,111 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Tags'
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Tags" -Tag "Core", "Tags" {
+
+    BeforeEach {
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        if (Test-Path -Path $buildPath) {
+            Remove-Item -Path $buildPath -Recurse -Force
+        }
+
+        New-TestTask -Name 'build'
+        New-TestTask -Name 'publish' -Depends @('build') -ExtraMetadata '# TAGS: release'
+        New-TestTask -Name 'changelog' -ExtraMetadata '# TAGS: release, docs'
+        New-TestTask -Name 'site' -ExtraMetadata '# TAGS: docs'
+        New-TestTask -Name 'lint'
+
+        # A tagged task in the golang namespace
+        $golangPath = Join-Path -Path $buildPath -ChildPath 'golang'
+        New-Item -ItemType Directory -Path $golangPath -Force | Out-Null
+        Set-Content -Path (Join-Path -Path $golangPath -ChildPath 'Invoke-Release.ps1') -Value @'
+# TASK: release
+# DESCRIPTION: Cross-compiles the Go binaries
+# DEPENDS:
+# TAGS: release
+
+Write-Host "Ran golang-release"
+exit 0
+'@
+    }
+
+    It "Should run every tagged task and its dependencies" {
+        $result = Invoke-Bolt -Arguments @('-Tag', 'release')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran build'
+        $result.Output | Should -Match 'Ran publish'
+        $result.Output | Should -Match 'Ran changelog'
+        $result.Output | Should -Match 'Ran golang-release'
+        $result.Output | Should -Not -Match 'Ran site'
+        $result.Output | Should -Not -Match 'Ran lint'
+    }
+
+    It "Should select the tasks with any of several tags" {
+        $result = Invoke-Bolt -Arguments @('-Tag', 'docs,release', '-Only')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran site'
+        $result.Output | Should -Match 'Ran publish'
+        $result.Output | Should -Not -Match 'Ran build'
+        ([regex]::Matches($result.Output, 'Ran changelog')).Count | Should -Be 1
+    }
+
+    It "Should run named tasks together with tagged tasks" {
+        $result = Invoke-Bolt -Arguments @('lint', '-Tag', 'docs')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '(?s)Ran lint.*Ran changelog.*Ran site'
+    }
+
+    It "Should fail when no task has the tag" {
+        $result = Invoke-Bolt -Arguments @('-Tag', 'nightly')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'No tasks are tagged nightly'
+    }
+
+    It "Should fail when neither tasks nor -Tag are given" {
+        $result = Invoke-Bolt -Arguments @('-Only')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'Specify the tasks to run, or -Tag'
+    }
+
+    It "Should filter -ListTasks by tag" {
+        $result = Invoke-Bolt -Arguments @('-ListTasks', '-Tag', 'docs', '-NoHeader')
+
+        $result.ExitCode | Should -Be 0
+        $lines = @($result.Output -split '\r?\n' | Where-Object { $_ })
+        $lines.Count | Should -Be 2
+        $lines[0] | Should -Match '^changelog\b'
+        $lines[1] | Should -Match '^site\b'
+    }
+
+    It "Should include tags in the -ListTasks output" {
+        $text = Invoke-Bolt -Arguments @('-ListTasks', '-Tag', 'release')
+        $text.Output | Should -Match 'Tags: release, docs'
+
+        $json = Invoke-Bolt -Arguments @('-ListTasks', '-Tag', 'release', '-OutputFormat', 'Json')
+        $tasks = $json.Output | ConvertFrom-Json
+        @($tasks.Name) | Should -Be @('changelog', 'golang-release', 'publish')
+        @(($tasks | Where-Object Name -eq 'changelog').Tags) | Should -Be @('release', 'docs')
+    }
+
+    It "Should report invalid tags with -ValidateTasks" {
+        New-TestTask -Name 'deploy' -ExtraMetadata '# TAGS: Release_1'
+
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match "deploy\s+\| TAGS\s+\| Tag 'Release_1' must use only lowercase letters"
+    }
+}
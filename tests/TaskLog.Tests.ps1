@@ -0,0 +1,123 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'TaskLog'
+
+    # Helper function to write bolt.config.json with a LogDir
+    function Set-LogDir {
+        param(
+            [string]$Path
+        )
+
+        @{ LogDir = $Path } | ConvertTo-Json | Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.config.json')
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Log Files" -Tag "Core", "TaskLog" {
+
+    BeforeEach {
+        foreach ($path in @('.build', '.bolt', 'logs', 'bolt.config.json')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+        $script:LogDir = Join-Path -Path $script:TempTestRoot -ChildPath 'logs'
+    }
+
+    It "Should copy standard output and standard error to a log file and the terminal" {
+        Set-LogDir -Path 'logs'
+        New-TestTask -Name 'compile' -Body "[Console]::Error.WriteLine('warning: slow disk')`nWrite-Output 'compiled 3 files'`nexit 0"
+
+        $result = Invoke-Bolt -Arguments @('compile')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran compile'
+        $result.Output | Should -Match 'compiled 3 files'
+        $logFile = @(Get-ChildItem -Path $script:LogDir -Filter 'compile-*.log')
+        $logFile.Count | Should -Be 1
+        $logFile[0].Name | Should -Match '^compile-\d{8}-\d{6}-\d{3}\.log$'
+        $log = Get-Content -Path $logFile[0].FullName -Raw
+        $log | Should -Match 'Ran compile'
+        $log | Should -Match 'compiled 3 files'
+        $log | Should -Match 'warning: slow disk'
+    }
+
+    It "Should write one log file per task in -Parallel runs" {
+        Set-LogDir -Path 'logs'
+        New-TestTask -Name 'lint'
+        New-TestTask -Name 'test'
+        New-TestTask -Name 'build' -Depends @('lint', 'test')
+
+        $result = Invoke-Bolt -Arguments @('build', '-Parallel')
+
+        $result.ExitCode | Should -Be 0
+        foreach ($task in @('lint', 'test', 'build')) {
+            $logFile = @(Get-ChildItem -Path $script:LogDir -Filter "$task-*.log")
+            $logFile.Count | Should -Be 1
+            Get-Content -Path $logFile[0].FullName -Raw | Should -Match "Ran $task"
+            Get-Content -Path $logFile[0].FullName -Raw | Should -Not -Match '\[\w+\]'
+        }
+    }
+
+    It "Should keep the whole log of a task that fails" {
+        Set-LogDir -Path 'logs'
+        New-TestTask -Name 'broken' -Body "Write-Output ('x' * 100)`nWrite-Output 'last words'`nexit 3"
+
+        $result = Invoke-Bolt -Arguments @('broken')
+
+        $result.ExitCode | Should -Be 1
+        $logFile = Get-ChildItem -Path $script:LogDir -Filter 'broken-*.log'
+        (Get-Content -Path $logFile.FullName)[-1] | Should -Be 'last words'
+    }
+
+    It "Should use file names without brackets for matrix instances" {
+        Set-LogDir -Path 'logs'
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+        Set-Content -Path (Join-Path $buildPath 'Invoke-Test.ps1') -Value "# TASK: test`n# DESCRIPTION: Matrix test`n# MATRIX: TARGET_OS=linux, windows`nexit 0"
+
+        $result = Invoke-Bolt -Arguments @('test')
+
+        $result.ExitCode | Should -Be 0
+        @(Get-ChildItem -Path $script:LogDir -Filter '*.log').Count | Should -Be 2
+        @(Get-ChildItem -Path $script:LogDir -Filter '*.log').Name | Should -Not -Match '[\[\]=,]'
+        Get-ChildItem -Path $script:LogDir -Filter 'test_TARGET_OS_linux_-*.log' | Should -Not -BeNullOrEmpty
+    }
+
+    It "Should list the log files of the last run with -ListLogs" {
+        Set-LogDir -Path 'logs'
+        New-TestTask -Name 'compile'
+        New-TestTask -Name 'package' -Depends @('compile')
+        (Invoke-Bolt -Arguments @('package')).ExitCode | Should -Be 0
+        Start-Sleep -Milliseconds 20
+        (Invoke-Bolt -Arguments @('compile')).ExitCode | Should -Be 0
+
+        $result = Invoke-Bolt -Arguments @('-ListLogs')
+
+        $result.ExitCode | Should -Be 0
+        $paths = @($result.Output -split '\r?\n' | Where-Object { $_ })
+        $paths.Count | Should -Be 1
+        $paths[0] | Should -Match 'compile-\d{8}-\d{6}-\d{3}\.log$'
+        $paths[0] | Should -Exist
+        @(Get-ChildItem -Path $script:LogDir -Filter '*.log').Count | Should -Be 3
+    }
+
+    It "Should fail -ListLogs without a LogDir" {
+        $result = Invoke-Bolt -Arguments @('-ListLogs')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'LogDir is not set in bolt.config.json'
+    }
+
+    It "Should not write log files without a LogDir" {
+        New-TestTask -Name 'compile'
+
+        $result = Invoke-Bolt -Arguments @('compile')
+
+        $result.ExitCode | Should -Be 0
+        $script:LogDir | Should -Not -Exist
+    }
+}
@@ -0,0 +1,255 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'TaskStore'
+    $script:ServedRoot = Join-Path -Path ([System.IO.Path]::GetTempPath()) -ChildPath "BoltTaskStoreServed_$(Get-Random)"
+    $script:RequestLog = Join-Path -Path $script:TempTestRoot -ChildPath 'requests.log'
+
+    # Helper function to publish a task file on the test server and return its list entry
+    function Publish-StoreTask {
+        param(
+            [string]$Path,
+            [string]$Content
+        )
+
+        $fullPath = Join-Path -Path $script:ServedRoot -ChildPath $Path
+        New-Item -ItemType Directory -Path (Split-Path -Path $fullPath -Parent) -Force | Out-Null
+        Set-Content -Path $fullPath -Value $Content
+
+        return @{
+            Url    = $Path
+            Sha256 = (Get-FileHash -Path $fullPath -Algorithm SHA256).Hash.ToLowerInvariant()
+        }
+    }
+
+    # Helper function to publish the task list on the test server
+    function Publish-TaskList {
+        param(
+            [hashtable[]]$Entries
+        )
+
+        ConvertTo-Json -InputObject @($Entries) -Depth 5 | Set-Content -Path (Join-Path -Path $script:ServedRoot -ChildPath 'tasks.json')
+    }
+
+    # Helper function to build task metadata like Get-ProjectTasks does
+    function New-TaskEntry {
+        param(
+            [string]$Name,
+            [string[]]$Dependencies = @()
+        )
+
+        return @{
+            Names        = @($Name)
+            Description  = "Task $Name"
+            Dependencies = $Dependencies
+            ScriptPath   = Join-Path -Path $script:TempTestRoot -ChildPath "Invoke-$Name.ps1"
+            IsCore       = $false
+            Namespace    = $null
+            Produces     = @()
+            Consumes     = @()
+            Matrix       = [ordered]@{}
+        }
+    }
+
+    New-Item -ItemType Directory -Path $script:ServedRoot -Force | Out-Null
+
+    # Load the task store functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('New-FileTaskStore', 'New-InMemoryTaskStore', 'Get-AllTasks', 'Get-CoreTasks', 'Get-BoltConfigFile', 'Get-ProjectTasks', 'Get-ProjectTasksFromMultipleDirectories', 'Expand-TaskMatrix', 'Add-ArtifactDependencies') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+
+    # Start a small file server for the served directory that logs each request
+    $serverScript = Join-Path -Path $script:TempTestRoot -ChildPath 'file-server.ps1'
+    Set-Content -Path $serverScript -Value @'
+param([string]$Prefix, [string]$Root, [string]$LogPath)
+
+$listener = [System.Net.HttpListener]::new()
+$listener.Prefixes.Add($Prefix)
+$listener.Start()
+
+while ($listener.IsListening) {
+    $context = $listener.GetContext()
+    $request = $context.Request
+    $response = $context.Response
+
+    Add-Content -Path $LogPath -Value "$($request.HttpMethod) $($request.Url.AbsolutePath)"
+
+    $path = Join-Path -Path $Root -ChildPath $request.Url.AbsolutePath.TrimStart('/')
+    if (Test-Path -LiteralPath $path -PathType Leaf) {
+        $bytes = [System.IO.File]::ReadAllBytes($path)
+        $response.OutputStream.Write($bytes, 0, $bytes.Length)
+    } else {
+        $response.StatusCode = 404
+    }
+
+    $response.Close()
+}
+'@
+    Start-TestServer -ScriptPath $serverScript -Arguments @($script:ServedRoot, $script:RequestLog)
+}
+
+AfterAll {
+    Remove-TestProject -AdditionalPath $script:ServedRoot
+}
+
+Describe "In-Memory Task Store" -Tag "Core", "TaskStore" {
+
+    It "Should load a copy of its tasks" {
+        $store = New-InMemoryTaskStore -Tasks @{ build = (New-TaskEntry -Name 'build') }
+
+        $tasks = $store.Load()
+        $tasks.Remove('build')
+
+        $store.Type | Should -Be 'memory'
+        $store.Load().Keys | Should -Be @('build')
+    }
+
+    It "Should wake a watch subscription when the tasks are replaced" {
+        $store = New-InMemoryTaskStore -Tasks @{ build = (New-TaskEntry -Name 'build') }
+        $subscription = $store.Watch()
+        try {
+            $subscription.Receive(50) | Should -BeNullOrEmpty
+
+            $store.Set(@{ lint = (New-TaskEntry -Name 'lint') })
+
+            $subscription.Receive(1000).Keys | Should -Be @('lint')
+            $subscription.Receive(50) | Should -BeNullOrEmpty
+        } finally {
+            $subscription.Dispose()
+        }
+    }
+
+    It "Should let Get-AllTasks run without task files" {
+        $store = New-InMemoryTaskStore -Tasks @{
+            build = (New-TaskEntry -Name 'build')
+            test  = (New-TaskEntry -Name 'test' -Dependencies @('build'))
+        }
+
+        $tasks = Get-AllTasks -ScriptRoot (Join-Path $script:TempTestRoot 'no-project') -TaskDirectory '.build' -Store $store
+
+        $tasks.Keys | Should -Contain 'build'
+        $tasks.Keys | Should -Contain 'check-index'
+        $tasks['test'].Dependencies | Should -Be @('build')
+    }
+}
+
+Describe "File Task Store" -Tag "Core", "TaskStore" {
+
+    BeforeEach {
+        $script:StoreRoot = Join-Path -Path $script:TempTestRoot -ChildPath "file-store-$(Get-Random)"
+        New-Item -ItemType Directory -Path (Join-Path $script:StoreRoot '.build/golang') -Force | Out-Null
+        Set-Content -Path (Join-Path $script:StoreRoot '.build/Invoke-Build.ps1') -Value "# TASK: build`n# DESCRIPTION: Builds`nexit 0"
+        Set-Content -Path (Join-Path $script:StoreRoot '.build/golang/Invoke-Test.ps1') -Value "# TASK: test`n# DESCRIPTION: Tests`nexit 0"
+    }
+
+    It "Should load root and namespace tasks from .build" {
+        $store = New-FileTaskStore -ScriptRoot $script:StoreRoot
+
+        $tasks = $store.Load()
+
+        $store.Type | Should -Be 'file'
+        @($tasks.Keys | Sort-Object) | Should -Be @('build', 'golang-test')
+    }
+
+    It "Should load only a custom task directory" {
+        $store = New-FileTaskStore -ScriptRoot $script:StoreRoot -TaskDirectory '.build/golang'
+
+        $store.Load().Keys | Should -Be @('test')
+    }
+
+    It "Should reload when a task file is added" {
+        $store = New-FileTaskStore -ScriptRoot $script:StoreRoot
+        $subscription = $store.Watch()
+        try {
+            Set-Content -Path (Join-Path $script:StoreRoot '.build/Invoke-Lint.ps1') -Value "# TASK: lint`n# DESCRIPTION: Lints`nexit 0"
+
+            $tasks = $subscription.Receive(5000)
+
+            $tasks | Should -Not -BeNullOrEmpty
+            $tasks.Keys | Should -Contain 'lint'
+        } finally {
+            $subscription.Dispose()
+        }
+    }
+
+    It "Should time out when nothing changes" {
+        $subscription = (New-FileTaskStore -ScriptRoot $script:StoreRoot).Watch()
+        try {
+            $subscription.Receive(100) | Should -BeNullOrEmpty
+        } finally {
+            $subscription.Dispose()
+        }
+    }
+}
+
+Describe "HTTP Task Store" -Tag "Core", "TaskStore" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.bolt') -Recurse -Force -ErrorAction SilentlyContinue
+        @{ TaskStoreURL = "$($script:ServerUrl)/tasks.json" } | ConvertTo-Json | Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.config.json')
+    }
+
+    It "Should run tasks from the task list instead of .build" {
+        $build = Publish-StoreTask -Path 'ci/Invoke-Build.ps1' -Content "# TASK: build`n# DESCRIPTION: Builds from the store`nWrite-Host 'Built from store'"
+        $deploy = Publish-StoreTask -Path 'ci/Invoke-Deploy.ps1' -Content "# TASK: deploy`n# DESCRIPTION: Deploys`n# DEPENDS: build`nWrite-Host 'Deployed'"
+        Publish-TaskList -Entries @($build, $deploy)
+
+        New-Item -ItemType Directory -Path (Join-Path $script:TempTestRoot '.build') -Force | Out-Null
+        Set-Content -Path (Join-Path $script:TempTestRoot '.build/Invoke-Local.ps1') -Value "# TASK: local`n# DESCRIPTION: Local task`nexit 0"
+
+        $result = Invoke-Bolt -Arguments @('deploy')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '(?s)Built from store.*Deployed'
+        (Invoke-Bolt -Arguments @('-ListTasks')).Output | Should -Not -Match 'local'
+    }
+
+    It "Should refuse a task file whose digest does not match the list" {
+        $build = Publish-StoreTask -Path 'ci/Invoke-Build.ps1' -Content "# TASK: build`n# DESCRIPTION: Builds`nexit 0"
+        $build.Sha256 = '0' * 64
+        Publish-TaskList -Entries @($build)
+
+        $result = Invoke-Bolt -Arguments @('build')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'failed SHA-256 verification'
+    }
+
+    It "Should download the task files again with -RefreshRemotes" {
+        $build = Publish-StoreTask -Path 'ci/Invoke-Build.ps1' -Content "# TASK: build`n# DESCRIPTION: Builds`nWrite-Host 'First build'"
+        Publish-TaskList -Entries @($build)
+        (Invoke-Bolt -Arguments @('build')).Output | Should -Match 'First build'
+
+        # Same list, changed file: the cached copy still matches the digest, a new download does not
+        Publish-StoreTask -Path 'ci/Invoke-Build.ps1' -Content "# TASK: build`n# DESCRIPTION: Builds`nWrite-Host 'Second build'" | Out-Null
+
+        (Invoke-Bolt -Arguments @('build')).Output | Should -Match 'First build'
+        $result = Invoke-Bolt -Arguments @('build', '-RefreshRemotes')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'failed SHA-256 verification'
+    }
+
+    It "Should fail when the task list cannot be fetched" {
+        @{ TaskStoreURL = "$($script:ServerUrl)/missing.json" } | ConvertTo-Json | Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.config.json')
+
+        $result = Invoke-Bolt -Arguments @('build')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'Failed to fetch the task list'
+    }
+
+    It "Should require https for other hosts" {
+        @{ TaskStoreURL = 'http://tasks.example.com/tasks.json' } | ConvertTo-Json | Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.config.json')
+
+        $result = Invoke-Bolt -Arguments @('-ListTasks')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'TaskStoreURL must use https'
+    }
+}
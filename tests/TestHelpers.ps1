@@ -0,0 +1,162 @@
+#Requires -Version 7.0
+
+# Helpers shared by the test files that run bolt.ps1 in a temp project.
+# Dot-source this file in a test file's BeforeAll, so the helpers are visible
+# to its tests, then create the project:
+#
+#     BeforeAll {
+#         . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+#         Initialize-TestProject -Name 'Cache'
+#     }
+#
+#     AfterAll {
+#         Remove-TestProject
+#     }
+#
+# A test file that needs a different task layout defines its own New-TestTask
+# after the dot-source line, and that one is used instead.
+
+# Helper function to create the temp project directory and copy bolt.ps1 into it.
+# Sets $script:ProjectRoot, $script:BoltScriptSource, and $script:TempTestRoot.
+function Initialize-TestProject {
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$Name
+    )
+
+    $script:ProjectRoot = Split-Path -Path $PSScriptRoot -Parent
+    $script:BoltScriptSource = Join-Path -Path $script:ProjectRoot -ChildPath 'bolt.ps1'
+    $script:TempTestRoot = Join-Path -Path ([System.IO.Path]::GetTempPath()) -ChildPath "Bolt$($Name)Tests_$(Get-Random)"
+
+    New-Item -ItemType Directory -Path $script:TempTestRoot -Force | Out-Null
+    Copy-Item -Path $script:BoltScriptSource -Destination (Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.ps1') -Force
+}
+
+# Helper function to stop the test server and delete the temp project, and any
+# other directories the test file created
+function Remove-TestProject {
+    param(
+        [string[]]$AdditionalPath = @()
+    )
+
+    if ($script:ServerProcess -and -not $script:ServerProcess.HasExited) {
+        Stop-Process -Id $script:ServerProcess.Id -Force -ErrorAction SilentlyContinue
+    }
+
+    foreach ($path in @($script:TempTestRoot) + $AdditionalPath) {
+        if ($path -and (Test-Path -Path $path)) {
+            Remove-Item -Path $path -Recurse -Force -ErrorAction SilentlyContinue
+        }
+    }
+}
+
+# Helper function to invoke bolt.ps1 with arguments, and optionally a file on stdin.
+# Runs that overlap pass their own -OutputName and -ErrorName.
+function Invoke-Bolt {
+    param(
+        [string[]]$Arguments,
+        [string]$InputFile,
+        [string]$OutputName = 'stdout.txt',
+        [string]$ErrorName = 'stderr.txt'
+    )
+
+    $boltScriptPath = Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.ps1'
+    $stdoutPath = Join-Path -Path $script:TempTestRoot -ChildPath $OutputName
+    $stderrPath = Join-Path -Path $script:TempTestRoot -ChildPath $ErrorName
+
+    $params = @{
+        FilePath = 'pwsh'
+        ArgumentList = @('-NoProfile', '-File', $boltScriptPath) + $Arguments
+        WorkingDirectory = $script:TempTestRoot
+        Wait = $true
+        NoNewWindow = $true
+        PassThru = $true
+        RedirectStandardOutput = $stdoutPath
+        RedirectStandardError = $stderrPath
+    }
+    if ($InputFile) {
+        $params.RedirectStandardInput = $InputFile
+    }
+
+    $stopwatch = [System.Diagnostics.Stopwatch]::StartNew()
+    $process = Start-Process @params
+    $stopwatch.Stop()
+
+    $stdout = Get-Content -Path $stdoutPath -Raw -ErrorAction SilentlyContinue
+    $stderr = Get-Content -Path $stderrPath -Raw -ErrorAction SilentlyContinue
+
+    return [PSCustomObject]@{
+        ExitCode = $process.ExitCode
+        Output = $stdout
+        Error = $stderr
+        Elapsed = $stopwatch.Elapsed
+    }
+}
+
+# Helper function to create a task file in the temp .build directory, or in
+# .build/<Namespace> with -Namespace
+function New-TestTask {
+    param(
+        [string]$Name,
+        [string[]]$Depends = @(),
+        [string]$ExtraMetadata = '',
+        [string]$Body = 'exit 0',
+        [string]$Namespace = ''
+    )
+
+    $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+    if ($Namespace) {
+        $buildPath = Join-Path -Path $buildPath -ChildPath $Namespace
+    }
+    New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+
+    $taskNameCapitalized = (Get-Culture).TextInfo.ToTitleCase($Name)
+    $content = @"
+# TASK: $Name
+# DESCRIPTION: Test task $Name
+# DEPENDS: $($Depends -join ', ')
+$ExtraMetadata
+
+Write-Host "Ran $Name"
+$Body
+"@
+    Set-Content -Path (Join-Path -Path $buildPath -ChildPath "Invoke-$taskNameCapitalized.ps1") -Value $content
+}
+
+# Helper function to find a free local TCP port
+function Get-FreePort {
+    $listener = [System.Net.Sockets.TcpListener]::new([System.Net.IPAddress]::Loopback, 0)
+    $listener.Start()
+    $port = $listener.LocalEndpoint.Port
+    $listener.Stop()
+    return $port
+}
+
+# Helper function to start a test HTTP server on a free port and wait until it
+# answers a request for -PingPath. The server script gets its URL prefix first,
+# then -Arguments. Sets $script:ServerPort, $script:ServerUrl, and
+# $script:ServerProcess; Remove-TestProject stops it.
+function Start-TestServer {
+    param(
+        [Parameter(Mandatory = $true)]
+        [string]$ScriptPath,
+
+        [string[]]$Arguments = @(),
+
+        [string]$PingPath = '/ping'
+    )
+
+    $script:ServerPort = Get-FreePort
+    $script:ServerUrl = "http://localhost:$($script:ServerPort)"
+    $script:ServerProcess = Start-Process -FilePath 'pwsh' -ArgumentList (@('-NoProfile', '-File', $ScriptPath, "$($script:ServerUrl)/") + $Arguments) -PassThru -NoNewWindow
+
+    $deadline = (Get-Date).AddSeconds(15)
+    while ((Get-Date) -lt $deadline) {
+        try {
+            Invoke-WebRequest -Uri "$($script:ServerUrl)$PingPath" -SkipHttpErrorCheck -TimeoutSec 2 | Out-Null
+            break
+        } catch {
+            Start-Sleep -Milliseconds 200
+        }
+    }
+}
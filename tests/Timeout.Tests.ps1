@@ -0,0 +1,127 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Timeout'
+
+    # Load ConvertFrom-Duration from bolt.ps1 without running the script
+    $boltAst = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $durationFunction = $boltAst.Find({
+        param($node)
+        $node -is [System.Management.Automation.Language.FunctionDefinitionAst] -and $node.Name -eq 'ConvertFrom-Duration'
+    }, $true)
+    . ([ScriptBlock]::Create($durationFunction.Extent.Text))
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Duration Parsing" -Tag "Core", "Timeout" {
+
+    It "Should parse '<Duration>' as <Milliseconds> ms" -ForEach @(
+        @{ Duration = '0'; Milliseconds = 0 }
+        @{ Duration = '500ms'; Milliseconds = 500 }
+        @{ Duration = '30s'; Milliseconds = 30000 }
+        @{ Duration = '1.5s'; Milliseconds = 1500 }
+        @{ Duration = '5m'; Milliseconds = 300000 }
+        @{ Duration = '1h30m'; Milliseconds = 5400000 }
+        @{ Duration = '2m30s500ms'; Milliseconds = 150500 }
+        @{ Duration = ' 10s '; Milliseconds = 10000 }
+    ) {
+        (ConvertFrom-Duration -Duration $Duration).TotalMilliseconds | Should -Be $Milliseconds
+    }
+
+    It "Should reject '<Duration>'" -ForEach @(
+        @{ Duration = '' }
+        @{ Duration = '30' }
+        @{ Duration = 'soon' }
+        @{ Duration = '10 s' }
+        @{ Duration = '-5s' }
+        @{ Duration = '1d' }
+    ) {
+        { ConvertFrom-Duration -Duration $Duration } | Should -Throw
+    }
+}
+
+Describe "Task Timeout" -Tag "Core", "Timeout" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should cancel a task within 50 ms of its deadline" {
+        New-TestTask -Name 'slow' -ExtraMetadata '# TIMEOUT: 1s' -Body 'Start-Sleep -Seconds 30; exit 0'
+
+        $result = Invoke-Bolt -Arguments @('slow', '-OutputFormat', 'Json')
+        $summary = $result.Output | ConvertFrom-Json
+
+        $result.ExitCode | Should -Be 1
+        $summary.Tasks[0].Status | Should -Be 'timeout'
+        $summary.Tasks[0].DurationMs | Should -BeGreaterOrEqual 1000
+        $summary.Tasks[0].DurationMs | Should -BeLessThan 1050
+    }
+
+    It "Should report the timeout in the human log" {
+        New-TestTask -Name 'slow' -ExtraMetadata '# TIMEOUT: 1s' -Body 'Start-Sleep -Seconds 30; exit 0'
+
+        $result = Invoke-Bolt -Arguments @('slow')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match "Task 'slow' timed out after 1s"
+    }
+
+    It "Should kill processes started by the task" {
+        $pidFile = Join-Path -Path $script:TempTestRoot -ChildPath 'child.pid'
+        $body = @"
+`$child = Start-Process -FilePath pwsh -ArgumentList '-NoProfile', '-Command', 'Start-Sleep -Seconds 30' -PassThru
+Set-Content -Path '$pidFile' -Value `$child.Id
+Start-Sleep -Seconds 30
+exit 0
+"@
+        New-TestTask -Name 'spawner' -ExtraMetadata '# TIMEOUT: 2s' -Body $body
+
+        (Invoke-Bolt -Arguments @('spawner')).ExitCode | Should -Be 1
+
+        $childId = [int](Get-Content -Path $pidFile)
+        Start-Sleep -Milliseconds 500
+        Get-Process -Id $childId -ErrorAction SilentlyContinue | Should -BeNullOrEmpty
+    }
+
+    It "Should not run tasks that depend on a timed out task" {
+        New-TestTask -Name 'slow' -ExtraMetadata '# TIMEOUT: 500ms' -Body 'Start-Sleep -Seconds 30; exit 0'
+        New-TestTask -Name 'after' -Depends @('slow')
+
+        $summary = (Invoke-Bolt -Arguments @('after', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+
+        ($summary.Tasks.Status -join ',') | Should -Be 'timeout,skipped'
+    }
+
+    It "Should time out tasks in -Parallel runs" {
+        New-TestTask -Name 'slow' -ExtraMetadata '# TIMEOUT: 1s' -Body 'Start-Sleep -Seconds 30; exit 0'
+
+        $result = Invoke-Bolt -Arguments @('slow', '-Parallel')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match '\[slow\]\s+timed out after 1s'
+    }
+
+    It "Should not affect tasks that finish in time" {
+        New-TestTask -Name 'quick' -ExtraMetadata '# TIMEOUT: 30s'
+
+        $result = Invoke-Bolt -Arguments @('quick')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran quick'
+    }
+
+    It "Should fail a task with an invalid timeout" {
+        New-TestTask -Name 'broken' -ExtraMetadata '# TIMEOUT: soon'
+
+        $result = Invoke-Bolt -Arguments @('broken')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match 'invalid TIMEOUT'
+        $result.Output | Should -Not -Match 'Ran broken'
+    }
+}
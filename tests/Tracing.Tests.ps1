@@ -0,0 +1,229 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Tracing'
+    $script:TraceDir = Join-Path -Path $script:TempTestRoot -ChildPath 'traces'
+
+    # Helper function to read the spans of the one trace the collector received
+    function Get-ReceivedSpans {
+        $files = @(Get-ChildItem -Path $script:TraceDir -Filter '*.json' -ErrorAction SilentlyContinue)
+        $files.Count | Should -Be 1
+        $trace = Get-Content -Path $files[0].FullName -Raw | ConvertFrom-Json
+        return @($trace.resourceSpans[0].scopeSpans[0].spans)
+    }
+
+    # Helper function to read one attribute value of a span
+    function Get-SpanAttribute {
+        param($Span, [string]$Key)
+
+        $value = ($Span.attributes | Where-Object { $_.key -eq $Key }).value
+        foreach ($field in @('stringValue', 'intValue', 'boolValue')) {
+            if ($null -ne $value.$field) {
+                return $value.$field
+            }
+        }
+        return $null
+    }
+
+    # Load the tracer for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('New-TaskTracer') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+
+    # Start a small OTLP/HTTP collector that saves each trace it receives
+    $serverScript = Join-Path -Path $script:TempTestRoot -ChildPath 'collector.ps1'
+    Set-Content -Path $serverScript -Value @'
+param([string]$Prefix, [string]$TraceDir)
+
+New-Item -ItemType Directory -Path $TraceDir -Force | Out-Null
+$listener = [System.Net.HttpListener]::new()
+$listener.Prefixes.Add($Prefix)
+$listener.Start()
+
+while ($listener.IsListening) {
+    $context = $listener.GetContext()
+    $request = $context.Request
+    $response = $context.Response
+
+    if ($request.HttpMethod -eq 'POST' -and $request.Url.AbsolutePath -eq '/v1/traces') {
+        $reader = [System.IO.StreamReader]::new($request.InputStream)
+        Set-Content -Path (Join-Path -Path $TraceDir -ChildPath "$([DateTime]::UtcNow.Ticks).json") -Value $reader.ReadToEnd()
+        $reader.Dispose()
+        $bytes = [System.Text.Encoding]::UTF8.GetBytes('{}')
+        $response.ContentType = 'application/json'
+        $response.OutputStream.Write($bytes, 0, $bytes.Length)
+    } else {
+        $response.StatusCode = 404
+    }
+
+    $response.Close()
+}
+'@
+    Start-TestServer -ScriptPath $serverScript -Arguments @($script:TraceDir)
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Tracer" -Tag "Core", "Tracing" {
+
+    BeforeEach {
+        $script:Sent = [System.Collections.Generic.List[object]]::new()
+        $script:Tracer = New-TaskTracer -Endpoint 'http://collector:4318' -ServiceName 'my-app' -TaskNames @('build') -Send {
+            param([string]$Url, [string]$Body)
+            $script:Sent.Add([PSCustomObject]@{ Url = $Url; Body = $Body })
+        }
+    }
+
+    It "Should post to /v1/traces under the endpoint" {
+        $script:Tracer.Url | Should -Be 'http://collector:4318/v1/traces'
+        (New-TaskTracer -Endpoint 'http://collector:4318/' -Send {}).Url | Should -Be 'http://collector:4318/v1/traces'
+        (New-TaskTracer -Endpoint 'https://otel.example.com/v1/traces' -Send {}).Url | Should -Be 'https://otel.example.com/v1/traces'
+    }
+
+    It "Should reject endpoints that are not http or https URLs" -ForEach @(
+        @{ Endpoint = 'localhost:4318' }
+        @{ Endpoint = 'ftp://collector/traces' }
+        @{ Endpoint = 'not a url' }
+    ) {
+        { New-TaskTracer -Endpoint $Endpoint -Send {} } | Should -Throw -ErrorId 'InvalidOtelEndpoint'
+    }
+
+    It "Should send a root span with a child span per task" {
+        $script:Tracer.AddTaskSpan('lint', 'success', 120, 0, $false)
+        $script:Tracer.AddTaskSpan('build', 'success', 340, 0, $false)
+        $script:Tracer.Shutdown()
+
+        $script:Sent.Count | Should -Be 1
+        $script:Sent[0].Url | Should -Be 'http://collector:4318/v1/traces'
+        $trace = $script:Sent[0].Body | ConvertFrom-Json
+        $resource = $trace.resourceSpans[0].resource
+        ($resource.attributes | Where-Object { $_.key -eq 'service.name' }).value.stringValue | Should -Be 'my-app'
+
+        $spans = @($trace.resourceSpans[0].scopeSpans[0].spans)
+        $spans.Count | Should -Be 3
+        $spans[0].name | Should -Be 'bolt run'
+        $spans[0].parentSpanId | Should -BeNullOrEmpty
+        $spans[0].traceId | Should -Match '^[0-9a-f]{32}$'
+        $spans[0].spanId | Should -Match '^[0-9a-f]{16}$'
+        foreach ($span in $spans[1..2]) {
+            $span.traceId | Should -Be $spans[0].traceId
+            $span.parentSpanId | Should -Be $spans[0].spanId
+            $span.spanId | Should -Not -Be $spans[0].spanId
+        }
+        ($spans[1..2].name -join ',') | Should -Be 'lint,build'
+    }
+
+    It "Should set the task attributes on each span" {
+        $script:Tracer.AddTaskSpan('build', 'skipped', 15, 0, $true)
+        $script:Tracer.Shutdown()
+
+        $span = @(($script:Sent[0].Body | ConvertFrom-Json).resourceSpans[0].scopeSpans[0].spans)[1]
+        Get-SpanAttribute -Span $span -Key 'bolt.task.name' | Should -Be 'build'
+        Get-SpanAttribute -Span $span -Key 'bolt.task.status' | Should -Be 'skipped'
+        Get-SpanAttribute -Span $span -Key 'bolt.task.exit_code' | Should -Be '0'
+        Get-SpanAttribute -Span $span -Key 'bolt.task.cached' | Should -BeTrue
+        Get-SpanAttribute -Span $span -Key 'bolt.task.duration_ms' | Should -Be '15'
+    }
+
+    It "Should start a task span its duration before it ends" {
+        $script:Tracer.AddTaskSpan('build', 'success', 1500, 0, $false)
+        $script:Tracer.Shutdown()
+
+        $span = @(($script:Sent[0].Body | ConvertFrom-Json).resourceSpans[0].scopeSpans[0].spans)[1]
+        ([long]$span.endTimeUnixNano - [long]$span.startTimeUnixNano) | Should -Be 1500000000
+    }
+
+    It "Should mark failed tasks and their run with an error status" {
+        $script:Tracer.AddTaskSpan('lint', 'success', 10, 0, $false)
+        $script:Tracer.AddTaskSpan('test', 'failure', 10, 3, $false)
+        $script:Tracer.Shutdown()
+
+        $spans = @(($script:Sent[0].Body | ConvertFrom-Json).resourceSpans[0].scopeSpans[0].spans)
+        $spans[0].status.code | Should -Be 2
+        $spans[1].status.code | Should -Be 0
+        $spans[2].status.code | Should -Be 2
+        Get-SpanAttribute -Span $spans[2] -Key 'bolt.task.exit_code' | Should -Be '3'
+    }
+
+    It "Should send the trace only once" {
+        $script:Tracer.Shutdown()
+        $script:Tracer.Shutdown()
+
+        $script:Sent.Count | Should -Be 1
+    }
+}
+
+Describe "Tracing Runs" -Tag "Core", "Tracing" {
+
+    BeforeEach {
+        foreach ($path in @('.build', '.bolt', 'traces', 'src')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+        New-Item -ItemType Directory -Path $script:TraceDir -Force | Out-Null
+    }
+
+    It "Should send a span for every task in the run" {
+        New-TestTask -Name 'lint'
+        New-TestTask -Name 'build' -Depends @('lint')
+
+        $result = Invoke-Bolt -Arguments @('build', '-OtelEndpoint', $script:ServerUrl, '-OtelServiceName', 'bolt-tests')
+
+        $result.ExitCode | Should -Be 0
+        $spans = Get-ReceivedSpans
+        $spans[0].name | Should -Be 'bolt run'
+        Get-SpanAttribute -Span $spans[0] -Key 'bolt.tasks' | Should -Be 'build'
+        ($spans[1..($spans.Count - 1)].name -join ',') | Should -Be 'lint,build'
+    }
+
+    It "Should send the trace when a task fails" {
+        New-TestTask -Name 'broken' -Body 'exit 4'
+
+        $result = Invoke-Bolt -Arguments @('broken', '-OtelEndpoint', $script:ServerUrl)
+
+        $result.ExitCode | Should -Be 1
+        $spans = Get-ReceivedSpans
+        $spans[0].status.code | Should -Be 2
+        $spans[1].status.code | Should -Be 2
+        Get-SpanAttribute -Span $spans[1] -Key 'bolt.task.exit_code' | Should -Be '4'
+    }
+
+    It "Should mark cached tasks" {
+        New-Item -ItemType Directory -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'src') -Force | Out-Null
+        Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'src/main.txt') -Value 'main'
+        New-TestTask -Name 'check' -ExtraMetadata '# INPUTS: src/*.txt'
+        (Invoke-Bolt -Arguments @('check')).ExitCode | Should -Be 0
+
+        $result = Invoke-Bolt -Arguments @('check', '-OtelEndpoint', $script:ServerUrl)
+
+        $result.ExitCode | Should -Be 0
+        $span = (Get-ReceivedSpans)[1]
+        Get-SpanAttribute -Span $span -Key 'bolt.task.cached' | Should -BeTrue
+        Get-SpanAttribute -Span $span -Key 'bolt.task.status' | Should -Be 'skipped'
+    }
+
+    It "Should only warn when the collector cannot be reached" {
+        New-TestTask -Name 'lint'
+
+        $result = Invoke-Bolt -Arguments @('lint', '-OtelEndpoint', "http://localhost:$(Get-FreePort)")
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran lint'
+        "$($result.Output)$($result.Error)" | Should -Match 'Could not send the trace'
+    }
+
+    It "Should reject an invalid endpoint before any task runs" {
+        New-TestTask -Name 'lint'
+
+        $result = Invoke-Bolt -Arguments @('lint', '-OtelEndpoint', 'localhost:4318')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'must be an http:// or https:// URL'
+        $result.Output | Should -Not -Match 'Ran lint'
+    }
+}
@@ -0,0 +1,257 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Upgrade'
+    $script:ServedRoot = Join-Path -Path ([System.IO.Path]::GetTempPath()) -ChildPath "BoltUpgradeServed_$(Get-Random)"
+
+    # Helper function to publish a release on the test server: a Bolt-<version>.zip
+    # holding Bolt/bolt.ps1 (and Bolt/Bolt.psm1 with -ModuleContent), its .sha256 file,
+    # and releases/latest with both assets
+    function Publish-Release {
+        param(
+            [string]$Version,
+            [string]$ScriptContent,
+            [string]$ModuleContent,
+            [string]$Sha256
+        )
+
+        $stagePath = Join-Path -Path $script:TempTestRoot -ChildPath "stage-$Version"
+        Remove-Item -Path $stagePath -Recurse -Force -ErrorAction SilentlyContinue
+        New-Item -ItemType Directory -Path (Join-Path $stagePath 'Bolt') -Force | Out-Null
+        Set-Content -Path (Join-Path $stagePath 'Bolt' | Join-Path -ChildPath 'bolt.ps1') -Value $ScriptContent
+        if ($ModuleContent) {
+            Set-Content -Path (Join-Path $stagePath 'Bolt' | Join-Path -ChildPath 'Bolt.psm1') -Value $ModuleContent
+        }
+
+        $archiveName = "Bolt-$Version.zip"
+        $archivePath = Join-Path -Path $script:ServedRoot -ChildPath $archiveName
+        Compress-Archive -Path (Join-Path $stagePath 'Bolt') -DestinationPath $archivePath -Force
+        $digest = if ($Sha256) { $Sha256 } else { (Get-FileHash -Path $archivePath -Algorithm SHA256).Hash }
+        "$digest  $archiveName" | Out-File -FilePath "$archivePath.sha256" -Encoding UTF8
+
+        $release = @{
+            tag_name = "v$Version"
+            assets   = @(
+                @{ name = $archiveName; browser_download_url = "$($script:ServerUrl)/$archiveName" }
+                @{ name = "$archiveName.sha256"; browser_download_url = "$($script:ServerUrl)/$archiveName.sha256" }
+            )
+        }
+        New-Item -ItemType Directory -Path (Join-Path $script:ServedRoot 'releases') -Force | Out-Null
+        $release | ConvertTo-Json -Depth 5 | Set-Content -Path (Join-Path $script:ServedRoot 'releases' | Join-Path -ChildPath 'latest')
+    }
+
+    New-Item -ItemType Directory -Path $script:ServedRoot -Force | Out-Null
+
+    # Load the upgrade functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('Get-BoltLatestRelease', 'Test-NewerBoltVersion', 'Get-BoltUpgradeTarget', 'Install-BoltRelease') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+
+    # Start a small file server for the served directory
+    $serverScript = Join-Path -Path $script:TempTestRoot -ChildPath 'file-server.ps1'
+    Set-Content -Path $serverScript -Value @'
+param([string]$Prefix, [string]$Root)
+
+$listener = [System.Net.HttpListener]::new()
+$listener.Prefixes.Add($Prefix)
+$listener.Start()
+
+while ($listener.IsListening) {
+    $context = $listener.GetContext()
+    $request = $context.Request
+    $response = $context.Response
+
+    $path = Join-Path -Path $Root -ChildPath $request.Url.AbsolutePath.TrimStart('/')
+    if (Test-Path -LiteralPath $path -PathType Leaf) {
+        $bytes = [System.IO.File]::ReadAllBytes($path)
+        $response.OutputStream.Write($bytes, 0, $bytes.Length)
+    } else {
+        $response.StatusCode = 404
+    }
+
+    $response.Close()
+}
+'@
+    Start-TestServer -ScriptPath $serverScript -Arguments @($script:ServedRoot)
+}
+
+AfterAll {
+    Remove-TestProject -AdditionalPath $script:ServedRoot
+}
+
+Describe "Bolt Version Comparison" -Tag "Core", "Upgrade" {
+
+    It "Should report <Candidate> newer than <Current> as <Expected>" -ForEach @(
+        @{ Current = '0.13.1'; Candidate = '0.14.0'; Expected = $true }
+        @{ Current = '0.13.1'; Candidate = 'v0.13.2'; Expected = $true }
+        @{ Current = '0.13.1'; Candidate = '0.13.1'; Expected = $false }
+        @{ Current = '0.14.0'; Candidate = '0.13.1'; Expected = $false }
+        @{ Current = '1.0.0-beta'; Candidate = '1.0.0'; Expected = $true }
+        @{ Current = '1.0.0'; Candidate = '1.0.0-rc1'; Expected = $false }
+        @{ Current = '0.0.0-dev'; Candidate = '0.1.0'; Expected = $true }
+        @{ Current = '0.9.0'; Candidate = '0.10.0'; Expected = $true }
+    ) {
+        Test-NewerBoltVersion -Current $Current -Candidate $Candidate | Should -Be $Expected
+    }
+
+    It "Should reject a version that is not a semantic version" {
+        $thrown = $null
+        try {
+            Test-NewerBoltVersion -Current '0.13.1' -Candidate 'nightly'
+        } catch {
+            $thrown = $_
+        }
+
+        $thrown.FullyQualifiedErrorId | Should -Be 'InvalidBoltVersion'
+        $thrown.Exception.Message | Should -Match "'nightly' is not a semantic version"
+    }
+
+    It "Should ship bolt.ps1 with the version line the release build stamps" {
+        Get-Content -Path $script:BoltScriptSource -Raw | Should -Match "(?m)^\`$script:BoltVersion = '0\.0\.0-dev'$"
+    }
+}
+
+Describe "Bolt Upgrade" -Tag "Core", "Upgrade" {
+
+    BeforeEach {
+        Get-ChildItem -Path $script:ServedRoot -Force | Remove-Item -Recurse -Force
+        $script:TargetPath = Join-Path -Path $script:TempTestRoot -ChildPath 'target' | Join-Path -ChildPath 'bolt.ps1'
+        New-Item -ItemType Directory -Path (Split-Path $script:TargetPath -Parent) -Force | Out-Null
+        Set-Content -Path $script:TargetPath -Value "# old bolt"
+    }
+
+    It "Should read the tag and assets of the latest release" {
+        Publish-Release -Version '0.14.0' -ScriptContent '# new bolt'
+
+        $release = Get-BoltLatestRelease -ApiUrl "$($script:ServerUrl)/releases/latest"
+
+        $release.Tag | Should -Be 'v0.14.0'
+        $release.Version | Should -Be '0.14.0'
+        $release.Assets['Bolt-0.14.0.zip'] | Should -Be "$($script:ServerUrl)/Bolt-0.14.0.zip"
+        $release.Assets['Bolt-0.14.0.zip.sha256'] | Should -Be "$($script:ServerUrl)/Bolt-0.14.0.zip.sha256"
+    }
+
+    It "Should report an unreachable release API" {
+        $thrown = $null
+        try {
+            Get-BoltLatestRelease -ApiUrl "$($script:ServerUrl)/releases/missing"
+        } catch {
+            $thrown = $_
+        }
+
+        $thrown.FullyQualifiedErrorId | Should -Be 'BoltReleaseUnavailable'
+        $thrown.Exception.Message | Should -Match 'Failed to read the latest bolt release'
+    }
+
+    It "Should replace the target with the bolt.ps1 from a verified release" {
+        Publish-Release -Version '0.14.0' -ScriptContent '# new bolt'
+        $release = Get-BoltLatestRelease -ApiUrl "$($script:ServerUrl)/releases/latest"
+
+        $path = Install-BoltRelease -Release $release -TargetPath $script:TargetPath
+
+        $path | Should -Be $script:TargetPath
+        (Get-Content -Path $script:TargetPath -Raw).Trim() | Should -Be '# new bolt'
+        Test-Path -Path "$($script:TargetPath).upgrade" | Should -BeFalse
+    }
+
+    It "Should leave the target unchanged when the checksum does not match" {
+        Publish-Release -Version '0.14.0' -ScriptContent '# tampered bolt' -Sha256 ('0' * 64)
+        $release = Get-BoltLatestRelease -ApiUrl "$($script:ServerUrl)/releases/latest"
+
+        $thrown = $null
+        try {
+            Install-BoltRelease -Release $release -TargetPath $script:TargetPath
+        } catch {
+            $thrown = $_
+        }
+
+        $thrown.FullyQualifiedErrorId | Should -Be 'BoltReleaseHashMismatch'
+        $thrown.Exception.Message | Should -Match "failed SHA-256 verification: expected 0{64}, got [0-9a-f]{64}"
+        (Get-Content -Path $script:TargetPath -Raw).Trim() | Should -Be '# old bolt'
+        Test-Path -Path "$($script:TargetPath).upgrade" | Should -BeFalse
+    }
+
+    It "Should reject <Case>" -ForEach @(
+        @{ Case = 'a release without a checksum file'; Assets = @{ 'Bolt-0.14.0.zip' = 'https://example.com/Bolt-0.14.0.zip' }; Message = 'has no Bolt-0\.14\.0\.zip\.sha256 asset' }
+        @{ Case = 'plain http to another host'; Assets = @{ 'Bolt-0.14.0.zip' = 'http://example.com/Bolt-0.14.0.zip'; 'Bolt-0.14.0.zip.sha256' = 'https://example.com/Bolt-0.14.0.zip.sha256' }; Message = 'must be an https:// URL' }
+    ) {
+        $release = [PSCustomObject]@{ Tag = 'v0.14.0'; Version = '0.14.0'; Assets = $Assets }
+
+        $thrown = $null
+        try {
+            Install-BoltRelease -Release $release -TargetPath $script:TargetPath
+        } catch {
+            $thrown = $_
+        }
+
+        $thrown.FullyQualifiedErrorId | Should -Be 'BoltReleaseUnavailable'
+        $thrown.Exception.Message | Should -Match $Message
+        (Get-Content -Path $script:TargetPath -Raw).Trim() | Should -Be '# old bolt'
+    }
+
+    It "Should replace the whole module directory with -Module" {
+        $modulePath = Join-Path -Path $script:TempTestRoot -ChildPath 'modules' | Join-Path -ChildPath 'Bolt'
+        New-Item -ItemType Directory -Path $modulePath -Force | Out-Null
+        Set-Content -Path (Join-Path $modulePath 'bolt.ps1') -Value '# old bolt'
+        Set-Content -Path (Join-Path $modulePath 'Bolt.psm1') -Value '# old module'
+        Set-Content -Path (Join-Path $modulePath 'stale.txt') -Value 'left over'
+        Publish-Release -Version '0.14.0' -ScriptContent '# new bolt' -ModuleContent '# new module'
+        $release = Get-BoltLatestRelease -ApiUrl "$($script:ServerUrl)/releases/latest"
+
+        $path = Install-BoltRelease -Release $release -TargetPath $modulePath -Module
+
+        $path | Should -Be $modulePath
+        (Get-Content -Path (Join-Path $modulePath 'bolt.ps1') -Raw).Trim() | Should -Be '# new bolt'
+        (Get-Content -Path (Join-Path $modulePath 'Bolt.psm1') -Raw).Trim() | Should -Be '# new module'
+        Test-Path -Path (Join-Path $modulePath 'stale.txt') | Should -BeFalse
+        Test-Path -Path "$modulePath.upgrade" | Should -BeFalse
+        Test-Path -Path "$modulePath.previous" | Should -BeFalse
+    }
+
+    It "Should upgrade the module directory of a module install" {
+        $modulePath = Join-Path -Path $script:TempTestRoot -ChildPath 'modules' | Join-Path -ChildPath 'Bolt'
+        New-Item -ItemType Directory -Path $modulePath -Force | Out-Null
+        Set-Content -Path (Join-Path $modulePath 'Bolt.psm1') -Value '# module'
+
+        $target = Get-BoltUpgradeTarget -ScriptPath (Join-Path $modulePath 'bolt.ps1') -Version '0.13.1'
+
+        $target.Path | Should -Be $modulePath
+        $target.Module | Should -BeTrue
+        (Get-BoltUpgradeTarget -ScriptPath $script:TargetPath -Version '0.13.1').Module | Should -BeFalse
+    }
+
+    It "Should refuse to apply an upgrade to a development copy" {
+        $result = Invoke-Bolt -Arguments @('-Upgrade', 'Apply')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match 'development copy \(version 0\.0\.0-dev\)'
+        Get-Content -Path (Join-Path $script:TempTestRoot 'bolt.ps1') -Raw | Should -Be (Get-Content -Path $script:BoltScriptSource -Raw)
+    }
+
+    It "Should refuse to apply an upgrade inside a git work tree" -Skip:(-not (Get-Command git -ErrorAction SilentlyContinue)) {
+        $repoPath = Join-Path -Path $script:TempTestRoot -ChildPath 'repo'
+        New-Item -ItemType Directory -Path (Join-Path $repoPath 'tools') -Force | Out-Null
+        git init --quiet $repoPath
+
+        $thrown = $null
+        try {
+            Get-BoltUpgradeTarget -ScriptPath (Join-Path $repoPath 'tools' | Join-Path -ChildPath 'bolt.ps1') -Version '0.13.1'
+        } catch {
+            $thrown = $_
+        }
+
+        $thrown.FullyQualifiedErrorId | Should -Be 'BoltUpgradeRefused'
+        $thrown.Exception.Message | Should -Match 'is in the git work tree'
+    }
+
+    It "Should reject an unknown -Upgrade action" {
+        $result = Invoke-Bolt -Arguments @('-Upgrade', 'Downgrade')
+
+        $result.ExitCode | Should -Not -Be 0
+        $result.Error | Should -Match 'Downgrade'
+    }
+}
@@ -0,0 +1,108 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Validation'
+
+    # Helper function to create a task file with extra metadata lines
+    function New-TestTask {
+        param(
+            [string]$Name,
+            [string[]]$Metadata = @('# DESCRIPTION: Test task', '# DEPENDS:'),
+            [string]$Body = "Write-Host 'Ran'`nexit 0"
+        )
+
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+
+        $taskNameCapitalized = (Get-Culture).TextInfo.ToTitleCase($Name)
+        $content = "# TASK: $Name`n$($Metadata -join "`n")`n`n$Body"
+        Set-Content -Path (Join-Path -Path $buildPath -ChildPath "Invoke-$taskNameCapitalized.ps1") -Value $content
+    }
+
+    # Helper function to read the Task | Field | Issue rows from the report
+    function Get-IssueRows {
+        param([string]$Output)
+
+        return @([regex]::Matches($Output, '(?m)^(\S+)\s+\|\s+(\S+)\s+\|\s+(.+)$') |
+            Where-Object { $_.Groups[1].Value -ne 'Task' -and $_.Groups[1].Value -notmatch '^-+$' } |
+            ForEach-Object {
+                [PSCustomObject]@{
+                    Task = $_.Groups[1].Value
+                    Field = $_.Groups[2].Value
+                    Issue = $_.Groups[3].Value.Trim()
+                }
+            })
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Metadata Validation" -Tag "Core", "Validation" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should exit with 0 and print no issue table for valid tasks" {
+        New-TestTask -Name 'base'
+        New-TestTask -Name 'build' -Metadata @('# DESCRIPTION: Builds', '# DEPENDS: base', '# TIMEOUT: 5m', '# INPUTS: src/**/*.ps1', '# OUTPUTS: out/app.zip')
+
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Not -Match 'Issues:'
+    }
+
+    It "Should report <Field> issues" -ForEach @(
+        @{ Field = 'DEPENDS'; Metadata = @('# DESCRIPTION: Test task', '# DEPENDS: missing-task'); Issue = "Dependency 'missing-task' does not exist" }
+        @{ Field = 'TIMEOUT'; Metadata = @('# DESCRIPTION: Test task', '# DEPENDS:', '# TIMEOUT: soon'); Issue = "Invalid duration 'soon'" }
+        @{ Field = 'TIMEOUT'; Metadata = @('# DESCRIPTION: Test task', '# DEPENDS:', '# TIMEOUT: 0s'); Issue = 'must be greater than zero' }
+        @{ Field = 'INPUTS'; Metadata = @('# DESCRIPTION: Test task', '# DEPENDS:', '# INPUTS: ../outside/*.txt'); Issue = 'must be relative to the project root' }
+        @{ Field = 'OUTPUTS'; Metadata = @('# DESCRIPTION: Test task', '# DEPENDS:', '# OUTPUTS: /tmp/out.txt'); Issue = 'must be relative to the project root' }
+    ) {
+        New-TestTask -Name 'broken' -Metadata $Metadata
+
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+        $rows = Get-IssueRows -Output $result.Output
+
+        $result.ExitCode | Should -Be 1
+        $rows.Count | Should -Be 1
+        $rows[0].Task | Should -Be 'broken'
+        $rows[0].Field | Should -Be $Field
+        $rows[0].Issue | Should -Match ([regex]::Escape($Issue))
+    }
+
+    It "Should report a task script with no commands" {
+        New-TestTask -Name 'empty' -Body "# Nothing to do yet`n<#`nexit 0`n#>"
+
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+        $rows = Get-IssueRows -Output $result.Output
+
+        $result.ExitCode | Should -Be 1
+        ($rows | ForEach-Object { "$($_.Task)|$($_.Field)" }) -join ',' | Should -Be 'empty|script'
+    }
+
+    It "Should list one row per issue sorted by task" {
+        New-TestTask -Name 'zeta' -Metadata @('# DESCRIPTION: Test task', '# DEPENDS: nope', '# TIMEOUT: soon')
+        New-TestTask -Name 'alpha' -Metadata @('# DESCRIPTION: Test task', '# DEPENDS: missing')
+
+        $rows = Get-IssueRows -Output (Invoke-Bolt -Arguments @('-ValidateTasks')).Output
+
+        ($rows | ForEach-Object { "$($_.Task)|$($_.Field)" }) -join ',' | Should -Be 'alpha|DEPENDS,zeta|DEPENDS,zeta|TIMEOUT'
+    }
+
+    It "Should only fail on a missing description with -Strict" {
+        New-TestTask -Name 'quiet' -Metadata @('# DEPENDS:')
+
+        $default = Invoke-Bolt -Arguments @('-ValidateTasks')
+        $strict = Invoke-Bolt -Arguments @('-ValidateTasks', '-Strict')
+        $rows = Get-IssueRows -Output $strict.Output
+
+        $default.ExitCode | Should -Be 0
+        $strict.ExitCode | Should -Be 1
+        $rows[0].Field | Should -Be 'DESCRIPTION'
+    }
+}
@@ -0,0 +1,138 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Substitution'
+
+    # Load Expand-TaskVariables from bolt.ps1 without running the script
+    $boltAst = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $expandFunction = $boltAst.Find({
+        param($node)
+        $node -is [System.Management.Automation.Language.FunctionDefinitionAst] -and $node.Name -eq 'Expand-TaskVariables'
+    }, $true)
+    . ([ScriptBlock]::Create("using namespace System.Management.Automation`n$($expandFunction.Extent.Text)"))
+
+    # Helper function to create a task with ENV lines and one inline before hook
+    function New-TestTask {
+        param(
+            [string]$Name,
+            [string[]]$Env = @(),
+            [string]$Before
+        )
+
+        $buildPath = Join-Path -Path $script:TempTestRoot -ChildPath '.build'
+        New-Item -ItemType Directory -Path $buildPath -Force | Out-Null
+
+        $envLines = ($Env | ForEach-Object { "# ENV: $_" }) -join "`n"
+        $taskNameCapitalized = (Get-Culture).TextInfo.ToTitleCase($Name)
+        $content = @"
+# TASK: $Name
+# DESCRIPTION: Test task $Name
+# DEPENDS:
+# BEFORE: $Before
+$envLines
+
+Write-Host "Main $Name"
+exit 0
+"@
+        Set-Content -Path (Join-Path -Path $buildPath -ChildPath "Invoke-$taskNameCapitalized.ps1") -Value $content
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Expand-TaskVariables" -Tag "Core", "Substitution" {
+
+    It "Should replace placeholders with environment values" {
+        Expand-TaskVariables -Value 'go build -o ${OUT}/${NAME}' -Environment @{ OUT = 'bin'; NAME = 'app' } |
+            Should -Be 'go build -o bin/app'
+    }
+
+    It "Should expand nested references" {
+        $environment = @{ A = '${B}-a'; B = '${C}-b'; C = 'c' }
+
+        Expand-TaskVariables -Value '${A}' -Environment $environment | Should -Be 'c-b-a'
+    }
+
+    It "Should allow 10 levels of nesting" {
+        $environment = @{ V10 = 'end' }
+        for ($i = 1; $i -lt 10; $i++) { $environment["V$i"] = "`${V$($i + 1)}" }
+
+        Expand-TaskVariables -Value '${V1}' -Environment $environment | Should -Be 'end'
+    }
+
+    It "Should throw a CircularReference error for a cycle" {
+        $environment = @{ A = '${B}'; B = '${A}' }
+
+        $caught = $null
+        try { Expand-TaskVariables -Value '${A}' -Environment $environment } catch { $caught = $_ }
+
+        $caught.FullyQualifiedErrorId | Should -Be 'CircularReference'
+        $caught.Exception.Message | Should -Match 'A -> B -> A'
+    }
+
+    It "Should throw an UndefinedVariable error by default" {
+        $caught = $null
+        try { Expand-TaskVariables -Value 'x ${MISSING}' -Environment @{} } catch { $caught = $_ }
+
+        $caught.FullyQualifiedErrorId | Should -Be 'UndefinedVariable'
+        $caught.Exception.Message | Should -Match '\$\{MISSING\}'
+    }
+
+    It "Should handle undefined variables with -UndefinedVars <Mode>" -ForEach @(
+        @{ Mode = 'Empty'; Expected = 'x []' }
+        @{ Mode = 'Passthrough'; Expected = 'x [${MISSING}]' }
+    ) {
+        Expand-TaskVariables -Value 'x [${MISSING}]' -Environment @{} -UndefinedVars $Mode | Should -Be $Expected
+    }
+
+    It "Should leave text without placeholders unchanged" {
+        Expand-TaskVariables -Value 'Write-Host $env:HOME "{braces}" $x' -Environment @{} | Should -Be 'Write-Host $env:HOME "{braces}" $x'
+    }
+}
+
+Describe "Inline Hook Substitution" -Tag "Core", "Substitution" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should substitute task environment variables in inline hooks" {
+        New-TestTask -Name 'greet' -Env @('BOLT_SUB_NAME=world') -Before "Write-Host 'value=[`${BOLT_SUB_NAME}]'"
+
+        $result = Invoke-Bolt -Arguments @('greet')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '(?m)^value=\[world\]'
+    }
+
+    It "Should fail the task for an undefined variable by default" {
+        New-TestTask -Name 'greet' -Before "Write-Host 'value=[`${BOLT_SUB_MISSING}]'"
+
+        $result = Invoke-Bolt -Arguments @('greet')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match "Undefined variable '\$\{BOLT_SUB_MISSING\}'"
+        $result.Output | Should -Not -Match 'Main greet'
+    }
+
+    It "Should substitute an empty string with -UndefinedVars Empty" {
+        New-TestTask -Name 'greet' -Before "Write-Host 'value=[`${BOLT_SUB_MISSING}]'"
+
+        $result = Invoke-Bolt -Arguments @('greet', '-UndefinedVars', 'Empty')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '(?m)^value=\[\]'
+    }
+
+    It "Should keep the placeholder with -UndefinedVars Passthrough" {
+        New-TestTask -Name 'greet' -Before "Write-Host 'value=[`${BOLT_SUB_MISSING}]'"
+
+        $result = Invoke-Bolt -Arguments @('greet', '-UndefinedVars', 'Passthrough')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '(?m)^value=\[\$\{BOLT_SUB_MISSING\}\]'
+    }
+}
@@ -0,0 +1,156 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'Watch'
+    $script:StdoutPath = Join-Path -Path $script:TempTestRoot -ChildPath 'stdout.txt'
+    $script:StdinPath = Join-Path -Path $script:TempTestRoot -ChildPath 'stdin.txt'
+
+    # Helper function to start bolt.ps1 in watch mode without waiting for it
+    function Start-BoltWatch {
+        param(
+            [string[]]$Arguments
+        )
+
+        $boltScriptPath = Join-Path -Path $script:TempTestRoot -ChildPath 'bolt.ps1'
+
+        # Redirect stdin so watch mode does not take over the test console
+        Set-Content -Path $script:StdinPath -Value ''
+
+        $params = @{
+            FilePath = 'pwsh'
+            ArgumentList = @('-NoProfile', '-File', $boltScriptPath) + $Arguments
+            WorkingDirectory = $script:TempTestRoot
+            NoNewWindow = $true
+            PassThru = $true
+            RedirectStandardInput = $script:StdinPath
+            RedirectStandardOutput = $script:StdoutPath
+            RedirectStandardError = (Join-Path -Path $script:TempTestRoot -ChildPath 'stderr.txt')
+        }
+
+        $process = Start-Process @params
+        # Keep the handle open so ExitCode is available after the process exits
+        $null = $process.Handle
+        return $process
+    }
+
+    # Helper function to read watch output so far
+    function Get-WatchOutput {
+        return (Get-Content -Path $script:StdoutPath -Raw -ErrorAction SilentlyContinue) ?? ''
+    }
+
+    # Helper function to wait until the output matches a pattern a number of times
+    function Wait-WatchOutput {
+        param(
+            [string]$Pattern,
+            [int]$Count = 1,
+            [int]$TimeoutSeconds = 30
+        )
+
+        $deadline = (Get-Date).AddSeconds($TimeoutSeconds)
+        while ((Get-Date) -lt $deadline) {
+            if ([regex]::Matches((Get-WatchOutput), $Pattern).Count -ge $Count) {
+                return $true
+            }
+            Start-Sleep -Milliseconds 100
+        }
+        return $false
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Watch Mode" -Tag "Core", "Watch" {
+
+    BeforeEach {
+        foreach ($path in @('.build', '.bolt', 'styles', 'scripts')) {
+            Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath $path) -Recurse -Force -ErrorAction SilentlyContinue
+        }
+        New-Item -ItemType Directory -Path (Join-Path $script:TempTestRoot 'styles'), (Join-Path $script:TempTestRoot 'scripts') -Force | Out-Null
+        Set-Content -Path (Join-Path $script:TempTestRoot 'styles/site.css') -Value 'body {}'
+        Set-Content -Path (Join-Path $script:TempTestRoot 'scripts/site.js') -Value '// js'
+
+        # site depends on styles and scripts, which each watch their own folder
+        New-TestTask -Name 'styles' -ExtraMetadata '# INPUTS: styles/*.css'
+        New-TestTask -Name 'scripts' -ExtraMetadata '# INPUTS: scripts/*.js'
+        New-TestTask -Name 'site' -Depends @('styles', 'scripts')
+
+        $script:WatchProcess = $null
+    }
+
+    AfterEach {
+        if ($script:WatchProcess -and -not $script:WatchProcess.HasExited) {
+            Stop-Process -Id $script:WatchProcess.Id -Force -ErrorAction SilentlyContinue
+            $script:WatchProcess.WaitForExit(5000) | Out-Null
+        }
+    }
+
+    It "Should run the tasks once before watching" {
+        $script:WatchProcess = Start-BoltWatch -Arguments @('site', '-Watch')
+
+        Wait-WatchOutput -Pattern 'Watching 2 task\(s\) for changes' | Should -BeTrue
+        $output = Get-WatchOutput
+        ([regex]::Matches($output, 'Ran styles')).Count | Should -Be 1
+        ([regex]::Matches($output, 'Ran scripts')).Count | Should -Be 1
+        ([regex]::Matches($output, 'Ran site')).Count | Should -Be 1
+    }
+
+    It "Should re-run only the changed task and its dependents" {
+        $script:WatchProcess = Start-BoltWatch -Arguments @('site', '-Watch')
+        Wait-WatchOutput -Pattern 'Watching 2 task' | Should -BeTrue
+
+        Set-Content -Path (Join-Path $script:TempTestRoot 'styles/site.css') -Value 'body { color: red; }'
+
+        Wait-WatchOutput -Pattern 'Ran site' -Count 2 | Should -BeTrue
+        $output = Get-WatchOutput
+        $output | Should -Match 'Change detected, running: styles, site'
+        ([regex]::Matches($output, 'Ran styles')).Count | Should -Be 2
+        ([regex]::Matches($output, 'Ran scripts')).Count | Should -Be 1
+    }
+
+    It "Should debounce a burst of changes into one run" {
+        $script:WatchProcess = Start-BoltWatch -Arguments @('site', '-Watch', '-Debounce', '500')
+        Wait-WatchOutput -Pattern 'Watching 2 task' | Should -BeTrue
+
+        foreach ($i in 1..5) {
+            Set-Content -Path (Join-Path $script:TempTestRoot "styles/extra$i.css") -Value "/* $i */"
+            Start-Sleep -Milliseconds 50
+        }
+
+        Wait-WatchOutput -Pattern 'Ran styles' -Count 2 | Should -BeTrue
+        Start-Sleep -Seconds 2
+        ([regex]::Matches((Get-WatchOutput), 'Change detected')).Count | Should -Be 1
+    }
+
+    It "Should ignore changes outside the declared inputs" {
+        $script:WatchProcess = Start-BoltWatch -Arguments @('site', '-Watch')
+        Wait-WatchOutput -Pattern 'Watching 2 task' | Should -BeTrue
+
+        Set-Content -Path (Join-Path $script:TempTestRoot 'styles/readme.txt') -Value 'not css'
+        Start-Sleep -Seconds 2
+
+        Get-WatchOutput | Should -Not -Match 'Change detected'
+    }
+
+    It "Should reload the tasks when a task file changes" {
+        $script:WatchProcess = Start-BoltWatch -Arguments @('site', '-Watch')
+        Wait-WatchOutput -Pattern 'Watching 2 task' | Should -BeTrue
+
+        Set-Content -Path (Join-Path $script:TempTestRoot '.build/Invoke-Site.ps1') -Value "# TASK: site`n# DESCRIPTION: Test task site`n# DEPENDS: styles, scripts`nWrite-Host 'Ran new site'`nexit 0"
+
+        Wait-WatchOutput -Pattern 'Ran new site' | Should -BeTrue
+        $output = Get-WatchOutput
+        $output | Should -Match 'Task definitions changed, running: site'
+        ([regex]::Matches($output, 'Ran styles')).Count | Should -Be 2
+    }
+
+    It "Should fail when no task declares inputs" {
+        $script:WatchProcess = Start-BoltWatch -Arguments @('site', '-Only', '-Watch')
+
+        $script:WatchProcess.WaitForExit(30000) | Should -BeTrue
+        $script:WatchProcess.ExitCode | Should -Be 1
+        Get-Content -Path (Join-Path $script:TempTestRoot 'stderr.txt') -Raw | Should -Match 'nothing to watch'
+    }
+}
@@ -0,0 +1,180 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'When'
+
+    # Load the condition functions for unit tests
+    $ast = [System.Management.Automation.Language.Parser]::ParseFile($script:BoltScriptSource, [ref]$null, [ref]$null)
+    $functionAsts = $ast.FindAll({ $args[0] -is [System.Management.Automation.Language.FunctionDefinitionAst] }, $true) |
+        Where-Object { $_.Name -in @('ConvertFrom-TaskCondition', 'Test-TaskCondition') }
+    foreach ($functionAst in $functionAsts) {
+        . ([ScriptBlock]::Create($functionAst.Extent.Text))
+    }
+}
+
+AfterAll {
+    Remove-TestProject
+}
+
+Describe "Task Conditions" -Tag "Core", "When" {
+
+    BeforeAll {
+        $script:ConditionRoot = Join-Path -Path $script:TempTestRoot -ChildPath 'condition-root'
+        New-Item -ItemType Directory -Path (Join-Path $script:ConditionRoot 'src') -Force | Out-Null
+        Set-Content -Path (Join-Path $script:ConditionRoot 'go.sum') -Value ''
+        $script:Environment = @{ CI = 'true'; GOOS = 'linux'; EMPTY = '' }
+    }
+
+    It "Should evaluate '<Expression>' as <Expected>" -ForEach @(
+        @{ Expression = "env.CI == 'true'"; Expected = $true }
+        @{ Expression = "env.CI != 'true'"; Expected = $false }
+        @{ Expression = "env.CI == true"; Expected = $true }
+        @{ Expression = "env.CI == 'True'"; Expected = $false }
+        @{ Expression = "exists('go.sum')"; Expected = $true }
+        @{ Expression = "exists(`"src`")"; Expected = $true }
+        @{ Expression = "exists('go.mod')"; Expected = $false }
+        @{ Expression = "env.CI == 'true' && exists('go.sum')"; Expected = $true }
+        @{ Expression = "env.CI == 'true' && exists('go.mod')"; Expected = $false }
+        @{ Expression = "exists('go.mod') || env.GOOS == 'linux'"; Expected = $true }
+        @{ Expression = "!exists('go.mod')"; Expected = $true }
+        @{ Expression = "!(env.GOOS == 'linux' || env.GOOS == 'darwin')"; Expected = $false }
+        @{ Expression = "exists('go.mod') || env.CI == 'true' && env.GOOS == 'windows'"; Expected = $false }
+        @{ Expression = "(exists('go.mod') || env.CI == 'true') && env.GOOS == 'linux'"; Expected = $true }
+        @{ Expression = "env.GOOS"; Expected = $true }
+        @{ Expression = "env.EMPTY"; Expected = $false }
+        @{ Expression = "env.NOT_SET == ''"; Expected = $true }
+        @{ Expression = "false || !false"; Expected = $true }
+    ) {
+        Test-TaskCondition -Expression $Expression -Environment $script:Environment -Root $script:ConditionRoot | Should -Be $Expected
+    }
+
+    It "Should treat an empty expression as true" {
+        Test-TaskCondition -Expression '' -Environment $script:Environment -Root $script:ConditionRoot | Should -BeTrue
+    }
+
+    It "Should reject '<Expression>'" -ForEach @(
+        @{ Expression = "env.CI = 'true'"; Message = "unexpected '=' at column 8" }
+        @{ Expression = "env.CI == 'true' &&"; Message = 'unexpected end of expression' }
+        @{ Expression = "(env.CI == 'true'"; Message = "expected '\)' but found end of expression" }
+        @{ Expression = "exists(go.sum)"; Message = 'exists\(\) takes one quoted path' }
+        @{ Expression = "env.CI == 'true')"; Message = "unexpected '\)' at column 17" }
+        @{ Expression = "CI == 'true'"; Message = "unexpected 'CI' at column 1" }
+        @{ Expression = "`$env:CI -eq 'true'"; Message = 'unexpected ''\$'' at column 1' }
+        @{ Expression = "env.CI == 'true"; Message = "unexpected ''' at column 11" }
+    ) {
+        $thrown = $null
+        try {
+            ConvertFrom-TaskCondition -Expression $Expression
+        } catch {
+            $thrown = $_
+        }
+
+        $thrown | Should -Not -BeNullOrEmpty
+        $thrown.FullyQualifiedErrorId | Should -Be 'InvalidCondition'
+        $thrown.Exception.Message | Should -Match $Message
+    }
+}
+
+Describe "Conditional Task Runs" -Tag "Core", "When" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'marker.txt') -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should run a task whose condition is true" {
+        New-TestTask -Name 'release' -ExtraMetadata "# ENV: RELEASE_CHANNEL=stable`n# WHEN: env.RELEASE_CHANNEL == 'stable'"
+
+        $result = Invoke-Bolt -Arguments @('release')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran release'
+    }
+
+    It "Should skip a task whose condition is false and run its dependents" {
+        New-TestTask -Name 'compile'
+        New-TestTask -Name 'sign' -Depends @('compile') -ExtraMetadata "# WHEN: exists('marker.txt')"
+        New-TestTask -Name 'package' -Depends @('sign')
+
+        $result = Invoke-Bolt -Arguments @('package')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match 'Ran compile'
+        $result.Output | Should -Not -Match 'Ran sign'
+        $result.Output | Should -Match "Task 'sign' condition is false: exists\('marker.txt'\) \(SKIPPED\(condition\)\)"
+        $result.Output | Should -Match 'Ran package'
+    }
+
+    It "Should read the process environment" {
+        $env:BOLT_WHEN_TEST = 'yes'
+        try {
+            New-TestTask -Name 'conditional' -ExtraMetadata "# WHEN: env.BOLT_WHEN_TEST == 'yes'"
+
+            $result = Invoke-Bolt -Arguments @('conditional')
+
+            $result.Output | Should -Match 'Ran conditional'
+        } finally {
+            Remove-Item -Path Env:BOLT_WHEN_TEST -ErrorAction SilentlyContinue
+        }
+    }
+
+    It "Should record SkipReason condition in JSON results" {
+        New-TestTask -Name 'sign' -ExtraMetadata "# WHEN: exists('marker.txt')"
+        New-TestTask -Name 'package' -Depends @('sign')
+
+        $result = Invoke-Bolt -Arguments @('package', '-OutputFormat', 'Json')
+
+        $result.ExitCode | Should -Be 0
+        $summary = $result.Output | ConvertFrom-Json
+        $summary.Tasks[0].Name | Should -Be 'sign'
+        $summary.Tasks[0].Status | Should -Be 'skipped'
+        $summary.Tasks[0].SkipReason | Should -Be 'condition'
+        $summary.Tasks[1].Status | Should -Be 'success'
+    }
+
+    It "Should skip tasks whose condition is false in -Parallel runs" {
+        New-TestTask -Name 'sign' -ExtraMetadata "# WHEN: exists('marker.txt')"
+        New-TestTask -Name 'package' -Depends @('sign')
+
+        $result = Invoke-Bolt -Arguments @('package', '-Parallel')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '\[sign\]\s+condition is false \(SKIPPED\(condition\)\)'
+        $result.Output | Should -Match 'Ran package'
+    }
+
+    It "Should show skipped tasks in the dry run plan" {
+        New-TestTask -Name 'sign' -ExtraMetadata "# WHEN: exists('marker.txt')"
+
+        $result = Invoke-Bolt -Arguments @('sign', '-DryRun')
+        $result.Output | Should -Match 'sign \(SKIPPED\(condition\)\)'
+        $result.Output | Should -Match "When: exists\('marker.txt'\)"
+
+        Set-Content -Path (Join-Path -Path $script:TempTestRoot -ChildPath 'marker.txt') -Value 'x'
+        $plan = (Invoke-Bolt -Arguments @('sign', '-DryRun', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+        $plan.Tasks[0].SkipReason | Should -BeNullOrEmpty
+        $plan.Tasks[0].When | Should -Be "exists('marker.txt')"
+    }
+
+    It "Should stop before any task runs when a condition is invalid" {
+        New-TestTask -Name 'compile'
+        New-TestTask -Name 'package' -Depends @('compile') -ExtraMetadata "# WHEN: env.CI = 'true'"
+
+        $result = Invoke-Bolt -Arguments @('package')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match "Task 'package': Invalid condition"
+        $result.Output | Should -Not -Match 'Ran compile'
+    }
+
+    It "Should report invalid conditions in -ValidateTasks" {
+        New-TestTask -Name 'package' -ExtraMetadata "# WHEN: exists(go.sum)"
+
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match 'WHEN'
+        $result.Output | Should -Match 'exists\(\) takes one quoted path'
+    }
+}
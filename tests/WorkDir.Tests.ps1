@@ -0,0 +1,129 @@
+#Requires -Version 7.0
+
+BeforeAll {
+    . (Join-Path -Path $PSScriptRoot -ChildPath 'TestHelpers.ps1')
+    Initialize-TestProject -Name 'WorkDir'
+
+    # Directories for the tasks to run in
+    New-Item -ItemType Directory -Path (Join-Path $script:TempTestRoot 'src/app') -Force | Out-Null
+    New-Item -ItemType Directory -Path (Join-Path $script:TempTestRoot 'src/lib') -Force | Out-Null
+
+    # A checkout somewhere else, as in CI
+    $script:CheckoutRoot = Join-Path -Path ([System.IO.Path]::GetTempPath()) -ChildPath "BoltWorkDirCheckout_$(Get-Random)"
+    New-Item -ItemType Directory -Path (Join-Path $script:CheckoutRoot 'src/app') -Force | Out-Null
+
+    $script:PrintLocation = 'Write-Host "cwd=$((Get-Location).Path)"'
+}
+
+AfterAll {
+    Remove-TestProject -AdditionalPath $script:CheckoutRoot
+}
+
+Describe "Task Working Directory" -Tag "Core", "WorkDir" {
+
+    BeforeEach {
+        Remove-Item -Path (Join-Path -Path $script:TempTestRoot -ChildPath '.build') -Recurse -Force -ErrorAction SilentlyContinue
+    }
+
+    It "Should run tasks in their script directory without # WORKDIR:" {
+        New-TestTask -Name 'build' -Body $script:PrintLocation
+
+        $result = Invoke-Bolt -Arguments @('build')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '(?m)^cwd=.*[\\/]\.build\r?$'
+    }
+
+    It "Should run the task in # WORKDIR: relative to the project root" {
+        New-TestTask -Name 'build' -ExtraMetadata '# WORKDIR: src/app' -Body $script:PrintLocation
+
+        $result = Invoke-Bolt -Arguments @('build')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '(?m)^cwd=.*[\\/]src[\\/]app\r?$'
+    }
+
+    It "Should use # WORKDIR: in child processes (<Mode>)" -ForEach @(
+        @{ Mode = '-OutputFormat Json'; Extra = @('-OutputFormat', 'Json') }
+        @{ Mode = '-CleanEnv'; Extra = @('-CleanEnv') }
+    ) {
+        New-TestTask -Name 'build' -ExtraMetadata '# WORKDIR: src/app' -Body "Set-Content -Path 'where.txt' -Value 'here'"
+
+        $result = Invoke-Bolt -Arguments (@('build') + $Extra)
+
+        $result.ExitCode | Should -Be 0
+        Test-Path -Path (Join-Path $script:TempTestRoot 'src/app/where.txt') | Should -BeTrue
+        Remove-Item -Path (Join-Path $script:TempTestRoot 'src/app/where.txt') -Force
+    }
+
+    It "Should give each -Parallel task its own working directory" {
+        New-TestTask -Name 'app' -ExtraMetadata '# WORKDIR: src/app' -Body $script:PrintLocation
+        New-TestTask -Name 'lib' -ExtraMetadata '# WORKDIR: src/lib' -Body $script:PrintLocation
+        New-TestTask -Name 'all' -Depends @('app', 'lib')
+
+        $result = Invoke-Bolt -Arguments @('all', '-Parallel')
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match '\[app\]\s+cwd=.*[\\/]src[\\/]app'
+        $result.Output | Should -Match '\[lib\]\s+cwd=.*[\\/]src[\\/]lib'
+    }
+
+    It "Should run inline hooks in # WORKDIR:" {
+        New-TestTask -Name 'build' -ExtraMetadata "# WORKDIR: src/app`n# BEFORE: Set-Content -Path 'hook.txt' -Value 'x'"
+
+        $result = Invoke-Bolt -Arguments @('build')
+
+        $result.ExitCode | Should -Be 0
+        Test-Path -Path (Join-Path $script:TempTestRoot 'src/app/hook.txt') | Should -BeTrue
+    }
+
+    It "Should resolve # WORKDIR: from -BaseDir" {
+        New-TestTask -Name 'build' -ExtraMetadata '# WORKDIR: src/app' -Body $script:PrintLocation
+
+        $result = Invoke-Bolt -Arguments @('build', '-BaseDir', $script:CheckoutRoot)
+
+        $result.ExitCode | Should -Be 0
+        $result.Output | Should -Match "(?m)^cwd=.*$([regex]::Escape((Split-Path -Path $script:CheckoutRoot -Leaf)))[\\/]src[\\/]app\r?$"
+    }
+
+    It "Should stop before any task runs when # WORKDIR: does not exist" {
+        New-TestTask -Name 'compile'
+        New-TestTask -Name 'package' -Depends @('compile') -ExtraMetadata '# WORKDIR: src/missing'
+
+        $result = Invoke-Bolt -Arguments @('package')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match "Task 'package': Working directory 'src/missing' does not exist"
+        $result.Output | Should -Not -Match 'Ran compile'
+    }
+
+    It "Should fail when -BaseDir does not exist" {
+        New-TestTask -Name 'build'
+
+        $result = Invoke-Bolt -Arguments @('build', '-BaseDir', 'no-such-dir')
+
+        $result.ExitCode | Should -Be 1
+        $result.Error | Should -Match "-BaseDir 'no-such-dir' does not exist"
+        $result.Output | Should -Not -Match 'Ran build'
+    }
+
+    It "Should show # WORKDIR: in the dry run plan" {
+        New-TestTask -Name 'build' -ExtraMetadata '# WORKDIR: src/app'
+
+        $result = Invoke-Bolt -Arguments @('build', '-DryRun')
+        $result.Output | Should -Match 'WorkDir: src/app'
+
+        $plan = (Invoke-Bolt -Arguments @('build', '-DryRun', '-OutputFormat', 'Json')).Output | ConvertFrom-Json
+        $plan.Tasks[0].WorkDir | Should -Be 'src/app'
+    }
+
+    It "Should report a missing # WORKDIR: in -ValidateTasks" {
+        New-TestTask -Name 'build' -ExtraMetadata '# WORKDIR: src/missing'
+
+        $result = Invoke-Bolt -Arguments @('-ValidateTasks')
+
+        $result.ExitCode | Should -Be 1
+        $result.Output | Should -Match 'WORKDIR'
+        $result.Output | Should -Match "Working directory 'src/missing' does not exist"
+    }
+}
@@ -509,9 +509,10 @@ exit 0
             $output | Should -Match '✗ Failures:'
         }
 
-        It 'Should exit with 0 when no failures' {
+        It 'Should exit with 1 when a dependency does not exist' {
+            # Fixtures include bad-deps, which depends on a task that does not exist
             & $script:BoltScriptPath -TaskDirectory "tests/fixtures" -ValidateTasks *>&1 | Out-Null
-            $LASTEXITCODE | Should -Be 0
+            $LASTEXITCODE | Should -Be 1
         }
 
         It 'Should validate task name format' {